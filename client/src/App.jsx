@@ -2,6 +2,12 @@ import { BrowserRouter as Router, Routes, Route, Navigate, useLocation } from 'r
 import { AuthProvider, useAuth } from './contexts/AuthContext';
 import Login from './components/auth/Login';
 import Signup from './components/auth/Signup';
+import ForgotPassword from './components/auth/ForgotPassword';
+import ResetPassword from './components/auth/ResetPassword';
+import VerifyEmail from './components/auth/VerifyEmail';
+import TwoFactorChallenge from './components/auth/TwoFactorChallenge';
+import AcceptInvitation from './components/auth/AcceptInvitation';
+import Unsubscribe from './components/auth/Unsubscribe';
 import OAuthCallback from './Pages/OAuthCallback';
 import './App.css';
 import Navbar from './components/Navbar';
@@ -12,8 +18,10 @@ import CampaignPage from './Pages/CampaignPage';
 import CheckoutPage from './Pages/CheckoutPage';
 import DonationTypePage from './Pages/DonationTypePage';
 import DonationSuccess from './Pages/DonationSuccess';
+import DonationReceipt from './Pages/DonationReceipt';
 import ProfilePage from './Pages/ProfilePage';
 import OrganizationAccountsPage from './Pages/Organization/OrganizationAccountsPage';
+import OrganizationTeamPage from './Pages/Organization/OrganizationTeamPage';
 import NgoRegistration from './Pages/NgoRegistration';
 import CreateCampaign from './Pages/CreateCampaign';
 import UploadProof from './Pages/Ngo/UploadProof';
@@ -77,17 +85,28 @@ const AppRoutes = () => {
           path="/auth/callback"
           element={<OAuthCallback />}
         />
+        <Route path="/login/2fa" element={<TwoFactorChallenge />} />
+        <Route path="/forgot-password" element={<ForgotPassword />} />
+        <Route path="/reset-password" element={<ResetPassword />} />
+        <Route path="/verify-email" element={<VerifyEmail />} />
+        <Route path="/invitations/accept" element={<AcceptInvitation />} />
+        <Route path="/unsubscribe" element={<Unsubscribe />} />
         <Route path="/makeContribution" element={isAuthenticated ? <ContributionsPage /> : <Navigate to="/login" replace />} />
         <Route path="/campaign/:causeID" element={isAuthenticated ? <CampaignPage key={location.pathname} /> : <Navigate to="/login" replace />} />
         <Route path="/checkout" element={isAuthenticated ? <CheckoutPage /> : <Navigate to="/login" replace />} />
         <Route path="/bloodDonation" element={isAuthenticated ? <BloodDonationPage /> : <Navigate to="/login" replace />} />
         <Route path="/volunteer" element={isAuthenticated ? <VolunteerPage /> : <Navigate to="/login" replace />} />
         <Route path="/donation/success" element={isAuthenticated ? <DonationSuccess /> : <Navigate to="/login" replace />} />
+        <Route path="/donations/:donationID/receipt" element={isAuthenticated ? <DonationReceipt /> : <Navigate to="/login" replace />} />
         <Route path="/profile" element={isAuthenticated ? <ProfilePage /> : <Navigate to="/login" replace />} />
         <Route
           path="/organization/accounts"
           element={isAuthenticated ? <OrganizationAccountsPage /> : <Navigate to="/login" replace />}
         />
+        <Route
+          path="/organization/team"
+          element={isAuthenticated ? <OrganizationTeamPage /> : <Navigate to="/login" replace />}
+        />
         <Route
           path="/organization/:organizationId/accounts"
           element={<OrganizationAccountsPage />}
@@ -7,6 +7,7 @@ import { formatGoal, formatCollected, getGoalLabel, getCollectedLabel } from "..
 import { LuLink2, LuLock, LuShieldCheck } from "react-icons/lu";
 import { BiUpvote, BiDownvote } from "react-icons/bi";
 import { useAuth } from "../contexts/AuthContext";
+import { canOrganization } from "../utils/organizationRoles";
 
 const PRODUCT_AID_TYPE_NAMES = [
   "Goods & Resources",
@@ -41,6 +42,7 @@ const CampaignPage = () => {
   const proofsFetchedRef = useRef(new Set());
   const [totalDisbursed, setTotalDisbursed] = useState(0);
   const [disbursementsLoading, setDisbursementsLoading] = useState(true);
+  const [inKind, setInKind] = useState({ items: [], total_estimated_value: 0 });
 
   useEffect(() => {
     const fetchData = async () => {
@@ -80,14 +82,12 @@ const CampaignPage = () => {
       // and load existing textual reviews.
       setReviewsLoading(true);
       const [myDonationsResult, reviewsResult] = await Promise.all([
-        apiRequest(API_ENDPOINTS.GET_MY_DONATIONS),
+        apiRequest(`${API_ENDPOINTS.GET_MY_DONATIONS}?cause_id=${causeID}&limit=1`),
         apiRequest(API_ENDPOINTS.GET_CAUSE_REVIEWS(causeID)),
       ]);
 
-      if (myDonationsResult.success && Array.isArray(myDonationsResult.data)) {
-        const hasDonated = myDonationsResult.data.some(
-          (d) => String(d.cause_id) === String(causeID)
-        );
+      if (myDonationsResult.success && Array.isArray(myDonationsResult.data?.data)) {
+        const hasDonated = myDonationsResult.data.data.length > 0;
 
         const hasReviewed = reviewsResult.data["reviews"].some(
           (d) => String(d.user_id) === String(user.id)
@@ -114,6 +114,14 @@ const CampaignPage = () => {
         setTotalDisbursed(disbursementsResult.data.total_disbursed || 0);
       }
       setDisbursementsLoading(false);
+
+      // Goods received in kind, shown alongside monetary donations
+      const inKindResult = await apiRequest(
+        API_ENDPOINTS.GET_CAUSE_RECEIVED_GOODS(causeID)
+      );
+      if (inKindResult.success && inKindResult.data) {
+        setInKind(inKindResult.data);
+      }
     };
 
     fetchData();
@@ -182,8 +190,9 @@ const CampaignPage = () => {
     { id: "donations", label: "Donations" },
   ];
 
+  // Members of the cause's organization who may post updates
   const isOwner =
-    user?.role === "organization" &&
+    canOrganization(organization, "updates") &&
     organization?.id &&
     cause?.organization?.id &&
     String(organization.id) === String(cause.organization.id);
@@ -239,7 +248,7 @@ const CampaignPage = () => {
           if (res.success) {
             setProofsBySession((prev) => ({
               ...prev,
-              [sessionId]: Array.isArray(res.data) ? res.data : [],
+              [sessionId]: Array.isArray(res.data?.data) ? res.data.data : [],
             }));
           }
           setProofsLoadingBySession((prev) => ({
@@ -1068,6 +1077,47 @@ const CampaignPage = () => {
                   </div>
                 </div>
               )}
+
+              {inKind.items.length > 0 && (
+                <div className="mt-6 rounded-xl border border-gray-200 bg-white shadow-sm p-5">
+                  <div className="flex items-start justify-between gap-4 flex-wrap">
+                    <h3 className="text-lg font-semibold text-[#3a0b2e]">
+                      Goods received
+                    </h3>
+                    {inKind.total_estimated_value > 0 && (
+                      <span className="text-sm text-gray-600">
+                        Estimated value: ₹{parseFloat(inKind.total_estimated_value).toLocaleString()}
+                      </span>
+                    )}
+                  </div>
+                  <ul className="mt-4 divide-y divide-gray-100">
+                    {inKind.items.map((item) => (
+                      <li key={item.pledge_id} className="py-3 flex items-center gap-3">
+                        {item.proof_image && (
+                          <img
+                            src={`${API_BASE_URL}/uploads/${item.proof_image}`}
+                            alt={item.item_name}
+                            className="h-12 w-12 rounded-lg object-cover border border-gray-200"
+                          />
+                        )}
+                        <div className="flex-1">
+                          <p className="text-sm font-medium text-gray-800">
+                            {item.quantity} {item.unit || ""} {item.item_name}
+                          </p>
+                          <p className="text-xs text-gray-500">
+                            Received {new Date(item.received_at).toLocaleDateString()}
+                          </p>
+                        </div>
+                        {item.estimated_value != null && (
+                          <span className="text-sm text-gray-700">
+                            ₹{parseFloat(item.estimated_value).toLocaleString()}
+                          </span>
+                        )}
+                      </li>
+                    ))}
+                  </ul>
+                </div>
+              )}
             </div>
           )}
         </div>
@@ -1145,6 +1195,22 @@ const CampaignPage = () => {
             {getCollectedLabel(aidTypeName)} of {formatGoal(goal, aidTypeName)} goal
           </p>
 
+          {cause.matching_campaigns?.map((m) => (
+            <div
+              key={m.campaign_id}
+              className="mb-4 rounded-lg border border-amber-200 bg-amber-50 px-4 py-3 text-sm"
+            >
+              <p className="font-semibold text-[#3a0b2e]">
+                {m.sponsor_name} matches {m.ratio === 1 ? "every rupee" : `${m.ratio}x`}
+              </p>
+              <p className="text-gray-600 text-xs mt-1">
+                ₹{parseFloat(m.remaining_amount).toLocaleString()} of ₹
+                {parseFloat(m.cap_amount).toLocaleString()} match left until{" "}
+                {new Date(m.ends_at).toLocaleDateString()}
+              </p>
+            </div>
+          ))}
+
           {/* {fundingStatus !== "Fully Funded" &&
             fundingStatus !== "Closed" && (
               <Link
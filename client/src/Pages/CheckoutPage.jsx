@@ -14,6 +14,13 @@ const CheckoutPage = () => {
 
   const [amount, setAmount] = useState(500);
   const [isAnonymous, setIsAnonymous] = useState(false);
+  const [isTribute, setIsTribute] = useState(false);
+  const [tribute, setTribute] = useState({
+    type: "honour",
+    name: "",
+    message: "",
+    notify_email: "",
+  });
   const [isIndianCitizen, setIsIndianCitizen] = useState(false);
   const [cause, setCause] = useState(null);
   const [loadingCause, setLoadingCause] = useState(true);
@@ -237,6 +244,52 @@ const CheckoutPage = () => {
                     Make this donation anonymous
                   </label>
                 </div>
+                <div className="flex items-center mt-2">
+                  <input
+                    type="checkbox"
+                    id="tribute"
+                    checked={isTribute}
+                    onChange={() => setIsTribute(!isTribute)}
+                    className="mr-2 accent-[#ff6200] cursor-pointer"
+                  />
+                  <label htmlFor="tribute" className="text-sm text-gray-600">
+                    Dedicate this donation to someone
+                  </label>
+                </div>
+                {isTribute && (
+                  <div className="mt-3 space-y-2">
+                    <select
+                      value={tribute.type}
+                      onChange={(e) => setTribute({ ...tribute, type: e.target.value })}
+                      className="w-full border border-gray-300 rounded-lg py-2 px-3 focus:outline-none focus:border-[#ff6200]"
+                    >
+                      <option value="honour">In honour of</option>
+                      <option value="memory">In memory of</option>
+                    </select>
+                    <input
+                      type="text"
+                      value={tribute.name}
+                      onChange={(e) => setTribute({ ...tribute, name: e.target.value })}
+                      placeholder="Their name"
+                      className="w-full border border-gray-300 rounded-lg py-2 px-3 focus:outline-none focus:border-[#ff6200] placeholder-gray-400"
+                    />
+                    <input
+                      type="email"
+                      value={tribute.notify_email}
+                      onChange={(e) => setTribute({ ...tribute, notify_email: e.target.value })}
+                      placeholder="Send an e-card to (optional)"
+                      className="w-full border border-gray-300 rounded-lg py-2 px-3 focus:outline-none focus:border-[#ff6200] placeholder-gray-400"
+                    />
+                    <textarea
+                      value={tribute.message}
+                      onChange={(e) => setTribute({ ...tribute, message: e.target.value })}
+                      placeholder="Message (optional)"
+                      maxLength={500}
+                      rows={2}
+                      className="w-full border border-gray-300 rounded-lg py-2 px-3 focus:outline-none focus:border-[#ff6200] placeholder-gray-400"
+                    />
+                  </div>
+                )}
               </div>
 
               {/* Mobile Number */}
@@ -398,6 +451,16 @@ const CheckoutPage = () => {
                     address: form.address,
                     pincode: form.pincode,
                     pan: form.pan,
+                    isAnonymous,
+                    tribute:
+                      isTribute && tribute.name.trim()
+                        ? {
+                            type: tribute.type,
+                            name: tribute.name.trim(),
+                            message: tribute.message.trim() || undefined,
+                            notify_email: tribute.notify_email.trim() || undefined,
+                          }
+                        : undefined,
                   }}
                   causeId={causeId}
                 />
@@ -0,0 +1,89 @@
+import { useEffect, useState } from "react";
+import { Link, useParams } from "react-router-dom";
+import { apiRequest, API_ENDPOINTS } from "../config/api";
+
+const Row = ({ label, children }) => (
+  <div className="flex flex-col sm:flex-row sm:items-center sm:justify-between gap-1 py-2">
+    <p className="text-sm text-gray-600">{label}</p>
+    <div className="text-sm text-gray-800 break-all sm:text-right">{children}</div>
+  </div>
+);
+
+// The receipt linked from the thank-you email. The donor sees their PAN and
+// billing details; anyone else only sees what's public about the donation.
+const DonationReceipt = () => {
+  const { donationID } = useParams();
+  const [donation, setDonation] = useState(null);
+  const [cause, setCause] = useState(null);
+  const [error, setError] = useState(null);
+
+  useEffect(() => {
+    (async () => {
+      const result = await apiRequest(API_ENDPOINTS.GET_DONATION(donationID));
+      if (!result.success) {
+        setError(result.error || "Failed to load the donation");
+        return;
+      }
+      setDonation(result.data);
+
+      const causeResult = await apiRequest(`${API_ENDPOINTS.GET_CAUSES}/${result.data.cause_id}`);
+      if (causeResult.success) setCause(causeResult.data);
+    })();
+  }, [donationID]);
+
+  if (error) {
+    return <p className="max-w-3xl mx-auto px-4 py-12 text-red-700">{error}</p>;
+  }
+  if (!donation) {
+    return <p className="max-w-3xl mx-auto px-4 py-12 text-gray-600">Loading...</p>;
+  }
+
+  return (
+    <div className="bg-gray-100 py-12 min-h-[70vh]">
+      <div className="max-w-3xl mx-auto px-4">
+        <div className="bg-white rounded-xl shadow-lg border border-gray-200 p-8">
+          <p className="text-sm font-semibold text-[#ff6200]">Donation receipt</p>
+          <h1 className="text-3xl font-bold text-[#3a0b2e] mt-2">
+            ₹{Number(donation.amount).toFixed(2)}
+          </h1>
+          <p className="text-gray-600 mt-1">
+            {new Date(donation.created_at).toLocaleString()}
+          </p>
+
+          <div className="mt-6 border-t border-gray-200 pt-4 divide-y divide-gray-100">
+            <Row label="Receipt number">{donation.id}</Row>
+            <Row label="Cause">
+              <Link
+                to={`/campaign/${donation.cause_id}`}
+                className="font-semibold text-[#3a0b2e] hover:text-[#ff6200] transition"
+              >
+                {cause?.title || "View campaign"}
+              </Link>
+            </Row>
+            {cause?.organization?.name && <Row label="Organization">{cause.organization.name}</Row>}
+            <Row label="Donor">{donation.name}</Row>
+            {donation.pan_number && <Row label="PAN">{donation.pan_number}</Row>}
+            {donation.billing_address && (
+              <Row label="Billing address">
+                {donation.billing_address}
+                {donation.pincode && ` ${donation.pincode}`}
+              </Row>
+            )}
+            <Row label="Status">{donation.status}</Row>
+            {donation.payment_id && <Row label="Payment ID">{donation.payment_id}</Row>}
+            {donation.tx_hash && <Row label="Ledger transaction">{donation.tx_hash}</Row>}
+          </div>
+
+          <button
+            onClick={() => window.print()}
+            className="mt-8 bg-[#ff6200] hover:bg-[#e45a00] text-white font-semibold px-5 py-3 rounded-lg transition cursor-pointer print:hidden"
+          >
+            Print receipt
+          </button>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default DonationReceipt;
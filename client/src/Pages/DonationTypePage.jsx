@@ -52,7 +52,7 @@ const DonationTypePage = () => {
       const causesResult = await apiRequest(`${apiEndpoint}/${causeCategory.id}`)
 
       if (causesResult.success && causesResult.data) {
-        setCauses(causesResult.data)
+        setCauses(causesResult.data.data || [])
         setLoadingCauses(false)
       }
     }
@@ -177,7 +177,7 @@ export default function UploadUpdate() {
           API_ENDPOINTS.GET_PROOF_IMAGES_BY_SESSION(sessionId)
         );
         if (res.success) {
-          setProofs(Array.isArray(res.data) ? res.data : []);
+          setProofs(Array.isArray(res.data?.data) ? res.data.data : []);
         } else {
           setProofsError(res.error || "Failed to load proofs");
           setProofs([]);
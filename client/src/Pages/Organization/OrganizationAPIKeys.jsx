@@ -0,0 +1,190 @@
+import { useEffect, useState } from "react";
+import { apiRequest, API_ENDPOINTS } from "../../config/api";
+
+const SCOPES = [
+  { value: "donations:read", label: "Read donations and donor details" },
+  { value: "causes:read", label: "Read unpublished causes" },
+  { value: "updates:write", label: "Post cause updates and receipts" },
+  { value: "disbursements:read", label: "Read disbursements" },
+];
+
+// Lets owners and admins issue and revoke the API keys their own systems
+// call the API with. A new key is shown once, right after it's created.
+const OrganizationAPIKeys = () => {
+  const [keys, setKeys] = useState(null);
+  const [name, setName] = useState("");
+  const [scopes, setScopes] = useState([]);
+  const [rateLimit, setRateLimit] = useState(60);
+  const [newKey, setNewKey] = useState(null);
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState(null);
+
+  const loadKeys = async () => {
+    const result = await apiRequest(API_ENDPOINTS.ORGANIZATION_API_KEYS);
+    if (result.success) {
+      setKeys(result.data);
+    } else {
+      setError(result.error || "Failed to load API keys");
+    }
+  };
+
+  useEffect(() => {
+    loadKeys();
+  }, []);
+
+  const toggleScope = (scope) =>
+    setScopes((current) =>
+      current.includes(scope)
+        ? current.filter((s) => s !== scope)
+        : [...current, scope]
+    );
+
+  const handleCreate = async (e) => {
+    e.preventDefault();
+    setBusy(true);
+    setError(null);
+    setNewKey(null);
+    const result = await apiRequest(API_ENDPOINTS.ORGANIZATION_API_KEYS, {
+      method: "POST",
+      body: JSON.stringify({
+        name: name.trim(),
+        scopes,
+        rate_limit_per_minute: Number(rateLimit),
+      }),
+    });
+    setBusy(false);
+    if (!result.success) {
+      setError(result.error || "Failed to create the API key");
+      return;
+    }
+    setNewKey(result.data.key);
+    setName("");
+    setScopes([]);
+    await loadKeys();
+  };
+
+  const handleRevoke = async (key) => {
+    if (!window.confirm(`Revoke "${key.name}"? Anything using it will stop working.`)) {
+      return;
+    }
+    setBusy(true);
+    setError(null);
+    const result = await apiRequest(API_ENDPOINTS.ORGANIZATION_API_KEY(key.id), {
+      method: "DELETE",
+    });
+    setBusy(false);
+    if (!result.success) {
+      setError(result.error || "Failed to revoke the API key");
+      return;
+    }
+    await loadKeys();
+  };
+
+  return (
+    <div className="mt-6 rounded-xl border border-gray-200 bg-white p-5 shadow-sm">
+      <h2 className="text-lg font-semibold text-[#3a0b2e] mb-1">API keys</h2>
+      <p className="text-sm text-gray-600 mb-4">
+        Let your own systems call the API. Send the key as{" "}
+        <code className="bg-gray-100 px-1 rounded">Authorization: Bearer &lt;key&gt;</code>.
+      </p>
+
+      {error && (
+        <div className="mb-4 text-sm bg-red-50 border border-red-200 text-red-700 rounded-lg px-3 py-2">
+          {error}
+        </div>
+      )}
+      {newKey && (
+        <div className="mb-4 text-sm bg-green-50 border border-green-200 text-green-800 rounded-lg px-3 py-2">
+          <p className="font-semibold mb-1">
+            Copy this key now. It won't be shown again.
+          </p>
+          <code className="block break-all bg-white border border-green-200 rounded px-2 py-1">
+            {newKey}
+          </code>
+        </div>
+      )}
+
+      <form onSubmit={handleCreate} className="mb-5 space-y-3">
+        <div className="flex flex-wrap gap-3 items-center">
+          <input
+            type="text"
+            value={name}
+            onChange={(e) => setName(e.target.value)}
+            placeholder="Key name, e.g. CRM sync"
+            maxLength={100}
+            className="flex-1 min-w-[200px] border border-gray-300 rounded-lg py-2 px-3 focus:outline-none focus:border-[#ff6200]"
+          />
+          <label className="text-sm text-gray-700 flex items-center gap-2">
+            Requests / minute
+            <input
+              type="number"
+              min={1}
+              max={600}
+              value={rateLimit}
+              onChange={(e) => setRateLimit(e.target.value)}
+              className="w-24 border border-gray-300 rounded-lg py-2 px-3"
+            />
+          </label>
+        </div>
+        <div className="flex flex-wrap gap-4">
+          {SCOPES.map((scope) => (
+            <label key={scope.value} className="text-sm text-gray-700 flex items-center gap-2">
+              <input
+                type="checkbox"
+                checked={scopes.includes(scope.value)}
+                onChange={() => toggleScope(scope.value)}
+              />
+              {scope.label}
+            </label>
+          ))}
+        </div>
+        <button
+          type="submit"
+          disabled={busy || !name.trim() || scopes.length === 0}
+          className="bg-[#ff6200] hover:bg-[#e45a00] text-white font-semibold px-4 py-2 rounded-lg transition cursor-pointer disabled:opacity-60"
+        >
+          Create key
+        </button>
+      </form>
+
+      {!keys ? (
+        <p className="text-gray-600">Loading...</p>
+      ) : keys.length === 0 ? (
+        <p className="text-sm text-gray-500">No API keys.</p>
+      ) : (
+        <ul className="divide-y divide-gray-100">
+          {keys.map((key) => (
+            <li
+              key={key.id}
+              className="py-3 flex flex-wrap items-center justify-between gap-3"
+            >
+              <div>
+                <p className="font-medium text-gray-800">
+                  {key.name}{" "}
+                  <code className="text-xs text-gray-500">clk_{key.prefix}_…</code>
+                </p>
+                <p className="text-sm text-gray-500">
+                  {key.scopes.join(", ")} · {key.rate_limit_per_minute}/min ·{" "}
+                  {key.last_used_at
+                    ? `last used ${new Date(key.last_used_at).toLocaleString()}`
+                    : "never used"}
+                  {key.expires_at &&
+                    ` · expires ${new Date(key.expires_at).toLocaleDateString()}`}
+                </p>
+              </div>
+              <button
+                onClick={() => handleRevoke(key)}
+                disabled={busy}
+                className="text-sm text-red-600 hover:text-red-700 font-semibold cursor-pointer"
+              >
+                Revoke
+              </button>
+            </li>
+          ))}
+        </ul>
+      )}
+    </div>
+  );
+};
+
+export default OrganizationAPIKeys;
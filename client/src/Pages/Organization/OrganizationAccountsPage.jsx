@@ -3,6 +3,7 @@ import { Link, useParams } from "react-router-dom";
 import { apiRequest, API_ENDPOINTS } from "../../config/api";
 import { useAuth } from "../../contexts/AuthContext";
 import { getCauseImage } from "../../utils/imageHelper";
+import { canOrganization } from "../../utils/organizationRoles";
 import causePlaceholder from "../../../public/domains/domain_example.png";
 
 const OrganizationAccountsPage = () => {
@@ -24,31 +25,8 @@ const OrganizationAccountsPage = () => {
 
   const organizationIdToFetch = organizationId || organization?.id;
 
-  const computeFundingStatus = (cause) => {
-    const collected = Number(cause?.collected_amount) || 0;
-    const rawGoal = cause?.goal_amount;
-    const goal = rawGoal != null ? parseFloat(rawGoal) : 0;
-    const hasValidGoal = rawGoal != null && !Number.isNaN(goal) && goal > 0;
-
-    const deadline = cause?.deadline ? new Date(cause.deadline) : null;
-    const now = new Date();
-
-    // Matches the backend logic (computeFundingStatus in models/cause.go).
-    if (!hasValidGoal) {
-      if (collected <= 0) return "Not Started";
-      return "Active";
-    }
-
-    if (collected <= 0) {
-      if (deadline && deadline < now) return "Closed";
-      return "Not Started";
-    }
-
-    if (collected >= goal) return "Fully Funded";
-
-    if (deadline && deadline < now) return "Closed";
-    return "Active";
-  };
+  // The server derives this from the cause's lifecycle state.
+  const computeFundingStatus = (cause) => cause?.funding_status || "Not Started";
 
   const getDaysLeft = (deadline) => {
     if (!deadline) return null;
@@ -90,7 +68,7 @@ const OrganizationAccountsPage = () => {
   // Fetch disbursements for the organization
   useEffect(() => {
     const fetchDisbursements = async () => {
-      if (!user || user?.role !== "organization") return;
+      if (!canOrganization(organization, "finances")) return;
 
       setLoadingDisbursements(true);
 
@@ -116,7 +94,7 @@ const OrganizationAccountsPage = () => {
     };
 
     fetchDisbursements();
-  }, [user]);
+  }, [organization]);
 
   const sortedCauses = useMemo(() => {
     return [...causes].sort((a, b) => {
@@ -134,7 +112,7 @@ const OrganizationAccountsPage = () => {
   };
 
   const canViewAsMyOrg =
-    !!organizationId || (user?.role === "organization" && organization?.id);
+    !!organizationId || !!organization?.id;
 
   const organizationName =
     causes?.[0]?.organization?.name || organization?.organization_name || "Organization";
@@ -171,6 +149,14 @@ const OrganizationAccountsPage = () => {
           Showing causes for{" "}
           <span className="font-semibold text-gray-800">{organizationName}</span>
         </p>
+        {organization?.member_role && (
+          <Link
+            to="/organization/team"
+            className="inline-block mt-2 text-[#ff6200] hover:text-[#e45a00] font-semibold"
+          >
+            Team members →
+          </Link>
+        )}
       </div>
 
       {/* Organization details */}
@@ -272,7 +258,7 @@ const OrganizationAccountsPage = () => {
       </div>
 
       {/* Disbursement History Section */}
-      {!!organizationId && user?.role === "organization" ? (
+      {!!organizationId && canOrganization(organization, "finances") ? (
         <div className="mb-6 rounded-xl border border-gray-200 bg-white p-5 shadow-sm">
           <h3 className="text-lg font-semibold text-[#3a0b2e] mb-4">
             Disbursement History
@@ -362,7 +348,7 @@ const OrganizationAccountsPage = () => {
       ) : sortedCauses.length === 0 ? (
         <div className="rounded-xl border border-gray-200 bg-white p-8 text-center">
           <p className="text-gray-600">No causes found for this organization.</p>
-          {!organizationId && user?.role === "organization" && (
+          {!organizationId && canOrganization(organization, "causes") && (
             <Link
               to="/createCampaign"
               className="inline-block mt-4 text-[#ff6200] hover:text-[#e45a00] font-semibold"
@@ -443,7 +429,7 @@ const OrganizationAccountsPage = () => {
                     >
                       View
                     </Link>
-                    {user?.role === "organization" &&
+                    {canOrganization(organization, "updates") &&
                       organization?.id &&
                       cause?.organization?.id &&
                       String(organization.id) === String(cause.organization.id) && (
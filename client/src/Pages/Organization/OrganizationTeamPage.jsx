@@ -0,0 +1,273 @@
+import { useEffect, useState } from "react";
+import { Link, useNavigate } from "react-router-dom";
+import { apiRequest, API_ENDPOINTS } from "../../config/api";
+import { useAuth } from "../../contexts/AuthContext";
+import {
+  ORGANIZATION_ROLES,
+  canOrganization,
+  roleLabel,
+} from "../../utils/organizationRoles";
+import OrganizationAPIKeys from "./OrganizationAPIKeys";
+import OrganizationWebhooks from "./OrganizationWebhooks";
+
+// Lists the organization's team. Owners and admins can also invite people,
+// change roles and remove members; everyone can leave.
+const OrganizationTeamPage = () => {
+  const { user, organization, fetchCurrentOrganization } = useAuth();
+  const navigate = useNavigate();
+  const [team, setTeam] = useState(null);
+  const [email, setEmail] = useState("");
+  const [role, setRole] = useState("viewer");
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState(null);
+  const [notice, setNotice] = useState(null);
+
+  const canManage = canOrganization(organization, "members");
+  const isOwner = organization?.member_role === "owner";
+  // Only owners may hand out the owner role.
+  const assignableRoles = ORGANIZATION_ROLES.filter(
+    (r) => isOwner || r.value !== "owner"
+  );
+
+  const loadTeam = async () => {
+    const result = await apiRequest(API_ENDPOINTS.GET_ORGANIZATION_TEAM);
+    if (result.success) {
+      setTeam(result.data);
+    } else {
+      setError(result.error || "Failed to load the team");
+    }
+  };
+
+  useEffect(() => {
+    loadTeam();
+  }, []);
+
+  const run = async (request, successNotice) => {
+    setBusy(true);
+    setError(null);
+    setNotice(null);
+    const result = await request();
+    setBusy(false);
+    if (!result.success) {
+      setError(result.error || "Something went wrong");
+      return false;
+    }
+    if (successNotice) setNotice(successNotice);
+    await loadTeam();
+    return true;
+  };
+
+  const handleInvite = async (e) => {
+    e.preventDefault();
+    const sent = await run(
+      () =>
+        apiRequest(API_ENDPOINTS.INVITE_ORGANIZATION_MEMBER, {
+          method: "POST",
+          body: JSON.stringify({ email: email.trim(), role }),
+        }),
+      `Invitation sent to ${email.trim()}`
+    );
+    if (sent) setEmail("");
+  };
+
+  const handleRevoke = (invitationId) =>
+    run(() =>
+      apiRequest(API_ENDPOINTS.REVOKE_ORGANIZATION_INVITATION(invitationId), {
+        method: "DELETE",
+      })
+    );
+
+  const handleRoleChange = (memberId, newRole) =>
+    run(() =>
+      apiRequest(API_ENDPOINTS.ORGANIZATION_MEMBER(memberId), {
+        method: "PATCH",
+        body: JSON.stringify({ role: newRole }),
+      })
+    );
+
+  const handleRemove = async (member) => {
+    const leaving = member.user_id === user?.id;
+    const prompt = leaving
+      ? "Leave this organization? You'll need a new invitation to rejoin."
+      : `Remove ${member.name} from the team?`;
+    if (!window.confirm(prompt)) return;
+
+    const removed = await run(() =>
+      apiRequest(API_ENDPOINTS.ORGANIZATION_MEMBER(member.user_id), {
+        method: "DELETE",
+      })
+    );
+    if (removed && leaving) {
+      await fetchCurrentOrganization();
+      navigate("/profile", { replace: true });
+    }
+  };
+
+  if (!organization?.member_role) {
+    return (
+      <div className="max-w-7xl mx-auto px-4 py-12 text-center">
+        <p className="text-gray-600">You aren't a member of an organization.</p>
+        <Link
+          to="/profile"
+          className="inline-block mt-4 text-[#ff6200] hover:text-[#e45a00] font-semibold"
+        >
+          Back to profile →
+        </Link>
+      </div>
+    );
+  }
+
+  return (
+    <div className="max-w-5xl mx-auto px-4 sm:px-6 lg:px-8 py-8">
+      <div className="mb-6">
+        <h1 className="text-3xl font-bold text-[#3a0b2e]">Team</h1>
+        <p className="text-gray-600 mt-2">
+          People who work on{" "}
+          <span className="font-semibold text-gray-800">
+            {organization.organization_name}
+          </span>
+          . Your role: <span className="font-semibold">{roleLabel(organization.member_role)}</span>
+        </p>
+      </div>
+
+      {error && (
+        <div className="mb-4 text-sm bg-red-50 border border-red-200 text-red-700 rounded-lg px-3 py-2">
+          {error}
+        </div>
+      )}
+      {notice && (
+        <div className="mb-4 text-sm bg-green-50 border border-green-200 text-green-700 rounded-lg px-3 py-2">
+          {notice}
+        </div>
+      )}
+
+      <div className="mb-6 rounded-xl border border-gray-200 bg-white p-5 shadow-sm">
+        <h2 className="text-lg font-semibold text-[#3a0b2e] mb-4">Members</h2>
+        {!team ? (
+          <p className="text-gray-600">Loading...</p>
+        ) : (
+          <ul className="divide-y divide-gray-100">
+            {team.members.map((member) => {
+              const isSelf = member.user_id === user?.id;
+              const editable =
+                canManage &&
+                !isSelf &&
+                !member.is_primary &&
+                (isOwner || member.role !== "owner");
+              return (
+                <li
+                  key={member.id}
+                  className="py-3 flex flex-wrap items-center justify-between gap-3"
+                >
+                  <div>
+                    <p className="font-medium text-gray-800">
+                      {member.name}
+                      {isSelf && <span className="text-gray-500"> (you)</span>}
+                    </p>
+                    <p className="text-sm text-gray-500">{member.email}</p>
+                  </div>
+                  <div className="flex items-center gap-3">
+                    {editable ? (
+                      <select
+                        value={member.role}
+                        disabled={busy}
+                        onChange={(e) => handleRoleChange(member.user_id, e.target.value)}
+                        className="border border-gray-300 rounded-lg py-1 px-2 text-sm"
+                      >
+                        {assignableRoles.map((r) => (
+                          <option key={r.value} value={r.value}>
+                            {r.label}
+                          </option>
+                        ))}
+                      </select>
+                    ) : (
+                      <span className="text-sm text-gray-700">
+                        {roleLabel(member.role)}
+                        {member.is_primary && " · primary account"}
+                      </span>
+                    )}
+                    {(editable || (isSelf && !member.is_primary)) && (
+                      <button
+                        onClick={() => handleRemove(member)}
+                        disabled={busy}
+                        className="text-sm text-red-600 hover:text-red-700 font-semibold cursor-pointer"
+                      >
+                        {isSelf ? "Leave" : "Remove"}
+                      </button>
+                    )}
+                  </div>
+                </li>
+              );
+            })}
+          </ul>
+        )}
+      </div>
+
+      {canManage && (
+        <div className="rounded-xl border border-gray-200 bg-white p-5 shadow-sm">
+          <h2 className="text-lg font-semibold text-[#3a0b2e] mb-4">Invitations</h2>
+          <form onSubmit={handleInvite} className="flex flex-wrap gap-3 items-center mb-4">
+            <input
+              type="email"
+              value={email}
+              onChange={(e) => setEmail(e.target.value)}
+              placeholder="Email address"
+              className="flex-1 min-w-[200px] border border-gray-300 rounded-lg py-2 px-3 focus:outline-none focus:border-[#ff6200]"
+            />
+            <select
+              value={role}
+              onChange={(e) => setRole(e.target.value)}
+              className="border border-gray-300 rounded-lg py-2 px-3"
+            >
+              {assignableRoles.map((r) => (
+                <option key={r.value} value={r.value}>
+                  {r.label}
+                </option>
+              ))}
+            </select>
+            <button
+              type="submit"
+              disabled={busy || !email.trim()}
+              className="bg-[#ff6200] hover:bg-[#e45a00] text-white font-semibold px-4 py-2 rounded-lg transition cursor-pointer disabled:opacity-60"
+            >
+              Send invitation
+            </button>
+          </form>
+
+          {team?.invitations.length === 0 ? (
+            <p className="text-sm text-gray-500">No pending invitations.</p>
+          ) : (
+            <ul className="divide-y divide-gray-100">
+              {team?.invitations.map((inv) => (
+                <li
+                  key={inv.id}
+                  className="py-3 flex flex-wrap items-center justify-between gap-3"
+                >
+                  <div>
+                    <p className="font-medium text-gray-800">{inv.email}</p>
+                    <p className="text-sm text-gray-500">
+                      {roleLabel(inv.role)} · expires{" "}
+                      {new Date(inv.expires_at).toLocaleDateString()}
+                    </p>
+                  </div>
+                  <button
+                    onClick={() => handleRevoke(inv.id)}
+                    disabled={busy}
+                    className="text-sm text-red-600 hover:text-red-700 font-semibold cursor-pointer"
+                  >
+                    Revoke
+                  </button>
+                </li>
+              ))}
+            </ul>
+          )}
+        </div>
+      )}
+
+      {canOrganization(organization, "apiKeys") && <OrganizationAPIKeys />}
+      {canOrganization(organization, "webhooks") && <OrganizationWebhooks />}
+    </div>
+  );
+};
+
+export default OrganizationTeamPage;
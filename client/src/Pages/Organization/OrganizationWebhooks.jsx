@@ -0,0 +1,323 @@
+import { useEffect, useState } from "react";
+import { apiRequest, API_ENDPOINTS } from "../../config/api";
+
+const EVENTS = [
+  { value: "donation.completed", label: "Donation completed" },
+  { value: "milestone.reached", label: "Milestone reached" },
+  { value: "receipt_verification.completed", label: "Receipt verification finished" },
+  { value: "dispute.opened", label: "Dispute opened" },
+];
+
+const STATUS_STYLES = {
+  succeeded: "bg-green-100 text-green-800",
+  pending: "bg-yellow-100 text-yellow-800",
+  dead: "bg-red-100 text-red-700",
+};
+
+// Delivery log for one endpoint, newest first, with replay.
+const WebhookDeliveries = ({ endpoint }) => {
+  const [deliveries, setDeliveries] = useState(null);
+  const [nextCursor, setNextCursor] = useState(null);
+  const [error, setError] = useState(null);
+
+  const load = async (cursor) => {
+    const result = await apiRequest(
+      API_ENDPOINTS.ORGANIZATION_WEBHOOK_DELIVERIES(endpoint.id, cursor)
+    );
+    if (!result.success) {
+      setError(result.error || "Failed to load deliveries");
+      return;
+    }
+    const page = result.data.data || [];
+    setDeliveries((current) => (cursor ? [...(current || []), ...page] : page));
+    setNextCursor(result.data.next_cursor);
+  };
+
+  useEffect(() => {
+    load(null);
+  }, [endpoint.id]);
+
+  const handleReplay = async (delivery) => {
+    setError(null);
+    const result = await apiRequest(
+      API_ENDPOINTS.REPLAY_WEBHOOK_DELIVERY(endpoint.id, delivery.id),
+      { method: "POST" }
+    );
+    if (!result.success) {
+      setError(result.error || "Failed to replay the delivery");
+      return;
+    }
+    setDeliveries((current) =>
+      current.map((d) => (d.id === delivery.id ? result.data : d))
+    );
+  };
+
+  if (error) {
+    return <p className="text-sm text-red-700 mt-2">{error}</p>;
+  }
+  if (!deliveries) {
+    return <p className="text-sm text-gray-600 mt-2">Loading...</p>;
+  }
+  if (deliveries.length === 0) {
+    return <p className="text-sm text-gray-500 mt-2">Nothing sent yet.</p>;
+  }
+
+  return (
+    <div className="mt-3">
+      <ul className="divide-y divide-gray-100 text-sm">
+        {deliveries.map((d) => (
+          <li key={d.id} className="py-2 flex flex-wrap items-center justify-between gap-2">
+            <div>
+              <span
+                className={`inline-block rounded px-2 py-0.5 text-xs font-semibold mr-2 ${
+                  STATUS_STYLES[d.status] || "bg-gray-100 text-gray-700"
+                }`}
+              >
+                {d.status}
+              </span>
+              <span className="font-medium text-gray-800">{d.event_type}</span>
+              <span className="text-gray-500">
+                {" "}
+                · {new Date(d.created_at).toLocaleString()} · {d.attempts} attempt
+                {d.attempts === 1 ? "" : "s"}
+                {d.last_status_code && ` · HTTP ${d.last_status_code}`}
+                {d.status === "pending" &&
+                  d.attempts > 0 &&
+                  d.next_attempt_at &&
+                  ` · retrying ${new Date(d.next_attempt_at).toLocaleString()}`}
+              </span>
+              {d.last_error && d.status !== "succeeded" && (
+                <p className="text-xs text-red-600 break-all">{d.last_error}</p>
+              )}
+            </div>
+            <button
+              onClick={() => handleReplay(d)}
+              className="text-sm text-[#ff6200] hover:text-[#e45a00] font-semibold cursor-pointer"
+            >
+              Replay
+            </button>
+          </li>
+        ))}
+      </ul>
+      {nextCursor && (
+        <button
+          onClick={() => load(nextCursor)}
+          className="mt-2 text-sm text-gray-700 hover:text-gray-900 font-semibold cursor-pointer"
+        >
+          Load older
+        </button>
+      )}
+    </div>
+  );
+};
+
+// Lets owners and admins register the URLs their own systems are told about
+// events on. An endpoint's signing secret is shown once, when it's created.
+const OrganizationWebhooks = () => {
+  const [endpoints, setEndpoints] = useState(null);
+  const [url, setUrl] = useState("");
+  const [description, setDescription] = useState("");
+  const [events, setEvents] = useState([]);
+  const [newSecret, setNewSecret] = useState(null);
+  const [openLog, setOpenLog] = useState(null);
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState(null);
+
+  const loadEndpoints = async () => {
+    const result = await apiRequest(API_ENDPOINTS.ORGANIZATION_WEBHOOKS);
+    if (result.success) {
+      setEndpoints(result.data);
+    } else {
+      setError(result.error || "Failed to load webhooks");
+    }
+  };
+
+  useEffect(() => {
+    loadEndpoints();
+  }, []);
+
+  const toggleEvent = (event) =>
+    setEvents((current) =>
+      current.includes(event)
+        ? current.filter((e) => e !== event)
+        : [...current, event]
+    );
+
+  const handleCreate = async (e) => {
+    e.preventDefault();
+    setBusy(true);
+    setError(null);
+    setNewSecret(null);
+    const result = await apiRequest(API_ENDPOINTS.ORGANIZATION_WEBHOOKS, {
+      method: "POST",
+      body: JSON.stringify({
+        url: url.trim(),
+        description: description.trim(),
+        event_types: events,
+      }),
+    });
+    setBusy(false);
+    if (!result.success) {
+      setError(result.error || "Failed to add the webhook");
+      return;
+    }
+    setNewSecret(result.data.secret);
+    setUrl("");
+    setDescription("");
+    setEvents([]);
+    await loadEndpoints();
+  };
+
+  const handleToggleActive = async (endpoint) => {
+    setBusy(true);
+    setError(null);
+    const result = await apiRequest(API_ENDPOINTS.ORGANIZATION_WEBHOOK(endpoint.id), {
+      method: "PATCH",
+      body: JSON.stringify({ is_active: !endpoint.is_active }),
+    });
+    setBusy(false);
+    if (!result.success) {
+      setError(result.error || "Failed to update the webhook");
+      return;
+    }
+    await loadEndpoints();
+  };
+
+  const handleDelete = async (endpoint) => {
+    if (!window.confirm(`Delete the webhook for ${endpoint.url}? Its delivery log goes too.`)) {
+      return;
+    }
+    setBusy(true);
+    setError(null);
+    const result = await apiRequest(API_ENDPOINTS.ORGANIZATION_WEBHOOK(endpoint.id), {
+      method: "DELETE",
+    });
+    setBusy(false);
+    if (!result.success) {
+      setError(result.error || "Failed to delete the webhook");
+      return;
+    }
+    if (openLog === endpoint.id) {
+      setOpenLog(null);
+    }
+    await loadEndpoints();
+  };
+
+  return (
+    <div className="mt-6 rounded-xl border border-gray-200 bg-white p-5 shadow-sm">
+      <h2 className="text-lg font-semibold text-[#3a0b2e] mb-1">Webhooks</h2>
+      <p className="text-sm text-gray-600 mb-4">
+        Have events POSTed to your own systems. Each request is signed in the{" "}
+        <code className="bg-gray-100 px-1 rounded">X-Webhook-Signature</code> header
+        with the endpoint's secret; failed deliveries are retried for about two days.
+      </p>
+
+      {error && (
+        <div className="mb-4 text-sm bg-red-50 border border-red-200 text-red-700 rounded-lg px-3 py-2">
+          {error}
+        </div>
+      )}
+      {newSecret && (
+        <div className="mb-4 text-sm bg-green-50 border border-green-200 text-green-800 rounded-lg px-3 py-2">
+          <p className="font-semibold mb-1">
+            Copy this signing secret now. It won't be shown again.
+          </p>
+          <code className="block break-all bg-white border border-green-200 rounded px-2 py-1">
+            {newSecret}
+          </code>
+        </div>
+      )}
+
+      <form onSubmit={handleCreate} className="mb-5 space-y-3">
+        <div className="flex flex-wrap gap-3">
+          <input
+            type="url"
+            value={url}
+            onChange={(e) => setUrl(e.target.value)}
+            placeholder="https://example.org/webhooks"
+            className="flex-1 min-w-[240px] border border-gray-300 rounded-lg py-2 px-3 focus:outline-none focus:border-[#ff6200]"
+          />
+          <input
+            type="text"
+            value={description}
+            onChange={(e) => setDescription(e.target.value)}
+            placeholder="Description (optional)"
+            maxLength={255}
+            className="flex-1 min-w-[200px] border border-gray-300 rounded-lg py-2 px-3 focus:outline-none focus:border-[#ff6200]"
+          />
+        </div>
+        <div className="flex flex-wrap gap-4">
+          {EVENTS.map((event) => (
+            <label key={event.value} className="text-sm text-gray-700 flex items-center gap-2">
+              <input
+                type="checkbox"
+                checked={events.includes(event.value)}
+                onChange={() => toggleEvent(event.value)}
+              />
+              {event.label}
+            </label>
+          ))}
+        </div>
+        <button
+          type="submit"
+          disabled={busy || !url.trim() || events.length === 0}
+          className="bg-[#ff6200] hover:bg-[#e45a00] text-white font-semibold px-4 py-2 rounded-lg transition cursor-pointer disabled:opacity-60"
+        >
+          Add webhook
+        </button>
+      </form>
+
+      {!endpoints ? (
+        <p className="text-gray-600">Loading...</p>
+      ) : endpoints.length === 0 ? (
+        <p className="text-sm text-gray-500">No webhooks.</p>
+      ) : (
+        <ul className="divide-y divide-gray-100">
+          {endpoints.map((endpoint) => (
+            <li key={endpoint.id} className="py-3">
+              <div className="flex flex-wrap items-center justify-between gap-3">
+                <div>
+                  <p className="font-medium text-gray-800 break-all">
+                    {endpoint.url}
+                    {!endpoint.is_active && (
+                      <span className="ml-2 text-xs text-gray-500">(paused)</span>
+                    )}
+                  </p>
+                  <p className="text-sm text-gray-500">
+                    {endpoint.description && `${endpoint.description} · `}
+                    {endpoint.event_types.join(", ")}
+                  </p>
+                </div>
+                <div className="flex gap-4">
+                  <button
+                    onClick={() => setOpenLog(openLog === endpoint.id ? null : endpoint.id)}
+                    className="text-sm text-gray-700 hover:text-gray-900 font-semibold cursor-pointer"
+                  >
+                    {openLog === endpoint.id ? "Hide log" : "Delivery log"}
+                  </button>
+                  <button
+                    onClick={() => handleToggleActive(endpoint)}
+                    disabled={busy}
+                    className="text-sm text-gray-700 hover:text-gray-900 font-semibold cursor-pointer"
+                  >
+                    {endpoint.is_active ? "Pause" : "Resume"}
+                  </button>
+                  <button
+                    onClick={() => handleDelete(endpoint)}
+                    disabled={busy}
+                    className="text-sm text-red-600 hover:text-red-700 font-semibold cursor-pointer"
+                  >
+                    Delete
+                  </button>
+                </div>
+              </div>
+              {openLog === endpoint.id && <WebhookDeliveries endpoint={endpoint} />}
+            </li>
+          ))}
+        </ul>
+      )}
+    </div>
+  );
+};
+
+export default OrganizationWebhooks;
@@ -3,12 +3,15 @@ import { Link } from "react-router-dom";
 import { useAuth } from "../contexts/AuthContext";
 import { apiRequest, API_ENDPOINTS, } from "../config/api";
 import { getCauseImage } from "../utils/imageHelper";
+import TwoFactorSettings from "../components/TwoFactorSettings";
+import LinkedAccounts from "../components/LinkedAccounts";
+import NotificationPreferences from "../components/NotificationPreferences";
 import heroimg from "/default_user_avatar.jpg";
 import causePlaceholder from "../../public/domains/domain_example.png";
 import { LuPencil, LuHeart, LuLock, LuShieldCheck } from "react-icons/lu";
 
 const ProfilePage = () => {
-  const { user, fetchCurrentUser } = useAuth();
+  const { user, fetchCurrentUser, logout } = useAuth();
   const [donations, setDonations] = useState([]);
   const [causesMap, setCausesMap] = useState({});
   const [loadingDonations, setLoadingDonations] = useState(true);
@@ -16,13 +19,17 @@ const ProfilePage = () => {
   const [editForm, setEditForm] = useState({ name: "" });
   const [saving, setSaving] = useState(false);
   const [saveError, setSaveError] = useState(null);
+  const [privacyBusy, setPrivacyBusy] = useState(false);
+  const [privacyError, setPrivacyError] = useState(null);
+  const [verificationNotice, setVerificationNotice] = useState(null);
 
   useEffect(() => {
     const fetchDonations = async () => {
       const result = await apiRequest(API_ENDPOINTS.GET_MY_DONATIONS);
       if (result.success && result.data) {
-        setDonations(result.data);
-        const uniqueCauseIds = [...new Set(result.data.map((d) => d.cause_id).filter(Boolean))];
+        const myDonations = result.data.data || [];
+        setDonations(myDonations);
+        const uniqueCauseIds = [...new Set(myDonations.map((d) => d.cause_id).filter(Boolean))];
         const causes = {};
         await Promise.all(
           uniqueCauseIds.map(async (id) => {
@@ -70,6 +77,58 @@ const ProfilePage = () => {
     setSaving(false);
   };
 
+  const handleResendVerification = async () => {
+    setVerificationNotice(null);
+    const result = await apiRequest(API_ENDPOINTS.RESEND_VERIFICATION_EMAIL, {
+      method: "POST",
+    });
+    setVerificationNotice(
+      result.success
+        ? `We've sent a new link to ${user.email}.`
+        : result.error || "Failed to send the verification email"
+    );
+  };
+
+  const handleExportData = async () => {
+    setPrivacyBusy(true);
+    setPrivacyError(null);
+    try {
+      const response = await fetch(API_ENDPOINTS.EXPORT_MY_DATA, {
+        headers: { Authorization: `Bearer ${localStorage.getItem("authToken")}` },
+      });
+      if (!response.ok) throw new Error("Failed to export your data");
+      const url = URL.createObjectURL(await response.blob());
+      const link = document.createElement("a");
+      link.href = url;
+      link.download = "charitylight-data.zip";
+      link.click();
+      URL.revokeObjectURL(url);
+    } catch (err) {
+      setPrivacyError(err.message);
+    }
+    setPrivacyBusy(false);
+  };
+
+  const handleEraseAccount = async () => {
+    const confirmed = window.confirm(
+      "This permanently deletes your account and personal data. Donation records are kept without your details, as required for the public ledger and tax law. Continue?"
+    );
+    if (!confirmed) return;
+
+    setPrivacyBusy(true);
+    setPrivacyError(null);
+    const result = await apiRequest(API_ENDPOINTS.ERASE_MY_ACCOUNT, {
+      method: "POST",
+      body: JSON.stringify({ confirm: true }),
+    });
+    if (result.success) {
+      await logout();
+      return;
+    }
+    setPrivacyError(result.error || "Failed to delete your account");
+    setPrivacyBusy(false);
+  };
+
   const toTitleCase = (s = "") =>
     s ? s.charAt(0).toUpperCase() + s.slice(1).toLowerCase() : "";
 
@@ -138,6 +197,22 @@ const ProfilePage = () => {
                   <p className="text-sm text-gray-500 mt-1">
                     Role: {toTitleCase(user.role)}
                   </p>
+                  {!user.is_verified && (
+                    <div className="mt-3 text-sm bg-amber-50 border border-amber-200 text-amber-800 rounded-lg px-3 py-2">
+                      Your email address isn't verified yet. Large donations
+                      {user.role === "organization" && " and new causes"} need a
+                      verified email.{" "}
+                      <button
+                        onClick={handleResendVerification}
+                        className="font-semibold underline cursor-pointer"
+                      >
+                        Resend verification email
+                      </button>
+                      {verificationNotice && (
+                        <p className="mt-1">{verificationNotice}</p>
+                      )}
+                    </div>
+                  )}
                   {/*<button
                     onClick={() => setIsEditing(true)}
                     className="mt-3 inline-flex items-center gap-2 text-[#ff6200] hover:text-[#e45a00] font-medium text-sm transition cursor-pointer"
@@ -270,6 +345,44 @@ const ProfilePage = () => {
           </>
         )}
       </div>
+
+      <TwoFactorSettings />
+
+      <LinkedAccounts />
+
+      <NotificationPreferences />
+
+      {/* Personal data section */}
+      <div className="mt-8 bg-white rounded-xl border border-gray-200 p-6">
+        <h2 className="text-xl font-bold text-[#3a0b2e] mb-2 flex items-center gap-2">
+          <LuShieldCheck className="text-[#ff6200]" />
+          Your Data
+        </h2>
+        <p className="text-sm text-gray-600 mb-4">
+          Download everything we hold about you, or delete your account.
+        </p>
+        <div className="flex flex-wrap gap-3">
+          <button
+            onClick={handleExportData}
+            disabled={privacyBusy}
+            className="bg-gray-200 hover:bg-gray-300 text-gray-800 font-semibold px-4 py-2 rounded-lg transition cursor-pointer disabled:opacity-60"
+          >
+            Download my data
+          </button>
+          {user.role === "user" && (
+            <button
+              onClick={handleEraseAccount}
+              disabled={privacyBusy}
+              className="bg-red-600 hover:bg-red-700 text-white font-semibold px-4 py-2 rounded-lg transition cursor-pointer disabled:opacity-60"
+            >
+              Delete my account
+            </button>
+          )}
+        </div>
+        {privacyError && (
+          <p className="text-red-500 text-sm mt-2">{privacyError}</p>
+        )}
+      </div>
     </div>
   );
 };
@@ -45,9 +45,12 @@ const DonateButton = ({ amount, donorInfo = {}, causeId }) => {
                   phone: donorInfo.mobile,
                   billing_address: donorInfo.address || undefined,
                   pincode: donorInfo.pincode || undefined,
-                  amount: Number(amount),
+                  // The server takes at most two decimal places
+                  amount: Math.round(Number(amount) * 100) / 100,
                   pan_number: donorInfo.pan || undefined,
                   payment_id: response.razorpay_payment_id,
+                  is_anonymous: Boolean(donorInfo.isAnonymous),
+                  tribute: donorInfo.tribute || undefined,
                 };
 
                 const donationResult = await apiRequest(
@@ -95,7 +98,7 @@ const DonateButton = ({ amount, donorInfo = {}, causeId }) => {
       rzp.open();
     } catch (error) {
       console.error("Payment error:", error);
-      alert("Something went wrong during payment.");
+      alert(error.message || "Something went wrong during payment.");
     }
   };
 
@@ -0,0 +1,159 @@
+import { useEffect, useRef, useState } from "react";
+import { useSearchParams } from "react-router-dom";
+import { LuLink } from "react-icons/lu";
+import { apiRequest, API_ENDPOINTS } from "../config/api";
+
+// Profile section listing the providers the user can sign in with. Linking
+// goes through the provider and comes back here with a link_token, which is
+// only linked once the signed-in user confirms it.
+const LinkedAccounts = () => {
+  const [searchParams, setSearchParams] = useSearchParams();
+  const [accounts, setAccounts] = useState(null);
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState(null);
+  const [notice, setNotice] = useState(null);
+  const linkToken = searchParams.get("link_token");
+  const linkProvider = searchParams.get("provider");
+  const linkHandled = useRef(false);
+
+  const loadAccounts = async () => {
+    const result = await apiRequest(API_ENDPOINTS.IDENTITIES);
+    if (result.success) setAccounts(result.data);
+  };
+
+  useEffect(() => {
+    loadAccounts();
+  }, []);
+
+  const labelFor = (name) =>
+    accounts?.providers.find((p) => p.name === name)?.label || name;
+
+  const clearLinkParams = () => {
+    const next = new URLSearchParams(searchParams);
+    next.delete("link_token");
+    next.delete("provider");
+    setSearchParams(next, { replace: true });
+  };
+
+  const handleConfirmLink = async () => {
+    if (linkHandled.current) return;
+    linkHandled.current = true;
+    setBusy(true);
+    setError(null);
+    // The token only links with the cookie the provider's callback set.
+    const result = await apiRequest(API_ENDPOINTS.IDENTITIES, {
+      method: "POST",
+      credentials: "include",
+      body: JSON.stringify({ token: linkToken }),
+    });
+    setBusy(false);
+    clearLinkParams();
+    if (!result.success) {
+      setError(result.error || "Failed to link the account");
+      return;
+    }
+    setNotice(`${labelFor(result.data.provider)} is now linked.`);
+    await loadAccounts();
+  };
+
+  const handleLink = (provider) => {
+    window.location.href = `${API_ENDPOINTS.OAUTH_BEGIN(provider)}?intent=link`;
+  };
+
+  const handleUnlink = async (provider) => {
+    if (!window.confirm(`Unlink ${labelFor(provider)}? You won't be able to sign in with it.`)) {
+      return;
+    }
+    setBusy(true);
+    setError(null);
+    setNotice(null);
+    const result = await apiRequest(API_ENDPOINTS.IDENTITY(provider), { method: "DELETE" });
+    setBusy(false);
+    if (!result.success) {
+      setError(result.error || "Failed to unlink the account");
+      return;
+    }
+    await loadAccounts();
+  };
+
+  if (!accounts) return null;
+
+  const linked = new Set(accounts.identities.map((i) => i.provider));
+  const unlinked = accounts.providers.filter((p) => !linked.has(p.name));
+
+  return (
+    <div className="mt-8 bg-white rounded-xl border border-gray-200 p-6">
+      <h2 className="text-xl font-bold text-[#3a0b2e] mb-2 flex items-center gap-2">
+        <LuLink className="text-[#ff6200]" />
+        Sign-in Methods
+      </h2>
+      <p className="text-sm text-gray-600 mb-4">
+        {accounts.has_password
+          ? "You can sign in with your email and password"
+          : "You don't have a password"}
+        {accounts.identities.length > 0 && ", or with the accounts below"}.
+      </p>
+
+      {linkToken && !linkHandled.current && (
+        <div className="mb-4 text-sm bg-amber-50 border border-amber-200 text-amber-800 rounded-lg px-3 py-2 flex flex-wrap items-center gap-3">
+          <span>Link the {labelFor(linkProvider)} account you just signed in with to this profile?</span>
+          <button
+            onClick={handleConfirmLink}
+            disabled={busy}
+            className="bg-[#ff6200] hover:bg-[#e45a00] text-white font-semibold px-3 py-1 rounded-lg transition cursor-pointer disabled:opacity-60"
+          >
+            Link
+          </button>
+          <button
+            onClick={clearLinkParams}
+            className="bg-gray-200 hover:bg-gray-300 text-gray-800 font-semibold px-3 py-1 rounded-lg transition cursor-pointer"
+          >
+            Cancel
+          </button>
+        </div>
+      )}
+      {notice && <p className="text-green-700 text-sm mb-3">{notice}</p>}
+
+      <ul className="divide-y divide-gray-100 mb-4">
+        {accounts.identities.map((identity) => (
+          <li key={identity.id} className="py-3 flex flex-wrap items-center justify-between gap-3">
+            <div>
+              <p className="font-medium text-gray-800">{labelFor(identity.provider)}</p>
+              <p className="text-sm text-gray-500">
+                {identity.email || "No email shared"}
+                {identity.last_used_at &&
+                  ` · last used ${new Date(identity.last_used_at).toLocaleDateString()}`}
+              </p>
+            </div>
+            <button
+              onClick={() => handleUnlink(identity.provider)}
+              disabled={busy}
+              className="text-sm text-red-600 hover:text-red-700 font-semibold cursor-pointer"
+            >
+              Unlink
+            </button>
+          </li>
+        ))}
+      </ul>
+
+      {unlinked.length > 0 && (
+        <div className="flex flex-wrap gap-3">
+          {unlinked.map((provider) => (
+            <button
+              key={provider.name}
+              onClick={() => handleLink(provider.name)}
+              disabled={busy}
+              className="bg-gray-200 hover:bg-gray-300 text-gray-800 font-semibold px-4 py-2 rounded-lg transition cursor-pointer"
+            >
+              Link {provider.label}
+            </button>
+          ))}
+        </div>
+      )}
+
+      {error && <p className="text-red-500 text-sm mt-2">{error}</p>}
+    </div>
+  );
+};
+
+export default LinkedAccounts;
@@ -2,6 +2,7 @@ import React from "react";
 import { useAuth } from "../contexts/AuthContext";
 import { NavLink, useNavigate } from "react-router-dom";
 import default_avatar from "/default_user_avatar.jpg";
+import NotificationBell from "./NotificationBell";
 
 const Navbar = () => {
     const { user, organization, logout } = useAuth();
@@ -16,7 +17,7 @@ const Navbar = () => {
     const userLandingPath =
         user?.role === "admin"
             ? "/admin"
-            : user?.role === "organization" && organization?.id
+            : organization?.id
                 ? `/organization/${organization.id}/accounts`
                 : user?.role === "organization"
                     ? "/organization/accounts"
@@ -65,6 +66,7 @@ const Navbar = () => {
                 <div className="flex items-center gap-5">
                     {user ? (
                         <div className="flex items-center gap-5">
+                            <NotificationBell />
                             <NavLink
                                 to={userLandingPath}
                                 className="flex items-center gap-3 text-right leading-tight hidden sm:block hover:opacity-80 transition"
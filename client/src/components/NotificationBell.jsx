@@ -0,0 +1,210 @@
+import { useEffect, useRef, useState } from "react";
+import { useNavigate } from "react-router-dom";
+import { LuBell } from "react-icons/lu";
+import { apiRequest, API_ENDPOINTS, refreshSession } from "../config/api";
+
+// The server closes the stream with this code when the access token is
+// invalid or has expired.
+const CLOSE_UNAUTHORIZED = 4401;
+const MAX_RETRY_DELAY = 60000;
+
+const timeAgo = (iso) => {
+  const seconds = Math.floor((Date.now() - new Date(iso).getTime()) / 1000);
+  if (seconds < 60) return "just now";
+  if (seconds < 3600) return `${Math.floor(seconds / 60)}m ago`;
+  if (seconds < 86400) return `${Math.floor(seconds / 3600)}h ago`;
+  return new Date(iso).toLocaleDateString();
+};
+
+// Navbar bell with the unread count and an inbox dropdown, kept live over
+// the notification websocket.
+const NotificationBell = () => {
+  const navigate = useNavigate();
+  const [unread, setUnread] = useState(0);
+  const [open, setOpen] = useState(false);
+  const [notifications, setNotifications] = useState(null);
+  const [nextCursor, setNextCursor] = useState(null);
+  const [error, setError] = useState(null);
+  const containerRef = useRef(null);
+
+  useEffect(() => {
+    let socket = null;
+    let retryTimer = null;
+    let retryDelay = 1000;
+    let stopped = false;
+
+    const reconnect = () => {
+      if (stopped) return;
+      retryTimer = setTimeout(connect, retryDelay);
+      retryDelay = Math.min(retryDelay * 2, MAX_RETRY_DELAY);
+    };
+
+    const connect = () => {
+      socket = new WebSocket(API_ENDPOINTS.NOTIFICATION_STREAM);
+      socket.onopen = () => {
+        // Sent as a message so the token stays out of server logs.
+        socket.send(JSON.stringify({ token: localStorage.getItem("authToken") }));
+      };
+      socket.onmessage = (message) => {
+        retryDelay = 1000;
+        const event = JSON.parse(message.data);
+        setUnread(event.unread);
+        if (event.type === "notification") {
+          setNotifications((current) => current && [event.notification, ...current]);
+        }
+      };
+      socket.onclose = async (event) => {
+        if (stopped) return;
+        if (event.code === CLOSE_UNAUTHORIZED) {
+          if ((await refreshSession()) && !stopped) {
+            connect();
+          }
+          return;
+        }
+        reconnect();
+      };
+    };
+
+    connect();
+    return () => {
+      stopped = true;
+      clearTimeout(retryTimer);
+      socket?.close();
+    };
+  }, []);
+
+  useEffect(() => {
+    if (!open) return;
+    const handleClick = (e) => {
+      if (containerRef.current && !containerRef.current.contains(e.target)) {
+        setOpen(false);
+      }
+    };
+    document.addEventListener("mousedown", handleClick);
+    return () => document.removeEventListener("mousedown", handleClick);
+  }, [open]);
+
+  const load = async (cursor) => {
+    const result = await apiRequest(API_ENDPOINTS.NOTIFICATIONS(cursor));
+    if (!result.success) {
+      setError(result.error || "Failed to load notifications");
+      return;
+    }
+    setError(null);
+    const page = result.data.data || [];
+    setNotifications((current) => (cursor ? [...(current || []), ...page] : page));
+    setNextCursor(result.data.next_cursor);
+  };
+
+  const toggle = () => {
+    if (!open) {
+      load(null);
+    }
+    setOpen(!open);
+  };
+
+  const markRead = (notification) => {
+    if (notification.read_at) return;
+    setNotifications((current) =>
+      current.map((n) =>
+        n.id === notification.id ? { ...n, read_at: new Date().toISOString() } : n
+      )
+    );
+    setUnread((count) => Math.max(count - 1, 0));
+    apiRequest(API_ENDPOINTS.MARK_NOTIFICATION_READ(notification.id), { method: "POST" });
+  };
+
+  const handleSelect = (notification) => {
+    markRead(notification);
+    if (notification.link) {
+      setOpen(false);
+      navigate(notification.link);
+    }
+  };
+
+  const markAllRead = async () => {
+    const result = await apiRequest(API_ENDPOINTS.MARK_ALL_NOTIFICATIONS_READ, { method: "POST" });
+    if (!result.success) {
+      setError(result.error || "Failed to mark notifications read");
+      return;
+    }
+    const now = new Date().toISOString();
+    setNotifications((current) => current?.map((n) => ({ ...n, read_at: n.read_at || now })));
+    setUnread(0);
+  };
+
+  return (
+    <div ref={containerRef} className="relative">
+      <button
+        onClick={toggle}
+        aria-label={unread ? `Notifications, ${unread} unread` : "Notifications"}
+        className="relative p-2 rounded-full text-gray-700 hover:text-[#f75c03] hover:bg-gray-100 transition cursor-pointer"
+      >
+        <LuBell className="w-6 h-6" />
+        {unread > 0 && (
+          <span className="absolute -top-0.5 -right-0.5 min-w-5 h-5 px-1 rounded-full bg-[#ff6200] text-white text-xs font-semibold flex items-center justify-center">
+            {unread > 99 ? "99+" : unread}
+          </span>
+        )}
+      </button>
+
+      {open && (
+        <div className="absolute right-0 mt-2 w-96 max-w-[90vw] bg-white border border-gray-200 rounded-xl shadow-lg z-50">
+          <div className="flex items-center justify-between px-4 py-3 border-b border-gray-100">
+            <h3 className="font-semibold text-[#3a0b2e]">Notifications</h3>
+            {unread > 0 && (
+              <button
+                onClick={markAllRead}
+                className="text-xs text-[#ff6200] hover:underline cursor-pointer"
+              >
+                Mark all read
+              </button>
+            )}
+          </div>
+
+          <div className="max-h-96 overflow-y-auto">
+            {error && <p className="text-sm text-red-700 px-4 py-3">{error}</p>}
+            {!notifications && !error && (
+              <p className="text-sm text-gray-600 px-4 py-3">Loading...</p>
+            )}
+            {notifications?.length === 0 && (
+              <p className="text-sm text-gray-600 px-4 py-6 text-center">
+                You have no notifications yet.
+              </p>
+            )}
+            {notifications?.map((n) => (
+              <button
+                key={n.id}
+                onClick={() => handleSelect(n)}
+                className={`w-full text-left px-4 py-3 border-b border-gray-50 hover:bg-gray-50 transition cursor-pointer flex gap-3 ${
+                  n.read_at ? "" : "bg-orange-50/50"
+                }`}
+              >
+                <span
+                  className={`mt-1.5 w-2 h-2 rounded-full shrink-0 ${
+                    n.read_at ? "bg-transparent" : "bg-[#ff6200]"
+                  }`}
+                />
+                <span className="min-w-0">
+                  <span className="block text-sm text-gray-800 font-medium">{n.title}</span>
+                  {n.body && <span className="block text-sm text-gray-600 truncate">{n.body}</span>}
+                  <span className="block text-xs text-gray-400 mt-0.5">{timeAgo(n.created_at)}</span>
+                </span>
+              </button>
+            ))}
+            {nextCursor && (
+              <button
+                onClick={() => load(nextCursor)}
+                className="w-full text-sm text-[#ff6200] py-2 hover:bg-gray-50 cursor-pointer"
+              >
+                Load more
+              </button>
+            )}
+          </div>
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default NotificationBell;
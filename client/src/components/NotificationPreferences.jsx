@@ -0,0 +1,99 @@
+import { useEffect, useState } from "react";
+import { LuMail } from "react-icons/lu";
+import { apiRequest, API_ENDPOINTS } from "../config/api";
+
+// Profile section with a switch per kind of notification email, and one to
+// stop them all. Account emails like password resets are always sent.
+const NotificationPreferences = () => {
+  const [prefs, setPrefs] = useState(null);
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState(null);
+
+  useEffect(() => {
+    (async () => {
+      const result = await apiRequest(API_ENDPOINTS.NOTIFICATION_PREFERENCES);
+      if (result.success) {
+        setPrefs(result.data);
+      } else {
+        setError(result.error || "Failed to load notification settings");
+      }
+    })();
+  }, []);
+
+  const save = async (change) => {
+    setBusy(true);
+    setError(null);
+    const result = await apiRequest(API_ENDPOINTS.NOTIFICATION_PREFERENCES, {
+      method: "PUT",
+      body: JSON.stringify(change),
+    });
+    setBusy(false);
+    if (!result.success) {
+      setError(result.error || "Failed to save notification settings");
+      return;
+    }
+    setPrefs(result.data);
+  };
+
+  if (!prefs) {
+    return error ? <p className="mt-8 text-sm text-red-700">{error}</p> : null;
+  }
+
+  return (
+    <div id="notifications" className="mt-8 bg-white rounded-xl border border-gray-200 p-6">
+      <h2 className="text-xl font-bold text-[#3a0b2e] mb-2 flex items-center gap-2">
+        <LuMail className="text-[#ff6200]" />
+        Email Notifications
+      </h2>
+      <p className="text-sm text-gray-600 mb-4">
+        Choose what we email you about. Security emails, like password resets, are
+        always sent.
+      </p>
+
+      {error && <p className="text-sm text-red-700 mb-3">{error}</p>}
+
+      {prefs.unsubscribed && (
+        <div className="mb-4 text-sm bg-amber-50 border border-amber-200 text-amber-800 rounded-lg px-3 py-2 flex flex-wrap items-center gap-3">
+          <span>You've unsubscribed from all notification emails.</span>
+          <button
+            onClick={() => save({ unsubscribed: false })}
+            disabled={busy}
+            className="bg-[#ff6200] hover:bg-[#e45a00] text-white font-semibold px-3 py-1 rounded-lg transition cursor-pointer disabled:opacity-60"
+          >
+            Resubscribe
+          </button>
+        </div>
+      )}
+
+      <ul className="divide-y divide-gray-100">
+        {prefs.kinds.map((kind) => (
+          <li key={kind.kind} className="py-3 flex items-start justify-between gap-4">
+            <div>
+              <p className="font-medium text-gray-800">{kind.label}</p>
+              <p className="text-sm text-gray-500">{kind.description}</p>
+            </div>
+            <input
+              type="checkbox"
+              className="mt-1 h-4 w-4 cursor-pointer"
+              checked={!prefs.unsubscribed && prefs.email[kind.kind]}
+              disabled={busy || prefs.unsubscribed}
+              onChange={(e) => save({ email: { [kind.kind]: e.target.checked } })}
+            />
+          </li>
+        ))}
+      </ul>
+
+      {!prefs.unsubscribed && (
+        <button
+          onClick={() => save({ unsubscribed: true })}
+          disabled={busy}
+          className="mt-3 text-sm text-gray-700 hover:text-gray-900 font-semibold cursor-pointer"
+        >
+          Unsubscribe from all
+        </button>
+      )}
+    </div>
+  );
+};
+
+export default NotificationPreferences;
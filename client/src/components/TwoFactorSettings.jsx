@@ -0,0 +1,172 @@
+import { useEffect, useState } from "react";
+import QRCode from "react-qr-code";
+import { LuLock } from "react-icons/lu";
+import { apiRequest, API_ENDPOINTS } from "../config/api";
+
+// Profile section for turning two-factor authentication on and off and for
+// replacing recovery codes.
+const TwoFactorSettings = () => {
+  const [status, setStatus] = useState(null);
+  const [enrollment, setEnrollment] = useState(null);
+  // "enroll", "regenerate" or "disable" while waiting for a code
+  const [action, setAction] = useState(null);
+  const [code, setCode] = useState("");
+  const [recoveryCodes, setRecoveryCodes] = useState([]);
+  const [busy, setBusy] = useState(false);
+  const [error, setError] = useState(null);
+
+  const loadStatus = async () => {
+    const result = await apiRequest(API_ENDPOINTS.TWO_FACTOR_STATUS);
+    if (result.success) setStatus(result.data);
+  };
+
+  useEffect(() => {
+    loadStatus();
+  }, []);
+
+  const reset = () => {
+    setAction(null);
+    setEnrollment(null);
+    setCode("");
+    setError(null);
+  };
+
+  const handleStartEnrollment = async () => {
+    setBusy(true);
+    setError(null);
+    setRecoveryCodes([]);
+    const result = await apiRequest(API_ENDPOINTS.TWO_FACTOR_ENROLL, { method: "POST" });
+    if (result.success) {
+      setEnrollment(result.data);
+      setAction("enroll");
+    } else {
+      setError(result.error || "Failed to start two-factor setup");
+    }
+    setBusy(false);
+  };
+
+  const handleSubmitCode = async (e) => {
+    e.preventDefault();
+    const endpoints = {
+      enroll: API_ENDPOINTS.TWO_FACTOR_ENROLL_CONFIRM,
+      regenerate: API_ENDPOINTS.TWO_FACTOR_RECOVERY_CODES,
+      disable: API_ENDPOINTS.TWO_FACTOR_DISABLE,
+    };
+
+    setBusy(true);
+    setError(null);
+    const result = await apiRequest(endpoints[action], {
+      method: "POST",
+      body: JSON.stringify({ code: code.trim() }),
+    });
+    setBusy(false);
+
+    if (!result.success) {
+      setError(result.error || "That code didn't work");
+      return;
+    }
+    reset();
+    setRecoveryCodes(result.data?.recovery_codes || []);
+    await loadStatus();
+  };
+
+  if (!status) return null;
+
+  return (
+    <div className="mt-8 bg-white rounded-xl border border-gray-200 p-6">
+      <h2 className="text-xl font-bold text-[#3a0b2e] mb-2 flex items-center gap-2">
+        <LuLock className="text-[#ff6200]" />
+        Two-Factor Authentication
+      </h2>
+      <p className="text-sm text-gray-600 mb-4">
+        {status.enabled
+          ? `On. You have ${status.recovery_codes_remaining} unused recovery codes.`
+          : "Ask for a code from an authenticator app each time you sign in."}
+        {status.required && " Required for your account."}
+      </p>
+
+      {recoveryCodes.length > 0 && (
+        <div className="mb-4 text-sm bg-amber-50 border border-amber-200 text-amber-800 rounded-lg px-3 py-2">
+          <p>
+            Save these recovery codes somewhere safe. Each one signs you in
+            once if you lose your phone. They won't be shown again.
+          </p>
+          <ul className="grid grid-cols-2 gap-1 mt-2 font-mono">
+            {recoveryCodes.map((c) => (
+              <li key={c}>{c}</li>
+            ))}
+          </ul>
+        </div>
+      )}
+
+      {enrollment && (
+        <div className="mb-4 text-sm text-gray-600">
+          <p className="mb-2">Scan this with your authenticator app:</p>
+          <div className="inline-block bg-white p-3 border border-gray-200 rounded-lg">
+            <QRCode value={enrollment.otpauth_uri} size={160} />
+          </div>
+          <p className="mt-2">
+            Or enter this key: <span className="font-mono">{enrollment.secret}</span>
+          </p>
+        </div>
+      )}
+
+      {action ? (
+        <form onSubmit={handleSubmitCode} className="flex flex-wrap gap-3 items-center">
+          <input
+            type="text"
+            autoComplete="one-time-code"
+            value={code}
+            onChange={(e) => setCode(e.target.value)}
+            placeholder={action === "enroll" ? "6-digit code" : "Code or recovery code"}
+            className="border border-gray-300 rounded-lg py-2 px-3 focus:outline-none focus:border-[#ff6200]"
+          />
+          <button
+            type="submit"
+            disabled={busy || !code.trim()}
+            className="bg-[#ff6200] hover:bg-[#e45a00] text-white font-semibold px-4 py-2 rounded-lg transition cursor-pointer disabled:opacity-60"
+          >
+            {action === "disable" ? "Turn off" : "Confirm"}
+          </button>
+          <button
+            type="button"
+            onClick={reset}
+            disabled={busy}
+            className="bg-gray-200 hover:bg-gray-300 text-gray-800 font-semibold px-4 py-2 rounded-lg transition cursor-pointer"
+          >
+            Cancel
+          </button>
+        </form>
+      ) : status.enabled ? (
+        <div className="flex flex-wrap gap-3">
+          <button
+            onClick={() => setAction("regenerate")}
+            className="bg-gray-200 hover:bg-gray-300 text-gray-800 font-semibold px-4 py-2 rounded-lg transition cursor-pointer"
+          >
+            New recovery codes
+          </button>
+          {!status.required && (
+            <button
+              onClick={() => setAction("disable")}
+              className="bg-red-600 hover:bg-red-700 text-white font-semibold px-4 py-2 rounded-lg transition cursor-pointer"
+            >
+              Turn off
+            </button>
+          )}
+        </div>
+      ) : (
+        <button
+          onClick={handleStartEnrollment}
+          disabled={busy}
+          className="bg-[#ff6200] hover:bg-[#e45a00] text-white font-semibold px-4 py-2 rounded-lg transition cursor-pointer disabled:opacity-60"
+        >
+          Turn on
+        </button>
+      )}
+
+      {error && <p className="text-red-500 text-sm mt-2">{error}</p>}
+    </div>
+  );
+};
+
+export default TwoFactorSettings;
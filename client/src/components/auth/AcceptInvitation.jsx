@@ -0,0 +1,84 @@
+import { useState } from 'react';
+import { Link, useNavigate, useSearchParams } from 'react-router-dom';
+import { apiRequest, API_ENDPOINTS } from '../../config/api';
+import { useAuth } from '../../contexts/AuthContext';
+import './Auth.css';
+
+const AcceptInvitation = () => {
+  const [searchParams] = useSearchParams();
+  const { user, isAuthenticated, logout } = useAuth();
+  const navigate = useNavigate();
+  const token = searchParams.get('token');
+  const [isLoading, setIsLoading] = useState(false);
+  const [error, setError] = useState('');
+
+  const handleAccept = async () => {
+    setIsLoading(true);
+    setError('');
+    const result = await apiRequest(API_ENDPOINTS.ACCEPT_ORGANIZATION_INVITATION, {
+      method: 'POST',
+      body: JSON.stringify({ token }),
+    });
+    setIsLoading(false);
+
+    if (!result.success) {
+      setError(result.error || 'This invitation is invalid or has expired.');
+      return;
+    }
+    // Joining signs the account out everywhere, so the next sign-in applies
+    // the member's role, including any 2FA it requires.
+    await logout();
+    navigate('/login?joined=1', { replace: true });
+  };
+
+  return (
+    <div className="auth-container">
+      <div className="auth-card">
+        <div className="auth-header">
+          <h1>Team Invitation</h1>
+          {!token ? (
+            <p>This invitation link is incomplete.</p>
+          ) : isAuthenticated ? (
+            <p>
+              Join the organization that invited you as <strong>{user.email}</strong>?
+              The invitation only works for the address it was sent to.
+            </p>
+          ) : (
+            <p>
+              Sign in or create an account with the email address the invitation
+              was sent to, then open the link from the email again.
+            </p>
+          )}
+        </div>
+
+        {error && <div className="error-message">{error}</div>}
+
+        {token && isAuthenticated && (
+          <button
+            className="auth-button primary"
+            onClick={handleAccept}
+            disabled={isLoading}
+          >
+            {isLoading ? 'Joining...' : 'Accept invitation'}
+          </button>
+        )}
+
+        <div className="auth-footer">
+          <p>
+            {isAuthenticated ? (
+              <Link to="/" className="auth-link">Not now</Link>
+            ) : (
+              <>
+                <Link to="/login" className="auth-link">Sign in</Link>
+                {' or '}
+                <Link to="/signup" className="auth-link">create an account</Link>
+              </>
+            )}
+          </p>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default AcceptInvitation;
@@ -0,0 +1,82 @@
+import { useState } from 'react';
+import { Link } from 'react-router-dom';
+import { apiRequest, API_ENDPOINTS } from '../../config/api';
+import { ValidationRules } from '../FormValidation';
+import './Auth.css';
+
+const ForgotPassword = () => {
+  const [email, setEmail] = useState('');
+  const [isLoading, setIsLoading] = useState(false);
+  const [error, setError] = useState('');
+  const [sent, setSent] = useState(false);
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    const fieldError = ValidationRules.required(email) || ValidationRules.email(email);
+    if (fieldError) {
+      setError(fieldError);
+      return;
+    }
+
+    setIsLoading(true);
+    setError('');
+    const result = await apiRequest(API_ENDPOINTS.FORGOT_PASSWORD, {
+      method: 'POST',
+      body: JSON.stringify({ email }),
+    });
+    setIsLoading(false);
+
+    if (result.success) {
+      setSent(true);
+    } else {
+      setError(result.error || 'Could not send the reset link. Please try again.');
+    }
+  };
+
+  return (
+    <div className="auth-container">
+      <div className="auth-card">
+        <div className="auth-header">
+          <h1>Reset Password</h1>
+          <p>We'll email you a link to choose a new password</p>
+        </div>
+
+        {sent ? (
+          <div className="success-message">
+            If an account uses {email}, a reset link is on its way. The link expires in 1 hour.
+          </div>
+        ) : (
+          <form onSubmit={handleSubmit} className="auth-form">
+            <div className="form-group">
+              <label htmlFor="email">Email</label>
+              <input
+                type="email"
+                id="email"
+                name="email"
+                value={email}
+                onChange={(e) => setEmail(e.target.value)}
+                placeholder="Enter your email"
+              />
+            </div>
+
+            {error && <div className="error-message">{error}</div>}
+
+            <button type="submit" className="auth-button primary" disabled={isLoading}>
+              {isLoading ? 'Sending...' : 'Send Reset Link'}
+            </button>
+          </form>
+        )}
+
+        <div className="auth-footer">
+          <p>
+            <Link to="/login" className="auth-link">
+              Back to sign in
+            </Link>
+          </p>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ForgotPassword;
@@ -2,6 +2,7 @@ import { useState, useEffect } from 'react';
 import { Link, useSearchParams, useNavigate } from 'react-router-dom';
 import { useAuth } from '../../contexts/AuthContext';
 import { ValidationRules } from '../FormValidation';
+import ProviderButtons from './ProviderButtons';
 import './Auth.css';
 
 const Login = () => {
@@ -14,6 +15,7 @@ const Login = () => {
   });
   const [isLoading, setIsLoading] = useState(false);
   const [error, setError] = useState('');
+  const [notice, setNotice] = useState('');
   const [fieldErrors, setFieldErrors] = useState({});
   const [touched, setTouched] = useState({});
 
@@ -23,6 +25,20 @@ const Login = () => {
     if (errorParam === 'oauth_failed') {
       setError('Google authentication failed. Please try again.');
     }
+    if (errorParam === 'account_exists') {
+      setError(
+        'An account with this email already exists. Sign in another way, then link this provider from your profile.'
+      );
+    }
+    if (errorParam === 'oauth_email_missing') {
+      setError("The provider didn't share your email address, so we couldn't create an account.");
+    }
+    if (searchParams.get('reset') === 'done') {
+      setNotice('Your password has been changed. Sign in with your new password.');
+    }
+    if (searchParams.get('joined')) {
+      setNotice("You've joined the team. Sign in again to continue.");
+    }
   }, [searchParams]);
 
   const handleChange = (e) => {
@@ -100,6 +116,8 @@ const Login = () => {
       const result = await login(formData.email, formData.password);
       if (!result.success) {
         setError(result.error || 'Login failed. Please try again.');
+      } else if (result.challenge) {
+        navigate('/login/2fa', { state: { challenge: result.challenge } });
       } else if (result.role === 'admin') {
         // Redirect admin users to the admin dashboard
         navigate('/admin', { replace: true });
@@ -167,6 +185,13 @@ const Login = () => {
             )}
           </div>
 
+          <div className="forgot-password">
+            <Link to="/forgot-password" className="auth-link">
+              Forgot password?
+            </Link>
+          </div>
+
+          {notice && <div className="success-message">{notice}</div>}
           {error && <div className="error-message">{error}</div>}
 
           <button
@@ -196,6 +221,7 @@ const Login = () => {
           </svg>
           Continue with Google
         </button>
+        <ProviderButtons disabled={isLoading} />
 
         <div className="auth-footer">
           <br />
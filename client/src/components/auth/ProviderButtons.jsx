@@ -0,0 +1,32 @@
+import { useEffect, useState } from 'react';
+import { apiRequest, API_ENDPOINTS } from '../../config/api';
+
+// Sign-in buttons for the OAuth and OpenID Connect providers the server has
+// configured, other than Google, which has its own branded button.
+const ProviderButtons = ({ disabled }) => {
+  const [providers, setProviders] = useState([]);
+
+  useEffect(() => {
+    apiRequest(API_ENDPOINTS.AUTH_PROVIDERS).then((result) => {
+      if (result.success) {
+        setProviders((result.data || []).filter((p) => p.name !== 'google'));
+      }
+    });
+  }, []);
+
+  return providers.map((provider) => (
+    <button
+      key={provider.name}
+      onClick={() => {
+        window.location.href = API_ENDPOINTS.OAUTH_BEGIN(provider.name);
+      }}
+      className="auth-button google"
+      style={{ marginTop: '0.75rem' }}
+      disabled={disabled}
+    >
+      Continue with {provider.label}
+    </button>
+  ));
+};
+
+export default ProviderButtons;
@@ -0,0 +1,93 @@
+import { useState } from 'react';
+import { Link, useNavigate, useSearchParams } from 'react-router-dom';
+import { apiRequest, API_ENDPOINTS } from '../../config/api';
+import './Auth.css';
+
+const ResetPassword = () => {
+  const [searchParams] = useSearchParams();
+  const navigate = useNavigate();
+  const token = searchParams.get('token') || '';
+  const [password, setPassword] = useState('');
+  const [confirmPassword, setConfirmPassword] = useState('');
+  const [isLoading, setIsLoading] = useState(false);
+  const [error, setError] = useState(token ? '' : 'This reset link is incomplete. Request a new one.');
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    if (password.length < 6) {
+      setError('Password must be at least 6 characters long');
+      return;
+    }
+    if (password !== confirmPassword) {
+      setError('Passwords do not match');
+      return;
+    }
+
+    setIsLoading(true);
+    setError('');
+    const result = await apiRequest(API_ENDPOINTS.RESET_PASSWORD, {
+      method: 'POST',
+      body: JSON.stringify({ token, password }),
+    });
+    setIsLoading(false);
+
+    if (result.success) {
+      navigate('/login?reset=done', { replace: true });
+    } else {
+      setError(result.error || 'Could not reset your password. Please try again.');
+    }
+  };
+
+  return (
+    <div className="auth-container">
+      <div className="auth-card">
+        <div className="auth-header">
+          <h1>Choose a New Password</h1>
+          <p>You'll be signed out of all your devices</p>
+        </div>
+
+        <form onSubmit={handleSubmit} className="auth-form">
+          <div className="form-group">
+            <label htmlFor="password">New password</label>
+            <input
+              type="password"
+              id="password"
+              name="password"
+              value={password}
+              onChange={(e) => setPassword(e.target.value)}
+              placeholder="At least 6 characters"
+            />
+          </div>
+
+          <div className="form-group">
+            <label htmlFor="confirmPassword">Confirm password</label>
+            <input
+              type="password"
+              id="confirmPassword"
+              name="confirmPassword"
+              value={confirmPassword}
+              onChange={(e) => setConfirmPassword(e.target.value)}
+              placeholder="Repeat the new password"
+            />
+          </div>
+
+          {error && <div className="error-message">{error}</div>}
+
+          <button type="submit" className="auth-button primary" disabled={isLoading || !token}>
+            {isLoading ? 'Saving...' : 'Set Password'}
+          </button>
+        </form>
+
+        <div className="auth-footer">
+          <p>
+            <Link to="/forgot-password" className="auth-link">
+              Request a new link
+            </Link>
+          </p>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default ResetPassword;
@@ -2,6 +2,7 @@ import { useState } from 'react';
 import { Link } from 'react-router-dom';
 import { useAuth } from '../../contexts/AuthContext';
 import { ValidationRules } from '../FormValidation';
+import ProviderButtons from './ProviderButtons';
 import './Auth.css';
 
 const Signup = () => {
@@ -229,6 +230,7 @@ const Signup = () => {
           </svg>
           Continue with Google
         </button>
+        <ProviderButtons disabled={isLoading} />
 
         <div className="auth-footer">
           <p>
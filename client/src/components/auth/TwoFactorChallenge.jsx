@@ -0,0 +1,173 @@
+import { useEffect, useState } from 'react';
+import { Link, useLocation, useNavigate } from 'react-router-dom';
+import QRCode from 'react-qr-code';
+import { useAuth } from '../../contexts/AuthContext';
+import { apiRequest, API_ENDPOINTS } from '../../config/api';
+import './Auth.css';
+
+const RecoveryCodeList = ({ codes }) => (
+  <div className="recovery-codes">
+    <p>
+      Save these recovery codes somewhere safe. Each one signs you in once if
+      you lose your phone. They won't be shown again.
+    </p>
+    <ul>
+      {codes.map((code) => (
+        <li key={code}>{code}</li>
+      ))}
+    </ul>
+  </div>
+);
+
+const TwoFactorChallenge = () => {
+  const { completeTwoFactor } = useAuth();
+  const navigate = useNavigate();
+  const location = useLocation();
+  const challenge = location.state?.challenge;
+  const enrollmentRequired = !!challenge?.enrollment_required;
+
+  const [enrollment, setEnrollment] = useState(null);
+  const [code, setCode] = useState('');
+  const [recoveryCodes, setRecoveryCodes] = useState([]);
+  const [destination, setDestination] = useState('/');
+  const [isLoading, setIsLoading] = useState(false);
+  const [error, setError] = useState('');
+
+  useEffect(() => {
+    if (!challenge?.token || !enrollmentRequired) return;
+
+    // Organizations and admins must set up two-factor authentication before
+    // their first sign-in completes.
+    const startEnrollment = async () => {
+      const result = await apiRequest(API_ENDPOINTS.TWO_FACTOR_CHALLENGE_ENROLL, {
+        method: 'POST',
+        body: JSON.stringify({ challenge_token: challenge.token }),
+      }, false);
+      if (result.success) {
+        setEnrollment(result.data);
+      } else {
+        setError(result.error || 'Could not start two-factor setup. Sign in again.');
+      }
+    };
+    startEnrollment();
+  }, [challenge?.token, enrollmentRequired]);
+
+  if (!challenge?.token) {
+    return (
+      <div className="auth-container">
+        <div className="auth-card">
+          <div className="auth-header">
+            <h1>Sign-in Expired</h1>
+            <p>Sign in again to continue.</p>
+          </div>
+          <div className="auth-footer">
+            <Link to="/login" className="auth-link">Back to sign in</Link>
+          </div>
+        </div>
+      </div>
+    );
+  }
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    if (!code.trim()) {
+      setError('Enter the code from your authenticator app');
+      return;
+    }
+
+    setIsLoading(true);
+    setError('');
+    const result = await completeTwoFactor(challenge.token, code.trim());
+    setIsLoading(false);
+
+    if (!result.success) {
+      setError(result.error);
+      return;
+    }
+
+    const next = result.role === 'admin' ? '/admin' : '/';
+    if (result.recoveryCodes.length > 0) {
+      setDestination(next);
+      setRecoveryCodes(result.recoveryCodes);
+    } else {
+      navigate(next, { replace: true });
+    }
+  };
+
+  if (recoveryCodes.length > 0) {
+    return (
+      <div className="auth-container">
+        <div className="auth-card">
+          <div className="auth-header">
+            <h1>Two-Factor Enabled</h1>
+          </div>
+          <RecoveryCodeList codes={recoveryCodes} />
+          <button
+            className="auth-button primary"
+            onClick={() => navigate(destination, { replace: true })}
+          >
+            I've saved my codes
+          </button>
+        </div>
+      </div>
+    );
+  }
+
+  return (
+    <div className="auth-container">
+      <div className="auth-card">
+        <div className="auth-header">
+          <h1>{enrollmentRequired ? 'Set Up Two-Factor' : 'Two-Factor Authentication'}</h1>
+          <p>
+            {enrollmentRequired
+              ? 'Your account needs two-factor authentication. Scan this code with an authenticator app, then enter the 6-digit code it shows.'
+              : 'Enter the 6-digit code from your authenticator app, or one of your recovery codes.'}
+          </p>
+        </div>
+
+        {enrollment && (
+          <div className="two-factor-qr">
+            <QRCode value={enrollment.otpauth_uri} size={180} />
+            <p>
+              Can't scan it? Enter this key: <code>{enrollment.secret}</code>
+            </p>
+          </div>
+        )}
+
+        <form onSubmit={handleSubmit} className="auth-form">
+          <div className="form-group">
+            <label htmlFor="code">Code</label>
+            <input
+              type="text"
+              id="code"
+              name="code"
+              autoComplete="one-time-code"
+              autoFocus
+              value={code}
+              onChange={(e) => setCode(e.target.value)}
+              placeholder={enrollmentRequired ? '123456' : '123456 or recovery code'}
+            />
+          </div>
+
+          {error && <div className="error-message">{error}</div>}
+
+          <button
+            type="submit"
+            className="auth-button primary"
+            disabled={isLoading || (enrollmentRequired && !enrollment)}
+          >
+            {isLoading ? 'Verifying...' : 'Verify'}
+          </button>
+        </form>
+
+        <div className="auth-footer">
+          <p>
+            <Link to="/login" className="auth-link">Back to sign in</Link>
+          </p>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default TwoFactorChallenge;
@@ -0,0 +1,73 @@
+import { useEffect, useRef, useState } from 'react';
+import { Link, useSearchParams } from 'react-router-dom';
+import { apiRequest, API_ENDPOINTS } from '../../config/api';
+import { useAuth } from '../../contexts/AuthContext';
+import './Auth.css';
+
+// Where the unsubscribe link in notification emails lands. It works
+// without signing in.
+const Unsubscribe = () => {
+  const [searchParams] = useSearchParams();
+  const { isAuthenticated } = useAuth();
+  const [status, setStatus] = useState('working');
+  const [error, setError] = useState('');
+  const submitted = useRef(false);
+
+  useEffect(() => {
+    if (submitted.current) return;
+    submitted.current = true;
+
+    const token = searchParams.get('token');
+    if (!token) {
+      setStatus('failed');
+      setError('This unsubscribe link is incomplete.');
+      return;
+    }
+
+    (async () => {
+      const result = await apiRequest(API_ENDPOINTS.UNSUBSCRIBE, {
+        method: 'POST',
+        body: JSON.stringify({ token }),
+      });
+      if (result.success) {
+        setStatus('done');
+      } else {
+        setStatus('failed');
+        setError(result.error || 'This link is invalid or has expired.');
+      }
+    })();
+  }, [searchParams]);
+
+  return (
+    <div className="auth-container">
+      <div className="auth-card">
+        <div className="auth-header">
+          <h1>Unsubscribe</h1>
+        </div>
+
+        {status === 'working' && <p>Unsubscribing...</p>}
+        {status === 'done' && (
+          <div className="success-message">
+            You won't get any more notification emails. You can turn them back on from
+            your profile.
+          </div>
+        )}
+        {status === 'failed' && (
+          <div className="error-message">
+            {error} You can change which emails you get from your profile.
+          </div>
+        )}
+
+        <div className="auth-footer">
+          <p>
+            <Link to={isAuthenticated ? '/profile#notifications' : '/login'} className="auth-link">
+              {isAuthenticated ? 'Notification settings' : 'Sign in'}
+            </Link>
+          </p>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default Unsubscribe;
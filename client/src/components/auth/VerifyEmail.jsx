@@ -0,0 +1,72 @@
+import { useEffect, useRef, useState } from 'react';
+import { Link, useSearchParams } from 'react-router-dom';
+import { apiRequest, API_ENDPOINTS } from '../../config/api';
+import { useAuth } from '../../contexts/AuthContext';
+import './Auth.css';
+
+const VerifyEmail = () => {
+  const [searchParams] = useSearchParams();
+  const { isAuthenticated, fetchCurrentUser } = useAuth();
+  const [status, setStatus] = useState('verifying');
+  const [error, setError] = useState('');
+  // Tokens are single use, so guard against the effect running twice.
+  const submitted = useRef(false);
+
+  useEffect(() => {
+    if (submitted.current) return;
+    submitted.current = true;
+
+    const token = searchParams.get('token');
+    if (!token) {
+      setStatus('failed');
+      setError('This verification link is incomplete.');
+      return;
+    }
+
+    (async () => {
+      const result = await apiRequest(API_ENDPOINTS.VERIFY_EMAIL, {
+        method: 'POST',
+        body: JSON.stringify({ token }),
+      });
+      if (result.success) {
+        setStatus('verified');
+        if (isAuthenticated) {
+          await fetchCurrentUser();
+        }
+      } else {
+        setStatus('failed');
+        setError(result.error || 'This link is invalid or has expired.');
+      }
+    })();
+  }, [searchParams, isAuthenticated, fetchCurrentUser]);
+
+  return (
+    <div className="auth-container">
+      <div className="auth-card">
+        <div className="auth-header">
+          <h1>Email Verification</h1>
+        </div>
+
+        {status === 'verifying' && <p>Verifying your email address...</p>}
+        {status === 'verified' && (
+          <div className="success-message">Your email address is verified. Thank you!</div>
+        )}
+        {status === 'failed' && (
+          <div className="error-message">
+            {error} You can request a new link from your profile page.
+          </div>
+        )}
+
+        <div className="auth-footer">
+          <p>
+            <Link to={isAuthenticated ? '/profile' : '/login'} className="auth-link">
+              {isAuthenticated ? 'Go to your profile' : 'Sign in'}
+            </Link>
+          </p>
+        </div>
+      </div>
+    </div>
+  );
+};
+
+export default VerifyEmail;
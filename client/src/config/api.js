@@ -12,10 +12,30 @@ export const API_ENDPOINTS = {
   REGISTER_ORGANIZATION: `${API_BASE_URL}/api/auth/register/organization`,
   LOGIN: `${API_BASE_URL}/api/auth/login`,
   LOGOUT: `${API_BASE_URL}/api/auth/logout`,
+  REFRESH_TOKEN: `${API_BASE_URL}/api/auth/refresh`,
+  LIST_SESSIONS: `${API_BASE_URL}/api/auth/sessions`,
+  REVOKE_SESSION: (sessionId) =>
+    `${API_BASE_URL}/api/auth/sessions/${sessionId}`,
+  REVOKE_ALL_SESSIONS: `${API_BASE_URL}/api/auth/sessions/revoke-all`,
+  VERIFY_EMAIL: `${API_BASE_URL}/api/auth/verify-email`,
+  RESEND_VERIFICATION_EMAIL: `${API_BASE_URL}/api/auth/verify-email/resend`,
+  FORGOT_PASSWORD: `${API_BASE_URL}/api/auth/password/forgot`,
+  RESET_PASSWORD: `${API_BASE_URL}/api/auth/password/reset`,
+  TWO_FACTOR_CHALLENGE_ENROLL: `${API_BASE_URL}/api/auth/2fa/challenge/enroll`,
+  TWO_FACTOR_CHALLENGE_VERIFY: `${API_BASE_URL}/api/auth/2fa/challenge/verify`,
+  TWO_FACTOR_STATUS: `${API_BASE_URL}/api/auth/2fa`,
+  TWO_FACTOR_ENROLL: `${API_BASE_URL}/api/auth/2fa/enroll`,
+  TWO_FACTOR_ENROLL_CONFIRM: `${API_BASE_URL}/api/auth/2fa/enroll/confirm`,
+  TWO_FACTOR_RECOVERY_CODES: `${API_BASE_URL}/api/auth/2fa/recovery-codes`,
+  TWO_FACTOR_DISABLE: `${API_BASE_URL}/api/auth/2fa/disable`,
   ME: `${API_BASE_URL}/api/auth/me`,
   UPDATE_PROFILE: `${API_BASE_URL}/api/auth/me`,
   GOOGLE_AUTH: `${API_BASE_URL}/api/auth/google`,
   GOOGLE_CALLBACK: `${API_BASE_URL}/api/auth/google/callback`,
+  AUTH_PROVIDERS: `${API_BASE_URL}/api/auth/providers`,
+  OAUTH_BEGIN: (provider) => `${API_BASE_URL}/api/auth/${provider}`,
+  IDENTITIES: `${API_BASE_URL}/api/auth/identities`,
+  IDENTITY: (provider) => `${API_BASE_URL}/api/auth/identities/${provider}`,
 
   // Payments
   CREATE_ORDER: `${API_BASE_URL}/api/payment/create-order`,
@@ -24,6 +44,18 @@ export const API_ENDPOINTS = {
   // Donations
   CREATE_DONATION: `${API_BASE_URL}/api/donations`,
   GET_MY_DONATIONS: `${API_BASE_URL}/api/donations/user/me`,
+  GET_DONATION: (donationId) => `${API_BASE_URL}/api/donations/${donationId}`,
+
+  // Notifications
+  NOTIFICATIONS: (cursor) =>
+    `${API_BASE_URL}/api/notifications${cursor ? `?cursor=${encodeURIComponent(cursor)}` : ""}`,
+  NOTIFICATIONS_UNREAD_COUNT: `${API_BASE_URL}/api/notifications/unread-count`,
+  MARK_NOTIFICATION_READ: (notificationId) =>
+    `${API_BASE_URL}/api/notifications/${notificationId}/read`,
+  MARK_ALL_NOTIFICATIONS_READ: `${API_BASE_URL}/api/notifications/read-all`,
+  NOTIFICATION_STREAM: `${WS_BASE_URL}/ws/notifications`,
+  NOTIFICATION_PREFERENCES: `${API_BASE_URL}/api/notifications/preferences`,
+  UNSUBSCRIBE: `${API_BASE_URL}/api/notifications/unsubscribe`,
 
   // Data
   GET_ALL_AID_TYPES: `${API_BASE_URL}/api/aids`,
@@ -45,6 +77,27 @@ export const API_ENDPOINTS = {
   GET_CAUSES_BY_ORGANIZATION: `${API_BASE_URL}/api/causes/organization`,
   ME_ORGANIZATION: `${API_BASE_URL}/api/auth/me/organization`,
 
+  // Organization team
+  GET_ORGANIZATION_TEAM: `${API_BASE_URL}/api/organization/team`,
+  INVITE_ORGANIZATION_MEMBER: `${API_BASE_URL}/api/organization/team/invitations`,
+  REVOKE_ORGANIZATION_INVITATION: (invitationId) =>
+    `${API_BASE_URL}/api/organization/team/invitations/${invitationId}`,
+  ORGANIZATION_MEMBER: (userId) =>
+    `${API_BASE_URL}/api/organization/team/members/${userId}`,
+  ACCEPT_ORGANIZATION_INVITATION: `${API_BASE_URL}/api/organization/invitations/accept`,
+  ORGANIZATION_API_KEYS: `${API_BASE_URL}/api/organization/api-keys`,
+  ORGANIZATION_API_KEY: (keyId) =>
+    `${API_BASE_URL}/api/organization/api-keys/${keyId}`,
+  ORGANIZATION_WEBHOOKS: `${API_BASE_URL}/api/organization/webhooks`,
+  ORGANIZATION_WEBHOOK: (endpointId) =>
+    `${API_BASE_URL}/api/organization/webhooks/${endpointId}`,
+  ORGANIZATION_WEBHOOK_DELIVERIES: (endpointId, cursor) =>
+    `${API_BASE_URL}/api/organization/webhooks/${endpointId}/deliveries${
+      cursor ? `?cursor=${encodeURIComponent(cursor)}` : ""
+    }`,
+  REPLAY_WEBHOOK_DELIVERY: (endpointId, deliveryId) =>
+    `${API_BASE_URL}/api/organization/webhooks/${endpointId}/deliveries/${deliveryId}/replay`,
+
   // Disbursements
   GET_MY_ORGANIZATION_DISBURSEMENTS: `${API_BASE_URL}/api/disbursements/my-organization`,
   GET_CAUSE_DISBURSEMENTS: (causeId) =>
@@ -73,6 +126,28 @@ export const API_ENDPOINTS = {
   GET_CAUSE_CHAIN_DONATIONS: (causeId) =>
     `${API_BASE_URL}/api/donations/chain/cause/${causeId}`,
 
+  // In-kind goods pledges
+  CREATE_GOODS_PLEDGE: `${API_BASE_URL}/api/pledges`,
+  GET_MY_GOODS_PLEDGES: `${API_BASE_URL}/api/pledges/me`,
+  GET_CAUSE_RECEIVED_GOODS: (causeId) =>
+    `${API_BASE_URL}/api/pledges/cause/${causeId}/received`,
+
+  // Peer-to-peer fundraisers
+  CREATE_FUNDRAISER: `${API_BASE_URL}/api/fundraisers`,
+  GET_MY_FUNDRAISERS: `${API_BASE_URL}/api/fundraisers/me`,
+  GET_FUNDRAISER: (fundraiserId) =>
+    `${API_BASE_URL}/api/fundraisers/${fundraiserId}`,
+  GET_FUNDRAISER_DONATIONS: (fundraiserId) =>
+    `${API_BASE_URL}/api/fundraisers/${fundraiserId}/donations`,
+  GET_CAUSE_FUNDRAISERS: (causeId) =>
+    `${API_BASE_URL}/api/fundraisers/cause/${causeId}`,
+  GET_CAUSE_FUNDRAISER_LEADERBOARD: (causeId) =>
+    `${API_BASE_URL}/api/fundraisers/cause/${causeId}/leaderboard`,
+
+  // Personal data export and erasure
+  EXPORT_MY_DATA: `${API_BASE_URL}/api/privacy/export`,
+  ERASE_MY_ACCOUNT: `${API_BASE_URL}/api/privacy/erase`,
+
   // PROOF OF WORK (NEW)
   CREATE_PROOF_SESSION: `${API_BASE_URL}/api/proof/session`,
   CREATE_CAUSE_PROOF_SESSION: `${API_BASE_URL}/api/proof/session/cause`,
@@ -88,7 +163,54 @@ export const API_ENDPOINTS = {
 };
 
 // API utility functions
-export const apiRequest = async (url, options = {}) => {
+export const storeSession = ({ token, refresh_token }) => {
+  localStorage.setItem('authToken', token);
+  if (refresh_token) {
+    localStorage.setItem('refreshToken', refresh_token);
+  }
+};
+
+export const clearSession = () => {
+  localStorage.removeItem('authToken');
+  localStorage.removeItem('refreshToken');
+};
+
+// Requests that fail together on an expired access token share one refresh,
+// because each refresh token can only be used once.
+let refreshPromise = null;
+
+export const refreshSession = () => {
+  if (!refreshPromise) {
+    refreshPromise = (async () => {
+      const refreshToken = localStorage.getItem('refreshToken');
+      if (!refreshToken) {
+        return false;
+      }
+
+      try {
+        const response = await fetch(API_ENDPOINTS.REFRESH_TOKEN, {
+          method: 'POST',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ refresh_token: refreshToken }),
+        });
+        if (!response.ok) {
+          clearSession();
+          return false;
+        }
+        storeSession(await response.json());
+        return true;
+      } catch (error) {
+        console.error('Session refresh failed:', error);
+        return false;
+      }
+    })().finally(() => {
+      refreshPromise = null;
+    });
+  }
+  return refreshPromise;
+};
+
+export const apiRequest = async (url, options = {}, retryOnUnauthorized = true) => {
   const defaultOptions = {
     headers: {
       'Content-Type': 'application/json',
@@ -113,6 +235,13 @@ export const apiRequest = async (url, options = {}) => {
   try {
     const response = await fetch(url, config);
 
+    // The access token expired: refresh the session once and try again.
+    if (response.status === 401 && retryOnUnauthorized && url !== API_ENDPOINTS.REFRESH_TOKEN) {
+      if (await refreshSession()) {
+        return apiRequest(url, options, false);
+      }
+    }
+
     // Handle non-JSON responses (like redirects)
     const contentType = response.headers.get('content-type');
     if (!contentType || !contentType.includes('application/json')) {
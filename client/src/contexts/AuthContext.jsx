@@ -1,5 +1,5 @@
 import { createContext, useContext, useState, useEffect } from 'react';
-import { apiRequest, API_ENDPOINTS } from '../config/api';
+import { apiRequest, API_ENDPOINTS, storeSession, clearSession } from '../config/api';
 
 const AuthContext = createContext();
 
@@ -43,16 +43,16 @@ export const AuthProvider = ({ children }) => {
           // Verify token with backend
           const me = await fetchCurrentUser();
           if (!me.success) {
-            // Token is invalid, remove it
-            localStorage.removeItem('authToken');
-          } else if (me.data.role === 'organization') {
-            // Load organization details for organization users
+            // Token is invalid and could not be refreshed, remove it
+            clearSession();
+          } else if (me.data.role !== 'admin') {
+            // Load the organization the user is a member of, if any
             await fetchCurrentOrganization();
           }
         }
       } catch (error) {
         console.error('Auth check failed:', error);
-        localStorage.removeItem('authToken');
+        clearSession();
       } finally {
         setIsLoading(false);
       }
@@ -61,6 +61,20 @@ export const AuthProvider = ({ children }) => {
     checkAuth();
   }, []);
 
+  // Stores the tokens from a completed sign-in and loads the user.
+  const startSession = async (authData) => {
+    const { user: userData } = authData;
+    storeSession(authData);
+    setUser(userData);
+
+    // Organization accounts and their team members also get the
+    // organization's details
+    if (userData.role !== 'admin') {
+      await fetchCurrentOrganization();
+    }
+    return userData;
+  };
+
   const login = async (email, password) => {
     try {
       const result = await apiRequest(API_ENDPOINTS.LOGIN, {
@@ -69,15 +83,13 @@ export const AuthProvider = ({ children }) => {
       });
 
       if (result.success && result.data) {
-        const { user: userData, token } = result.data;
-        localStorage.setItem('authToken', token);
-        setUser(userData);
-
-        // If this is an organization user, also fetch organization details
-        if (userData.role === 'organization') {
-          await fetchCurrentOrganization();
+        // Accounts with two-factor authentication get a challenge instead
+        // of tokens; the caller asks for the code.
+        if (result.data.challenge) {
+          return { success: true, challenge: result.data.challenge };
         }
 
+        const userData = await startSession(result.data);
         return { success: true, role: userData.role };
       } else {
         return { success: false, error: result.error || 'Login failed. Please try again.' };
@@ -88,6 +100,23 @@ export const AuthProvider = ({ children }) => {
     }
   };
 
+  // Finishes a challenged sign-in with a code from the authenticator app or
+  // a recovery code. For accounts that had to set up two-factor
+  // authentication, the new recovery codes are returned to show once.
+  const completeTwoFactor = async (challengeToken, code) => {
+    const result = await apiRequest(API_ENDPOINTS.TWO_FACTOR_CHALLENGE_VERIFY, {
+      method: 'POST',
+      body: JSON.stringify({ challenge_token: challengeToken, code }),
+    }, false);
+
+    if (!result.success || !result.data) {
+      return { success: false, error: result.error || 'Verification failed. Please try again.' };
+    }
+
+    const userData = await startSession(result.data);
+    return { success: true, role: userData.role, recoveryCodes: result.data.recovery_codes || [] };
+  };
+
   const signup = async (name, email, password) => {
     try {
       const result = await apiRequest(API_ENDPOINTS.REGISTER, {
@@ -96,8 +125,8 @@ export const AuthProvider = ({ children }) => {
       });
 
       if (result.success && result.data) {
-        const { user: userData, token } = result.data;
-        localStorage.setItem('authToken', token);
+        const { user: userData } = result.data;
+        storeSession(result.data);
         setUser(userData);
 
         // If this is an organization signup, also fetch organization details
@@ -122,15 +151,17 @@ export const AuthProvider = ({ children }) => {
 
   const logout = async () => {
     try {
-      // Call logout endpoint to invalidate token on server
+      // Revoke the session on the server. The refresh token is sent too in
+      // case the access token has already expired.
       await apiRequest(API_ENDPOINTS.LOGOUT, {
         method: 'POST',
-      });
+        body: JSON.stringify({ refresh_token: localStorage.getItem('refreshToken') }),
+      }, false);
     } catch (error) {
       console.error('Logout API call failed:', error);
     } finally {
       // Always clear local storage and user state
-      localStorage.removeItem('authToken');
+      clearSession();
       setUser(null);
       setOrganization(null);
     }
@@ -141,6 +172,7 @@ export const AuthProvider = ({ children }) => {
     organization,
     isLoading,
     login,
+    completeTwoFactor,
     signup,
     googleAuth,
     logout,
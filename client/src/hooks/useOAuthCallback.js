@@ -1,6 +1,7 @@
 import { useEffect, useState } from 'react';
 import { useAuth } from '../contexts/AuthContext';
 import { useNavigate } from 'react-router-dom';
+import { storeSession } from '../config/api';
 
 export const useOAuthCallback = () => {
   const { user, isLoading, fetchCurrentUser } = useAuth();
@@ -11,7 +12,11 @@ export const useOAuthCallback = () => {
     const handleOAuthCallback = async () => {
       try {
         const urlParams = new URLSearchParams(window.location.search);
-        const token = urlParams.get('token');
+        // Tokens arrive in the fragment so they never reach server logs
+        const hashParams = new URLSearchParams(window.location.hash.slice(1));
+        const token = hashParams.get('token');
+        const refreshToken = hashParams.get('refresh_token');
+        const challenge = urlParams.get('challenge');
         const error = urlParams.get('error');
 
         if (error) {
@@ -19,9 +24,20 @@ export const useOAuthCallback = () => {
           return;
         }
 
+        if (challenge) {
+          // Two-factor authentication is needed before tokens are issued
+          navigate('/login/2fa', {
+            replace: true,
+            state: {
+              challenge: { token: challenge, enrollment_required: urlParams.get('enroll') === '1' },
+            },
+          });
+          return;
+        }
+
         if (token) {
           // Persist token and fetch current user
-          localStorage.setItem('authToken', token);
+          storeSession({ token, refresh_token: refreshToken });
           const me = await fetchCurrentUser();
           if (me.success) {
             // Redirect admin users to the admin dashboard
@@ -4,13 +4,20 @@ import { API_ENDPOINTS } from "../config/api";
 export const createOrder = async (amount) => {
   const receipt = "rcpt_" + Math.floor(Math.random() * 100000);
   try {
-    const response = await axios.post(API_ENDPOINTS.CREATE_ORDER, {
-      amount,
-      receipt,
-    });
+    // Signed-in donors are identified so the server can apply the limit
+    // on donations from unverified accounts before payment.
+    const token = localStorage.getItem("authToken");
+    const response = await axios.post(
+      API_ENDPOINTS.CREATE_ORDER,
+      { amount, receipt },
+      token ? { headers: { Authorization: `Bearer ${token}` } } : undefined
+    );
     return response.data;
   } catch (error) {
     console.error("Error creating order:", error);
+    if (error.response?.status === 403 && typeof error.response.data === "string") {
+      throw new Error(error.response.data.trim());
+    }
     throw new Error("Failed to create payment order");
   }
 };
@@ -0,0 +1,34 @@
+/**
+ * Organization team roles and what each may do. Mirrors the server's grants
+ * so the UI only offers actions the API will allow.
+ */
+
+export const ORGANIZATION_ROLES = [
+  { value: "owner", label: "Owner" },
+  { value: "admin", label: "Admin" },
+  { value: "finance", label: "Finance" },
+  { value: "field_agent", label: "Field agent" },
+  { value: "viewer", label: "Viewer" },
+];
+
+const ALL = ["members", "apiKeys", "webhooks", "causes", "updates", "proofs", "pledges", "finances"];
+
+const ROLE_PERMISSIONS = {
+  owner: ALL,
+  admin: ALL,
+  finance: ["finances"],
+  field_agent: ["updates", "proofs", "pledges"],
+  viewer: [],
+};
+
+export const roleLabel = (role) =>
+  ORGANIZATION_ROLES.find((r) => r.value === role)?.label || role;
+
+/**
+ * Whether a member of `organization` (as returned by /api/auth/me/organization)
+ * may do `permission`: one of members, apiKeys, webhooks, causes, updates, proofs,
+ * pledges or finances.
+ */
+export const canOrganization = (organization, permission) =>
+  !!organization?.member_role &&
+  (ROLE_PERMISSIONS[organization.member_role] || []).includes(permission);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_cause_updates_cause_created_at;
+DROP INDEX IF EXISTS idx_causes_organization_id;
+DROP INDEX IF EXISTS idx_causes_aid_type_id;
+DROP INDEX IF EXISTS idx_causes_domain_id;
+DROP INDEX IF EXISTS idx_causes_created_at;
+DROP INDEX IF EXISTS idx_donations_cause_id;
+DROP INDEX IF EXISTS idx_donations_status_created_at;
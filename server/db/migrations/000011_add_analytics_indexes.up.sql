@@ -0,0 +1,21 @@
+-- Indexes backing the time-series queries used by the admin analytics endpoint.
+CREATE INDEX IF NOT EXISTS idx_donations_status_created_at
+    ON donations(status, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_donations_cause_id
+    ON donations(cause_id);
+
+CREATE INDEX IF NOT EXISTS idx_causes_created_at
+    ON causes(created_at);
+
+CREATE INDEX IF NOT EXISTS idx_causes_domain_id
+    ON causes(domain_id);
+
+CREATE INDEX IF NOT EXISTS idx_causes_aid_type_id
+    ON causes(aid_type_id);
+
+CREATE INDEX IF NOT EXISTS idx_causes_organization_id
+    ON causes(organization_id);
+
+CREATE INDEX IF NOT EXISTS idx_cause_updates_cause_created_at
+    ON cause_updates(cause_id, created_at);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS cause_review_responses;
+DROP TABLE IF EXISTS cause_review_reports;
+
+ALTER TABLE cause_reviews
+    DROP COLUMN IF EXISTS hidden_at,
+    DROP COLUMN IF EXISTS hidden_by,
+    DROP COLUMN IF EXISTS hidden_reason,
+    DROP COLUMN IF EXISTS is_hidden,
+    DROP COLUMN IF EXISTS updated_at;
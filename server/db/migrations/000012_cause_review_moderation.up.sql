@@ -0,0 +1,33 @@
+ALTER TABLE cause_reviews
+    ADD COLUMN IF NOT EXISTS rating INTEGER CHECK (rating >= 1 AND rating <= 5),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS is_hidden BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS hidden_reason TEXT,
+    ADD COLUMN IF NOT EXISTS hidden_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS cause_review_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    review_id UUID NOT NULL REFERENCES cause_reviews(id) ON DELETE CASCADE,
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(50) NOT NULL,
+    details TEXT,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (review_id, reporter_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cause_review_reports_open
+    ON cause_review_reports(review_id) WHERE resolved_at IS NULL;
+
+-- One official reply per review from the organization that owns the cause.
+CREATE TABLE IF NOT EXISTS cause_review_responses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    review_id UUID NOT NULL UNIQUE REFERENCES cause_reviews(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    responder_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    response_text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
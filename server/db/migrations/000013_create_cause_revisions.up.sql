@@ -0,0 +1,23 @@
+-- Snapshot of the donor-facing fields of a cause after every edit.
+-- Revision 1 is the state before the first edit.
+CREATE TABLE IF NOT EXISTS cause_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    cause_id UUID NOT NULL REFERENCES causes(id) ON DELETE CASCADE,
+    revision_number INT NOT NULL,
+    edited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    goal_amount NUMERIC(12, 2),
+    deadline TIMESTAMP WITH TIME ZONE,
+    execution_plan TEXT,
+    execution_lat DOUBLE PRECISION,
+    execution_lng DOUBLE PRECISION,
+    execution_radius_meters INT,
+    changes JSONB NOT NULL DEFAULT '[]'::jsonb,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (cause_id, revision_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cause_revisions_cause
+    ON cause_revisions(cause_id, revision_number DESC);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS cause_state_transitions;
+
+DROP INDEX IF EXISTS idx_causes_state;
+
+ALTER TABLE causes
+    DROP COLUMN IF EXISTS state_changed_at,
+    DROP COLUMN IF EXISTS state;
+
+DROP TYPE IF EXISTS cause_state;
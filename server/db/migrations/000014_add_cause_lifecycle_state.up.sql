@@ -0,0 +1,48 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'cause_state') THEN
+        CREATE TYPE cause_state AS ENUM (
+            'draft',
+            'pending_review',
+            'live',
+            'fully_funded',
+            'executing',
+            'completed',
+            'closed',
+            'suspended'
+        );
+    END IF;
+END
+$$;
+
+ALTER TABLE causes
+    ADD COLUMN IF NOT EXISTS state cause_state NOT NULL DEFAULT 'live',
+    ADD COLUMN IF NOT EXISTS state_changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+-- Derive a starting state for existing causes from the old signals.
+UPDATE causes SET state = 'closed' WHERE is_active = false;
+
+UPDATE causes SET state = 'fully_funded'
+WHERE state = 'live' AND goal_amount > 0 AND collected_amount >= goal_amount;
+
+UPDATE causes SET state = 'closed'
+WHERE state = 'live' AND deadline IS NOT NULL AND deadline < NOW();
+
+CREATE INDEX IF NOT EXISTS idx_causes_state
+    ON causes(state);
+
+CREATE TABLE IF NOT EXISTS cause_state_transitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    cause_id UUID NOT NULL REFERENCES causes(id) ON DELETE CASCADE,
+    from_state cause_state,
+    to_state cause_state NOT NULL,
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cause_state_transitions_cause
+    ON cause_state_transitions(cause_id, created_at DESC);
+
+COMMENT ON COLUMN cause_state_transitions.actor_type IS 'system, organization or admin';
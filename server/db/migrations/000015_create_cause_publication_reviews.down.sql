@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS cause_publication_reviews;
+
+DROP TYPE IF EXISTS cause_publication_decision;
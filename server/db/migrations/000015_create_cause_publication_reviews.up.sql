@@ -0,0 +1,20 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'cause_publication_decision') THEN
+        CREATE TYPE cause_publication_decision AS ENUM ('approved', 'rejected');
+    END IF;
+END
+$$;
+
+-- Admin decisions on causes submitted for publication.
+CREATE TABLE IF NOT EXISTS cause_publication_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    cause_id UUID NOT NULL REFERENCES causes(id) ON DELETE CASCADE,
+    reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    decision cause_publication_decision NOT NULL,
+    comments TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cause_publication_reviews_cause
+    ON cause_publication_reviews(cause_id, created_at DESC);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_causes_deadline;
+DROP INDEX IF EXISTS idx_causes_location;
+DROP INDEX IF EXISTS idx_organizations_name_search;
+DROP INDEX IF EXISTS idx_causes_search_vector;
+
+ALTER TABLE causes
+    DROP COLUMN IF EXISTS search_vector,
+    DROP COLUMN IF EXISTS location_city,
+    DROP COLUMN IF EXISTS location_state;
@@ -0,0 +1,24 @@
+ALTER TABLE causes
+    ADD COLUMN IF NOT EXISTS location_state VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS location_city VARCHAR(100);
+
+-- Weighted document for full-text search. Organization name lives in another
+-- table, so it is matched separately against idx_organizations_name_search.
+ALTER TABLE causes
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(problem_statement, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_causes_search_vector
+    ON causes USING GIN (search_vector);
+
+CREATE INDEX IF NOT EXISTS idx_organizations_name_search
+    ON organizations USING GIN (to_tsvector('english', organization_name));
+
+CREATE INDEX IF NOT EXISTS idx_causes_location
+    ON causes(lower(location_state), lower(location_city));
+
+CREATE INDEX IF NOT EXISTS idx_causes_deadline
+    ON causes(deadline);
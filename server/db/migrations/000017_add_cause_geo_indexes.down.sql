@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_causes_execution_lat_lng;
+DROP INDEX IF EXISTS idx_causes_execution_earth;
+
+DROP EXTENSION IF EXISTS earthdistance;
+DROP EXTENSION IF EXISTS cube;
@@ -0,0 +1,12 @@
+CREATE EXTENSION IF NOT EXISTS cube;
+CREATE EXTENSION IF NOT EXISTS earthdistance;
+
+-- Radius searches: earth_box(...) @> ll_to_earth(...) is served by this index.
+CREATE INDEX IF NOT EXISTS idx_causes_execution_earth
+    ON causes USING GIST (ll_to_earth(execution_lat, execution_lng))
+    WHERE execution_lat IS NOT NULL AND execution_lng IS NOT NULL;
+
+-- Map bounding-box searches on raw coordinates.
+CREATE INDEX IF NOT EXISTS idx_causes_execution_lat_lng
+    ON causes(execution_lat, execution_lng)
+    WHERE execution_lat IS NOT NULL AND execution_lng IS NOT NULL;
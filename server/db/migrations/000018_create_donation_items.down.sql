@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_donation_items_product;
+DROP INDEX IF EXISTS idx_donation_items_donation;
+DROP TABLE IF EXISTS donation_items;
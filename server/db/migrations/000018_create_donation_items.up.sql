@@ -0,0 +1,13 @@
+-- Line items for donations that fund specific cause products. unit_price
+-- is the product price at the time of donation.
+CREATE TABLE IF NOT EXISTS donation_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    donation_id UUID NOT NULL REFERENCES donations(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES cause_products(id) ON DELETE RESTRICT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price NUMERIC(12,2) NOT NULL CHECK (unit_price > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_donation_items_donation ON donation_items(donation_id);
+CREATE INDEX IF NOT EXISTS idx_donation_items_product ON donation_items(product_id);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_goods_pledges_received;
+DROP INDEX IF EXISTS idx_goods_pledges_donor;
+DROP INDEX IF EXISTS idx_goods_pledges_cause;
+DROP TABLE IF EXISTS goods_pledges;
+
+DROP TYPE IF EXISTS goods_delivery_method;
+DROP TYPE IF EXISTS goods_pledge_status;
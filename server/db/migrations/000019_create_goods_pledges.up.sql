@@ -0,0 +1,42 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'goods_pledge_status') THEN
+        CREATE TYPE goods_pledge_status AS ENUM (
+            'pledged', 'accepted', 'rejected', 'scheduled', 'received', 'cancelled'
+        );
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'goods_delivery_method') THEN
+        CREATE TYPE goods_delivery_method AS ENUM ('drop_off', 'pickup');
+    END IF;
+END
+$$;
+
+-- In-kind donations. item_name is copied from the product for product
+-- pledges so the record survives product edits.
+CREATE TABLE IF NOT EXISTS goods_pledges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    cause_id UUID NOT NULL REFERENCES causes(id) ON DELETE CASCADE,
+    donor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    product_id UUID REFERENCES cause_products(id) ON DELETE SET NULL,
+    item_name TEXT NOT NULL,
+    description TEXT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit VARCHAR(32),
+    estimated_unit_value NUMERIC(12,2) CHECK (estimated_unit_value >= 0),
+    status goods_pledge_status NOT NULL DEFAULT 'pledged',
+    rejection_reason TEXT,
+    delivery_method goods_delivery_method,
+    delivery_scheduled_at TIMESTAMP WITH TIME ZONE,
+    delivery_notes TEXT,
+    received_quantity INTEGER CHECK (received_quantity > 0),
+    received_at TIMESTAMP WITH TIME ZONE,
+    proof_image_id UUID REFERENCES proof_images(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_goods_pledges_cause ON goods_pledges(cause_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_goods_pledges_donor ON goods_pledges(donor_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_goods_pledges_received
+    ON goods_pledges(cause_id, received_at DESC)
+    WHERE status = 'received';
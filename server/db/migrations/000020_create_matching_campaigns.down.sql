@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_donations_matched_donation;
+
+ALTER TABLE donations
+    DROP COLUMN IF EXISTS matched_donation_id,
+    DROP COLUMN IF EXISTS matching_campaign_id;
+
+DROP INDEX IF EXISTS idx_matching_campaigns_domain;
+DROP INDEX IF EXISTS idx_matching_campaigns_cause;
+DROP TABLE IF EXISTS matching_campaigns;
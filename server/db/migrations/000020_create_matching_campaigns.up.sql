@@ -0,0 +1,34 @@
+-- Sponsor matching for donations to one cause or to every cause in a
+-- domain. matched_amount only grows up to cap_amount.
+CREATE TABLE IF NOT EXISTS matching_campaigns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    sponsor_user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    sponsor_name VARCHAR(255) NOT NULL,
+    cause_id UUID REFERENCES causes(id) ON DELETE CASCADE,
+    domain_id UUID REFERENCES cause_domains(id) ON DELETE CASCADE,
+    ratio NUMERIC(6,2) NOT NULL CHECK (ratio > 0),
+    cap_amount NUMERIC(14,2) NOT NULL CHECK (cap_amount > 0),
+    matched_amount NUMERIC(14,2) NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((cause_id IS NULL) <> (domain_id IS NULL)),
+    CHECK (ends_at > starts_at),
+    CHECK (matched_amount >= 0 AND matched_amount <= cap_amount)
+);
+
+CREATE INDEX IF NOT EXISTS idx_matching_campaigns_cause
+    ON matching_campaigns(cause_id) WHERE is_active;
+CREATE INDEX IF NOT EXISTS idx_matching_campaigns_domain
+    ON matching_campaigns(domain_id) WHERE is_active;
+
+-- A sponsor donation points at the campaign and the donor donation it
+-- matched.
+ALTER TABLE donations
+    ADD COLUMN IF NOT EXISTS matching_campaign_id UUID REFERENCES matching_campaigns(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS matched_donation_id UUID REFERENCES donations(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_donations_matched_donation
+    ON donations(matched_donation_id) WHERE matched_donation_id IS NOT NULL;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_donations_fundraiser;
+
+ALTER TABLE donations DROP COLUMN IF EXISTS fundraiser_id;
+
+DROP INDEX IF EXISTS idx_fundraisers_leaderboard;
+DROP INDEX IF EXISTS idx_fundraisers_owner;
+DROP INDEX IF EXISTS idx_fundraisers_cause;
+DROP TABLE IF EXISTS fundraisers;
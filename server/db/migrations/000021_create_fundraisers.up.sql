@@ -0,0 +1,28 @@
+-- Personal fundraising pages run by supporters for a parent cause. Money
+-- raised is also counted on the cause; raised_amount and donation_count
+-- are kept alongside for the fundraiser's own progress and leaderboards.
+CREATE TABLE IF NOT EXISTS fundraisers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    cause_id UUID NOT NULL REFERENCES causes(id) ON DELETE CASCADE,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    story TEXT,
+    goal_amount NUMERIC(12,2) NOT NULL CHECK (goal_amount > 0),
+    raised_amount NUMERIC(14,2) NOT NULL DEFAULT 0,
+    donation_count INTEGER NOT NULL DEFAULT 0,
+    cover_image_url TEXT,
+    deadline TIMESTAMP WITH TIME ZONE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_fundraisers_cause ON fundraisers(cause_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_fundraisers_owner ON fundraisers(owner_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_fundraisers_leaderboard ON fundraisers(cause_id, raised_amount DESC);
+
+ALTER TABLE donations
+    ADD COLUMN IF NOT EXISTS fundraiser_id UUID REFERENCES fundraisers(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_donations_fundraiser
+    ON donations(fundraiser_id, created_at DESC, id DESC) WHERE fundraiser_id IS NOT NULL;
@@ -0,0 +1,9 @@
+ALTER TABLE donations
+    DROP COLUMN IF EXISTS tribute_notified_at,
+    DROP COLUMN IF EXISTS tribute_notify_email,
+    DROP COLUMN IF EXISTS tribute_message,
+    DROP COLUMN IF EXISTS tribute_name,
+    DROP COLUMN IF EXISTS tribute_type,
+    DROP COLUMN IF EXISTS is_anonymous;
+
+DROP TYPE IF EXISTS donation_tribute_type;
@@ -0,0 +1,21 @@
+-- Anonymous donations keep the donor's details for the tax receipt but hide
+-- them from public listings and the ledger.
+ALTER TABLE donations
+    ADD COLUMN IF NOT EXISTS is_anonymous BOOLEAN NOT NULL DEFAULT FALSE;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'donation_tribute_type') THEN
+        CREATE TYPE donation_tribute_type AS ENUM ('honour', 'memory');
+    END IF;
+END
+$$;
+
+-- A tribute dedicates the donation to someone. tribute_notified_at is set
+-- once the e-card has been sent to tribute_notify_email.
+ALTER TABLE donations
+    ADD COLUMN IF NOT EXISTS tribute_type donation_tribute_type,
+    ADD COLUMN IF NOT EXISTS tribute_name VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS tribute_message TEXT,
+    ADD COLUMN IF NOT EXISTS tribute_notify_email VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS tribute_notified_at TIMESTAMP WITH TIME ZONE;
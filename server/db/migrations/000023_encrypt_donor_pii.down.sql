@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_pii_access_logs_actor;
+DROP INDEX IF EXISTS idx_pii_access_logs_resource;
+DROP TABLE IF EXISTS pii_access_logs;
+
+-- Encrypted values do not fit the old column sizes, so the columns are left
+-- as TEXT.
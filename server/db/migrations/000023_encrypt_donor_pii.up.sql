@@ -0,0 +1,23 @@
+-- phone, billing_address and pan_number hold AES-GCM ciphertext from now on
+-- ("enc:<key id>:<base64>"), which does not fit the old column sizes. Rows
+-- written before this migration stay plaintext and are read as-is.
+ALTER TABLE donations
+    ALTER COLUMN phone TYPE TEXT,
+    ALTER COLUMN billing_address TYPE TEXT,
+    ALTER COLUMN pan_number TYPE TEXT;
+
+-- Every read of unmasked donor data by someone other than the donor.
+CREATE TABLE IF NOT EXISTS pii_access_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    actor_role VARCHAR(50) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id UUID NOT NULL,
+    purpose VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pii_access_logs_resource
+    ON pii_access_logs(resource_type, resource_id);
+CREATE INDEX IF NOT EXISTS idx_pii_access_logs_actor
+    ON pii_access_logs(actor_id, created_at DESC);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_data_erasure_requests_user;
+DROP TABLE IF EXISTS data_erasure_requests;
+
+DROP INDEX IF EXISTS idx_donation_tax_records_retain_until;
+DROP TABLE IF EXISTS donation_tax_records;
+
+ALTER TABLE donations DROP CONSTRAINT IF EXISTS donations_user_id_fkey;
+ALTER TABLE donations
+    ADD CONSTRAINT donations_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE users DROP COLUMN IF EXISTS erased_at;
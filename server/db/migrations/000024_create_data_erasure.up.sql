@@ -0,0 +1,41 @@
+-- Erased accounts are pseudonymized in place rather than deleted, so rows
+-- tied to the ledger keep a valid user_id.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP WITH TIME ZONE;
+
+-- Donations are anchored on DonationLedger and must never disappear with
+-- their donor.
+ALTER TABLE donations DROP CONSTRAINT IF EXISTS donations_user_id_fkey;
+ALTER TABLE donations
+    ADD CONSTRAINT donations_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+-- Donor details needed for 80G receipts and Form 10BD, kept after the donor
+-- is erased until retain_until. PII columns hold the same ciphertext as
+-- donations did.
+CREATE TABLE IF NOT EXISTS donation_tax_records (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    donation_id UUID NOT NULL UNIQUE REFERENCES donations(id) ON DELETE RESTRICT,
+    donor_name VARCHAR(255) NOT NULL,
+    pan_number TEXT NOT NULL,
+    billing_address TEXT,
+    pincode VARCHAR(7),
+    amount NUMERIC(12,2) NOT NULL,
+    donated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    retain_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_donation_tax_records_retain_until
+    ON donation_tax_records(retain_until);
+
+CREATE TABLE IF NOT EXISTS data_erasure_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    summary JSONB NOT NULL DEFAULT '{}'::jsonb,
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_erasure_requests_user
+    ON data_erasure_requests(user_id);
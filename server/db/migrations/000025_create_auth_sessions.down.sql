@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_session;
+DROP TABLE IF EXISTS refresh_tokens;
+
+DROP INDEX IF EXISTS idx_auth_sessions_user;
+DROP TABLE IF EXISTS auth_sessions;
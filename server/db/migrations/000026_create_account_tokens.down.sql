@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_account_tokens_user;
+DROP TABLE IF EXISTS account_tokens;
+DROP TYPE IF EXISTS account_token_purpose;
@@ -0,0 +1,23 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'account_token_purpose') THEN
+        CREATE TYPE account_token_purpose AS ENUM ('email_verification', 'password_reset');
+    END IF;
+END $$;
+
+-- Email verification and password reset tokens. The token sent to the user
+-- is signed with the server secret and names this row; the row makes it
+-- single use. email is the address the token was sent to, so a token stops
+-- working if the account's email changes.
+CREATE TABLE IF NOT EXISTS account_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose account_token_purpose NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_tokens_user
+    ON account_tokens(user_id, purpose, created_at DESC);
@@ -0,0 +1,43 @@
+-- TOTP secret per user, encrypted like donor PII. enabled_at stays NULL
+-- until the user confirms enrollment with a first code. last_used_step is
+-- the last accepted time step, so a code can't be used twice.
+CREATE TABLE IF NOT EXISTS user_two_factor (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    enabled_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- One-time recovery codes, stored as SHA-256 hashes.
+CREATE TABLE IF NOT EXISTS two_factor_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, code_hash)
+);
+
+-- A password sign-in waiting for its second factor. The client holds the
+-- token; only its hash is stored.
+CREATE TABLE IF NOT EXISTS login_challenges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    enrollment_required BOOLEAN NOT NULL DEFAULT FALSE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_challenges_user ON login_challenges(user_id);
+
+-- 2FA is mandatory for organizations and admins: end their existing
+-- sessions so the next sign-in goes through enrollment.
+UPDATE auth_sessions
+SET revoked_at = NOW(), revoked_reason = 'two_factor_required'
+WHERE revoked_at IS NULL
+    AND user_id IN (SELECT id FROM users WHERE role IN ('organization', 'admin'));
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_organization_invitations_org;
+DROP TABLE IF EXISTS organization_invitations;
+DROP INDEX IF EXISTS idx_organization_members_org;
+DROP TABLE IF EXISTS organization_members;
+DROP TYPE IF EXISTS organization_member_role;
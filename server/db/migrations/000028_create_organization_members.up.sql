@@ -0,0 +1,44 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'organization_member_role') THEN
+        CREATE TYPE organization_member_role AS ENUM ('owner', 'admin', 'finance', 'field_agent', 'viewer');
+    END IF;
+END $$;
+
+-- People who act for an organization. An account belongs to at most one
+-- organization. The organization's own login (organizations.user_id) is
+-- its first owner and can't be removed.
+CREATE TABLE IF NOT EXISTS organization_members (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    role organization_member_role NOT NULL,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_members_org ON organization_members(organization_id);
+
+INSERT INTO organization_members (organization_id, user_id, role)
+SELECT id, user_id, 'owner' FROM organizations
+ON CONFLICT (user_id) DO NOTHING;
+
+-- Emailed invitations to join an organization. The link carries a random
+-- token; only its hash is stored.
+CREATE TABLE IF NOT EXISTS organization_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role organization_member_role NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    accepted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_invitations_org
+    ON organization_invitations(organization_id, created_at DESC);
@@ -0,0 +1,20 @@
+-- Keys organizations use to call the API from their own systems. A key
+-- looks like clk_<prefix>_<secret>; the prefix identifies it in lists and
+-- lookups and only a hash of the whole key is stored.
+CREATE TABLE IF NOT EXISTS organization_api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL UNIQUE,
+    key_hash VARCHAR(64) NOT NULL,
+    -- Space separated, as in OAuth.
+    scopes TEXT NOT NULL,
+    rate_limit_per_minute INTEGER NOT NULL CHECK (rate_limit_per_minute > 0),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_api_keys_org ON organization_api_keys(organization_id);
@@ -0,0 +1,21 @@
+-- OAuth and OpenID Connect accounts a user can sign in with, alongside or
+-- instead of a password. Each provider account belongs to one user, and a
+-- user links at most one account per provider.
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    provider_user_id VARCHAR(255) NOT NULL,
+    -- The address the provider reported when the account was linked.
+    email VARCHAR(255),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, provider_user_id),
+    UNIQUE (user_id, provider)
+);
+
+-- Accounts that signed up with an OAuth provider before identities existed.
+INSERT INTO user_identities (user_id, provider, provider_user_id, email, created_at)
+SELECT id, provider, provider_id, email, created_at FROM users
+WHERE provider <> 'email' AND provider_id IS NOT NULL
+ON CONFLICT DO NOTHING;
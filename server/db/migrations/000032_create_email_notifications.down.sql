@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS notification_preferences;
+DROP INDEX IF EXISTS idx_email_outbox_user;
+DROP INDEX IF EXISTS idx_email_outbox_due;
+DROP TABLE IF EXISTS email_outbox;
+DROP TYPE IF EXISTS email_outbox_status;
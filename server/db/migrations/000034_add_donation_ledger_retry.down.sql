@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_donations_ledger_pending;
+ALTER TABLE donations DROP COLUMN IF EXISTS ledger_retry_at;
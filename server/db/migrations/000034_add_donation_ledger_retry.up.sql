@@ -0,0 +1,6 @@
+-- Donations are saved before they are written to the donation ledger. Until
+-- tx_hash is set, ledger_retry_at is when the ledger sync may next try.
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS ledger_retry_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_donations_ledger_pending
+    ON donations(ledger_retry_at) WHERE tx_hash IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE donations DROP COLUMN IF EXISTS milestone_recorded_at;
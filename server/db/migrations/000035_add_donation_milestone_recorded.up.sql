@@ -0,0 +1,6 @@
+-- When a donation was counted by the milestone tracker. Ledger retries
+-- check it so that no donation is added to a cause's total twice.
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS milestone_recorded_at TIMESTAMP WITH TIME ZONE;
+
+-- Donations already on the ledger were counted when they were recorded.
+UPDATE donations SET milestone_recorded_at = created_at WHERE tx_hash IS NOT NULL;
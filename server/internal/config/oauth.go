@@ -2,15 +2,21 @@ package config
 
 import (
 	"errors"
+	"log"
 	"net/http"
 	"os"
 	"time"
 
+	"server/internal/models"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/gorilla/sessions"
 	"github.com/markbates/goth"
 	"github.com/markbates/goth/gothic"
+	"github.com/markbates/goth/providers/azureadv2"
+	"github.com/markbates/goth/providers/github"
 	"github.com/markbates/goth/providers/google"
+	"github.com/markbates/goth/providers/openidConnect"
 )
 
 func ConfigureOAuth() {
@@ -44,18 +50,62 @@ func ConfigureOAuth() {
 		return "", errors.New("provider not specified")
 	}
 
-	// Configure Google OAuth
-	googleClientID := os.Getenv("GOOGLE_CLIENT_ID")
-	googleClientSecret := os.Getenv("GOOGLE_CLIENT_SECRET")
 	baseURL := os.Getenv("BASE_URL")
 	if baseURL == "" {
 		baseURL = "http://localhost:8080"
 	}
+	// Callback path should include provider for chi routing
+	callbackURL := func(name string) string {
+		return baseURL + "/api/auth/" + name + "/callback"
+	}
+
+	oauthProviders = nil
+	use := func(label string, p goth.Provider) {
+		goth.UseProviders(p)
+		oauthProviders = append(oauthProviders, models.OAuthProvider{Name: p.Name(), Label: label})
+	}
+
+	// Configure Google OAuth
+	if id, secret := os.Getenv("GOOGLE_CLIENT_ID"), os.Getenv("GOOGLE_CLIENT_SECRET"); id != "" && secret != "" {
+		use("Google", google.New(id, secret, callbackURL("google")))
+	}
 
-	if googleClientID != "" && googleClientSecret != "" {
-		goth.UseProviders(
-			// Callback path should include provider for chi routing
-			google.New(googleClientID, googleClientSecret, baseURL+"/api/auth/google/callback"),
-		)
+	// Configure GitHub OAuth. user:email lets goth read a private primary
+	// address.
+	if id, secret := os.Getenv("GITHUB_CLIENT_ID"), os.Getenv("GITHUB_CLIENT_SECRET"); id != "" && secret != "" {
+		use("GitHub", github.New(id, secret, callbackURL("github"), "read:user", "user:email"))
 	}
+
+	// Configure Microsoft (Entra ID) sign-in. MICROSOFT_TENANT defaults to
+	// "common", which takes both personal and work accounts.
+	if id, secret := os.Getenv("MICROSOFT_CLIENT_ID"), os.Getenv("MICROSOFT_CLIENT_SECRET"); id != "" && secret != "" {
+		p := azureadv2.New(id, secret, callbackURL("microsoft"), azureadv2.ProviderOptions{
+			Tenant: azureadv2.TenantType(os.Getenv("MICROSOFT_TENANT")),
+		})
+		p.SetName("microsoft")
+		use("Microsoft", p)
+	}
+
+	// Configure a generic OpenID Connect provider, such as Okta, Auth0 or
+	// Keycloak, from its discovery document.
+	if id, secret, discoveryURL := os.Getenv("OIDC_CLIENT_ID"), os.Getenv("OIDC_CLIENT_SECRET"), os.Getenv("OIDC_DISCOVERY_URL"); id != "" && secret != "" && discoveryURL != "" {
+		p, err := openidConnect.NewNamed("oidc", id, secret, callbackURL("oidc"), discoveryURL, "openid", "email", "profile")
+		if err != nil {
+			log.Printf("Warning: OpenID Connect sign-in disabled: %v", err)
+		} else {
+			label := os.Getenv("OIDC_LABEL")
+			if label == "" {
+				label = "Single sign-on"
+			}
+			use(label, p)
+		}
+	}
+}
+
+// oauthProviders are the providers ConfigureOAuth set up, in button order.
+var oauthProviders []models.OAuthProvider
+
+// OAuthProviders lists the sign-in providers that are configured.
+func OAuthProviders() []models.OAuthProvider {
+	return append([]models.OAuthProvider{}, oauthProviders...)
 }
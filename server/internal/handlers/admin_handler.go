@@ -1,35 +1,104 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+	"time"
 
 	"server/internal/middleware"
+	"server/internal/models"
+	"server/internal/policy"
 	"server/internal/repository"
 	"server/internal/services"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
+// Upper bound on buckets per series so a wide range at daily granularity
+// can't turn into an unbounded generate_series.
+const maxAnalyticsBuckets = 400
+
 type AdminHandler struct {
-	adminRepo  repository.AdminRepository
-	jwtService services.JWTService
+	adminRepo          repository.AdminRepository
+	analyticsRepo      repository.AnalyticsRepository
+	piiAccessLogRepo   repository.PIIAccessLogRepository
+	causeReviewService services.CauseReviewService
+	lifecycleService   services.CauseLifecycleService
+	matchingService    services.MatchingCampaignService
+	disputeService     services.DisputeService
+	authorizer         *policy.Authorizer
+	jwtService         services.JWTService
 }
 
-func NewAdminHandler(adminRepo repository.AdminRepository, jwtService services.JWTService) *AdminHandler {
+func NewAdminHandler(
+	adminRepo repository.AdminRepository,
+	analyticsRepo repository.AnalyticsRepository,
+	piiAccessLogRepo repository.PIIAccessLogRepository,
+	causeReviewService services.CauseReviewService,
+	lifecycleService services.CauseLifecycleService,
+	matchingService services.MatchingCampaignService,
+	disputeService services.DisputeService,
+	authorizer *policy.Authorizer,
+	jwtService services.JWTService,
+) *AdminHandler {
 	return &AdminHandler{
-		adminRepo:  adminRepo,
-		jwtService: jwtService,
+		adminRepo:          adminRepo,
+		analyticsRepo:      analyticsRepo,
+		piiAccessLogRepo:   piiAccessLogRepo,
+		causeReviewService: causeReviewService,
+		lifecycleService:   lifecycleService,
+		matchingService:    matchingService,
+		disputeService:     disputeService,
+		authorizer:         authorizer,
+		jwtService:         jwtService,
 	}
 }
 
 func (h *AdminHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/api/admin", func(r chi.Router) {
-		// All admin routes require a valid JWT and the "admin" role
+		// All admin routes require a valid JWT and the permission for the
+		// area they belong to
 		r.Group(func(protected chi.Router) {
-			protected.Use(middleware.AuthMiddleware(h.jwtService))
-			protected.Use(middleware.RequireRole("admin"))
-			protected.Get("/dashboard", h.GetDashboardData)
+			protected.Use(middleware.UserAuthMiddleware(h.jwtService))
+			require := func(p policy.Permission) func(http.Handler) http.Handler {
+				return middleware.RequirePermission(h.authorizer, p)
+			}
+
+			protected.With(require(policy.AdminDashboardView)).Get("/dashboard", h.GetDashboardData)
+			protected.With(require(policy.AdminDashboardView)).Get("/analytics", h.GetAnalytics)
+
+			protected.Group(func(reviews chi.Router) {
+				reviews.Use(require(policy.ReviewModerate))
+				reviews.Get("/reviews/moderation", h.GetReviewModerationQueue)
+				reviews.Post("/reviews/{reviewID}/hide", h.HideReview)
+				reviews.Post("/reviews/{reviewID}/restore", h.RestoreReview)
+			})
+
+			protected.Group(func(causes chi.Router) {
+				causes.Use(require(policy.CauseApprove))
+				causes.Get("/causes/pending", h.GetPendingCauses)
+				causes.Post("/causes/{ID}/approve", h.ApproveCause)
+				causes.Post("/causes/{ID}/reject", h.RejectCause)
+			})
+
+			protected.Group(func(matching chi.Router) {
+				matching.Use(require(policy.MatchingCampaignManage))
+				matching.Get("/matching-campaigns", h.GetMatchingCampaigns)
+				matching.Post("/matching-campaigns", h.CreateMatchingCampaign)
+				matching.Post("/matching-campaigns/{ID}/deactivate", h.DeactivateMatchingCampaign)
+			})
+
+			protected.Group(func(disputes chi.Router) {
+				disputes.Use(require(policy.DisputeResolve))
+				disputes.Get("/disputes", h.ListDisputes)
+				disputes.Patch("/disputes/{ID}", h.UpdateDispute)
+			})
+
+			protected.With(require(policy.PIIAccessLogView)).Get("/pii-access-logs", h.GetPIIAccessLogs)
 		})
 	})
 }
@@ -44,3 +113,425 @@ func (h *AdminHandler) GetDashboardData(w http.ResponseWriter, r *http.Request)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(data)
 }
+
+// GetAnalytics returns chart-ready time series for the admin dashboard.
+//
+// Query params: interval (day|week|month, default day), from and to
+// (RFC3339 or YYYY-MM-DD, default last 30 days), domain_id, aid_type_id,
+// organization_id.
+func (h *AdminHandler) GetAnalytics(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAnalyticsFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.analyticsRepo.GetAdminAnalytics(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Failed to fetch analytics", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+// GetReviewModerationQueue lists reported reviews awaiting a decision, or
+// currently hidden reviews with ?status=hidden.
+func (h *AdminHandler) GetReviewModerationQueue(w http.ResponseWriter, r *http.Request) {
+	hidden := r.URL.Query().Get("status") == "hidden"
+
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	queue, err := h.causeReviewService.GetModerationQueue(r.Context(), hidden, params)
+	if err != nil {
+		http.Error(w, "Failed to fetch moderation queue", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(queue.Response(params))
+}
+
+func (h *AdminHandler) HideReview(w http.ResponseWriter, r *http.Request) {
+	reviewID, err := uuid.Parse(chi.URLParam(r, "reviewID"))
+	if err != nil {
+		http.Error(w, "Invalid review ID", http.StatusBadRequest)
+		return
+	}
+
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.ModerateCauseReviewRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.causeReviewService.HideReview(r.Context(), reviewID, adminID, req.Reason); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	metadata := map[string]interface{}{}
+	if req.Reason != nil {
+		metadata["reason"] = *req.Reason
+	}
+	if err := h.adminRepo.LogAction(r.Context(), adminID, "review_hidden", "cause_review", reviewID, metadata); err != nil {
+		log.Printf("Warning: failed to log admin action: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "review hidden",
+	})
+}
+
+func (h *AdminHandler) RestoreReview(w http.ResponseWriter, r *http.Request) {
+	reviewID, err := uuid.Parse(chi.URLParam(r, "reviewID"))
+	if err != nil {
+		http.Error(w, "Invalid review ID", http.StatusBadRequest)
+		return
+	}
+
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.causeReviewService.RestoreReview(r.Context(), reviewID, adminID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.adminRepo.LogAction(r.Context(), adminID, "review_restored", "cause_review", reviewID, nil); err != nil {
+		log.Printf("Warning: failed to log admin action: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "review restored",
+	})
+}
+
+func parseAnalyticsFilter(r *http.Request) (models.AnalyticsFilter, error) {
+	q := r.URL.Query()
+
+	filter := models.AnalyticsFilter{
+		Interval: models.AnalyticsIntervalDay,
+		To:       time.Now().UTC(),
+	}
+
+	if interval := q.Get("interval"); interval != "" {
+		filter.Interval = models.AnalyticsInterval(interval)
+		if !filter.Interval.IsValid() {
+			return filter, errors.New("interval must be one of day, week, month")
+		}
+	}
+
+	if to := q.Get("to"); to != "" {
+		t, err := parseQueryTime(to)
+		if err != nil {
+			return filter, errors.New("Invalid to date")
+		}
+		filter.To = t.UTC()
+	}
+
+	filter.From = filter.To.AddDate(0, 0, -30)
+	if from := q.Get("from"); from != "" {
+		t, err := parseQueryTime(from)
+		if err != nil {
+			return filter, errors.New("Invalid from date")
+		}
+		filter.From = t.UTC()
+	}
+
+	if filter.From.After(filter.To) {
+		return filter, errors.New("from must be before to")
+	}
+
+	if filter.BucketCount() > maxAnalyticsBuckets {
+		return filter, errors.New("Date range too large for the selected interval")
+	}
+
+	for param, dest := range map[string]**uuid.UUID{
+		"domain_id":       &filter.DomainID,
+		"aid_type_id":     &filter.AidTypeID,
+		"organization_id": &filter.OrganizationID,
+	} {
+		raw := q.Get(param)
+		if raw == "" {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, errors.New("Invalid " + param)
+		}
+		*dest = &id
+	}
+
+	return filter, nil
+}
+
+func parseQueryTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// GetPendingCauses lists causes submitted by organizations and awaiting a
+// publication decision.
+func (h *AdminHandler) GetPendingCauses(w http.ResponseWriter, r *http.Request) {
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	causes, err := h.lifecycleService.GetPendingReview(r.Context(), params)
+	if err != nil {
+		http.Error(w, "Failed to fetch pending causes", http.StatusInternalServerError)
+		return
+	}
+
+	causesResponse := make([]models.CauseResponse, 0, len(causes.Items))
+	for _, cause := range causes.Items {
+		causesResponse = append(causesResponse, cause.ToCauseResponse())
+	}
+
+	response := causes.Response(params)
+	response.Data = causesResponse
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *AdminHandler) ApproveCause(w http.ResponseWriter, r *http.Request) {
+	h.reviewCausePublication(w, r, models.CausePublicationApproved)
+}
+
+func (h *AdminHandler) RejectCause(w http.ResponseWriter, r *http.Request) {
+	h.reviewCausePublication(w, r, models.CausePublicationRejected)
+}
+
+func (h *AdminHandler) reviewCausePublication(w http.ResponseWriter, r *http.Request, decision models.CausePublicationDecision) {
+	causeID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid cause ID", http.StatusBadRequest)
+		return
+	}
+
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CausePublicationReviewRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	cause, review, err := h.lifecycleService.ReviewPublication(r.Context(), causeID, adminID, decision, req.Comments)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	metadata := map[string]interface{}{}
+	if req.Comments != nil {
+		metadata["comments"] = *req.Comments
+	}
+	if err := h.adminRepo.LogAction(r.Context(), adminID, "cause_"+string(decision), "cause", causeID, metadata); err != nil {
+		log.Printf("Warning: failed to log admin action: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"cause":  cause.ToCauseResponse(),
+		"review": review,
+	})
+}
+
+func (h *AdminHandler) GetMatchingCampaigns(w http.ResponseWriter, r *http.Request) {
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	campaigns, err := h.matchingService.List(r.Context(), params)
+	if err != nil {
+		http.Error(w, "Failed to fetch matching campaigns", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(campaigns.Response(params))
+}
+
+func (h *AdminHandler) CreateMatchingCampaign(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateMatchingCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	campaign, err := h.matchingService.Create(r.Context(), adminID, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	metadata := map[string]interface{}{
+		"sponsor_name": campaign.SponsorName,
+		"ratio":        campaign.Ratio,
+		"cap_amount":   campaign.CapAmount,
+	}
+	if err := h.adminRepo.LogAction(r.Context(), adminID, "matching_campaign_created", "matching_campaign", campaign.ID, metadata); err != nil {
+		log.Printf("Warning: failed to log admin action: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(campaign)
+}
+
+func (h *AdminHandler) DeactivateMatchingCampaign(w http.ResponseWriter, r *http.Request) {
+	campaignID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid campaign ID", http.StatusBadRequest)
+		return
+	}
+
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	campaign, err := h.matchingService.Deactivate(r.Context(), campaignID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Matching campaign not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.adminRepo.LogAction(r.Context(), adminID, "matching_campaign_deactivated", "matching_campaign", campaignID, nil); err != nil {
+		log.Printf("Warning: failed to log admin action: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(campaign)
+}
+
+// GetPIIAccessLogs lists reads of unmasked donor data, newest first.
+// Optional resource_id narrows it to one donation or cause.
+func (h *AdminHandler) GetPIIAccessLogs(w http.ResponseWriter, r *http.Request) {
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var resourceID *uuid.UUID
+	if raw := r.URL.Query().Get("resource_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			http.Error(w, "Invalid resource_id", http.StatusBadRequest)
+			return
+		}
+		resourceID = &id
+	}
+
+	entries, err := h.piiAccessLogRepo.List(r.Context(), resourceID, params)
+	if err != nil {
+		http.Error(w, "Failed to fetch access logs", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries.Response(params))
+}
+
+// ListDisputes lists disputes newest first, filtered by ?status= if given.
+func (h *AdminHandler) ListDisputes(w http.ResponseWriter, r *http.Request) {
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	status := models.DisputeStatus(r.URL.Query().Get("status"))
+	if status != "" && !status.IsValid() {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	disputes, err := h.disputeService.List(r.Context(), status, params)
+	if err != nil {
+		log.Printf("failed to fetch disputes: %v", err)
+		http.Error(w, "Failed to fetch disputes", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(disputes.Response(params))
+}
+
+// UpdateDispute moves a dispute along. Whoever opened it and the
+// organization it's about are emailed when its status changes.
+func (h *AdminHandler) UpdateDispute(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+	disputeID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid dispute ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateDisputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	dispute, err := h.disputeService.Update(r.Context(), disputeID, adminID, &req)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Dispute not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dispute)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseAnalyticsFilterBucketCap(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantErr bool
+	}{
+		// 400 calendar months is about 434 periods of 28 days
+		{"interval=month&from=2000-01-01&to=2033-04-30", false},
+		{"interval=month&from=2000-01-01&to=2033-05-01", true},
+		{"interval=day&from=2025-01-01&to=2026-02-04", false},
+		{"interval=day&from=2025-01-01&to=2026-02-05", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := parseAnalyticsFilter(httptest.NewRequest(http.MethodGet, "/api/admin/analytics?"+tt.query, nil))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseAnalyticsFilter() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseAnalyticsFilterUTC(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/admin/analytics?interval=month&from=2025-02-01T01:00:00%2B05:30&to=2025-03-01T00:00:00Z", nil)
+	filter, err := parseAnalyticsFilter(r)
+	if err != nil {
+		t.Fatalf("parseAnalyticsFilter() error = %v", err)
+	}
+	if filter.From.Location() != time.UTC || !filter.From.Equal(time.Date(2025, 1, 31, 19, 30, 0, 0, time.UTC)) {
+		t.Errorf("From = %s, want 2025-01-31T19:30:00Z", filter.From)
+	}
+	// From falls in January in UTC, so the range covers three months
+	if got := filter.BucketCount(); got != 3 {
+		t.Errorf("BucketCount() = %d, want 3", got)
+	}
+}
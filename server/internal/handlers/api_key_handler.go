@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"server/internal/middleware"
+	"server/internal/models"
+	"server/internal/policy"
+	"server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// APIKeyHandler lets organization owners and admins manage the keys their
+// own systems call the API with.
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyService
+	authorizer    *policy.Authorizer
+	jwtService    services.JWTService
+}
+
+func NewAPIKeyHandler(apiKeyService services.APIKeyService, authorizer *policy.Authorizer, jwtService services.JWTService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		authorizer:    authorizer,
+		jwtService:    jwtService,
+	}
+}
+
+func (h *APIKeyHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/api/organization/api-keys", func(r chi.Router) {
+		r.Use(middleware.UserAuthMiddleware(h.jwtService))
+		r.Use(middleware.RequirePermission(h.authorizer, policy.APIKeysManage))
+
+		r.Get("/", h.ListAPIKeys)
+		r.With(middleware.RateLimit(20, time.Hour)).Post("/", h.CreateAPIKey)
+		r.Delete("/{ID}", h.RevokeAPIKey)
+	})
+}
+
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.APIKeysManage)
+	if !ok {
+		return
+	}
+
+	keys, err := h.apiKeyService.List(r.Context(), org.ID)
+	if err != nil {
+		log.Printf("failed to list API keys for organization %v: %v", org.ID, err)
+		http.Error(w, "Failed to fetch API keys", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(keys)
+}
+
+// CreateAPIKey issues a key. The response is the only time the key itself
+// is shown.
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.APIKeysManage)
+	if !ok {
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.apiKeyService.Create(r.Context(), org.ID, userID, &req)
+	if errors.Is(err, services.ErrTooManyAPIKeys) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// RevokeAPIKey stops a key working immediately.
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.APIKeysManage)
+	if !ok {
+		return
+	}
+
+	keyID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid API key ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.apiKeyService.Revoke(r.Context(), org.ID, keyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "API key not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("failed to revoke API key %v: %v", keyID, err)
+		http.Error(w, "Failed to revoke API key", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
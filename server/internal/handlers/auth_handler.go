@@ -1,30 +1,54 @@
 package handlers
 
 import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
+	"time"
 
+	"server/internal/config"
 	"server/internal/middleware"
 	"server/internal/models"
+	"server/internal/policy"
+	"server/internal/repository"
 	"server/internal/services"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/markbates/goth"
 	"github.com/markbates/goth/gothic"
 )
 
 type AuthHandler struct {
-	authService services.AuthService
-	jwtService  services.JWTService
+	authService      services.AuthService
+	sessionService   services.SessionService
+	accountService   services.AccountService
+	twoFactorService services.TwoFactorService
+	identityService  services.IdentityService
+	authorizer       *policy.Authorizer
+	jwtService       services.JWTService
+	apiKeyService    services.APIKeyService
 }
 
-func NewAuthHandler(authService services.AuthService, jwtService services.JWTService) *AuthHandler {
+func NewAuthHandler(authService services.AuthService, sessionService services.SessionService, accountService services.AccountService, twoFactorService services.TwoFactorService, identityService services.IdentityService, authorizer *policy.Authorizer, jwtService services.JWTService, apiKeyService services.APIKeyService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		jwtService:  jwtService,
+		authService:      authService,
+		sessionService:   sessionService,
+		accountService:   accountService,
+		twoFactorService: twoFactorService,
+		identityService:  identityService,
+		authorizer:       authorizer,
+		jwtService:       jwtService,
+		apiKeyService:    apiKeyService,
 	}
 }
 
@@ -34,14 +58,42 @@ func (h *AuthHandler) RegisterRoutes(r chi.Router) {
 		r.Post("/register/organization", h.RegisterOrganization)
 
 		r.Post("/login", h.Login)
-		r.Post("/logout", h.Logout)
+		r.Post("/refresh", h.Refresh)
+		r.With(middleware.OptionalAuthMiddleware(h.jwtService, h.apiKeyService)).Post("/logout", h.Logout)
+
+		// Links from verification and reset emails land here. The endpoints
+		// that send email are limited per IP, and per account in the service.
+		r.With(middleware.RateLimit(20, 15*time.Minute)).Post("/verify-email", h.VerifyEmail)
+		r.With(middleware.RateLimit(5, 15*time.Minute)).Post("/password/forgot", h.ForgotPassword)
+		r.With(middleware.RateLimit(10, 15*time.Minute)).Post("/password/reset", h.ResetPassword)
+
+		// Second step of a sign-in that returned a challenge instead of tokens.
+		r.With(middleware.RateLimit(10, 15*time.Minute)).Post("/2fa/challenge/enroll", h.BeginChallengeEnrollment)
+		r.With(middleware.RateLimit(20, 15*time.Minute)).Post("/2fa/challenge/verify", h.CompleteChallenge)
 
 		r.Group(func(protected chi.Router) {
-			protected.Use(middleware.AuthMiddleware(h.jwtService))
+			protected.Use(middleware.UserAuthMiddleware(h.jwtService))
 			protected.Get("/me", h.GetMe)
 			protected.Get("/me/organization", h.GetMyOrganization)
+			protected.With(middleware.RateLimit(5, 15*time.Minute)).Post("/verify-email/resend", h.ResendVerificationEmail)
+
+			protected.Get("/sessions", h.ListSessions)
+			protected.Post("/sessions/revoke-all", h.RevokeAllSessions)
+			protected.Delete("/sessions/{ID}", h.RevokeSession)
+
+			protected.Get("/2fa", h.GetTwoFactorStatus)
+			protected.Post("/2fa/enroll", h.BeginTwoFactorEnrollment)
+			protected.Post("/2fa/enroll/confirm", h.ConfirmTwoFactorEnrollment)
+			protected.Post("/2fa/recovery-codes", h.RegenerateRecoveryCodes)
+			protected.Post("/2fa/disable", h.DisableTwoFactor)
+
+			protected.Get("/identities", h.ListIdentities)
+			protected.With(middleware.RateLimit(10, 15*time.Minute)).Post("/identities", h.LinkIdentity)
+			protected.Delete("/identities/{provider}", h.UnlinkIdentity)
 		})
 
+		r.Get("/providers", h.ListProviders)
+
 		// Dynamic provider routes to work with chi and gothic
 		r.Get("/{provider}", h.BeginAuth)
 		r.Get("/{provider}/callback", h.CompleteAuth)
@@ -66,11 +118,12 @@ func (h *AuthHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	authResp, err := h.authService.RegisterUser(r.Context(), &req)
+	authResp, err := h.authService.RegisterUser(withClientInfo(r), &req)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	h.sendVerificationEmail(r, authResp.User.ID)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(authResp)
@@ -95,11 +148,12 @@ func (h *AuthHandler) RegisterOrganization(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	authResp, err := h.authService.RegisterOrganization(r.Context(), &req)
+	authResp, err := h.authService.RegisterOrganization(withClientInfo(r), &req)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	h.sendVerificationEmail(r, authResp.User.ID)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(authResp)
@@ -118,7 +172,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	authResp, err := h.authService.Login(r.Context(), &req)
+	authResp, err := h.authService.Login(withClientInfo(r), &req)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusUnauthorized)
 		return
@@ -146,20 +200,25 @@ func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(userResp)
 }
 
+// GetMyOrganization returns the organization the caller is a member of,
+// with the caller's role in it.
 func (h *AuthHandler) GetMyOrganization(w http.ResponseWriter, r *http.Request) {
-	userID, ok := middleware.GetUserIDFromContext(r.Context())
-	if !ok {
-		http.Error(w, "User not found", http.StatusUnauthorized)
+	subject, err := middleware.SubjectFromRequest(r, h.authorizer)
+	if err != nil {
+		middleware.WritePermissionError(w, err)
 		return
 	}
-
-	organization, err := h.authService.GetOrganizationByID(r.Context(), userID)
-	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+	if subject.Member == nil {
+		http.Error(w, "Organization not found", http.StatusNotFound)
+		return
+	}
+	if err := policy.Check(subject, policy.OrganizationView, policy.OfOrganization(subject.Member.OrganizationID)); err != nil {
+		middleware.WritePermissionError(w, err)
 		return
 	}
 
-	organizationResp := organization.ToOrganizationResponse()
+	organizationResp := subject.Member.Organization.ToOrganizationResponse()
+	organizationResp.MemberRole = subject.Member.Role
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(organizationResp)
 }
@@ -176,9 +235,45 @@ func (h *AuthHandler) BeginAuth(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "unsupported provider", http.StatusBadRequest)
 		return
 	}
+
+	// The state comes back to CompleteAuth, which is how it knows whether
+	// ?intent=link asked to link the account rather than sign in with it.
+	state, err := oauthState(r.URL.Query().Get("intent") == "link")
+	if err != nil {
+		http.Error(w, "Failed to start sign-in", http.StatusInternalServerError)
+		return
+	}
+	q := r.URL.Query()
+	q.Set("state", state)
+	r.URL.RawQuery = q.Encode()
+
 	gothic.BeginAuthHandler(w, r)
 }
 
+const linkStatePrefix = "link."
+
+// linkNonceKey is the gothic session value a link token is bound to.
+const linkNonceKey = "link_nonce"
+
+func oauthState(link bool) (string, error) {
+	state, err := newNonce()
+	if err != nil {
+		return "", err
+	}
+	if link {
+		state = linkStatePrefix + state
+	}
+	return state, nil
+}
+
+func newNonce() (string, error) {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(nonce), nil
+}
+
 // CompleteAuth completes the OAuth flow and logs the user in
 func (h *AuthHandler) CompleteAuth(w http.ResponseWriter, r *http.Request) {
 	// Complete the OAuth process
@@ -193,6 +288,39 @@ func (h *AuthHandler) CompleteAuth(w http.ResponseWriter, r *http.Request) {
 		provider = "google"
 	}
 
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:5173"
+	}
+	redirectURL, _ := url.Parse(frontendURL)
+	q := redirectURL.Query()
+
+	// Linking finishes in the app, as whoever is signed in there, and only
+	// from this browser: the token is bound to a nonce in its session
+	// cookie.
+	if strings.HasPrefix(gothic.GetState(r), linkStatePrefix) {
+		nonce, err := newNonce()
+		if err != nil {
+			http.Error(w, "Failed to link account", http.StatusInternalServerError)
+			return
+		}
+		if err := gothic.StoreInSession(linkNonceKey, nonce, r, w); err != nil {
+			http.Error(w, "Failed to link account", http.StatusInternalServerError)
+			return
+		}
+		token, err := h.identityService.LinkToken(provider, user.UserID, user.Email, nonce)
+		if err != nil {
+			http.Error(w, "Failed to link account", http.StatusInternalServerError)
+			return
+		}
+		redirectURL.Path = "/profile"
+		q.Set("link_token", token)
+		q.Set("provider", provider)
+		redirectURL.RawQuery = q.Encode()
+		http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+		return
+	}
+
 	fmt.Printf("%+v\n", user)
 
 	// Derive a friendly display name if provider didn't populate Name
@@ -226,13 +354,25 @@ func (h *AuthHandler) CompleteAuth(w http.ResponseWriter, r *http.Request) {
 
 	// Create or update user in database
 	authResp, err := h.authService.CreateOrUpdateOAuthUser(
-		r.Context(),
+		withClientInfo(r),
 		provider,
 		user.UserID,
 		displayName,
 		user.Email,
 		user.AvatarURL,
 	)
+	if errors.Is(err, services.ErrAccountExists) || errors.Is(err, services.ErrOAuthEmailMissing) {
+		redirectURL.Path = "/login"
+		if errors.Is(err, services.ErrAccountExists) {
+			q.Set("error", "account_exists")
+		} else {
+			q.Set("error", "oauth_email_missing")
+		}
+		q.Set("provider", provider)
+		redirectURL.RawQuery = q.Encode()
+		http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+		return
+	}
 	if err != nil {
 		fmt.Println(err)
 		http.Error(w, "Failed to create/update user", http.StatusInternalServerError)
@@ -240,25 +380,497 @@ func (h *AuthHandler) CompleteAuth(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Redirect back to frontend with token
-	frontendURL := os.Getenv("FRONTEND_URL")
-	if frontendURL == "" {
-		frontendURL = "http://localhost:5173"
+	redirectURL.Path = "/auth/callback"
+	if authResp.Challenge != nil {
+		q.Set("challenge", authResp.Challenge.Token)
+		if authResp.Challenge.EnrollmentRequired {
+			q.Set("enroll", "1")
+		}
+		redirectURL.RawQuery = q.Encode()
+		http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+		return
 	}
 
-	redirectURL, _ := url.Parse(frontendURL)
-	redirectURL.Path = "/auth/callback"
-	q := redirectURL.Query()
-	q.Set("token", authResp.Token)
+	// The tokens go in the fragment, which browsers never send to a server,
+	// so they stay out of access logs, proxies and Referer headers.
+	tokens := url.Values{}
+	tokens.Set("token", authResp.Token)
+	tokens.Set("refresh_token", authResp.RefreshToken)
 	redirectURL.RawQuery = q.Encode()
 
-	redirectURLString := redirectURL.String()
+	http.Redirect(w, r, redirectURL.String()+"#"+tokens.Encode(), http.StatusFound)
+}
+
+// Refresh exchanges a refresh token for a new token pair. Each refresh
+// token works once; presenting a used one again revokes the session.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := h.sessionService.Refresh(withClientInfo(r), req.RefreshToken)
+	if errors.Is(err, repository.ErrInvalidRefreshToken) || errors.Is(err, repository.ErrRefreshTokenReused) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to refresh session", http.StatusInternalServerError)
+		return
+	}
 
-	http.Redirect(w, r, redirectURLString, http.StatusFound)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(authResp)
 }
 
+// Logout revokes the session of the bearer token, or of the refresh token in
+// the body when the access token has already expired.
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
-	// In a real application, you might want to blacklist the token
-	// For now, we'll just return a success message
+	var err error
+	userID, hasUser := middleware.GetUserIDFromContext(r.Context())
+	sessionID, hasSession := middleware.GetSessionIDFromContext(r.Context())
+	if hasUser && hasSession {
+		err = h.sessionService.Revoke(r.Context(), userID, sessionID, models.SessionRevokedLogout)
+	} else {
+		var req models.RefreshTokenRequest
+		if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr == nil && req.RefreshToken != "" {
+			err = h.sessionService.RevokeByRefreshToken(r.Context(), req.RefreshToken)
+		}
+	}
+	// Logging out of a session that is already gone is not an error.
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Failed to log out", http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"message": "Logged out successfully"})
 }
+
+// ListSessions lists the caller's signed-in devices.
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+	sessionID, _ := middleware.GetSessionIDFromContext(r.Context())
+
+	sessions, err := h.sessionService.List(r.Context(), userID, sessionID)
+	if err != nil {
+		http.Error(w, "Failed to list sessions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sessions)
+}
+
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.sessionService.Revoke(r.Context(), userID, sessionID, models.SessionRevokedByUser)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to revoke session", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeAllSessions signs the caller out everywhere, including this device.
+func (h *AuthHandler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	revoked, err := h.sessionService.RevokeAll(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "Failed to revoke sessions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{"revoked": revoked})
+}
+
+// ListProviders lists the configured sign-in providers, for the login page.
+func (h *AuthHandler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(config.OAuthProviders())
+}
+
+// ListIdentities lists the provider accounts linked to the caller, and the
+// providers they could link.
+func (h *AuthHandler) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	identities, err := h.identityService.List(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "Failed to list linked accounts", http.StatusInternalServerError)
+		return
+	}
+	identities.Providers = config.OAuthProviders()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(identities)
+}
+
+// LinkIdentity links the provider account in the link_token that
+// GET /api/auth/{provider}?intent=link sent the app back with. The request
+// must carry the session cookie that callback set.
+func (h *AuthHandler) LinkIdentity(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.LinkIdentityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// A missing nonce fails the check in Link.
+	nonce, _ := gothic.GetFromSession(linkNonceKey, r)
+	identity, err := h.identityService.Link(r.Context(), userID, req.Token, nonce)
+	if err == nil {
+		gothic.Logout(w, r)
+	}
+	switch {
+	case errors.Is(err, services.ErrInvalidLinkToken):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, repository.ErrIdentityLinked):
+		http.Error(w, "This account is already linked to you or to someone else, or you already linked one from this provider", http.StatusConflict)
+		return
+	case err != nil:
+		log.Printf("failed to link identity for user %v: %v", userID, err)
+		http.Error(w, "Failed to link account", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(identity)
+}
+
+func (h *AuthHandler) UnlinkIdentity(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.identityService.Unlink(r.Context(), userID, chi.URLParam(r, "provider"))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "No linked account for this provider", http.StatusNotFound)
+		return
+	case errors.Is(err, services.ErrLastSignInMethod):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, "Failed to unlink account", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sendVerificationEmail emails a newly registered user. A failure is only
+// logged; the user can ask for another link.
+func (h *AuthHandler) sendVerificationEmail(r *http.Request, userID uuid.UUID) {
+	if err := h.accountService.SendVerificationEmail(r.Context(), userID); err != nil {
+		log.Printf("failed to send verification email to %s: %v", userID, err)
+	}
+}
+
+func (h *AuthHandler) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.accountService.SendVerificationEmail(r.Context(), userID)
+	if errors.Is(err, services.ErrTooManyEmails) {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Verification email sent"})
+}
+
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req models.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.accountService.VerifyEmail(r.Context(), req.Token)
+	if errors.Is(err, repository.ErrInvalidAccountToken) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to verify email", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user.ToUserResponse())
+}
+
+// ForgotPassword always answers the same way, whether or not the email
+// belongs to an account.
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Email == "" {
+		http.Error(w, "Email is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.accountService.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		log.Printf("password reset request failed: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "If an account uses this email, a reset link is on its way",
+	})
+}
+
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Password) < 6 {
+		http.Error(w, "Password must be at least 6 characters long", http.StatusBadRequest)
+		return
+	}
+
+	err := h.accountService.ResetPassword(r.Context(), req.Token, req.Password)
+	if errors.Is(err, repository.ErrInvalidAccountToken) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to reset password", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Password updated, sign in with your new password"})
+}
+
+func (h *AuthHandler) BeginChallengeEnrollment(w http.ResponseWriter, r *http.Request) {
+	var req models.LoginChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	enrollment, err := h.twoFactorService.BeginChallengeEnrollment(r.Context(), req.ChallengeToken)
+	if err != nil {
+		writeTwoFactorError(w, err, "Failed to start two-factor setup")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(enrollment)
+}
+
+// CompleteChallenge finishes a challenged sign-in with a TOTP or recovery
+// code and returns the tokens.
+func (h *AuthHandler) CompleteChallenge(w http.ResponseWriter, r *http.Request) {
+	var req models.LoginChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Code == "" {
+		http.Error(w, "Code is required", http.StatusBadRequest)
+		return
+	}
+
+	authResp, err := h.twoFactorService.CompleteChallenge(withClientInfo(r), req.ChallengeToken, req.Code)
+	if err != nil {
+		writeTwoFactorError(w, err, "Failed to sign in")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(authResp)
+}
+
+func (h *AuthHandler) GetTwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.authService.GetUserByID(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	status, err := h.twoFactorService.Status(r.Context(), user)
+	if err != nil {
+		http.Error(w, "Failed to get two-factor status", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+func (h *AuthHandler) BeginTwoFactorEnrollment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	enrollment, err := h.twoFactorService.BeginEnrollment(r.Context(), userID)
+	if err != nil {
+		writeTwoFactorError(w, err, "Failed to start two-factor setup")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(enrollment)
+}
+
+func (h *AuthHandler) ConfirmTwoFactorEnrollment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		http.Error(w, "Code is required", http.StatusBadRequest)
+		return
+	}
+
+	codes, err := h.twoFactorService.ConfirmEnrollment(r.Context(), userID, req.Code)
+	if err != nil {
+		writeTwoFactorError(w, err, "Failed to enable two-factor authentication")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		http.Error(w, "Code is required", http.StatusBadRequest)
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(r.Context(), userID, req.Code)
+	if err != nil {
+		writeTwoFactorError(w, err, "Failed to regenerate recovery codes")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *AuthHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		http.Error(w, "Code is required", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.authService.GetUserByID(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.twoFactorService.Disable(r.Context(), user, req.Code); err != nil {
+		writeTwoFactorError(w, err, "Failed to disable two-factor authentication")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Two-factor authentication disabled"})
+}
+
+// writeTwoFactorError maps two-factor errors to status codes and hides
+// anything unexpected behind fallback.
+func writeTwoFactorError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, repository.ErrInvalidLoginChallenge):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, services.ErrInvalidTwoFactorCode), errors.Is(err, services.ErrTwoFactorNotEnabled):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrTwoFactorAlreadyEnabled):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrTwoFactorRequired):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Printf("%s: %v", fallback, err)
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
+
+// withClientInfo labels any session started while handling r with the
+// caller's device.
+func withClientInfo(r *http.Request) context.Context {
+	return services.WithClientInfo(r.Context(), models.ClientInfo{
+		UserAgent: r.UserAgent(),
+		IPAddress: middleware.ClientIP(r),
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"mime"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -16,6 +17,8 @@ import (
 
 	"server/internal/middleware"
 	"server/internal/models"
+	"server/internal/policy"
+	"server/internal/repository"
 	"server/internal/services"
 
 	"github.com/go-chi/chi/v5"
@@ -24,60 +27,88 @@ import (
 
 type CauseHandler struct {
 	causeService       services.CauseService
-	authService        services.AuthService
+	authorizer         *policy.Authorizer
 	jwtService         services.JWTService
+	apiKeyService      services.APIKeyService
 	causeVoteService   services.CauseVoteService
 	causeReviewService services.CauseReviewService
 	ipfsService        services.IPFSService
+	lifecycleService   services.CauseLifecycleService
 }
 
 func NewCauseHandler(
 	causeService services.CauseService,
-	authService services.AuthService,
+	authorizer *policy.Authorizer,
 	jwtService services.JWTService,
+	apiKeyService services.APIKeyService,
 	causeVoteService services.CauseVoteService,
 	causeReviewService services.CauseReviewService,
 	ipfsService services.IPFSService,
+	lifecycleService services.CauseLifecycleService,
 ) *CauseHandler {
 	return &CauseHandler{
 		causeService:       causeService,
-		authService:        authService,
+		authorizer:         authorizer,
 		jwtService:         jwtService,
+		apiKeyService:      apiKeyService,
 		causeVoteService:   causeVoteService,
 		causeReviewService: causeReviewService,
 		ipfsService:        ipfsService,
+		lifecycleService:   lifecycleService,
 	}
 }
 
 func (c *CauseHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/api/causes", func(r chi.Router) {
 		r.Group(func(blood chi.Router) {
-			blood.Use(middleware.AuthMiddleware(c.jwtService))
+			blood.Use(middleware.UserAuthMiddleware(c.jwtService))
 			blood.Post("/blood", c.CreateCauseBlood)
 			blood.Get("/blood/eligibility", c.CheckBloodDonationEligibility)
 			blood.Post("/volunteer", c.CreateCauseVolunteer)
 		})
 
+		// Organization API keys with the updates:write scope can post updates
 		r.Group(func(protected chi.Router) {
-			protected.Use(middleware.AuthMiddleware(c.jwtService))
-			protected.Post("/", c.CreateCause)
-			protected.Post("/cover/upload", c.UploadCoverImage)
-			protected.Post("/products/upload", c.UploadProductImage)
+			protected.Use(middleware.AuthMiddleware(c.jwtService, c.apiKeyService))
 			protected.Post("/updates/upload/receipt", c.UploadUpdateReceipt)
 			// new {
 			protected.Get("/updates/receipt-status/{id}", c.GetReceiptStatus)
 			// }
 			protected.Post("/{ID}/updates", c.CreateCauseUpdate)
+		})
+
+		r.Group(func(protected chi.Router) {
+			protected.Use(middleware.UserAuthMiddleware(c.jwtService))
+			protected.Post("/", c.CreateCause)
+			protected.Post("/cover/upload", c.UploadCoverImage)
+			protected.Post("/products/upload", c.UploadProductImage)
 			protected.Post("/{ID}/upvote", c.UpvoteCause)
 			protected.Post("/{ID}/downvote", c.DownvoteCause)
 			protected.Get("/{ID}/votes", c.GetCauseVotes)
 			protected.Post("/{ID}/reviews", c.CreateCauseReview)
+			protected.Put("/{ID}/reviews/{reviewID}", c.UpdateCauseReview)
+			protected.Delete("/{ID}/reviews/{reviewID}", c.DeleteCauseReview)
+			protected.Post("/{ID}/reviews/{reviewID}/report", c.ReportCauseReview)
+			protected.Put("/{ID}/reviews/{reviewID}/response", c.ReplyToCauseReview)
+			protected.Delete("/{ID}/reviews/{reviewID}/response", c.DeleteCauseReviewReply)
+			protected.Patch("/{ID}", c.UpdateCause)
+			protected.Post("/{ID}/state", c.ChangeCauseState)
+			protected.Post("/{ID}/submit", c.SubmitCauseForReview)
 			protected.Delete("/{ID}", c.DeleteCause)
 		})
 
 		r.Get("/", c.GetAllCauses)
-		r.Get("/{ID}", c.GetCauseByID)
-		r.Get("/organization/{ID}", c.GetCauseByOrganizationID)
+		r.Get("/search", c.SearchCauses)
+		r.Get("/nearby", c.GetNearbyCauses)
+		r.Get("/map", c.GetCausesInBoundingBox)
+		// Owners and admins can also see unpublished causes here
+		r.Group(func(optional chi.Router) {
+			optional.Use(middleware.OptionalAuthMiddleware(c.jwtService, c.apiKeyService))
+			optional.Get("/{ID}", c.GetCauseByID)
+			optional.Get("/organization/{ID}", c.GetCauseByOrganizationID)
+		})
+		r.Get("/{ID}/revisions", c.GetCauseRevisions)
+		r.Get("/{ID}/state/history", c.GetCauseStateHistory)
 		r.Get("/{ID}/reviews", c.GetCauseReviews)
 		r.Get("/{ID}/reviews/count", c.GetCauseReviewCount)
 
@@ -178,17 +209,8 @@ func (c *CauseHandler) CreateCause(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userID, ok := middleware.GetUserIDFromContext(r.Context())
-
+	organization, ok := organizationForRequest(w, r, c.authorizer, policy.CauseCreate)
 	if !ok {
-		http.Error(w, "User not found", http.StatusUnauthorized)
-		return
-	}
-
-	organization, err := c.authService.GetOrganizationByID(r.Context(), userID)
-
-	if err != nil || organization == nil || organization.User.Role != string(models.RoleTypeOrganization) {
-		http.Error(w, "Not authenticated", http.StatusUnauthorized)
 		return
 	}
 
@@ -222,10 +244,22 @@ func (c *CauseHandler) GetCauseByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !cause.State.IsPublic() && !c.canViewUnpublished(r, cause.Organization.ID) {
+		http.Error(w, "cause not found", http.StatusNotFound)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(cause.ToCauseResponse())
 }
 
+// canViewUnpublished reports whether the caller is an admin or a member of
+// the organization that owns the cause. Callers without a token are
+// anonymous.
+func (c *CauseHandler) canViewUnpublished(r *http.Request, organizationID uuid.UUID) bool {
+	return allowed(r, c.authorizer, policy.CauseViewUnpublished, policy.OfOrganization(organizationID))
+}
+
 func (c *CauseHandler) GetCauseByOrganizationID(w http.ResponseWriter, r *http.Request) {
 	ID, err := uuid.Parse(chi.URLParam(r, "ID"))
 	if err != nil {
@@ -238,7 +272,7 @@ func (c *CauseHandler) GetCauseByOrganizationID(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	causesResult, err := c.causeService.GetByOrganizationID(r.Context(), ID)
+	causesResult, err := c.causeService.GetByOrganizationID(r.Context(), ID, c.canViewUnpublished(r, ID))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -283,7 +317,7 @@ func (c *CauseHandler) CreateCauseReview(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	review, err := c.causeReviewService.CreateReview(r.Context(), causeID, userID, reviewText)
+	review, err := c.causeReviewService.CreateReview(r.Context(), causeID, userID, reviewText, req.Rating)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -293,6 +327,180 @@ func (c *CauseHandler) CreateCauseReview(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(review)
 }
 
+// reviewOfCause returns the {reviewID} URL param, answering 404 if that
+// review isn't on cause {ID}.
+func (c *CauseHandler) reviewOfCause(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	causeID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid cause ID", http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	reviewID, err := uuid.Parse(chi.URLParam(r, "reviewID"))
+	if err != nil {
+		http.Error(w, "Invalid review ID", http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+
+	review, err := c.causeReviewService.GetReviewByID(r.Context(), reviewID)
+	if errors.Is(err, repository.ErrReviewNotFound) || (err == nil && review.CauseID != causeID) {
+		http.Error(w, "Review not found", http.StatusNotFound)
+		return uuid.Nil, false
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return uuid.Nil, false
+	}
+	return reviewID, true
+}
+
+func (c *CauseHandler) UpdateCauseReview(w http.ResponseWriter, r *http.Request) {
+	reviewID, ok := c.reviewOfCause(w, r)
+	if !ok {
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.UpdateCauseReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.ReviewText != nil {
+		trimmed := strings.TrimSpace(*req.ReviewText)
+		req.ReviewText = &trimmed
+	}
+
+	review, err := c.causeReviewService.UpdateReview(r.Context(), reviewID, userID, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+func (c *CauseHandler) DeleteCauseReview(w http.ResponseWriter, r *http.Request) {
+	reviewID, ok := c.reviewOfCause(w, r)
+	if !ok {
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	if err := c.causeReviewService.DeleteReview(r.Context(), reviewID, userID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "review deleted successfully",
+	})
+}
+
+func (c *CauseHandler) ReportCauseReview(w http.ResponseWriter, r *http.Request) {
+	reviewID, ok := c.reviewOfCause(w, r)
+	if !ok {
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.ReportCauseReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.causeReviewService.ReportReview(r.Context(), reviewID, userID, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "review reported",
+	})
+}
+
+// ReplyToCauseReview creates or replaces the organization's official
+// response to a review on one of its causes.
+func (c *CauseHandler) ReplyToCauseReview(w http.ResponseWriter, r *http.Request) {
+	reviewID, ok := c.reviewOfCause(w, r)
+	if !ok {
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	organization, ok := organizationForRequest(w, r, c.authorizer, policy.CauseReviewReply)
+	if !ok {
+		return
+	}
+
+	var req models.CauseReviewReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	responseText := strings.TrimSpace(req.ResponseText)
+	if responseText == "" {
+		http.Error(w, "response_text is required", http.StatusBadRequest)
+		return
+	}
+
+	reply, err := c.causeReviewService.ReplyToReview(r.Context(), reviewID, organization.ID, userID, responseText)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply)
+}
+
+func (c *CauseHandler) DeleteCauseReviewReply(w http.ResponseWriter, r *http.Request) {
+	reviewID, ok := c.reviewOfCause(w, r)
+	if !ok {
+		return
+	}
+
+	organization, ok := organizationForRequest(w, r, c.authorizer, policy.CauseReviewReply)
+	if !ok {
+		return
+	}
+
+	if err := c.causeReviewService.DeleteReply(r.Context(), reviewID, organization.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "response deleted successfully",
+	})
+}
+
 func (c *CauseHandler) GetCauseReviews(w http.ResponseWriter, r *http.Request) {
 	ID, err := uuid.Parse(chi.URLParam(r, "ID"))
 	if err != nil {
@@ -300,7 +508,13 @@ func (c *CauseHandler) GetCauseReviews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	reviews, err := c.causeReviewService.GetReviewsByCauseID(r.Context(), ID)
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reviews, err := c.causeReviewService.GetReviewsByCauseID(r.Context(), ID, params)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -445,14 +659,20 @@ func (c *CauseHandler) GetCauseByDomainID(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	causesResult, err := c.causeService.GetByDomainID(r.Context(), ID)
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	causesResult, err := c.causeService.GetByDomainID(r.Context(), ID, params)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(causesResult)
+	json.NewEncoder(w).Encode(causesResult.Response(params))
 }
 
 func (c *CauseHandler) GetCauseByAidTypeID(w http.ResponseWriter, r *http.Request) {
@@ -467,71 +687,226 @@ func (c *CauseHandler) GetCauseByAidTypeID(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	causesResult, err := c.causeService.GetByAidTypeID(r.Context(), ID)
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	causesResult, err := c.causeService.GetByAidTypeID(r.Context(), ID, params)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(causesResult)
+	json.NewEncoder(w).Encode(causesResult.Response(params))
 }
 
+// GetAllCauses lists public causes, newest first, one cursor page at a time.
 func (c *CauseHandler) GetAllCauses(w http.ResponseWriter, r *http.Request) {
-	limit := r.URL.Query().Get("limit")
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	var ctx context.Context
+	causes, err := c.causeService.GetAllPaginated(r.Context(), params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	if limit != "" {
-		_, err := strconv.Atoi(limit)
-		if err != nil {
-			http.Error(w, "Limit Parameter is invalid", http.StatusBadRequest)
-			return
-		}
-		ctx = context.WithValue(r.Context(), "limit", limit)
-	} else {
-		ctx = r.Context()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(causes.Response(params))
+}
+
+// organizationCause loads a cause the organization owns. A cause of another
+// organization is forbidden.
+func (c *CauseHandler) organizationCause(w http.ResponseWriter, r *http.Request, id uuid.UUID, organization *models.Organization) (*models.Cause, bool) {
+	cause, err := c.causeService.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Cause not found", http.StatusNotFound)
+		return nil, false
 	}
+	if cause.Organization.ID != organization.ID {
+		http.Error(w, "Not authorized for this cause", http.StatusForbidden)
+		return nil, false
+	}
+	return cause, true
+}
 
-	causesResult, err := c.causeService.GetAll(ctx)
+func (c *CauseHandler) UpdateCause(w http.ResponseWriter, r *http.Request) {
+	ID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	organization, ok := organizationForRequest(w, r, c.authorizer, policy.CauseUpdate)
+	if !ok {
+		return
+	}
+
+	if _, ok := c.organizationCause(w, r, ID, organization); !ok {
+		return
+	}
+
+	var req models.UpdateCauseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	updated, revision, err := c.causeService.Update(r.Context(), ID, userID, &req)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(causesResult)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"cause":    updated.ToCauseResponse(),
+		"revision": revision,
+	})
 }
 
-func (c *CauseHandler) DeleteCause(w http.ResponseWriter, r *http.Request) {
+// GetCauseRevisions returns the public edit history of a cause, newest first.
+func (c *CauseHandler) GetCauseRevisions(w http.ResponseWriter, r *http.Request) {
 	ID, err := uuid.Parse(chi.URLParam(r, "ID"))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if ID.String() == "" {
-		http.Error(w, "CauseID required", http.StatusBadRequest)
+	revisions, err := c.causeService.GetRevisions(r.Context(), ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// ChangeCauseState moves a cause through its lifecycle. Admins may take any
+// legal transition; organizations only those allowed for the cause owner.
+func (c *CauseHandler) ChangeCauseState(w http.ResponseWriter, r *http.Request) {
+	ID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.ChangeCauseStateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	actor := models.CauseStateActorAdmin
+	if !allowed(r, c.authorizer, policy.CauseModerate, policy.Resource{}) {
+		cause, err := c.causeService.GetByID(r.Context(), ID)
+		if err != nil {
+			http.Error(w, "Cause not found", http.StatusNotFound)
+			return
+		}
+		if _, ok := authorize(w, r, c.authorizer, policy.CausePublish, policy.OfOrganization(cause.Organization.ID)); !ok {
+			return
+		}
+		actor = models.CauseStateActorOrganization
+	}
+
+	cause, err := c.lifecycleService.Transition(r.Context(), ID, req.State, actor, &userID, req.Reason)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cause.ToCauseResponse())
+}
+
+// SubmitCauseForReview sends a draft cause to the admin publication queue.
+func (c *CauseHandler) SubmitCauseForReview(w http.ResponseWriter, r *http.Request) {
+	ID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
 	if !ok {
 		http.Error(w, "User not found", http.StatusUnauthorized)
 		return
 	}
 
-	organization, err := c.authService.GetOrganizationByID(r.Context(), userID)
-	if err != nil || organization == nil || organization.User.Role != string(models.RoleTypeOrganization) {
-		http.Error(w, "Not authorized", http.StatusUnauthorized)
+	organization, ok := organizationForRequest(w, r, c.authorizer, policy.CausePublish)
+	if !ok {
 		return
 	}
 
-	cause, err := c.causeService.GetByID(r.Context(), ID)
+	if _, ok := c.organizationCause(w, r, ID, organization); !ok {
+		return
+	}
+
+	cause, err := c.lifecycleService.SubmitForReview(r.Context(), ID, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cause.ToCauseResponse())
+}
 
-	if err != nil || cause.Organization.ID != organization.ID {
-		http.Error(w, "Not authorized", http.StatusUnauthorized)
+func (c *CauseHandler) GetCauseStateHistory(w http.ResponseWriter, r *http.Request) {
+	ID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	history, err := c.lifecycleService.GetHistory(r.Context(), ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+func (c *CauseHandler) DeleteCause(w http.ResponseWriter, r *http.Request) {
+	ID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if ID.String() == "" {
+		http.Error(w, "CauseID required", http.StatusBadRequest)
+		return
+	}
+
+	organization, ok := organizationForRequest(w, r, c.authorizer, policy.CauseDelete)
+	if !ok {
+		return
+	}
+
+	if _, ok := c.organizationCause(w, r, ID, organization); !ok {
 		return
 	}
 
@@ -617,15 +992,8 @@ func (c *CauseHandler) GetAidTypeByID(w http.ResponseWriter, r *http.Request) {
 // UploadUpdateReceipt handles upload of receipt images for execution updates.
 // Works similarly to UploadProductImage but stores under uploads/receipts.
 func (c *CauseHandler) UploadUpdateReceipt(w http.ResponseWriter, r *http.Request) {
-	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	org, ok := organizationForRequest(w, r, c.authorizer, policy.UpdatePost)
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	org, err := c.authService.GetOrganizationByID(r.Context(), userID)
-	if err != nil || org == nil || org.User.Role != string(models.RoleTypeOrganization) {
-		http.Error(w, "Not authorized", http.StatusUnauthorized)
 		return
 	}
 
@@ -712,15 +1080,8 @@ func (c *CauseHandler) GetReceiptStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	org, ok := organizationForRequest(w, r, c.authorizer, policy.UpdatePost)
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	org, err := c.authService.GetOrganizationByID(r.Context(), userID)
-	if err != nil || org == nil || org.User.Role != string(models.RoleTypeOrganization) {
-		http.Error(w, "Not authorized", http.StatusUnauthorized)
 		return
 	}
 
@@ -775,15 +1136,8 @@ func (c *CauseHandler) CreateCauseUpdate(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	org, ok := organizationForRequest(w, r, c.authorizer, policy.UpdatePost)
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	org, err := c.authService.GetOrganizationByID(r.Context(), userID)
-	if err != nil || org == nil || org.User.Role != string(models.RoleTypeOrganization) {
-		http.Error(w, "Not authorized", http.StatusUnauthorized)
 		return
 	}
 
@@ -816,15 +1170,8 @@ func (c *CauseHandler) CreateCauseUpdate(w http.ResponseWriter, r *http.Request)
 // returning a public URL path that can be saved as cover_image_url.
 func (c *CauseHandler) UploadCoverImage(w http.ResponseWriter, r *http.Request) {
 	// Ensure requester is an authenticated organization (same as CreateCause)
-	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	_, ok := organizationForRequest(w, r, c.authorizer, policy.CauseUpdate)
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	org, err := c.authService.GetOrganizationByID(r.Context(), userID)
-	if err != nil || org == nil || org.User.Role != string(models.RoleTypeOrganization) {
-		http.Error(w, "Not authorized", http.StatusUnauthorized)
 		return
 	}
 
@@ -902,15 +1249,8 @@ func (c *CauseHandler) UploadCoverImage(w http.ResponseWriter, r *http.Request)
 // a URL that can be saved in the cause_products.image_url column.
 func (c *CauseHandler) UploadProductImage(w http.ResponseWriter, r *http.Request) {
 	// Reuse the same authentication as CreateCause
-	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	_, ok := organizationForRequest(w, r, c.authorizer, policy.CauseUpdate)
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	org, err := c.authService.GetOrganizationByID(r.Context(), userID)
-	if err != nil || org == nil || org.User.Role != string(models.RoleTypeOrganization) {
-		http.Error(w, "Not authorized", http.StatusUnauthorized)
 		return
 	}
 
@@ -980,20 +1320,191 @@ func (c *CauseHandler) UploadProductImage(w http.ResponseWriter, r *http.Request
 	})
 }
 
-// GetAllCausesPaginated returns causes with pagination support
-func (c *CauseHandler) GetAllCausesPaginated(w http.ResponseWriter, r *http.Request) {
-	// Get pagination params
-	params := models.GetPaginationParams(r)
+// SearchCauses runs a full-text query with optional facets over public causes.
+//
+// Query params: q, domain_id, aid_type_id, funding_status, region (the
+// cause's location_state), city, trust_band (each repeatable or comma
+// separated), deadline_from, deadline_to (RFC3339 or YYYY-MM-DD), sort
+// (relevance, newest, ending_soon, most_funded, closest_to_goal), limit,
+// cursor. A cursor only works with the sort it was issued for.
+func (c *CauseHandler) SearchCauses(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseCauseSearchFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.Page = params
+
+	result, err := c.causeService.Search(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	causes := make([]models.CauseResponse, 0, len(result.Causes.Items))
+	for _, cause := range result.Causes.Items {
+		causes = append(causes, cause.ToCauseResponse())
+	}
+
+	response := result.Causes.Response(params)
+	response.Data = causes
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.CauseSearchResponse{
+		CursorPage: response,
+		Total:      result.Total,
+		Facets:     result.Facets,
+	})
+}
+
+func parseCauseSearchFilter(r *http.Request) (*models.CauseSearchFilter, error) {
+	q := r.URL.Query()
+
+	filter := &models.CauseSearchFilter{
+		Query:           strings.TrimSpace(q.Get("q")),
+		FundingStatuses: queryValues(q, "funding_status"),
+		Regions:         queryValues(q, "region"),
+		Cities:          queryValues(q, "city"),
+		Sort:            models.CauseSearchSort(q.Get("sort")),
+	}
+
+	for _, raw := range queryValues(q, "domain_id") {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, errors.New("Invalid domain_id")
+		}
+		filter.DomainIDs = append(filter.DomainIDs, id)
+	}
+	for _, raw := range queryValues(q, "aid_type_id") {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, errors.New("Invalid aid_type_id")
+		}
+		filter.AidTypeIDs = append(filter.AidTypeIDs, id)
+	}
+	for _, band := range queryValues(q, "trust_band") {
+		filter.TrustBands = append(filter.TrustBands, models.TrustBand(band))
+	}
+
+	if raw := q.Get("deadline_from"); raw != "" {
+		t, err := parseQueryTime(raw)
+		if err != nil {
+			return nil, errors.New("Invalid deadline_from")
+		}
+		filter.DeadlineFrom = &t
+	}
+	if raw := q.Get("deadline_to"); raw != "" {
+		t, err := parseQueryTime(raw)
+		if err != nil {
+			return nil, errors.New("Invalid deadline_to")
+		}
+		filter.DeadlineTo = &t
+	}
+
+	return filter, nil
+}
+
+// queryValues collects a parameter given either repeated or comma separated.
+func queryValues(q url.Values, key string) []string {
+	var values []string
+	for _, raw := range q[key] {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// GetNearbyCauses returns causes closest to a point.
+//
+// Query params: lat, lng (required), radius in meters (default 10km), limit
+// and cursor.
+func (c *CauseHandler) GetNearbyCauses(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	lat, errLat := strconv.ParseFloat(q.Get("lat"), 64)
+	lng, errLng := strconv.ParseFloat(q.Get("lng"), 64)
+	if errLat != nil || errLng != nil {
+		http.Error(w, "lat and lng are required", http.StatusBadRequest)
+		return
+	}
 
-	// Get paginated causes
-	causes, total, err := c.causeService.GetAllPaginated(r.Context(), params.PerPage, params.Offset)
+	params, err := models.GetCursorParams(r)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Create paginated response
-	response := models.NewPaginatedResponse(causes, params.Page, params.PerPage, total)
+	query := &models.NearbyCausesQuery{Lat: lat, Lng: lng, Page: params}
+	if raw := q.Get("radius"); raw != "" {
+		radius, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			http.Error(w, "Invalid radius", http.StatusBadRequest)
+			return
+		}
+		query.RadiusMeters = radius
+	}
+
+	nearby, err := c.causeService.GetNearby(r.Context(), query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	causes := make([]models.NearbyCauseResponse, 0, len(nearby.Items))
+	for _, n := range nearby.Items {
+		causes = append(causes, models.NearbyCauseResponse{
+			CauseResponse:  n.Cause.ToCauseResponse(),
+			DistanceMeters: n.DistanceMeters,
+		})
+	}
+
+	response := nearby.Response(query.Page)
+	response.Data = causes
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetCausesInBoundingBox returns map pins for causes inside the visible area.
+//
+// Query params: min_lat, min_lng, max_lat, max_lng (required) and limit.
+func (c *CauseHandler) GetCausesInBoundingBox(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var coords [4]float64
+	for i, key := range []string{"min_lat", "min_lng", "max_lat", "max_lng"} {
+		v, err := strconv.ParseFloat(q.Get(key), 64)
+		if err != nil {
+			http.Error(w, key+" is required", http.StatusBadRequest)
+			return
+		}
+		coords[i] = v
+	}
+
+	box := &models.CauseBoundingBox{MinLat: coords[0], MinLng: coords[1], MaxLat: coords[2], MaxLng: coords[3]}
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			http.Error(w, "Limit Parameter is invalid", http.StatusBadRequest)
+			return
+		}
+		box.Limit = limit
+	}
+
+	response, err := c.causeService.GetInBoundingBox(r.Context(), box)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -5,35 +5,38 @@ import (
 	"net/http"
 	"server/internal/middleware"
 	"server/internal/models"
+	"server/internal/policy"
 	"server/internal/repository"
 	"server/internal/services"
-	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 type DisbursementHandler struct {
-	disbursementRepo  repository.DisbursementRepository
-	organizationRepo  repository.OrganizationRepository
-	jwtService        services.JWTService
+	disbursementRepo repository.DisbursementRepository
+	authorizer       *policy.Authorizer
+	jwtService       services.JWTService
+	apiKeyService    services.APIKeyService
 }
 
 func NewDisbursementHandler(
 	disbursementRepo repository.DisbursementRepository,
-	organizationRepo repository.OrganizationRepository,
+	authorizer *policy.Authorizer,
 	jwtService services.JWTService,
+	apiKeyService services.APIKeyService,
 ) *DisbursementHandler {
 	return &DisbursementHandler{
-		disbursementRepo:  disbursementRepo,
-		organizationRepo:  organizationRepo,
-		jwtService:        jwtService,
+		disbursementRepo: disbursementRepo,
+		authorizer:       authorizer,
+		jwtService:       jwtService,
+		apiKeyService:    apiKeyService,
 	}
 }
 
 func (h *DisbursementHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/api/disbursements", func(r chi.Router) {
-		r.Use(middleware.AuthMiddleware(h.jwtService))
+		r.Use(middleware.AuthMiddleware(h.jwtService, h.apiKeyService))
 		r.Get("/my-organization", h.GetMyOrganizationDisbursements)
 		r.Get("/cause/{causeID}", h.GetCauseDisbursements)
 	})
@@ -41,40 +44,19 @@ func (h *DisbursementHandler) RegisterRoutes(r chi.Router) {
 
 // GetMyOrganizationDisbursements returns disbursements for the authenticated organization
 func (h *DisbursementHandler) GetMyOrganizationDisbursements(w http.ResponseWriter, r *http.Request) {
-	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	organization, ok := organizationForRequest(w, r, h.authorizer, policy.DisbursementView)
 	if !ok {
-		http.Error(w, "User not found", http.StatusUnauthorized)
 		return
 	}
 
-	// Get organization by user ID
-	organization, err := h.organizationRepo.GetByID(r.Context(), userID)
-	if err != nil || organization == nil {
-		http.Error(w, "Organization not found", http.StatusNotFound)
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Parse pagination parameters
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-	
-	limit := 50
-	offset := 0
-	
-	if limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
-			limit = parsedLimit
-		}
-	}
-	
-	if offsetStr != "" {
-		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
-			offset = parsedOffset
-		}
-	}
-
 	// Get disbursements
-	disbursements, err := h.disbursementRepo.GetByOrganizationID(r.Context(), organization.ID, limit, offset)
+	disbursements, err := h.disbursementRepo.GetByOrganizationID(r.Context(), organization.ID, params)
 	if err != nil {
 		http.Error(w, "Failed to fetch disbursements", http.StatusInternalServerError)
 		return
@@ -88,8 +70,8 @@ func (h *DisbursementHandler) GetMyOrganizationDisbursements(w http.ResponseWrit
 	}
 
 	// Convert to response format
-	var responses []models.DisbursementResponse
-	for _, d := range disbursements {
+	responses := make([]models.DisbursementResponse, 0, len(disbursements.Items))
+	for _, d := range disbursements.Items {
 		responses = append(responses, d.ToResponse())
 	}
 
@@ -97,8 +79,9 @@ func (h *DisbursementHandler) GetMyOrganizationDisbursements(w http.ResponseWrit
 	response := map[string]interface{}{
 		"disbursements": responses,
 		"total":         total,
-		"limit":         limit,
-		"offset":        offset,
+		"limit":         params.Limit,
+		"next_cursor":   disbursements.NextCursor,
+		"prev_cursor":   disbursements.PrevCursor,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"server/internal/middleware"
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type DisputeHandler struct {
+	disputeService services.DisputeService
+	jwtService     services.JWTService
+}
+
+func NewDisputeHandler(disputeService services.DisputeService, jwtService services.JWTService) *DisputeHandler {
+	return &DisputeHandler{
+		disputeService: disputeService,
+		jwtService:     jwtService,
+	}
+}
+
+func (h *DisputeHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/api/disputes", func(r chi.Router) {
+		r.Use(middleware.UserAuthMiddleware(h.jwtService))
+		r.With(middleware.RateLimit(5, time.Hour)).Post("/", h.OpenDispute)
+	})
+}
+
+// OpenDispute raises a dispute about a public cause for the platform to
+// review.
+func (h *DisputeHandler) OpenDispute(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.OpenDisputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	dispute, err := h.disputeService.Open(r.Context(), userID, &req)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Cause not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dispute)
+}
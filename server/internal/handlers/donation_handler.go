@@ -2,10 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+	"time"
 
 	"server/internal/middleware"
 	"server/internal/models"
+	"server/internal/policy"
+	"server/internal/repository"
 	"server/internal/services"
 
 	"github.com/go-chi/chi/v5"
@@ -13,16 +18,35 @@ import (
 )
 
 type DonationHandler struct {
-	donationService services.DonationService
-	authService     services.AuthService
-	jwtService      services.JWTService
+	donationService  services.DonationService
+	causeService     services.CauseService
+	authService      services.AuthService
+	accountService   services.AccountService
+	piiAccessLogRepo repository.PIIAccessLogRepository
+	authorizer       *policy.Authorizer
+	jwtService       services.JWTService
+	apiKeyService    services.APIKeyService
 }
 
-func NewDonationHandler(donationService services.DonationService, authService services.AuthService, jwtService services.JWTService) *DonationHandler {
+func NewDonationHandler(
+	donationService services.DonationService,
+	causeService services.CauseService,
+	authService services.AuthService,
+	accountService services.AccountService,
+	piiAccessLogRepo repository.PIIAccessLogRepository,
+	authorizer *policy.Authorizer,
+	jwtService services.JWTService,
+	apiKeyService services.APIKeyService,
+) *DonationHandler {
 	return &DonationHandler{
-		donationService: donationService,
-		authService:     authService,
-		jwtService:      jwtService,
+		donationService:  donationService,
+		causeService:     causeService,
+		authService:      authService,
+		accountService:   accountService,
+		piiAccessLogRepo: piiAccessLogRepo,
+		authorizer:       authorizer,
+		jwtService:       jwtService,
+		apiKeyService:    apiKeyService,
 	}
 }
 
@@ -30,15 +54,26 @@ func (c *DonationHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/api/donations", func(r chi.Router) {
 
 		r.Group(func(protected chi.Router) {
-			protected.Use(middleware.AuthMiddleware(c.jwtService))
+			protected.Use(middleware.UserAuthMiddleware(c.jwtService))
 			protected.Post("/", c.CreateDonation)
 			protected.Get("/user/me", c.GetDonationByUserID)
 			// protected.Delete("/{ID}", c.DeleteDonation)
 		})
 
-		r.Get("/{ID}", c.GetDonationByID)
+		// Organization API keys with the donations:read scope can list donors
+		r.Group(func(protected chi.Router) {
+			protected.Use(middleware.AuthMiddleware(c.jwtService, c.apiKeyService))
+			protected.Get("/cause/{ID}/donors", c.GetCauseDonors)
+		})
+
+		// The donor, the cause's organization and admins also see the
+		// donor's contact details and PAN here
+		r.Group(func(optional chi.Router) {
+			optional.Use(middleware.OptionalAuthMiddleware(c.jwtService, c.apiKeyService))
+			optional.Get("/{ID}", c.GetDonationByID)
+			optional.Get("/payment/{ID}", c.GetDonationByPaymentID)
+		})
 		r.Get("/cause/{ID}", c.GetDonationByCauseID)
-		r.Get("/payment/{ID}", c.GetDonationByPaymentID)
 
 		r.Get("/chain/{ID}", c.GetDonationFromChainByID)
 		r.Get("/chain/cause/{ID}", c.GetDonationFromChainByCauseID)
@@ -50,6 +85,7 @@ func GetUserFromContext(w http.ResponseWriter, r *http.Request, c *DonationHandl
 
 	if !ok {
 		http.Error(w, "User not found", http.StatusUnauthorized)
+		return nil, middleware.ErrUnauthenticated
 	}
 
 	user, err := c.authService.GetUserByID(r.Context(), userID)
@@ -69,17 +105,34 @@ func (c *DonationHandler) CreateDonation(w http.ResponseWriter, r *http.Request)
 	}
 
 	user, err := GetUserFromContext(w, r, c)
+	if err != nil {
+		return
+	}
 
 	if *req.Name == "" {
 		req.Name = &user.Name
 	}
 
-	if req.CauseID.String() == "" || req.UserID.String() == "" || *req.Name == "" || req.Phone == "" || req.Amount == 0.0 {
+	if req.CauseID.String() == "" || req.UserID.String() == "" || *req.Name == "" || req.Phone == "" || req.Amount <= 0 {
 		http.Error(w, "CauseID, UserID, Name, Phone, Amount is required", http.StatusBadRequest)
 		return
 	}
 
+	err = c.accountService.CheckDonationAllowed(r.Context(), user.ID, req.Amount.Rupees())
+	if errors.Is(err, services.ErrEmailNotVerified) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to check account", http.StatusInternalServerError)
+		return
+	}
+
 	donation, err := c.donationService.Create(r.Context(), &req)
+	if errors.Is(err, repository.ErrProductOversubscribed) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -107,6 +160,9 @@ func GetIDFromURL(w http.ResponseWriter, r *http.Request) (*uuid.UUID, error) {
 
 func (c *DonationHandler) GetDonationByID(w http.ResponseWriter, r *http.Request) {
 	ID, err := GetIDFromURL(w, r)
+	if err != nil {
+		return
+	}
 
 	donation, err := c.donationService.GetByID(r.Context(), *ID)
 	if err != nil {
@@ -114,14 +170,131 @@ func (c *DonationHandler) GetDonationByID(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	c.writeDonation(w, r, donation)
+}
+
+// writeDonation sends the private view to callers allowed to see the
+// donor's personal data and the public view to everyone else.
+func (c *DonationHandler) writeDonation(w http.ResponseWriter, r *http.Request, donation *models.Donation) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(donation.ToDonationResponse())
+	if c.canViewDonorPII(r, donation) {
+		json.NewEncoder(w).Encode(donation.ToDonationResponse())
+		return
+	}
+	json.NewEncoder(w).Encode(donation.ToPublicDonationResponse())
+}
+
+// canViewDonorPII reports whether the caller is the donor, a member of the
+// organization that owns the cause who may see donors, or an admin. Reads
+// by anyone but the donor are written to the PII access log, and are
+// refused if that fails.
+func (c *DonationHandler) canViewDonorPII(r *http.Request, donation *models.Donation) bool {
+	cause, err := c.causeService.GetByID(r.Context(), donation.CauseID)
+	if err != nil {
+		return false
+	}
+	res := policy.Resource{OrganizationID: cause.Organization.ID, OwnerID: donation.UserID}
+	s, err := middleware.Authorize(r, c.authorizer, policy.DonorView, res)
+	if err != nil {
+		return false
+	}
+	if s.APIKey == nil && s.UserID == donation.UserID {
+		return true
+	}
+
+	return c.logPIIAccess(r, s, "donation", donation.ID, models.PIIPurposeDonationDetail) == nil
+}
+
+// logPIIAccess records that s read PII. Reads with an API key are
+// attributed to the member who created the key.
+func (c *DonationHandler) logPIIAccess(r *http.Request, s *policy.Subject, resourceType string, resourceID uuid.UUID, purpose string) error {
+	actorID, role := &s.UserID, string(s.AccountRole)
+	if s.APIKey != nil {
+		actorID, role = s.APIKey.CreatedBy, "api_key"
+	}
+
+	err := c.piiAccessLogRepo.Create(r.Context(), &models.PIIAccessLog{
+		ID:           uuid.New(),
+		ActorID:      actorID,
+		ActorRole:    role,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Purpose:      purpose,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		log.Printf("Warning: failed to log PII access by %s to %s %v: %v", role, resourceType, resourceID, err)
+	}
+	return err
+}
+
+// GetCauseDonors lists a cause's donations with the donors' details for
+// the owning organization and admins. Phone, address and PAN are masked
+// unless ?unmask=true, which is recorded in the PII access log.
+func (c *DonationHandler) GetCauseDonors(w http.ResponseWriter, r *http.Request) {
+	causeID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid cause ID", http.StatusBadRequest)
+		return
+	}
+
+	cause, err := c.causeService.GetByID(r.Context(), causeID)
+	if err != nil {
+		http.Error(w, "Cause not found", http.StatusNotFound)
+		return
+	}
+
+	subject, ok := authorize(w, r, c.authorizer, policy.DonorView, policy.OfOrganization(cause.Organization.ID))
+	if !ok {
+		return
+	}
+
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	unmask := r.URL.Query().Get("unmask") == "true"
+	if unmask {
+		if err := c.logPIIAccess(r, subject, "cause", causeID, models.PIIPurposeCauseDonorList); err != nil {
+			http.Error(w, "Failed to record access", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	donationsResult, err := c.donationService.GetByCauseID(r.Context(), causeID, params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	donationsResponse := make([]*models.CreateDonationResponse, 0, len(donationsResult.Items))
+	for _, donation := range donationsResult.Items {
+		if unmask {
+			donationsResponse = append(donationsResponse, donation.ToDonationResponse())
+		} else {
+			donationsResponse = append(donationsResponse, donation.ToMaskedDonationResponse())
+		}
+	}
+
+	response := donationsResult.Response(params)
+	response.Data = donationsResponse
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
 func (c *DonationHandler) GetDonationByCauseID(w http.ResponseWriter, r *http.Request) {
 	ID, err := GetIDFromURL(w, r)
 
-	donationsResult, err := c.donationService.GetByCauseID(r.Context(), *ID)
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	donationsResult, err := c.donationService.GetByCauseID(r.Context(), *ID, params)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -129,16 +302,21 @@ func (c *DonationHandler) GetDonationByCauseID(w http.ResponseWriter, r *http.Re
 
 	w.Header().Set("Content-Type", "application/json")
 
-	var donationsResponse []*models.CreateDonationResponse
-	for _, donation := range donationsResult {
-		donationsResponse = append(donationsResponse, donation.ToDonationResponse())
+	donationsResponse := make([]*models.PublicDonationResponse, 0, len(donationsResult.Items))
+	for _, donation := range donationsResult.Items {
+		donationsResponse = append(donationsResponse, donation.ToPublicDonationResponse())
 	}
 
-	json.NewEncoder(w).Encode(donationsResponse)
+	response := donationsResult.Response(params)
+	response.Data = donationsResponse
+	json.NewEncoder(w).Encode(response)
 }
 
 func (c *DonationHandler) GetDonationByPaymentID(w http.ResponseWriter, r *http.Request) {
 	ID, err := GetIDFromURL(w, r)
+	if err != nil {
+		return
+	}
 
 	donation, err := c.donationService.GetByPaymentID(r.Context(), *ID)
 	if err != nil {
@@ -146,14 +324,34 @@ func (c *DonationHandler) GetDonationByPaymentID(w http.ResponseWriter, r *http.
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(donation.ToDonationResponse())
+	c.writeDonation(w, r, donation)
 }
 
 func (c *DonationHandler) GetDonationByUserID(w http.ResponseWriter, r *http.Request) {
 	user, err := GetUserFromContext(w, r, c)
+	if err != nil {
+		return
+	}
+
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Optional cause_id narrows the list, e.g. to check whether the user has
+	// donated to a cause without paging through everything.
+	var causeID *uuid.UUID
+	if raw := r.URL.Query().Get("cause_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			http.Error(w, "Invalid cause_id", http.StatusBadRequest)
+			return
+		}
+		causeID = &id
+	}
 
-	donationsResult, err := c.donationService.GetByUserID(r.Context(), user.ID)
+	donationsResult, err := c.donationService.GetByUserID(r.Context(), user.ID, causeID, params)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -161,12 +359,14 @@ func (c *DonationHandler) GetDonationByUserID(w http.ResponseWriter, r *http.Req
 
 	w.Header().Set("Content-Type", "application/json")
 
-	var donationsResponse []*models.CreateDonationResponse
-	for _, donation := range donationsResult {
+	donationsResponse := make([]*models.CreateDonationResponse, 0, len(donationsResult.Items))
+	for _, donation := range donationsResult.Items {
 		donationsResponse = append(donationsResponse, donation.ToDonationResponse())
 	}
 
-	json.NewEncoder(w).Encode(donationsResponse)
+	response := donationsResult.Response(params)
+	response.Data = donationsResponse
+	json.NewEncoder(w).Encode(response)
 }
 
 func (c *DonationHandler) GetDonationFromChainByID(w http.ResponseWriter, r *http.Request) {
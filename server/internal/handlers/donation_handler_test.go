@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const testAPIKey = models.APIKeyPrefix + "0123456789ab_secret"
+
+// fakeAPIKeys accepts testAPIKey for an organization.
+type fakeAPIKeys struct {
+	services.APIKeyService
+}
+
+func (fakeAPIKeys) Authenticate(ctx context.Context, key string) (*models.OrganizationAPIKey, error) {
+	if key != testAPIKey {
+		return nil, services.ErrInvalidAPIKey
+	}
+	return &models.OrganizationAPIKey{
+		ID:                 uuid.New(),
+		OrganizationID:     uuid.New(),
+		Scopes:             []models.APIKeyScope{models.ScopeDonationsRead},
+		RateLimitPerMinute: models.DefaultAPIKeyRateLimit,
+	}, nil
+}
+
+func TestDonationsForUserRejectAPIKeys(t *testing.T) {
+	// Only the API key service is set: a key must be turned away before
+	// anything looks up a user.
+	h := NewDonationHandler(nil, nil, nil, nil, nil, nil, nil, fakeAPIKeys{})
+	router := chi.NewRouter()
+	h.RegisterRoutes(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/donations/user/me", nil)
+	req.Header.Set("Authorization", "Bearer "+testAPIKey)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+}
+
+func TestGetDonationByUserIDWithoutUser(t *testing.T) {
+	h := NewDonationHandler(nil, nil, nil, nil, nil, nil, nil, nil)
+
+	rec := httptest.NewRecorder()
+	h.GetDonationByUserID(rec, httptest.NewRequest(http.MethodGet, "/api/donations/user/me", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", rec.Code)
+	}
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"server/internal/middleware"
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type FundraiserHandler struct {
+	fundraiserService services.FundraiserService
+	jwtService        services.JWTService
+}
+
+func NewFundraiserHandler(fundraiserService services.FundraiserService, jwtService services.JWTService) *FundraiserHandler {
+	return &FundraiserHandler{
+		fundraiserService: fundraiserService,
+		jwtService:        jwtService,
+	}
+}
+
+func (h *FundraiserHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/api/fundraisers", func(r chi.Router) {
+		r.Group(func(protected chi.Router) {
+			protected.Use(middleware.UserAuthMiddleware(h.jwtService))
+			protected.Post("/", h.CreateFundraiser)
+			protected.Get("/me", h.GetMyFundraisers)
+			protected.Patch("/{ID}", h.UpdateFundraiser)
+			protected.Post("/{ID}/close", h.CloseFundraiser)
+		})
+
+		r.Get("/{ID}", h.GetFundraiser)
+		r.Get("/{ID}/donations", h.GetFundraiserDonations)
+		r.Get("/cause/{ID}", h.GetCauseFundraisers)
+		r.Get("/cause/{ID}/leaderboard", h.GetCauseLeaderboard)
+	})
+}
+
+func writeFundraiserError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Fundraiser not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+func (h *FundraiserHandler) CreateFundraiser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateFundraiserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	fundraiser, err := h.fundraiserService.Create(r.Context(), userID, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(fundraiser)
+}
+
+func (h *FundraiserHandler) GetMyFundraisers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fundraisers, err := h.fundraiserService.GetMine(r.Context(), userID, params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fundraisers.Response(params))
+}
+
+func (h *FundraiserHandler) UpdateFundraiser(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid fundraiser ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.UpdateFundraiserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	fundraiser, err := h.fundraiserService.Update(r.Context(), userID, id, &req)
+	if err != nil {
+		writeFundraiserError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fundraiser)
+}
+
+func (h *FundraiserHandler) CloseFundraiser(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid fundraiser ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	fundraiser, err := h.fundraiserService.Close(r.Context(), userID, id)
+	if err != nil {
+		writeFundraiserError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fundraiser)
+}
+
+func (h *FundraiserHandler) GetFundraiser(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid fundraiser ID", http.StatusBadRequest)
+		return
+	}
+
+	fundraiser, err := h.fundraiserService.GetByID(r.Context(), id)
+	if err != nil {
+		writeFundraiserError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fundraiser)
+}
+
+func (h *FundraiserHandler) GetFundraiserDonations(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid fundraiser ID", http.StatusBadRequest)
+		return
+	}
+
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	donations, err := h.fundraiserService.GetDonations(r.Context(), id, params)
+	if err != nil {
+		writeFundraiserError(w, err)
+		return
+	}
+
+	donationsResponse := make([]*models.PublicDonationResponse, 0, len(donations.Items))
+	for _, donation := range donations.Items {
+		donationsResponse = append(donationsResponse, donation.ToPublicDonationResponse())
+	}
+
+	response := donations.Response(params)
+	response.Data = donationsResponse
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *FundraiserHandler) GetCauseFundraisers(w http.ResponseWriter, r *http.Request) {
+	causeID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid cause ID", http.StatusBadRequest)
+		return
+	}
+
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fundraisers, err := h.fundraiserService.GetByCauseID(r.Context(), causeID, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Cause not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fundraisers.Response(params))
+}
+
+// GetCauseLeaderboard ranks a cause's fundraisers by amount raised. Query
+// param limit defaults to 10, at most 50.
+func (h *FundraiserHandler) GetCauseLeaderboard(w http.ResponseWriter, r *http.Request) {
+	causeID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid cause ID", http.StatusBadRequest)
+		return
+	}
+
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries, err := h.fundraiserService.GetLeaderboard(r.Context(), causeID, limit)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Cause not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
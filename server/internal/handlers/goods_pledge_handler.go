@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"server/internal/middleware"
+	"server/internal/models"
+	"server/internal/policy"
+	"server/internal/repository"
+	"server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type GoodsPledgeHandler struct {
+	pledgeService services.GoodsPledgeService
+	authorizer    *policy.Authorizer
+	jwtService    services.JWTService
+}
+
+func NewGoodsPledgeHandler(pledgeService services.GoodsPledgeService, authorizer *policy.Authorizer, jwtService services.JWTService) *GoodsPledgeHandler {
+	return &GoodsPledgeHandler{
+		pledgeService: pledgeService,
+		authorizer:    authorizer,
+		jwtService:    jwtService,
+	}
+}
+
+func (h *GoodsPledgeHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/api/pledges", func(r chi.Router) {
+		// Public: goods a cause has received, for the transparency view.
+		r.Get("/cause/{ID}/received", h.GetReceivedGoods)
+
+		r.Group(func(protected chi.Router) {
+			protected.Use(middleware.UserAuthMiddleware(h.jwtService))
+
+			// Donor
+			protected.Post("/", h.CreatePledge)
+			protected.Get("/me", h.GetMyPledges)
+			protected.Post("/{ID}/cancel", h.CancelPledge)
+
+			// Organization owning the cause
+			protected.Get("/cause/{ID}", h.GetCausePledges)
+			protected.Post("/{ID}/accept", h.AcceptPledge)
+			protected.Post("/{ID}/reject", h.RejectPledge)
+			protected.Post("/{ID}/schedule", h.SchedulePledgeDelivery)
+			protected.Post("/{ID}/receive", h.ReceivePledge)
+		})
+	})
+}
+
+// writePledgeError maps pledge service errors to a status code.
+func writePledgeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Pledge not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrGoodsPledgeChanged):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrPledgeNotAuthorized):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
+
+func writePledge(w http.ResponseWriter, pledge *models.GoodsPledge) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pledge)
+}
+
+func (h *GoodsPledgeHandler) CreatePledge(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateGoodsPledgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	pledge, err := h.pledgeService.Pledge(r.Context(), userID, &req)
+	if err != nil {
+		writePledgeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	writePledge(w, pledge)
+}
+
+func (h *GoodsPledgeHandler) GetMyPledges(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pledges, err := h.pledgeService.GetMine(r.Context(), userID, params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pledges.Response(params))
+}
+
+func (h *GoodsPledgeHandler) CancelPledge(w http.ResponseWriter, r *http.Request) {
+	pledgeID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid pledge ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	pledge, err := h.pledgeService.Cancel(r.Context(), userID, pledgeID)
+	if err != nil {
+		writePledgeError(w, err)
+		return
+	}
+	writePledge(w, pledge)
+}
+
+func (h *GoodsPledgeHandler) GetCausePledges(w http.ResponseWriter, r *http.Request) {
+	causeID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid cause ID", http.StatusBadRequest)
+		return
+	}
+
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.PledgeManage)
+	if !ok {
+		return
+	}
+
+	var status *models.GoodsPledgeStatus
+	if raw := r.URL.Query().Get("status"); raw != "" {
+		s := models.GoodsPledgeStatus(raw)
+		if !s.IsValid() {
+			http.Error(w, "Invalid status", http.StatusBadRequest)
+			return
+		}
+		status = &s
+	}
+
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pledges, err := h.pledgeService.GetForCause(r.Context(), org.ID, causeID, status, params)
+	if err != nil {
+		writePledgeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pledges.Response(params))
+}
+
+func (h *GoodsPledgeHandler) AcceptPledge(w http.ResponseWriter, r *http.Request) {
+	pledgeID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid pledge ID", http.StatusBadRequest)
+		return
+	}
+
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.PledgeManage)
+	if !ok {
+		return
+	}
+
+	pledge, err := h.pledgeService.Accept(r.Context(), org.ID, pledgeID)
+	if err != nil {
+		writePledgeError(w, err)
+		return
+	}
+	writePledge(w, pledge)
+}
+
+func (h *GoodsPledgeHandler) RejectPledge(w http.ResponseWriter, r *http.Request) {
+	pledgeID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid pledge ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.RejectGoodsPledgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.PledgeManage)
+	if !ok {
+		return
+	}
+
+	pledge, err := h.pledgeService.Reject(r.Context(), org.ID, pledgeID, req.Reason)
+	if err != nil {
+		writePledgeError(w, err)
+		return
+	}
+	writePledge(w, pledge)
+}
+
+func (h *GoodsPledgeHandler) SchedulePledgeDelivery(w http.ResponseWriter, r *http.Request) {
+	pledgeID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid pledge ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ScheduleGoodsDeliveryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.PledgeManage)
+	if !ok {
+		return
+	}
+
+	pledge, err := h.pledgeService.ScheduleDelivery(r.Context(), org.ID, pledgeID, &req)
+	if err != nil {
+		writePledgeError(w, err)
+		return
+	}
+	writePledge(w, pledge)
+}
+
+// ReceivePledge marks goods as received. The photo is uploaded first through
+// a proof session for the cause; its image ID is passed here.
+func (h *GoodsPledgeHandler) ReceivePledge(w http.ResponseWriter, r *http.Request) {
+	pledgeID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid pledge ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ReceiveGoodsPledgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.PledgeManage)
+	if !ok {
+		return
+	}
+
+	pledge, err := h.pledgeService.MarkReceived(r.Context(), org.ID, pledgeID, &req)
+	if err != nil {
+		writePledgeError(w, err)
+		return
+	}
+	writePledge(w, pledge)
+}
+
+func (h *GoodsPledgeHandler) GetReceivedGoods(w http.ResponseWriter, r *http.Request) {
+	causeID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid cause ID", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.pledgeService.GetInKindSummary(r.Context(), causeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Cause not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
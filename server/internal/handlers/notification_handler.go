@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"server/internal/middleware"
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// notificationStreamAuthWait is how long a new stream has to send its
+	// token.
+	notificationStreamAuthWait = 10 * time.Second
+	notificationStreamPongWait = 60 * time.Second
+	// notificationStreamPingPeriod must be shorter than the pong wait.
+	notificationStreamPingPeriod = 50 * time.Second
+	notificationStreamWriteWait  = 10 * time.Second
+
+	// closeUnauthorized tells the client to refresh its access token and
+	// connect again, from the private range of websocket close codes.
+	closeUnauthorized = 4401
+)
+
+// NotificationHandler serves the in-app inbox and its live stream, and lets
+// users choose which notification emails they get and unsubscribe from them.
+type NotificationHandler struct {
+	notificationService services.NotificationService
+	jwtService          services.JWTService
+}
+
+func NewNotificationHandler(notificationService services.NotificationService, jwtService services.JWTService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		jwtService:          jwtService,
+	}
+}
+
+func (h *NotificationHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/api/notifications", func(r chi.Router) {
+		// Unsubscribe links are followed from email, signed in or not
+		r.With(middleware.RateLimit(20, 15*time.Minute)).Post("/unsubscribe", h.Unsubscribe)
+
+		r.Group(func(protected chi.Router) {
+			protected.Use(middleware.UserAuthMiddleware(h.jwtService))
+			protected.Get("/", h.ListNotifications)
+			protected.Get("/unread-count", h.GetUnreadCount)
+			protected.Post("/read-all", h.MarkAllRead)
+			protected.Post("/{ID}/read", h.MarkRead)
+			protected.Get("/preferences", h.GetPreferences)
+			protected.Put("/preferences", h.UpdatePreferences)
+		})
+	})
+
+	// Authenticated by the stream's first message; see NotificationStream.
+	r.Get("/ws/notifications", h.NotificationStream)
+}
+
+// ListNotifications lists the user's inbox newest first, only what's
+// unread with ?unread=true.
+func (h *NotificationHandler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	notifications, err := h.notificationService.ListNotifications(r.Context(), userID, unreadOnly, params)
+	if err != nil {
+		log.Printf("failed to fetch notifications: %v", err)
+		http.Error(w, "Failed to fetch notifications", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notifications.Response(params))
+}
+
+func (h *NotificationHandler) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	unread, err := h.notificationService.UnreadCount(r.Context(), userID)
+	if err != nil {
+		log.Printf("failed to count unread notifications: %v", err)
+		http.Error(w, "Failed to count unread notifications", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.UnreadCountResponse{Unread: unread})
+}
+
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+	notificationID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.notificationService.MarkRead(r.Context(), userID, notificationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Notification not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("failed to mark notification read: %v", err)
+		http.Error(w, "Failed to mark notification read", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.notificationService.MarkAllRead(r.Context(), userID); err != nil {
+		log.Printf("failed to mark notifications read: %v", err)
+		http.Error(w, "Failed to mark notifications read", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// NotificationStream pushes the user's models.NotificationEvents over a
+// websocket, starting with their unread count. Browsers can't set headers
+// on a websocket and a token in the URL would end up in request logs, so
+// the client sends its access token as the first message. The stream is
+// closed with closeUnauthorized when the token expires, so that a revoked
+// session doesn't keep listening; the client refreshes and reconnects.
+func (h *NotificationHandler) NotificationStream(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	conn.SetReadLimit(4096)
+	conn.SetReadDeadline(time.Now().Add(notificationStreamAuthWait))
+	var auth models.NotificationStreamAuth
+	if err := conn.ReadJSON(&auth); err != nil {
+		return
+	}
+	// API keys are for servers, which get webhooks instead.
+	if auth.Token == "" || strings.HasPrefix(auth.Token, models.APIKeyPrefix) {
+		closeNotificationStream(conn, closeUnauthorized, "invalid token")
+		return
+	}
+	claims, err := h.jwtService.ValidateToken(r.Context(), auth.Token)
+	if err != nil || claims.ExpiresAt == nil {
+		closeNotificationStream(conn, closeUnauthorized, "invalid token")
+		return
+	}
+
+	events, unsubscribe := h.notificationService.Subscribe(claims.UserID)
+	defer unsubscribe()
+
+	unread, err := h.notificationService.UnreadCount(r.Context(), claims.UserID)
+	if err != nil {
+		log.Printf("failed to count unread notifications: %v", err)
+		closeNotificationStream(conn, websocket.CloseInternalServerErr, "")
+		return
+	}
+	conn.SetWriteDeadline(time.Now().Add(notificationStreamWriteWait))
+	if err := conn.WriteJSON(&models.NotificationEvent{Type: models.NotificationEventUnread, Unread: unread}); err != nil {
+		return
+	}
+
+	// The client sends nothing more, but reading is how pongs and its
+	// closing are noticed.
+	closed := make(chan struct{})
+	conn.SetReadDeadline(time.Now().Add(notificationStreamPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(notificationStreamPongWait))
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(notificationStreamPingPeriod)
+	defer ping.Stop()
+	expired := time.NewTimer(time.Until(claims.ExpiresAt.Time))
+	defer expired.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			conn.SetWriteDeadline(time.Now().Add(notificationStreamWriteWait))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(notificationStreamWriteWait)); err != nil {
+				return
+			}
+		case <-expired.C:
+			closeNotificationStream(conn, closeUnauthorized, "token expired")
+			return
+		case <-closed:
+			return
+		}
+	}
+}
+
+func closeNotificationStream(conn *websocket.Conn, code int, reason string) {
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(notificationStreamWriteWait))
+}
+
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	prefs, err := h.notificationService.GetPreferences(r.Context(), userID)
+	if err != nil {
+		log.Printf("failed to fetch notification preferences: %v", err)
+		http.Error(w, "Failed to fetch notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// UpdatePreferences turns the kinds named in the body on or off, and can
+// unsubscribe from or resubscribe to every notification email.
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.UpdateNotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.notificationService.UpdatePreferences(r.Context(), userID, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// Unsubscribe turns every notification email off for the user an email's
+// unsubscribe link was sent to.
+func (h *NotificationHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	var req models.UnsubscribeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		http.Error(w, "Token is required", http.StatusBadRequest)
+		return
+	}
+
+	err := h.notificationService.Unsubscribe(r.Context(), req.Token)
+	if errors.Is(err, services.ErrInvalidUnsubscribeToken) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("failed to unsubscribe: %v", err)
+		http.Error(w, "Failed to unsubscribe", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
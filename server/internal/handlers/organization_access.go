@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+
+	"server/internal/middleware"
+	"server/internal/models"
+	"server/internal/policy"
+)
+
+// authorize checks that the caller may perform p on res. Otherwise it
+// writes an error response and returns false.
+func authorize(w http.ResponseWriter, r *http.Request, authz *policy.Authorizer, p policy.Permission, res policy.Resource) (*policy.Subject, bool) {
+	s, err := middleware.Authorize(r, authz, p, res)
+	if err != nil {
+		middleware.WritePermissionError(w, err)
+		return nil, false
+	}
+	return s, true
+}
+
+// allowed reports whether the caller may perform p on res, treating
+// anonymous callers and lookup failures as a no.
+func allowed(r *http.Request, authz *policy.Authorizer, p policy.Permission, res policy.Resource) bool {
+	_, err := middleware.Authorize(r, authz, p, res)
+	return err == nil
+}
+
+// organizationForRequest returns the organization the caller acts for if
+// their role in it, or their API key's scopes, grant p. Otherwise it
+// writes an error response and returns false.
+func organizationForRequest(w http.ResponseWriter, r *http.Request, authz *policy.Authorizer, p policy.Permission) (*models.Organization, bool) {
+	s, err := middleware.SubjectFromRequest(r, authz)
+	if err != nil {
+		middleware.WritePermissionError(w, err)
+		return nil, false
+	}
+	org := s.Organization()
+	if org == nil {
+		middleware.WritePermissionError(w, policy.ErrForbidden)
+		return nil, false
+	}
+	if err := policy.Check(s, p, policy.OfOrganization(org.ID)); err != nil {
+		middleware.WritePermissionError(w, err)
+		return nil, false
+	}
+	return org, true
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"server/internal/middleware"
+	"server/internal/models"
+	"server/internal/repository"
+	"server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type OrganizationMemberHandler struct {
+	memberService services.OrganizationMemberService
+	jwtService    services.JWTService
+}
+
+func NewOrganizationMemberHandler(memberService services.OrganizationMemberService, jwtService services.JWTService) *OrganizationMemberHandler {
+	return &OrganizationMemberHandler{
+		memberService: memberService,
+		jwtService:    jwtService,
+	}
+}
+
+func (h *OrganizationMemberHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/api/organization", func(r chi.Router) {
+		r.Use(middleware.UserAuthMiddleware(h.jwtService))
+
+		r.Get("/team", h.GetTeam)
+		r.With(middleware.RateLimit(30, time.Hour)).Post("/team/invitations", h.InviteMember)
+		r.Delete("/team/invitations/{ID}", h.RevokeInvitation)
+		r.Patch("/team/members/{ID}", h.UpdateMemberRole)
+		r.Delete("/team/members/{ID}", h.RemoveMember)
+
+		r.With(middleware.RateLimit(10, 15*time.Minute)).Post("/invitations/accept", h.AcceptInvitation)
+	})
+}
+
+// writeMemberError maps organization member service errors to a status
+// code.
+func writeMemberError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrPermissionDenied), errors.Is(err, services.ErrPrimaryMember):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, repository.ErrNotOrganizationMember):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Invitation not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrInvalidInvitation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrAlreadyMember):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrTooManyInvitations):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	default:
+		log.Printf("organization team request failed: %v", err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+	}
+}
+
+// GetTeam lists the members of the caller's organization and, for those who
+// may manage the team, its pending invitations.
+func (h *OrganizationMemberHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	team, err := h.memberService.ListTeam(r.Context(), userID)
+	if err != nil {
+		writeMemberError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(team)
+}
+
+func (h *OrganizationMemberHandler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.InviteMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	invitation, err := h.memberService.Invite(r.Context(), userID, &req)
+	if err != nil {
+		writeInviteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(invitation)
+}
+
+// writeInviteError reports validation failures from Invite as 400s.
+func writeInviteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrPermissionDenied),
+		errors.Is(err, repository.ErrNotOrganizationMember),
+		errors.Is(err, services.ErrTooManyInvitations):
+		writeMemberError(w, err)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
+
+func (h *OrganizationMemberHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	invitationID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid invitation ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.memberService.RevokeInvitation(r.Context(), userID, invitationID); err != nil {
+		writeMemberError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateMemberRole changes a member's role. The ID is the member's user ID.
+func (h *OrganizationMemberHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	memberID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !req.Role.IsValid() {
+		http.Error(w, "Unknown role", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.memberService.UpdateRole(r.Context(), userID, memberID, req.Role); err != nil {
+		writeMemberError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveMember takes a member off the team. The ID is the member's user ID;
+// members may pass their own to leave.
+func (h *OrganizationMemberHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	memberID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid member ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.memberService.Remove(r.Context(), userID, memberID); err != nil {
+		writeMemberError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AcceptInvitation joins the caller to the organization that invited them
+// and signs them out everywhere.
+func (h *OrganizationMemberHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.AcceptInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	member, err := h.memberService.AcceptInvitation(r.Context(), userID, req.Token)
+	if err != nil {
+		writeMemberError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(member)
+}
@@ -2,18 +2,21 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"server/internal/middleware"
 	"server/internal/services"
 )
 
 type PaymentHandler struct {
 	paymentService *services.PaymentService
+	accountService services.AccountService
 	keyID          string
 }
 
-func NewPaymentHandler(ps *services.PaymentService, keyID string) *PaymentHandler {
-	return &PaymentHandler{paymentService: ps, keyID: keyID}
+func NewPaymentHandler(ps *services.PaymentService, accountService services.AccountService, keyID string) *PaymentHandler {
+	return &PaymentHandler{paymentService: ps, accountService: accountService, keyID: keyID}
 }
 
 func (h *PaymentHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
@@ -28,6 +31,19 @@ func (h *PaymentHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Refuse before the donor pays rather than when the donation is recorded
+	if userID, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		err := h.accountService.CheckDonationAllowed(r.Context(), userID, float64(req.Amount))
+		if errors.Is(err, services.ErrEmailNotVerified) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Failed to check account", http.StatusInternalServerError)
+			return
+		}
+	}
+
 	order, err := h.paymentService.CreateOrder(req.Amount, req.Receipt)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"server/internal/middleware"
+	"server/internal/models"
+	"server/internal/repository"
+	"server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type PrivacyHandler struct {
+	privacyService services.PrivacyService
+	jwtService     services.JWTService
+}
+
+func NewPrivacyHandler(privacyService services.PrivacyService, jwtService services.JWTService) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+		jwtService:     jwtService,
+	}
+}
+
+func (h *PrivacyHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/api/privacy", func(r chi.Router) {
+		r.Use(middleware.UserAuthMiddleware(h.jwtService))
+		r.Get("/export", h.ExportData)
+		r.Post("/erase", h.EraseAccount)
+	})
+}
+
+// ExportData downloads everything held about the caller as a zip of JSON
+// files.
+func (h *PrivacyHandler) ExportData(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	archive, err := h.privacyService.ExportData(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "Failed to export data", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("charitylight-data-%s.zip", time.Now().UTC().Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(archive)
+}
+
+// EraseAccount erases the caller's account. The body must be
+// {"confirm": true}.
+func (h *PrivacyHandler) EraseAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.EraseAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !req.Confirm {
+		http.Error(w, "Erasure must be confirmed", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.privacyService.EraseAccount(r.Context(), userID)
+	if errors.Is(err, repository.ErrAlreadyErased) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
@@ -14,6 +14,7 @@ import (
 
 	"server/internal/middleware"
 	"server/internal/models"
+	"server/internal/policy"
 	"server/internal/repository"
 	"server/internal/services"
 
@@ -23,18 +24,18 @@ import (
 )
 
 type ProofHandler struct {
-	jwtService       services.JWTService
-	proofService     services.ProofService
-	organizationRepo repository.OrganizationRepository
-	causeRepo        repository.CauseRepository
+	jwtService   services.JWTService
+	proofService services.ProofService
+	authorizer   *policy.Authorizer
+	causeRepo    repository.CauseRepository
 }
 
-func NewProofHandler(jwt services.JWTService, proofService services.ProofService, organizationRepo repository.OrganizationRepository, causeRepo repository.CauseRepository) *ProofHandler {
+func NewProofHandler(jwt services.JWTService, proofService services.ProofService, authorizer *policy.Authorizer, causeRepo repository.CauseRepository) *ProofHandler {
 	return &ProofHandler{
-		jwtService:       jwt,
-		proofService:     proofService,
-		organizationRepo: organizationRepo,
-		causeRepo:        causeRepo,
+		jwtService:   jwt,
+		proofService: proofService,
+		authorizer:   authorizer,
+		causeRepo:    causeRepo,
 	}
 }
 
@@ -55,7 +56,7 @@ func (h *ProofHandler) RegisterRoutes(r chi.Router) {
 		r.Post("/session", h.CreateProofSession)
 		// NGO cause session: auth required, body { "causeId": "uuid" } -> DB-backed session
 		r.Group(func(protected chi.Router) {
-			protected.Use(middleware.AuthMiddleware(h.jwtService))
+			protected.Use(middleware.UserAuthMiddleware(h.jwtService))
 			protected.Post("/session/cause", h.CreateCauseProofSession)
 		})
 
@@ -96,15 +97,8 @@ func (h *ProofHandler) CreateCauseProofSession(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.ProofUpload)
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
-	org, err := h.organizationRepo.GetByID(r.Context(), userID)
-	if err != nil || org == nil {
-		http.Error(w, "Organization not found", http.StatusForbidden)
 		return
 	}
 
@@ -280,6 +274,7 @@ func (h *ProofHandler) UploadProofImage(w http.ResponseWriter, r *http.Request)
 				Flags:        flags,
 			}
 			if img != nil {
+				resp.ImageID = &img.ID
 				resp.Score = img.MetadataScore
 				// Persist AI verification + media path so the NGO can fetch them later.
 				validationStatus := aiStatus
@@ -326,7 +321,7 @@ func (h *ProofHandler) emitToSession(sessionID string, payload interface{}) {
 	}
 }
 
-// GetProofImagesBySession returns all proof images stored for a given DB-backed proof session.
+// GetProofImagesBySession returns a page of proof images stored for a given DB-backed proof session.
 // This is used to show proof-of-work results on the UploadUpdate page (and later in campaign updates).
 func (h *ProofHandler) GetProofImagesBySession(w http.ResponseWriter, r *http.Request) {
 	sessionIDStr := chi.URLParam(r, "sessionID")
@@ -336,7 +331,13 @@ func (h *ProofHandler) GetProofImagesBySession(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	imgs, err := h.proofService.GetProofImagesBySessionID(r.Context(), sessionIDParsed)
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	imgs, err := h.proofService.GetProofImagesBySessionID(r.Context(), sessionIDParsed, params)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -352,8 +353,8 @@ func (h *ProofHandler) GetProofImagesBySession(w http.ResponseWriter, r *http.Re
 		Score     *int       `json:"score,omitempty"`
 	}
 
-	out := make([]ProofImageItem, 0, len(imgs))
-	for _, img := range imgs {
+	out := make([]ProofImageItem, 0, len(imgs.Items))
+	for _, img := range imgs.Items {
 		if img == nil {
 			continue
 		}
@@ -381,6 +382,9 @@ func (h *ProofHandler) GetProofImagesBySession(w http.ResponseWriter, r *http.Re
 		})
 	}
 
+	response := imgs.Response(params)
+	response.Data = out
+
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(out)
+	_ = json.NewEncoder(w).Encode(response)
 }
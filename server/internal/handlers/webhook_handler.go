@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"server/internal/middleware"
+	"server/internal/models"
+	"server/internal/policy"
+	"server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler lets organization owners and admins register the
+// endpoints their own systems receive events on, and see and replay what
+// was sent.
+type WebhookHandler struct {
+	webhookService services.WebhookService
+	authorizer     *policy.Authorizer
+	jwtService     services.JWTService
+}
+
+func NewWebhookHandler(webhookService services.WebhookService, authorizer *policy.Authorizer, jwtService services.JWTService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		authorizer:     authorizer,
+		jwtService:     jwtService,
+	}
+}
+
+func (h *WebhookHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/api/organization/webhooks", func(r chi.Router) {
+		r.Use(middleware.UserAuthMiddleware(h.jwtService))
+		r.Use(middleware.RequirePermission(h.authorizer, policy.WebhooksManage))
+
+		r.Get("/", h.ListEndpoints)
+		r.Get("/events", h.ListEventTypes)
+		r.With(middleware.RateLimit(20, time.Hour)).Post("/", h.CreateEndpoint)
+		r.Patch("/{ID}", h.UpdateEndpoint)
+		r.Delete("/{ID}", h.DeleteEndpoint)
+		r.Get("/{ID}/deliveries", h.ListDeliveries)
+		r.With(middleware.RateLimit(60, time.Hour)).Post("/{ID}/deliveries/{deliveryID}/replay", h.ReplayDelivery)
+	})
+}
+
+func writeWebhookError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Webhook endpoint not found", http.StatusNotFound)
+	default:
+		log.Printf("failed to %s: %v", action, err)
+		http.Error(w, "Failed to "+action, http.StatusInternalServerError)
+	}
+}
+
+func (h *WebhookHandler) ListEndpoints(w http.ResponseWriter, r *http.Request) {
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.WebhooksManage)
+	if !ok {
+		return
+	}
+
+	endpoints, err := h.webhookService.List(r.Context(), org.ID)
+	if err != nil {
+		writeWebhookError(w, err, "fetch webhook endpoints")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(endpoints)
+}
+
+// ListEventTypes lists the events an endpoint can subscribe to.
+func (h *WebhookHandler) ListEventTypes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.WebhookEventTypes)
+}
+
+// CreateEndpoint registers an endpoint. The response is the only time its
+// signing secret is shown.
+func (h *WebhookHandler) CreateEndpoint(w http.ResponseWriter, r *http.Request) {
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.WebhooksManage)
+	if !ok {
+		return
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateWebhookEndpointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.webhookService.Create(r.Context(), org.ID, userID, &req)
+	if errors.Is(err, services.ErrTooManyWebhookEndpoints) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateEndpoint changes an endpoint's URL, description or events, or
+// turns it off and on. Deliveries wait while it's off.
+func (h *WebhookHandler) UpdateEndpoint(w http.ResponseWriter, r *http.Request) {
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.WebhooksManage)
+	if !ok {
+		return
+	}
+	endpointID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid webhook endpoint ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateWebhookEndpointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	endpoint, err := h.webhookService.Update(r.Context(), org.ID, endpointID, &req)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Webhook endpoint not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(endpoint)
+}
+
+// DeleteEndpoint removes an endpoint along with its delivery log.
+func (h *WebhookHandler) DeleteEndpoint(w http.ResponseWriter, r *http.Request) {
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.WebhooksManage)
+	if !ok {
+		return
+	}
+	endpointID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid webhook endpoint ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.webhookService.Delete(r.Context(), org.ID, endpointID); err != nil {
+		writeWebhookError(w, err, "delete webhook endpoint")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries is an endpoint's delivery log, newest first.
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.WebhooksManage)
+	if !ok {
+		return
+	}
+	endpointID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid webhook endpoint ID", http.StatusBadRequest)
+		return
+	}
+	params, err := models.GetCursorParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(r.Context(), org.ID, endpointID, params)
+	if err != nil {
+		writeWebhookError(w, err, "fetch webhook deliveries")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries.Response(params))
+}
+
+// ReplayDelivery sends a delivery again from its first attempt, whether it
+// succeeded, is being retried or is dead.
+func (h *WebhookHandler) ReplayDelivery(w http.ResponseWriter, r *http.Request) {
+	org, ok := organizationForRequest(w, r, h.authorizer, policy.WebhooksManage)
+	if !ok {
+		return
+	}
+	endpointID, err := uuid.Parse(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "Invalid webhook endpoint ID", http.StatusBadRequest)
+		return
+	}
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		http.Error(w, "Invalid delivery ID", http.StatusBadRequest)
+		return
+	}
+
+	delivery, err := h.webhookService.Replay(r.Context(), org.ID, endpointID, deliveryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Delivery not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeWebhookError(w, err, "replay webhook delivery")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
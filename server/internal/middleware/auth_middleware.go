@@ -3,8 +3,11 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"server/internal/models"
 	"server/internal/services"
 
 	"github.com/google/uuid"
@@ -15,9 +18,19 @@ type contextKey string
 const (
 	UserIDKey contextKey = "user_id"
 	UserRoleKey contextKey = "user_role"
+	SessionIDKey contextKey = "session_id"
+	APIKeyKey contextKey = "api_key"
 )
 
-func AuthMiddleware(jwtService services.JWTService) func(http.Handler) http.Handler {
+// apiKeyLimiter holds each API key to its own requests per minute, shared
+// across every route the key calls.
+var apiKeyLimiter = NewRateLimiter(models.DefaultAPIKeyRateLimit, time.Minute)
+
+// AuthMiddleware requires a valid access token, checked by jwtService, or
+// an organization API key, checked by apiKeyService. Keys carry no user ID,
+// so it is only for routes whose handlers authorize through the policy
+// package; routes that need a signed-in person use UserAuthMiddleware.
+func AuthMiddleware(jwtService services.JWTService, apiKeyService services.APIKeyService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Get token from Authorization header
@@ -36,8 +49,21 @@ func AuthMiddleware(jwtService services.JWTService) func(http.Handler) http.Hand
 			// Extract token
 			token := strings.TrimPrefix(authHeader, "Bearer ")
 
+			if strings.HasPrefix(token, models.APIKeyPrefix) {
+				apiKey, err := apiKeyService.Authenticate(r.Context(), token)
+				if err != nil {
+					http.Error(w, "Invalid API key", http.StatusUnauthorized)
+					return
+				}
+				if !allowAPIKey(w, apiKey) {
+					return
+				}
+				next.ServeHTTP(w, withAPIKey(r, apiKey))
+				return
+			}
+
 			// Validate token
-			claims, err := jwtService.ValidateToken(token)
+			claims, err := jwtService.ValidateToken(r.Context(), token)
 			if err != nil {
 				http.Error(w, "Invalid token", http.StatusUnauthorized)
 				return
@@ -46,48 +72,126 @@ func AuthMiddleware(jwtService services.JWTService) func(http.Handler) http.Hand
 			// Add user ID and role to context
 			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 			ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
+			ctx = context.WithValue(ctx, SessionIDKey, claims.SessionID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
-// GetUserIDFromContext extracts user ID from request context
-func GetUserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
-	userID, ok := ctx.Value(UserIDKey).(uuid.UUID)
-	return userID, ok
-}
+// UserAuthMiddleware requires a valid access token. Organization API keys
+// are turned away, since they don't act for a person.
+func UserAuthMiddleware(jwtService services.JWTService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				http.Error(w, "Authorization header required", http.StatusUnauthorized)
+				return
+			}
+			if !strings.HasPrefix(authHeader, "Bearer ") {
+				http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+				return
+			}
 
-// GetUserRoleFromContext extracts user role from request context
-func GetUserRoleFromContext(ctx context.Context) (string, bool) {
-	role, ok := ctx.Value(UserRoleKey).(string)
-	return role, ok
+			token := strings.TrimPrefix(authHeader, "Bearer ")
+			if strings.HasPrefix(token, models.APIKeyPrefix) {
+				http.Error(w, "API keys can't be used for this endpoint", http.StatusForbidden)
+				return
+			}
+
+			claims, err := jwtService.ValidateToken(r.Context(), token)
+			if err != nil {
+				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+			ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
+			ctx = context.WithValue(ctx, SessionIDKey, claims.SessionID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
-// RequireRole creates middleware that restricts access to specific roles
-func RequireRole(allowedRoles ...string) func(http.Handler) http.Handler {
+// OptionalAuthMiddleware attaches the user to the context when a valid
+// bearer token is present, and otherwise lets the request through as
+// anonymous. Used on public routes that show more to owners and admins.
+func OptionalAuthMiddleware(jwtService services.JWTService, apiKeyService services.APIKeyService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			role, ok := GetUserRoleFromContext(r.Context())
-			if !ok {
-				http.Error(w, "Unauthorized: role not found", http.StatusUnauthorized)
+			authHeader := r.Header.Get("Authorization")
+			if !strings.HasPrefix(authHeader, "Bearer ") {
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			// Check if user's role is in allowed roles
-			allowed := false
-			for _, allowedRole := range allowedRoles {
-				if role == allowedRole {
-					allowed = true
-					break
+			token := strings.TrimPrefix(authHeader, "Bearer ")
+			if strings.HasPrefix(token, models.APIKeyPrefix) {
+				apiKey, err := apiKeyService.Authenticate(r.Context(), token)
+				if err != nil {
+					next.ServeHTTP(w, r)
+					return
+				}
+				if !allowAPIKey(w, apiKey) {
+					return
 				}
+				next.ServeHTTP(w, withAPIKey(r, apiKey))
+				return
 			}
 
-			if !allowed {
-				http.Error(w, "Forbidden: insufficient permissions", http.StatusForbidden)
+			claims, err := jwtService.ValidateToken(r.Context(), token)
+			if err != nil {
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			next.ServeHTTP(w, r)
+			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
+			ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
+			ctx = context.WithValue(ctx, SessionIDKey, claims.SessionID)
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// GetUserIDFromContext extracts user ID from request context
+func GetUserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(UserIDKey).(uuid.UUID)
+	return userID, ok
+}
+
+// GetUserRoleFromContext extracts user role from request context
+func GetUserRoleFromContext(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(UserRoleKey).(string)
+	return role, ok
+}
+
+// GetSessionIDFromContext extracts the session the access token belongs to
+func GetSessionIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	sessionID, ok := ctx.Value(SessionIDKey).(uuid.UUID)
+	return sessionID, ok
+}
+
+// GetAPIKeyFromContext returns the organization API key the request was
+// made with, if it wasn't made by a signed-in user.
+func GetAPIKeyFromContext(ctx context.Context) (*models.OrganizationAPIKey, bool) {
+	apiKey, ok := ctx.Value(APIKeyKey).(*models.OrganizationAPIKey)
+	return apiKey, ok
+}
+
+// withAPIKey attaches an API key to the request. No user ID is set: keys
+// act for their organization, not a person, so handlers that need a
+// signed-in user turn them away and only those that check permissions
+// through the policy package accept them.
+func withAPIKey(r *http.Request, apiKey *models.OrganizationAPIKey) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), APIKeyKey, apiKey))
+}
+
+// allowAPIKey applies the key's rate limit, writing a 429 when it's used up.
+func allowAPIKey(w http.ResponseWriter, apiKey *models.OrganizationAPIKey) bool {
+	ok, retryAfter := apiKeyLimiter.AllowLimit(apiKey.ID.String(), apiKey.RateLimitPerMinute, time.Now())
+	if !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		http.Error(w, "API key rate limit exceeded, try again later", http.StatusTooManyRequests)
+	}
+	return ok
+}
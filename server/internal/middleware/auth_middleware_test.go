@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// fakeAPIKeys accepts one key. The JWT service is left nil: requests with
+// an API key must never reach it.
+type fakeAPIKeys struct {
+	services.APIKeyService
+	key    string
+	apiKey *models.OrganizationAPIKey
+}
+
+func (f *fakeAPIKeys) Authenticate(ctx context.Context, key string) (*models.OrganizationAPIKey, error) {
+	if key != f.key {
+		return nil, services.ErrInvalidAPIKey
+	}
+	copied := *f.apiKey
+	return &copied, nil
+}
+
+func newFakeAPIKeys(rateLimit int) *fakeAPIKeys {
+	return &fakeAPIKeys{
+		key:    models.APIKeyPrefix + "0123456789ab_secret",
+		apiKey: &models.OrganizationAPIKey{ID: uuid.New(), OrganizationID: uuid.New(), RateLimitPerMinute: rateLimit},
+	}
+}
+
+func serveWithKey(handler http.Handler, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+key)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthMiddlewareAPIKey(t *testing.T) {
+	keys := newFakeAPIKeys(2)
+	var got *models.OrganizationAPIKey
+	handler := AuthMiddleware(nil, keys)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = GetAPIKeyFromContext(r.Context())
+		if _, ok := GetUserIDFromContext(r.Context()); ok {
+			t.Error("API key request has a user ID")
+		}
+	}))
+
+	if rec := serveWithKey(handler, keys.key); rec.Code != http.StatusOK {
+		t.Fatalf("valid key: status = %d, want 200", rec.Code)
+	}
+	if got == nil || got.ID != keys.apiKey.ID {
+		t.Errorf("key in context = %v, want %s", got, keys.apiKey.ID)
+	}
+	if rec := serveWithKey(handler, models.APIKeyPrefix+"0123456789ab_wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong key: status = %d, want 401", rec.Code)
+	}
+}
+
+func TestUserAuthMiddlewareRejectsAPIKeys(t *testing.T) {
+	keys := newFakeAPIKeys(2)
+	handler := UserAuthMiddleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("API key reached a user-only handler")
+	}))
+
+	if rec := serveWithKey(handler, keys.key); rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+}
+
+func TestAPIKeyRateLimitIsPerKey(t *testing.T) {
+	limited := newFakeAPIKeys(2)
+	other := newFakeAPIKeys(2)
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	limitedHandler := AuthMiddleware(nil, limited)(ok)
+	otherHandler := OptionalAuthMiddleware(nil, other)(ok)
+
+	for i := 0; i < 2; i++ {
+		if rec := serveWithKey(limitedHandler, limited.key); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i+1, rec.Code)
+		}
+	}
+	rec := serveWithKey(limitedHandler, limited.key)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("over the limit: status = %d, want 429", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("429 without Retry-After")
+	}
+
+	// Another key, through the optional middleware, has its own budget.
+	if rec := serveWithKey(otherHandler, other.key); rec.Code != http.StatusOK {
+		t.Errorf("other key: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestAllowLimit(t *testing.T) {
+	limiter := NewRateLimiter(100, time.Minute)
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := limiter.AllowLimit("a", 3, start); !ok {
+			t.Fatalf("request %d refused within a limit of 3", i+1)
+		}
+	}
+	ok, retryAfter := limiter.AllowLimit("a", 3, start.Add(10*time.Second))
+	if ok {
+		t.Fatal("fourth request allowed with a limit of 3")
+	}
+	if retryAfter != 50*time.Second {
+		t.Errorf("retry after %v, want 50s", retryAfter)
+	}
+	if ok, _ := limiter.AllowLimit("b", 1, start); !ok {
+		t.Error("another key refused")
+	}
+	if ok, _ := limiter.AllowLimit("a", 3, start.Add(time.Minute)); !ok {
+		t.Error("request refused after the window reset")
+	}
+}
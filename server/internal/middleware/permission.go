@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"server/internal/policy"
+)
+
+const SubjectKey contextKey = "policy_subject"
+
+// ErrUnauthenticated is returned for permission checks on requests without
+// a signed-in user or an API key.
+var ErrUnauthenticated = errors.New("not signed in")
+
+// SubjectFromRequest returns the caller as a policy subject, reusing the one
+// RequirePermission loaded if there is one.
+func SubjectFromRequest(r *http.Request, authz *policy.Authorizer) (*policy.Subject, error) {
+	if s, ok := r.Context().Value(SubjectKey).(*policy.Subject); ok {
+		return s, nil
+	}
+
+	if apiKey, ok := GetAPIKeyFromContext(r.Context()); ok {
+		return &policy.Subject{APIKey: apiKey}, nil
+	}
+
+	userID, ok := GetUserIDFromContext(r.Context())
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	role, _ := GetUserRoleFromContext(r.Context())
+	return authz.Subject(r.Context(), userID, role)
+}
+
+// Authorize checks that the caller may perform p on res. It fails with
+// ErrUnauthenticated, policy.ErrForbidden, policy.ErrOrganizationUnverified
+// or an error loading the caller.
+func Authorize(r *http.Request, authz *policy.Authorizer, p policy.Permission, res policy.Resource) (*policy.Subject, error) {
+	s, err := SubjectFromRequest(r, authz)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.Check(s, p, res); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// WritePermissionError writes the response for an error from Authorize.
+func WritePermissionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	case errors.Is(err, policy.ErrForbidden), errors.Is(err, policy.ErrOrganizationUnverified):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Printf("permission check failed: %v", err)
+		http.Error(w, "Failed to check permissions", http.StatusInternalServerError)
+	}
+}
+
+// RequirePermission lets through callers who hold p for some resource; the
+// handler checks the specific one. It must run after AuthMiddleware.
+func RequirePermission(authz *policy.Authorizer, p policy.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s, err := Authorize(r, authz, p, policy.Resource{})
+			if err != nil {
+				WritePermissionError(w, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), SubjectKey, s)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
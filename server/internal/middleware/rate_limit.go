@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter counts requests per client IP in fixed windows. Counts are
+// kept in memory, so each server instance limits on its own.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// Allow records a request from key and reports whether it is within the
+// limit. When it is not, it also returns how long until the window resets.
+func (l *RateLimiter) Allow(key string, now time.Time) (bool, time.Duration) {
+	return l.AllowLimit(key, l.limit, now)
+}
+
+// AllowLimit is Allow with a limit for this key, for callers whose clients
+// each have their own.
+func (l *RateLimiter) AllowLimit(key string, limit int, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Drop finished windows once the map grows, so it stays bounded by the
+	// number of clients active within one window.
+	if len(l.windows) > 10000 {
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= l.window {
+				delete(l.windows, k)
+			}
+		}
+	}
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		l.windows[key] = &rateWindow{start: now, count: 1}
+		return true, 0
+	}
+	if w.count >= limit {
+		return false, w.start.Add(l.window).Sub(now)
+	}
+	w.count++
+	return true, 0
+}
+
+// RateLimit rejects requests over limit per client IP per window with 429.
+func RateLimit(limit int, window time.Duration) func(http.Handler) http.Handler {
+	limiter := NewRateLimiter(limit, window)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ok, retryAfter := limiter.Allow(ClientIP(r), time.Now())
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+				http.Error(w, "Too many requests, try again later", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP returns the IP address of the peer that sent r.
+func ClientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
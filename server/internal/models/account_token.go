@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccountTokenPurpose string
+
+const (
+	AccountTokenEmailVerification AccountTokenPurpose = "email_verification"
+	AccountTokenPasswordReset     AccountTokenPurpose = "password_reset"
+	// AccountTokenEmailUnsubscribe signs the unsubscribe link in
+	// notification emails. These tokens aren't stored: the signed ID is the
+	// user's, and using one twice does no harm.
+	AccountTokenEmailUnsubscribe AccountTokenPurpose = "email_unsubscribe"
+)
+
+// TTL is how long a token of this purpose stays valid after it is sent.
+func (p AccountTokenPurpose) TTL() time.Duration {
+	switch p {
+	case AccountTokenPasswordReset:
+		return time.Hour
+	case AccountTokenEmailUnsubscribe:
+		return 365 * 24 * time.Hour
+	}
+	return 48 * time.Hour
+}
+
+// AccountToken is the stored half of an email verification or password
+// reset link. The link itself carries a signature over the token ID.
+type AccountToken struct {
+	ID        uuid.UUID           `json:"id" db:"id"`
+	UserID    uuid.UUID           `json:"user_id" db:"user_id"`
+	Purpose   AccountTokenPurpose `json:"purpose" db:"purpose"`
+	Email     string              `json:"email" db:"email"`
+	ExpiresAt time.Time           `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time          `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
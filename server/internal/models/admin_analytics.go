@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AnalyticsInterval string
+
+const (
+	AnalyticsIntervalDay   AnalyticsInterval = "day"
+	AnalyticsIntervalWeek  AnalyticsInterval = "week"
+	AnalyticsIntervalMonth AnalyticsInterval = "month"
+)
+
+func (i AnalyticsInterval) IsValid() bool {
+	switch i {
+	case AnalyticsIntervalDay, AnalyticsIntervalWeek, AnalyticsIntervalMonth:
+		return true
+	}
+	return false
+}
+
+// Truncate returns the start of the bucket t falls in, in UTC, the way the
+// analytics queries bucket with date_trunc. Weeks start on Monday.
+func (i AnalyticsInterval) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch i {
+	case AnalyticsIntervalWeek:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+	case AnalyticsIntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// AnalyticsFilter narrows every series to a time window and, optionally,
+// a single domain, aid type or organization.
+type AnalyticsFilter struct {
+	Interval       AnalyticsInterval
+	From           time.Time
+	To             time.Time
+	DomainID       *uuid.UUID
+	AidTypeID      *uuid.UUID
+	OrganizationID *uuid.UUID
+}
+
+// BucketCount is the number of points each series returns: one per bucket
+// from the one holding From to the one holding To.
+func (f AnalyticsFilter) BucketCount() int {
+	from, to := f.Interval.Truncate(f.From), f.Interval.Truncate(f.To)
+	switch f.Interval {
+	case AnalyticsIntervalWeek:
+		return int(to.Sub(from).Hours()/(7*24)) + 1
+	case AnalyticsIntervalMonth:
+		return (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	}
+	return int(to.Sub(from).Hours()/24) + 1
+}
+
+type AnalyticsPoint struct {
+	Bucket time.Time `json:"bucket"`
+	Value  float64   `json:"value"`
+}
+
+type AdminAnalyticsSeries struct {
+	DonationVolume       []AnalyticsPoint `json:"donation_volume"`
+	DonationCount        []AnalyticsPoint `json:"donation_count"`
+	NewDonors            []AnalyticsPoint `json:"new_donors"`
+	NewCauses            []AnalyticsPoint `json:"new_causes"`
+	DisbursedAmount      []AnalyticsPoint `json:"disbursed_amount"`
+	VerificationPassRate []AnalyticsPoint `json:"verification_pass_rate"`
+	AvgReceiptScore      []AnalyticsPoint `json:"avg_receipt_score"`
+}
+
+type AdminAnalyticsResponse struct {
+	Interval       AnalyticsInterval    `json:"interval"`
+	From           time.Time            `json:"from"`
+	To             time.Time            `json:"to"`
+	DomainID       *uuid.UUID           `json:"domain_id,omitempty"`
+	AidTypeID      *uuid.UUID           `json:"aid_type_id,omitempty"`
+	OrganizationID *uuid.UUID           `json:"organization_id,omitempty"`
+	Series         AdminAnalyticsSeries `json:"series"`
+}
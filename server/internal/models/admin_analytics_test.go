@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyticsBucketCount(t *testing.T) {
+	ist := time.FixedZone("IST", 5*60*60+30*60)
+	tests := []struct {
+		name     string
+		interval AnalyticsInterval
+		from, to time.Time
+		want     int
+	}{
+		{"same day", AnalyticsIntervalDay, time.Date(2025, 3, 4, 1, 0, 0, 0, time.UTC), time.Date(2025, 3, 4, 23, 0, 0, 0, time.UTC), 1},
+		{"across midnight", AnalyticsIntervalDay, time.Date(2025, 3, 4, 23, 0, 0, 0, time.UTC), time.Date(2025, 3, 5, 1, 0, 0, 0, time.UTC), 2},
+		// 01:00 IST on the 5th is still the 4th in UTC
+		{"offset input", AnalyticsIntervalDay, time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC), time.Date(2025, 3, 5, 1, 0, 0, 0, ist), 1},
+		// Tuesday to the following Monday spans two ISO weeks
+		{"weeks start on Monday", AnalyticsIntervalWeek, time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), 2},
+		{"last to first of month", AnalyticsIntervalMonth, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), 2},
+		{"400 months", AnalyticsIntervalMonth, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2033, 4, 30, 0, 0, 0, 0, time.UTC), 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := AnalyticsFilter{Interval: tt.interval, From: tt.from, To: tt.to}
+			if got := f.BucketCount(); got != tt.want {
+				t.Errorf("BucketCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyticsTruncate(t *testing.T) {
+	at := time.Date(2025, 3, 1, 2, 0, 0, 0, time.FixedZone("IST", 5*60*60+30*60))
+	for interval, want := range map[AnalyticsInterval]time.Time{
+		AnalyticsIntervalDay:   time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC),
+		AnalyticsIntervalWeek:  time.Date(2025, 2, 24, 0, 0, 0, 0, time.UTC),
+		AnalyticsIntervalMonth: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		if got := interval.Truncate(at); !got.Equal(want) || got.Location() != time.UTC {
+			t.Errorf("%s Truncate() = %s, want %s", interval, got, want)
+		}
+	}
+}
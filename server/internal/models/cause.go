@@ -31,10 +31,14 @@ type Cause struct {
 	ExecutionStartTime    *time.Time          `json:"execution_start_time" db:"execution_start_time"`
 	ExecutionEndTime      *time.Time          `json:"execution_end_time" db:"execution_end_time"`
 	FundingStatus         *string             `json:"funding_status" db:"funding_status"`
+	State                 CauseState          `json:"state" db:"state"`
+	StateChangedAt        time.Time           `json:"state_changed_at" db:"state_changed_at"`
 
 	// Extended project & execution metadata
 	BeneficiariesCount int       `json:"beneficiaries_count" db:"beneficiaries_count"`
 	ExecutionLocation  *string   `json:"execution_location" db:"execution_location"`
+	LocationState      *string   `json:"location_state" db:"location_state"`
+	LocationCity       *string   `json:"location_city" db:"location_city"`
 	ImpactGoal         *string   `json:"impact_goal" db:"impact_goal"`
 	ProblemStatement   *string   `json:"problem_statement" db:"problem_statement"`
 	ExecutionPlan      *string   `json:"execution_plan" db:"execution_plan"`
@@ -44,6 +48,9 @@ type Cause struct {
 	// Optional related aggregates for campaign page
 	Products []*CauseProduct `json:"products,omitempty"`
 	Updates  []*CauseUpdate  `json:"updates,omitempty"`
+
+	// Running matching campaigns that still have budget left.
+	Matching []*CauseMatchingSummary `json:"matching_campaigns,omitempty"`
 }
 
 type CauseCategory struct {
@@ -76,12 +83,18 @@ type CreateCauseRequest struct {
 	// Project details (mandatory in UI, optional in API for backward compatibility)
 	BeneficiariesCount *int    `json:"beneficiaries_count,omitempty"`
 	ExecutionLocation  *string `json:"execution_location,omitempty"`
+	LocationState      *string `json:"location_state,omitempty"`
+	LocationCity       *string `json:"location_city,omitempty"`
 	ImpactGoal         *string `json:"impact_goal,omitempty"`
 	ProblemStatement   *string `json:"problem_statement,omitempty"`
 	ExecutionPlan      *string `json:"execution_plan,omitempty"`
 
 	// Optional initial products for structured campaigns
 	Products []*CreateCauseProductInput `json:"products,omitempty"`
+
+	// SaveAsDraft keeps the cause private to the organization. Otherwise it
+	// goes straight to the admin review queue.
+	SaveAsDraft bool `json:"save_as_draft,omitempty"`
 }
 
 type CauseByIDRequest struct {
@@ -108,9 +121,13 @@ type CauseResponse struct {
 	ExecutionStartTime    *time.Time          `json:"execution_start_time"`
 	ExecutionEndTime      *time.Time          `json:"execution_end_time"`
 	FundingStatus         *string             `json:"funding_status"`
+	State                 CauseState          `json:"state"`
+	StateChangedAt        time.Time           `json:"state_changed_at"`
 
 	BeneficiariesCount int       `json:"beneficiaries_count"`
 	ExecutionLocation  *string   `json:"execution_location"`
+	LocationState      *string   `json:"location_state"`
+	LocationCity       *string   `json:"location_city"`
 	ImpactGoal         *string   `json:"impact_goal"`
 	ProblemStatement   *string   `json:"problem_statement"`
 	ExecutionPlan      *string   `json:"execution_plan"`
@@ -119,12 +136,14 @@ type CauseResponse struct {
 
 	Products []*CauseProduct `json:"products,omitempty"`
 	Updates  []*CauseUpdate  `json:"updates,omitempty"`
+
+	// Running matching campaigns that still have budget left.
+	Matching []*CauseMatchingSummary `json:"matching_campaigns,omitempty"`
 }
 
 // ToCauseResponse converts a Cause to CauseResponse
 func (c *Cause) ToCauseResponse() CauseResponse {
-	// Derive funding status for donor UI based on live data
-	computedStatus := computeFundingStatus(c)
+	c.DeriveFundingStatus()
 
 	return CauseResponse{
 		ID:                    c.ID,
@@ -144,10 +163,14 @@ func (c *Cause) ToCauseResponse() CauseResponse {
 		ExecutionRadiusMeters: c.ExecutionRadiusMeters,
 		ExecutionStartTime:    c.ExecutionStartTime,
 		ExecutionEndTime:      c.ExecutionEndTime,
-		FundingStatus:         &computedStatus,
+		FundingStatus:         c.FundingStatus,
+		State:                 c.State,
+		StateChangedAt:        c.StateChangedAt,
 
 		BeneficiariesCount: c.BeneficiariesCount,
 		ExecutionLocation:  c.ExecutionLocation,
+		LocationState:      c.LocationState,
+		LocationCity:       c.LocationCity,
 		ImpactGoal:         c.ImpactGoal,
 		ProblemStatement:   c.ProblemStatement,
 		ExecutionPlan:      c.ExecutionPlan,
@@ -155,45 +178,20 @@ func (c *Cause) ToCauseResponse() CauseResponse {
 		UpdatedAt:          c.UpdatedAt,
 
 		Products: c.Products,
+		Matching: c.Matching,
 		Updates:  c.Updates,
 	}
 }
 
-// computeFundingStatus applies donor-facing funding state rules:
-// - collected_amount == 0 → "Not Started"
-// - collected_amount > 0 and < goal_amount → "Active"
-// - collected_amount >= goal_amount → "Fully Funded"
-// - deadline passed and not fully funded → "Closed"
-func computeFundingStatus(c *Cause) string {
-	// Defensive defaults
-	if c.GoalAmount == nil || *c.GoalAmount <= 0 {
-		if c.CollectedAmount <= 0 {
-			return "Not Started"
-		}
-		return "Active"
-	}
-
-	now := time.Now()
-	hasDeadline := c.Deadline != nil
-	collected := float64(c.CollectedAmount)
-	goal := float64(*c.GoalAmount)
-
-	if collected <= 0 {
-		if hasDeadline && c.Deadline.Before(now) {
-			return "Closed"
-		}
-		return "Not Started"
+// DeriveFundingStatus sets FundingStatus from the cause's lifecycle state,
+// replacing whatever was stored with the cause.
+func (c *Cause) DeriveFundingStatus() {
+	var goal float32
+	if c.GoalAmount != nil {
+		goal = *c.GoalAmount
 	}
-
-	if collected >= goal {
-		return "Fully Funded"
-	}
-
-	if hasDeadline && c.Deadline.Before(now) {
-		return "Closed"
-	}
-
-	return "Active"
+	status := c.State.FundingStatus(c.CollectedAmount, goal)
+	c.FundingStatus = &status
 }
 
 // CreateCauseProductInput represents a product payload when creating a cause
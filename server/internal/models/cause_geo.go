@@ -0,0 +1,52 @@
+package models
+
+import "github.com/google/uuid"
+
+// NearbyCausesQuery finds causes whose execution point lies within
+// RadiusMeters of (Lat, Lng), one page at a time.
+type NearbyCausesQuery struct {
+	Lat          float64
+	Lng          float64
+	RadiusMeters float64
+	Page         CursorParams
+}
+
+// CauseBoundingBox selects causes for the visible area of a map. MinLng may
+// be greater than MaxLng when the box crosses the antimeridian.
+type CauseBoundingBox struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+	Limit  int
+}
+
+type NearbyCause struct {
+	Cause          *Cause
+	DistanceMeters float64
+}
+
+type NearbyCauseResponse struct {
+	CauseResponse
+	DistanceMeters float64 `json:"distance_meters"`
+}
+
+// CauseMapPin is the minimal data needed to draw a cause on a map.
+type CauseMapPin struct {
+	ID                    uuid.UUID  `json:"id" db:"id"`
+	Title                 string     `json:"title" db:"title"`
+	Lat                   float64    `json:"lat" db:"execution_lat"`
+	Lng                   float64    `json:"lng" db:"execution_lng"`
+	ExecutionRadiusMeters *int       `json:"execution_radius_meters" db:"execution_radius_meters"`
+	CoverImageURL         *string    `json:"cover_image_url" db:"cover_image_url"`
+	CollectedAmount       float32    `json:"collected_amount" db:"collected_amount"`
+	GoalAmount            *float32   `json:"goal_amount" db:"goal_amount"`
+	State                 CauseState `json:"state" db:"state"`
+}
+
+type CauseMapResponse struct {
+	Pins []*CauseMapPin `json:"pins"`
+	// Truncated is set when more causes fall inside the box than were
+	// returned; the client should zoom in.
+	Truncated bool `json:"truncated"`
+}
@@ -17,5 +17,19 @@ type CauseProduct struct {
 	QuantityFunded int       `json:"quantity_funded" db:"quantity_funded"`
 	ImageURL       string    `json:"image_url" db:"image_url"`
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+
+	QuantityRemaining int     `json:"quantity_remaining"`
+	FundedPercentage  float64 `json:"funded_percentage"`
+}
+
+// SetProgress fills the derived funding progress fields.
+func (p *CauseProduct) SetProgress() {
+	p.QuantityRemaining = p.QuantityNeeded - p.QuantityFunded
+	if p.QuantityRemaining < 0 {
+		p.QuantityRemaining = 0
+	}
+	if p.QuantityNeeded > 0 {
+		p.FundedPercentage = float64(p.QuantityFunded) / float64(p.QuantityNeeded) * 100
+	}
 }
 
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CausePublicationDecision string
+
+const (
+	CausePublicationApproved CausePublicationDecision = "approved"
+	CausePublicationRejected CausePublicationDecision = "rejected"
+)
+
+type CausePublicationReviewRequest struct {
+	Comments *string `json:"comments,omitempty"`
+}
+
+type CausePublicationReview struct {
+	ID         uuid.UUID                `json:"id" db:"id"`
+	CauseID    uuid.UUID                `json:"cause_id" db:"cause_id"`
+	ReviewerID *uuid.UUID               `json:"reviewer_id,omitempty" db:"reviewer_id"`
+	Decision   CausePublicationDecision `json:"decision" db:"decision"`
+	Comments   *string                  `json:"comments,omitempty" db:"comments"`
+	CreatedAt  time.Time                `json:"created_at" db:"created_at"`
+}
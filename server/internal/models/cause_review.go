@@ -6,21 +6,99 @@ import (
 	"github.com/google/uuid"
 )
 
+// Reasons a donor can give when reporting a review.
+const (
+	ReviewReportReasonSpam       = "spam"
+	ReviewReportReasonAbuse      = "abuse"
+	ReviewReportReasonMisleading = "misleading"
+	ReviewReportReasonOffTopic   = "off_topic"
+	ReviewReportReasonOther      = "other"
+)
+
+func IsValidReviewReportReason(reason string) bool {
+	switch reason {
+	case ReviewReportReasonSpam, ReviewReportReasonAbuse, ReviewReportReasonMisleading,
+		ReviewReportReasonOffTopic, ReviewReportReasonOther:
+		return true
+	}
+	return false
+}
+
 type CreateCauseReviewRequest struct {
 	ReviewText string `json:"review_text"`
+	Rating     *int   `json:"rating,omitempty"`
+}
+
+type UpdateCauseReviewRequest struct {
+	ReviewText *string `json:"review_text,omitempty"`
+	Rating     *int    `json:"rating,omitempty"`
+}
+
+type ReportCauseReviewRequest struct {
+	Reason  string  `json:"reason"`
+	Details *string `json:"details,omitempty"`
+}
+
+type CauseReviewReplyRequest struct {
+	ResponseText string `json:"response_text"`
+}
+
+type ModerateCauseReviewRequest struct {
+	Reason *string `json:"reason,omitempty"`
+}
+
+type CauseReviewReply struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	ResponseText   string    `json:"response_text"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 type CauseReviewResponse struct {
-	ID        uuid.UUID `json:"id"`
-	CauseID   uuid.UUID `json:"cause_id"`
-	UserID    uuid.UUID `json:"user_id"`
-	UserName  string    `json:"user_name"`
-	ReviewText string   `json:"review_text"`
-	CreatedAt time.Time `json:"created_at"`
+	ID         uuid.UUID         `json:"id"`
+	CauseID    uuid.UUID         `json:"cause_id"`
+	UserID     uuid.UUID         `json:"user_id"`
+	UserName   string            `json:"user_name"`
+	ReviewText string            `json:"review_text"`
+	Rating     *int              `json:"rating,omitempty"`
+	IsHidden   bool              `json:"is_hidden,omitempty"`
+	Reply      *CauseReviewReply `json:"ngo_response,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  *time.Time        `json:"updated_at,omitempty"`
 }
 
+// CauseReviewsResponse is one page of a cause's reviews. Count and
+// AverageRating cover all visible reviews, not just this page.
 type CauseReviewsResponse struct {
-	Count   int                  `json:"count"`
-	Reviews []*CauseReviewResponse `json:"reviews"`
+	Count         int                    `json:"count"`
+	AverageRating *float64               `json:"average_rating,omitempty"`
+	Reviews       []*CauseReviewResponse `json:"reviews"`
+	NextCursor    *string                `json:"next_cursor"`
+	PrevCursor    *string                `json:"prev_cursor"`
+}
+
+// ReportedCauseReview is an entry in the admin moderation queue.
+type ReportedCauseReview struct {
+	Review         *CauseReviewResponse `json:"review"`
+	OpenReports    int                  `json:"open_reports"`
+	Reasons        []string             `json:"reasons"`
+	HiddenReason   *string              `json:"hidden_reason,omitempty"`
+	HiddenAt       *time.Time           `json:"hidden_at,omitempty"`
+	LastReportedAt *time.Time           `json:"last_reported_at,omitempty"`
 }
 
+// QueueTime is the moderation queue ordering key: when the review was
+// hidden for the hidden list, otherwise when it was last reported.
+func (r *ReportedCauseReview) QueueTime() time.Time {
+	if r.Review.IsHidden {
+		if r.HiddenAt != nil {
+			return *r.HiddenAt
+		}
+		return r.Review.CreatedAt
+	}
+	if r.LastReportedAt != nil {
+		return *r.LastReportedAt
+	}
+	return r.Review.CreatedAt
+}
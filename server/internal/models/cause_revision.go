@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UpdateCauseRequest holds the editable fields of a cause. Nil fields are
+// left unchanged.
+type UpdateCauseRequest struct {
+	Title                 *string    `json:"title,omitempty"`
+	Description           *string    `json:"description,omitempty"`
+	GoalAmount            *float32   `json:"goal_amount,omitempty"`
+	Deadline              *time.Time `json:"deadline,omitempty"`
+	ExecutionPlan         *string    `json:"execution_plan,omitempty"`
+	ExecutionLat          *float64   `json:"execution_lat,omitempty"`
+	ExecutionLng          *float64   `json:"execution_lng,omitempty"`
+	ExecutionRadiusMeters *int       `json:"execution_radius_meters,omitempty"`
+
+	// Reason is shown to donors alongside the revision.
+	Reason *string `json:"reason,omitempty"`
+}
+
+type CauseFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type CauseRevision struct {
+	ID                    uuid.UUID          `json:"id" db:"id"`
+	CauseID               uuid.UUID          `json:"cause_id" db:"cause_id"`
+	RevisionNumber        int                `json:"revision_number" db:"revision_number"`
+	EditedBy              *uuid.UUID         `json:"edited_by,omitempty" db:"edited_by"`
+	Title                 string             `json:"title" db:"title"`
+	Description           *string            `json:"description" db:"description"`
+	GoalAmount            *float32           `json:"goal_amount" db:"goal_amount"`
+	Deadline              *time.Time         `json:"deadline" db:"deadline"`
+	ExecutionPlan         *string            `json:"execution_plan" db:"execution_plan"`
+	ExecutionLat          *float64           `json:"execution_lat" db:"execution_lat"`
+	ExecutionLng          *float64           `json:"execution_lng" db:"execution_lng"`
+	ExecutionRadiusMeters *int               `json:"execution_radius_meters" db:"execution_radius_meters"`
+	Changes               []CauseFieldChange `json:"changes" db:"changes"`
+	Reason                *string            `json:"reason,omitempty" db:"reason"`
+	CreatedAt             time.Time          `json:"created_at" db:"created_at"`
+}
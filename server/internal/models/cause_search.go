@@ -0,0 +1,109 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CauseSearchSort string
+
+const (
+	CauseSearchSortRelevance     CauseSearchSort = "relevance"
+	CauseSearchSortNewest        CauseSearchSort = "newest"
+	CauseSearchSortEndingSoon    CauseSearchSort = "ending_soon"
+	CauseSearchSortMostFunded    CauseSearchSort = "most_funded"
+	CauseSearchSortClosestToGoal CauseSearchSort = "closest_to_goal"
+)
+
+func (s CauseSearchSort) IsValid() bool {
+	switch s {
+	case CauseSearchSortRelevance, CauseSearchSortNewest, CauseSearchSortEndingSoon,
+		CauseSearchSortMostFunded, CauseSearchSortClosestToGoal:
+		return true
+	}
+	return false
+}
+
+// TrustBand buckets an organization's overall trust score.
+type TrustBand string
+
+const (
+	TrustBandHigh    TrustBand = "high"    // 75 and above
+	TrustBandMedium  TrustBand = "medium"  // 50 up to 75
+	TrustBandLow     TrustBand = "low"     // below 50
+	TrustBandUnrated TrustBand = "unrated" // no score calculated yet
+)
+
+func (b TrustBand) IsValid() bool {
+	switch b {
+	case TrustBandHigh, TrustBandMedium, TrustBandLow, TrustBandUnrated:
+		return true
+	}
+	return false
+}
+
+// Funding status labels, from CauseState.FundingStatus.
+const (
+	FundingStatusNotStarted  = "Not Started"
+	FundingStatusActive      = "Active"
+	FundingStatusFullyFunded = "Fully Funded"
+	FundingStatusClosed      = "Closed"
+)
+
+func IsValidFundingStatus(status string) bool {
+	switch status {
+	case FundingStatusNotStarted, FundingStatusActive, FundingStatusFullyFunded, FundingStatusClosed:
+		return true
+	}
+	return false
+}
+
+// CauseSearchFilter holds the query and facets for GET /api/causes/search.
+// Values within one facet are ORed; different facets are ANDed.
+type CauseSearchFilter struct {
+	Query           string
+	DomainIDs       []uuid.UUID
+	AidTypeIDs      []uuid.UUID
+	FundingStatuses []string
+	// Regions match a cause's location_state.
+	Regions      []string
+	Cities       []string
+	TrustBands   []TrustBand
+	DeadlineFrom *time.Time
+	DeadlineTo   *time.Time
+	Sort         CauseSearchSort
+	Page         CursorParams
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+// CauseSearchFacets counts matches per facet value. Each facet is counted
+// with every other filter applied but not its own, so the client can show
+// how many results selecting another value would add.
+type CauseSearchFacets struct {
+	Domains         []FacetCount `json:"domains"`
+	AidTypes        []FacetCount `json:"aid_types"`
+	FundingStatuses []FacetCount `json:"funding_statuses"`
+	Regions         []FacetCount `json:"regions"`
+	Cities          []FacetCount `json:"cities"`
+	TrustBands      []FacetCount `json:"trust_bands"`
+}
+
+type CauseSearchResult struct {
+	Causes *Page[*Cause]
+	Total  int64
+	Facets CauseSearchFacets
+}
+
+// CauseSearchResponse is a page of results (in data) plus the total number
+// of matches and facet counts.
+type CauseSearchResponse struct {
+	*CursorPage
+	Total  int64             `json:"total"`
+	Facets CauseSearchFacets `json:"facets"`
+}
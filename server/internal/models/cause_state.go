@@ -0,0 +1,181 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CauseState string
+
+const (
+	CauseStateDraft         CauseState = "draft"
+	CauseStatePendingReview CauseState = "pending_review"
+	CauseStateLive          CauseState = "live"
+	CauseStateFullyFunded   CauseState = "fully_funded"
+	CauseStateExecuting     CauseState = "executing"
+	CauseStateCompleted     CauseState = "completed"
+	CauseStateClosed        CauseState = "closed"
+	CauseStateSuspended     CauseState = "suspended"
+)
+
+// CauseStateActor identifies who is driving a state transition.
+type CauseStateActor string
+
+const (
+	CauseStateActorSystem       CauseStateActor = "system"
+	CauseStateActorOrganization CauseStateActor = "organization"
+	CauseStateActorAdmin        CauseStateActor = "admin"
+)
+
+type causeStateEdge struct {
+	from CauseState
+	to   CauseState
+}
+
+// causeStateTransitions lists every legal edge and the actors allowed to
+// take it. Admins may take any legal edge.
+var causeStateTransitions = map[causeStateEdge][]CauseStateActor{
+	{CauseStateDraft, CauseStatePendingReview}: {CauseStateActorOrganization},
+	{CauseStateDraft, CauseStateClosed}:        {CauseStateActorOrganization},
+
+	{CauseStatePendingReview, CauseStateDraft}:  {CauseStateActorOrganization},
+	{CauseStatePendingReview, CauseStateLive}:   {},
+	{CauseStatePendingReview, CauseStateClosed}: {},
+
+	{CauseStateLive, CauseStateFullyFunded}: {CauseStateActorSystem},
+	{CauseStateLive, CauseStateExecuting}:   {CauseStateActorOrganization},
+	{CauseStateLive, CauseStateClosed}:      {CauseStateActorSystem, CauseStateActorOrganization},
+	{CauseStateLive, CauseStateSuspended}:   {},
+
+	// A goal raised above the collected amount reopens fundraising.
+	{CauseStateFullyFunded, CauseStateLive}:      {CauseStateActorSystem},
+	{CauseStateFullyFunded, CauseStateExecuting}: {CauseStateActorOrganization},
+	{CauseStateFullyFunded, CauseStateSuspended}: {},
+
+	{CauseStateExecuting, CauseStateCompleted}: {CauseStateActorOrganization},
+	{CauseStateExecuting, CauseStateSuspended}: {},
+
+	// Fundraising closed on deadline with partial funds can still be executed.
+	{CauseStateClosed, CauseStateExecuting}: {CauseStateActorOrganization},
+	{CauseStateClosed, CauseStateSuspended}: {},
+
+	{CauseStateSuspended, CauseStateLive}:        {},
+	{CauseStateSuspended, CauseStateFullyFunded}: {},
+	{CauseStateSuspended, CauseStateExecuting}:   {},
+	{CauseStateSuspended, CauseStateClosed}:      {},
+}
+
+func (s CauseState) IsValid() bool {
+	switch s {
+	case CauseStateDraft, CauseStatePendingReview, CauseStateLive, CauseStateFullyFunded,
+		CauseStateExecuting, CauseStateCompleted, CauseStateClosed, CauseStateSuspended:
+		return true
+	}
+	return false
+}
+
+// CanTransitionCause reports whether actor may move a cause from one state
+// to another, with a reason suitable for returning to the caller.
+func CanTransitionCause(from, to CauseState, actor CauseStateActor) error {
+	if !to.IsValid() {
+		return fmt.Errorf("unknown cause state %q", to)
+	}
+	if from == to {
+		return fmt.Errorf("cause is already %s", to)
+	}
+
+	actors, ok := causeStateTransitions[causeStateEdge{from, to}]
+	if !ok {
+		return fmt.Errorf("cannot move a cause from %s to %s", from, to)
+	}
+	if actor == CauseStateActorAdmin {
+		return nil
+	}
+	for _, a := range actors {
+		if a == actor {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s cannot move a cause from %s to %s", actor, from, to)
+}
+
+// IsPublic is false until an admin has approved the cause.
+func (s CauseState) IsPublic() bool {
+	return s != CauseStateDraft && s != CauseStatePendingReview
+}
+
+// AcceptsDonations is true only while the cause is openly fundraising.
+func (s CauseState) AcceptsDonations() bool {
+	return s == CauseStateLive
+}
+
+// AllowsProofUploads is true once a cause is public and until it is
+// finished or suspended.
+func (s CauseState) AllowsProofUploads() bool {
+	switch s {
+	case CauseStateLive, CauseStateFullyFunded, CauseStateExecuting:
+		return true
+	}
+	return false
+}
+
+// AllowsDisbursement blocks milestone payouts for causes that were never
+// approved or have been suspended.
+func (s CauseState) AllowsDisbursement() bool {
+	switch s {
+	case CauseStateDraft, CauseStatePendingReview, CauseStateSuspended:
+		return false
+	}
+	return true
+}
+
+// FundingStatus is the donor-facing label for a cause in state s that has
+// collected the given amount towards goal. Deadlines aren't checked here:
+// the lifecycle job closes causes whose deadline has passed.
+func (s CauseState) FundingStatus(collected, goal float32) string {
+	switch s {
+	case CauseStateLive:
+		if collected <= 0 {
+			return FundingStatusNotStarted
+		}
+		return FundingStatusActive
+	case CauseStateFullyFunded:
+		return FundingStatusFullyFunded
+	case CauseStateExecuting, CauseStateCompleted:
+		// Causes closed with partial funds can go on to be executed.
+		if goal > 0 && collected >= goal {
+			return FundingStatusFullyFunded
+		}
+		return FundingStatusClosed
+	case CauseStateClosed, CauseStateSuspended:
+		return FundingStatusClosed
+	}
+	return FundingStatusNotStarted
+}
+
+type ChangeCauseStateRequest struct {
+	State  CauseState `json:"state"`
+	Reason *string    `json:"reason,omitempty"`
+}
+
+type CauseStateTransition struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	CauseID   uuid.UUID       `json:"cause_id" db:"cause_id"`
+	FromState *CauseState     `json:"from_state" db:"from_state"`
+	ToState   CauseState      `json:"to_state" db:"to_state"`
+	ActorType CauseStateActor `json:"actor_type" db:"actor_type"`
+	ActorID   *uuid.UUID      `json:"actor_id,omitempty" db:"actor_id"`
+	Reason    *string         `json:"reason,omitempty" db:"reason"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// ScheduledCauseTransition is a cause the lifecycle job should move because
+// its goal was reached or its deadline passed.
+type ScheduledCauseTransition struct {
+	CauseID uuid.UUID
+	From    CauseState
+	To      CauseState
+	Reason  string
+}
@@ -0,0 +1,78 @@
+package models
+
+import "testing"
+
+func TestCanTransitionCause(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    CauseState
+		to      CauseState
+		actor   CauseStateActor
+		allowed bool
+	}{
+		{"org submits draft", CauseStateDraft, CauseStatePendingReview, CauseStateActorOrganization, true},
+		{"org cannot self-approve", CauseStatePendingReview, CauseStateLive, CauseStateActorOrganization, false},
+		{"admin approves", CauseStatePendingReview, CauseStateLive, CauseStateActorAdmin, true},
+		{"system marks fully funded", CauseStateLive, CauseStateFullyFunded, CauseStateActorSystem, true},
+		{"org cannot mark fully funded", CauseStateLive, CauseStateFullyFunded, CauseStateActorOrganization, false},
+		{"system closes on deadline", CauseStateLive, CauseStateClosed, CauseStateActorSystem, true},
+		{"org starts execution", CauseStateFullyFunded, CauseStateExecuting, CauseStateActorOrganization, true},
+		{"org completes", CauseStateExecuting, CauseStateCompleted, CauseStateActorOrganization, true},
+		{"completed is terminal", CauseStateCompleted, CauseStateLive, CauseStateActorAdmin, false},
+		{"org cannot suspend", CauseStateLive, CauseStateSuspended, CauseStateActorOrganization, false},
+		{"admin suspends", CauseStateLive, CauseStateSuspended, CauseStateActorAdmin, true},
+		{"org cannot lift suspension", CauseStateSuspended, CauseStateLive, CauseStateActorOrganization, false},
+		{"no self transition", CauseStateLive, CauseStateLive, CauseStateActorAdmin, false},
+		{"unknown target", CauseStateLive, CauseState("archived"), CauseStateActorAdmin, false},
+		{"draft cannot skip review", CauseStateDraft, CauseStateLive, CauseStateActorAdmin, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CanTransitionCause(tt.from, tt.to, tt.actor)
+			if tt.allowed && err != nil {
+				t.Fatalf("expected transition to be allowed, got %v", err)
+			}
+			if !tt.allowed && err == nil {
+				t.Fatalf("expected transition %s -> %s by %s to be rejected", tt.from, tt.to, tt.actor)
+			}
+		})
+	}
+}
+
+func TestCauseStateGuards(t *testing.T) {
+	if !CauseStateLive.AcceptsDonations() || CauseStateSuspended.AcceptsDonations() || CauseStateDraft.AcceptsDonations() {
+		t.Fatal("only live causes should accept donations")
+	}
+	if CauseStateCompleted.AllowsProofUploads() || !CauseStateExecuting.AllowsProofUploads() {
+		t.Fatal("proof uploads should be allowed while executing and not after completion")
+	}
+	if CauseStateSuspended.AllowsDisbursement() || !CauseStateFullyFunded.AllowsDisbursement() {
+		t.Fatal("suspended causes must not receive disbursements")
+	}
+}
+
+func TestCauseFundingStatus(t *testing.T) {
+	tests := []struct {
+		state     CauseState
+		collected float32
+		goal      float32
+		want      string
+	}{
+		{CauseStateDraft, 0, 1000, FundingStatusNotStarted},
+		{CauseStateLive, 0, 1000, FundingStatusNotStarted},
+		{CauseStateLive, 400, 1000, FundingStatusActive},
+		// The lifecycle job hasn't moved the cause yet.
+		{CauseStateLive, 1000, 1000, FundingStatusActive},
+		{CauseStateFullyFunded, 1000, 1000, FundingStatusFullyFunded},
+		{CauseStateClosed, 400, 1000, FundingStatusClosed},
+		{CauseStateExecuting, 400, 1000, FundingStatusClosed},
+		{CauseStateCompleted, 1200, 1000, FundingStatusFullyFunded},
+		{CauseStateSuspended, 400, 1000, FundingStatusClosed},
+	}
+	for _, tt := range tests {
+		if got := tt.state.FundingStatus(tt.collected, tt.goal); got != tt.want {
+			t.Errorf("%s with %v of %v: got %q, want %q", tt.state, tt.collected, tt.goal, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultCursorLimit = 50
+	maxCursorLimit     = 200
+)
+
+type CursorDirection string
+
+const (
+	CursorNext CursorDirection = "next"
+	CursorPrev CursorDirection = "prev"
+)
+
+// ErrInvalidCursor is returned for cursors that can't be decoded or that
+// came from a list with a different ordering.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks a position in a list ordered by (timestamp, id), or by
+// (value, id) for lists ranked by a number such as relevance or distance.
+// The id breaks ties so rows sharing a key are never skipped or repeated.
+// Clients treat the encoded form as opaque.
+type Cursor struct {
+	Timestamp time.Time       `json:"t"`
+	Value     *float64        `json:"v,omitempty"`
+	ID        uuid.UUID       `json:"i"`
+	Direction CursorDirection `json:"d"`
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Direction != CursorNext && c.Direction != CursorPrev {
+		return nil, ErrInvalidCursor
+	}
+
+	return &c, nil
+}
+
+// CursorParams is the requested page: up to Limit rows after (or, for a
+// prev cursor, before) Cursor. A nil Cursor means the first page.
+type CursorParams struct {
+	Limit  int
+	Cursor *Cursor
+}
+
+// Backward reports whether the page is being fetched towards the start of
+// the list, in which case repositories query in reverse order.
+func (p CursorParams) Backward() bool {
+	return p.Cursor != nil && p.Cursor.Direction == CursorPrev
+}
+
+// GetCursorParams reads limit and cursor from the query string.
+func GetCursorParams(r *http.Request) (CursorParams, error) {
+	params := CursorParams{Limit: defaultCursorLimit}
+
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return params, errors.New("limit must be a positive integer")
+		}
+		if limit > maxCursorLimit {
+			limit = maxCursorLimit
+		}
+		params.Limit = limit
+	}
+
+	if raw := r.URL.Query().Get("cursor"); raw != "" {
+		cursor, err := DecodeCursor(raw)
+		if err != nil {
+			return params, err
+		}
+		params.Cursor = cursor
+	}
+
+	return params, nil
+}
+
+// CursorPage wraps one page of a cursor-paginated list. A nil cursor means
+// there is nothing further in that direction.
+type CursorPage struct {
+	Data       interface{} `json:"data"`
+	NextCursor *string     `json:"next_cursor"`
+	PrevCursor *string     `json:"prev_cursor"`
+	Limit      int         `json:"limit"`
+}
+
+// Page is one page of items in display order, with cursors for the
+// neighbouring pages.
+type Page[T any] struct {
+	Items      []T
+	NextCursor *string
+	PrevCursor *string
+}
+
+// Response builds the JSON envelope for the page.
+func (pg *Page[T]) Response(p CursorParams) *CursorPage {
+	return &CursorPage{
+		Data:       pg.Items,
+		NextCursor: pg.NextCursor,
+		PrevCursor: pg.PrevCursor,
+		Limit:      p.Limit,
+	}
+}
+
+// Paginate turns the rows a repository fetched for p (up to Limit+1, in
+// query order) into a page. key gives the ordering key of an item.
+func Paginate[T any](items []T, p CursorParams, key func(T) (time.Time, uuid.UUID)) *Page[T] {
+	return paginate(items, p, func(item T) Cursor {
+		ts, id := key(item)
+		return Cursor{Timestamp: ts, ID: id}
+	})
+}
+
+// PaginateByValue is Paginate for lists ordered by a numeric key.
+func PaginateByValue[T any](items []T, p CursorParams, key func(T) (float64, uuid.UUID)) *Page[T] {
+	return paginate(items, p, func(item T) Cursor {
+		value, id := key(item)
+		return Cursor{Value: &value, ID: id}
+	})
+}
+
+func paginate[T any](items []T, p CursorParams, position func(T) Cursor) *Page[T] {
+	hasMore := len(items) > p.Limit
+	if hasMore {
+		items = items[:p.Limit]
+	}
+
+	if p.Backward() {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	if len(items) == 0 {
+		return &Page[T]{Items: items}
+	}
+
+	cursorFor := func(item T, dir CursorDirection) *string {
+		c := position(item)
+		c.Direction = dir
+		encoded := c.Encode()
+		return &encoded
+	}
+
+	// Whichever page the client came from still exists, so a cursor back
+	// towards it is always returned.
+	var next, prev *string
+	if p.Backward() {
+		next = cursorFor(items[len(items)-1], CursorNext)
+		if hasMore {
+			prev = cursorFor(items[0], CursorPrev)
+		}
+	} else {
+		if hasMore {
+			next = cursorFor(items[len(items)-1], CursorNext)
+		}
+		if p.Cursor != nil {
+			prev = cursorFor(items[0], CursorPrev)
+		}
+	}
+
+	return &Page[T]{Items: items, NextCursor: next, PrevCursor: prev}
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type cursorItem struct {
+	at time.Time
+	id uuid.UUID
+}
+
+func cursorItemKey(i cursorItem) (time.Time, uuid.UUID) {
+	return i.at, i.id
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := Cursor{Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC), ID: uuid.New(), Direction: CursorPrev}
+
+	decoded, err := DecodeCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if !decoded.Timestamp.Equal(c.Timestamp) || decoded.ID != c.ID || decoded.Direction != c.Direction {
+		t.Fatalf("got %+v, want %+v", decoded, c)
+	}
+
+	for _, bad := range []string{"not base64!", "bm90IGpzb24", Cursor{ID: uuid.New()}.Encode()} {
+		if _, err := DecodeCursor(bad); err == nil {
+			t.Errorf("DecodeCursor(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	items := make([]cursorItem, 5)
+	for i := range items {
+		items[i] = cursorItem{at: base.Add(time.Duration(i) * time.Minute), id: uuid.New()}
+	}
+
+	t.Run("first page with more", func(t *testing.T) {
+		p := CursorParams{Limit: 2}
+		page := Paginate(append([]cursorItem(nil), items[:3]...), p, cursorItemKey)
+		if len(page.Items) != 2 || page.Items[0] != items[0] {
+			t.Fatalf("unexpected items %v", page.Items)
+		}
+		if page.NextCursor == nil || page.PrevCursor != nil {
+			t.Fatalf("want next only, got next=%v prev=%v", page.NextCursor, page.PrevCursor)
+		}
+		next, _ := DecodeCursor(*page.NextCursor)
+		if next.ID != items[1].id || next.Direction != CursorNext {
+			t.Fatalf("next cursor points at %v", next)
+		}
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		p := CursorParams{Limit: 2, Cursor: &Cursor{Direction: CursorNext}}
+		page := Paginate(append([]cursorItem(nil), items[3:]...), p, cursorItemKey)
+		if page.NextCursor != nil || page.PrevCursor == nil {
+			t.Fatalf("want prev only, got next=%v prev=%v", page.NextCursor, page.PrevCursor)
+		}
+	})
+
+	t.Run("backward page is reversed", func(t *testing.T) {
+		// A prev query returns rows in reverse order, with one extra row.
+		p := CursorParams{Limit: 2, Cursor: &Cursor{Direction: CursorPrev}}
+		page := Paginate([]cursorItem{items[2], items[1], items[0]}, p, cursorItemKey)
+		if len(page.Items) != 2 || page.Items[0] != items[1] || page.Items[1] != items[2] {
+			t.Fatalf("unexpected items %v", page.Items)
+		}
+		if page.NextCursor == nil || page.PrevCursor == nil {
+			t.Fatalf("want both cursors, got next=%v prev=%v", page.NextCursor, page.PrevCursor)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		page := Paginate([]cursorItem{}, CursorParams{Limit: 2}, cursorItemKey)
+		if len(page.Items) != 0 || page.NextCursor != nil || page.PrevCursor != nil {
+			t.Fatalf("unexpected page %+v", page)
+		}
+	})
+}
+
+func TestPaginateByValue(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	distances := map[uuid.UUID]float64{ids[0]: 120.5, ids[1]: 980, ids[2]: 4000.25}
+	key := func(id uuid.UUID) (float64, uuid.UUID) { return distances[id], id }
+
+	page := PaginateByValue(append([]uuid.UUID(nil), ids...), CursorParams{Limit: 2}, key)
+	if page.NextCursor == nil {
+		t.Fatal("want a next cursor")
+	}
+
+	next, err := DecodeCursor(*page.NextCursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if next.Value == nil || *next.Value != 980 || next.ID != ids[1] {
+		t.Fatalf("next cursor points at %+v", next)
+	}
+
+	// Timestamp cursors carry no value, so value lists can tell them apart.
+	plain, _ := DecodeCursor(Cursor{ID: ids[0], Direction: CursorNext}.Encode())
+	if plain.Value != nil {
+		t.Fatalf("timestamp cursor decoded with value %v", *plain.Value)
+	}
+}
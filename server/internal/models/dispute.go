@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen      DisputeStatus = "open"
+	DisputeStatusInReview  DisputeStatus = "in_review"
+	DisputeStatusResolved  DisputeStatus = "resolved"
+	DisputeStatusDismissed DisputeStatus = "dismissed"
+)
+
+type DisputePriority string
+
+const (
+	DisputePriorityLow      DisputePriority = "low"
+	DisputePriorityMedium   DisputePriority = "medium"
+	DisputePriorityHigh     DisputePriority = "high"
+	DisputePriorityCritical DisputePriority = "critical"
+)
+
+// Dispute is a concern someone has raised about how an organization is
+// running a cause, for the platform to look into.
+type Dispute struct {
+	ID              uuid.UUID       `json:"id" db:"id"`
+	OrganizationID  uuid.UUID       `json:"organization_id" db:"organization_id"`
+	CauseID         *uuid.UUID      `json:"cause_id,omitempty" db:"cause_id"`
+	OpenedBy        *uuid.UUID      `json:"opened_by,omitempty" db:"opened_by"`
+	Title           string          `json:"title" db:"title"`
+	Description     string          `json:"description" db:"description"`
+	EvidenceURL     *string         `json:"evidence_url,omitempty" db:"evidence_url"`
+	Status          DisputeStatus   `json:"status" db:"status"`
+	Priority        DisputePriority `json:"priority" db:"priority"`
+	ResolutionNotes *string         `json:"resolution_notes,omitempty" db:"resolution_notes"`
+	ResolvedBy      *uuid.UUID      `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolvedAt      *time.Time      `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+type OpenDisputeRequest struct {
+	CauseID     uuid.UUID `json:"cause_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	EvidenceURL *string   `json:"evidence_url"`
+}
+
+func (s DisputeStatus) IsValid() bool {
+	switch s {
+	case DisputeStatusOpen, DisputeStatusInReview, DisputeStatusResolved, DisputeStatusDismissed:
+		return true
+	}
+	return false
+}
+
+// IsClosed reports whether the dispute has been decided.
+func (s DisputeStatus) IsClosed() bool {
+	return s == DisputeStatusResolved || s == DisputeStatusDismissed
+}
+
+func (p DisputePriority) IsValid() bool {
+	switch p {
+	case DisputePriorityLow, DisputePriorityMedium, DisputePriorityHigh, DisputePriorityCritical:
+		return true
+	}
+	return false
+}
+
+// UpdateDisputeRequest is how the platform moves a dispute along. Fields
+// left out are unchanged.
+type UpdateDisputeRequest struct {
+	Status          *DisputeStatus   `json:"status"`
+	Priority        *DisputePriority `json:"priority"`
+	ResolutionNotes *string          `json:"resolution_notes"`
+}
@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"server/internal/blockchain"
 	"server/internal/blockchain/contracts"
+	"server/internal/pii"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +18,22 @@ const (
 	DonationStatusFailed    DonationStatus = "failed"
 )
 
+// TributeType says how a dedicated donation honours someone.
+type TributeType string
+
+const (
+	TributeTypeHonour TributeType = "honour"
+	TributeTypeMemory TributeType = "memory"
+)
+
+func (t TributeType) IsValid() bool {
+	return t == TributeTypeHonour || t == TributeTypeMemory
+}
+
+// AnonymousDonorName replaces the donor's name in public views of an
+// anonymous donation.
+const AnonymousDonorName = "Anonymous"
+
 type Donation struct {
 	ID             uuid.UUID      `json:"id" db:"id"`
 	CauseID        uuid.UUID      `json:"cause_id" db:"cause_id"`
@@ -25,12 +42,77 @@ type Donation struct {
 	Phone          string         `json:"phone" db:"phone"`
 	BillingAddress *string        `json:"billing_address,omitempty" db:"billing_address"`
 	Pincode        *string        `json:"pincode,omitempty" db:"pincode"`
-	Amount         float32        `json:"amount" db:"amount"`
+	Amount         Paise          `json:"amount" db:"amount"`
 	Status         DonationStatus `json:"status" db:"status"`
 	PanNumber      *string        `json:"pan_number,omitempty" db:"pan_number"`
 	PaymentID      *string        `json:"payment_id,omitempty" db:"payment_id"`
 	TxHash         *string        `json:"tx_hash,omitempty" db:"tx_hash"`
 	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+
+	// LedgerRetryAt is when the ledger sync may next try to record a
+	// donation whose TxHash is still unset.
+	LedgerRetryAt *time.Time `json:"-" db:"ledger_retry_at"`
+
+	// Set when the donation was made through a peer-to-peer fundraiser.
+	FundraiserID *uuid.UUID `json:"fundraiser_id,omitempty" db:"fundraiser_id"`
+
+	// Set on sponsor donations created by a matching campaign.
+	MatchingCampaignID *uuid.UUID `json:"matching_campaign_id,omitempty" db:"matching_campaign_id"`
+	MatchedDonationID  *uuid.UUID `json:"matched_donation_id,omitempty" db:"matched_donation_id"`
+
+	// IsAnonymous hides the donor from public listings and the ledger. Name
+	// and PAN are still kept for the 80G receipt.
+	IsAnonymous bool `json:"is_anonymous" db:"is_anonymous"`
+
+	// Tribute dedication; TributeType is nil for ordinary donations.
+	TributeType        *TributeType `json:"tribute_type,omitempty" db:"tribute_type"`
+	TributeName        *string      `json:"tribute_name,omitempty" db:"tribute_name"`
+	TributeMessage     *string      `json:"tribute_message,omitempty" db:"tribute_message"`
+	TributeNotifyEmail *string      `json:"tribute_notify_email,omitempty" db:"tribute_notify_email"`
+	TributeNotifiedAt  *time.Time   `json:"tribute_notified_at,omitempty" db:"tribute_notified_at"`
+
+	Items []*DonationItem `json:"items,omitempty"`
+}
+
+// DonorRef is the donor ID written to the ledger; anonymous donations are
+// recorded against the nil UUID.
+func (d *Donation) DonorRef() uuid.UUID {
+	if d.IsAnonymous {
+		return uuid.Nil
+	}
+	return d.UserID
+}
+
+// DonationTribute dedicates a donation in honour or memory of someone.
+// NotifyEmail, when set, receives an e-card about the gift.
+type DonationTribute struct {
+	Type        TributeType `json:"type"`
+	Name        string      `json:"name"`
+	Message     *string     `json:"message,omitempty"`
+	NotifyEmail *string     `json:"notify_email,omitempty"`
+}
+
+// DonationItem is one product line of a donation, e.g. 5 blankets at 400.
+// UnitPrice is the product price when the donation was made.
+type DonationItem struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	DonationID uuid.UUID `json:"donation_id" db:"donation_id"`
+	ProductID  uuid.UUID `json:"product_id" db:"product_id"`
+	Quantity   int       `json:"quantity" db:"quantity"`
+	UnitPrice  float64   `json:"unit_price" db:"unit_price"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+func (i *DonationItem) Subtotal() float64 {
+	return i.UnitPrice * float64(i.Quantity)
+}
+
+// DonationItemInput is a requested line item. UnitPrice is the price the
+// donor was shown; it must match the current product price.
+type DonationItemInput struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+	UnitPrice Paise     `json:"unit_price"`
 }
 
 type CreateDonationRequest struct {
@@ -40,9 +122,19 @@ type CreateDonationRequest struct {
 	Phone          string    `json:"phone" validate:"required"`
 	BillingAddress *string   `json:"billing_address,omitempty"`
 	Pincode        *string   `json:"pincode,omitempty"`
-	Amount         float32   `json:"amount" validate:"required,gt=0"`
+	Amount         Paise     `json:"amount" validate:"required,gt=0"`
 	PanNumber      *string   `json:"pan_number,omitempty"`
 	PaymentID      *string   `json:"payment_id,omitempty"`
+
+	// FundraiserID attributes the donation to a fundraiser for the cause.
+	FundraiserID *uuid.UUID `json:"fundraiser_id,omitempty"`
+
+	IsAnonymous bool             `json:"is_anonymous,omitempty"`
+	Tribute     *DonationTribute `json:"tribute,omitempty"`
+
+	// Items funds specific products. When set, Amount must equal the sum
+	// of the line items.
+	Items []*DonationItemInput `json:"items,omitempty"`
 }
 
 type CreateDonationResponse struct {
@@ -53,11 +145,21 @@ type CreateDonationResponse struct {
 	Phone          string         `json:"phone"`
 	BillingAddress *string        `json:"billing_address,omitempty"`
 	Pincode        *string        `json:"pincode,omitempty"`
-	Amount         float32        `json:"amount"`
+	Amount         Paise          `json:"amount"`
 	Status         DonationStatus `json:"status"`
 	PaymentID      *string        `json:"payment_id,omitempty"`
 	TxHash         *string        `json:"tx_hash,omitempty"`
 	CreatedAt      time.Time      `json:"created_at"`
+
+	FundraiserID       *uuid.UUID      `json:"fundraiser_id,omitempty"`
+	MatchingCampaignID *uuid.UUID      `json:"matching_campaign_id,omitempty"`
+	MatchedDonationID  *uuid.UUID      `json:"matched_donation_id,omitempty"`
+	Items              []*DonationItem `json:"items,omitempty"`
+
+	IsAnonymous bool             `json:"is_anonymous"`
+	Tribute     *DonationTribute `json:"tribute,omitempty"`
+
+	PanNumber *string `json:"pan_number,omitempty"`
 }
 
 type DonationLedgerResponse struct {
@@ -82,6 +184,88 @@ func (d *Donation) ToDonationResponse() *CreateDonationResponse {
 		TxHash:         d.TxHash,
 		PaymentID:      d.PaymentID,
 		CreatedAt:      d.CreatedAt,
+		PanNumber:      d.PanNumber,
+
+		FundraiserID:       d.FundraiserID,
+		MatchingCampaignID: d.MatchingCampaignID,
+		MatchedDonationID:  d.MatchedDonationID,
+		Items:              d.Items,
+
+		IsAnonymous: d.IsAnonymous,
+		Tribute:     d.tribute(),
+	}
+}
+
+// PublicDonationResponse is what anyone can see about a donation. It never
+// carries the donor's contact details or PAN.
+type PublicDonationResponse struct {
+	ID          uuid.UUID        `json:"id"`
+	CauseID     uuid.UUID        `json:"cause_id"`
+	UserID      *uuid.UUID       `json:"user_id,omitempty"`
+	Name        string           `json:"name"`
+	Amount      Paise            `json:"amount"`
+	Status      DonationStatus   `json:"status"`
+	TxHash      *string          `json:"tx_hash,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	IsAnonymous bool             `json:"is_anonymous"`
+	Tribute     *DonationTribute `json:"tribute,omitempty"`
+
+	FundraiserID       *uuid.UUID      `json:"fundraiser_id,omitempty"`
+	MatchingCampaignID *uuid.UUID      `json:"matching_campaign_id,omitempty"`
+	MatchedDonationID  *uuid.UUID      `json:"matched_donation_id,omitempty"`
+	Items              []*DonationItem `json:"items,omitempty"`
+}
+
+// ToPublicDonationResponse is the view shown in public donor listings.
+// Anonymous donors are shown without their name or user ID, and the
+// honoree's email is never included.
+func (d *Donation) ToPublicDonationResponse() *PublicDonationResponse {
+	response := &PublicDonationResponse{
+		ID:          d.ID,
+		CauseID:     d.CauseID,
+		Name:        d.Name,
+		Amount:      d.Amount,
+		Status:      d.Status,
+		TxHash:      d.TxHash,
+		CreatedAt:   d.CreatedAt,
+		IsAnonymous: d.IsAnonymous,
+		Tribute:     d.tribute(),
+
+		FundraiserID:       d.FundraiserID,
+		MatchingCampaignID: d.MatchingCampaignID,
+		MatchedDonationID:  d.MatchedDonationID,
+		Items:              d.Items,
+	}
+	if d.IsAnonymous {
+		response.Name = AnonymousDonorName
+	} else {
+		response.UserID = &d.UserID
+	}
+	if response.Tribute != nil {
+		response.Tribute.NotifyEmail = nil
+	}
+	return response
+}
+
+// ToMaskedDonationResponse is the private view with phone, address and PAN
+// partly hidden, for NGOs browsing their donors.
+func (d *Donation) ToMaskedDonationResponse() *CreateDonationResponse {
+	response := d.ToDonationResponse()
+	response.Phone = pii.MaskPhone(d.Phone)
+	response.BillingAddress = pii.MaskAddressPtr(d.BillingAddress)
+	response.PanNumber = pii.MaskPANPtr(d.PanNumber)
+	return response
+}
+
+func (d *Donation) tribute() *DonationTribute {
+	if d.TributeType == nil || d.TributeName == nil {
+		return nil
+	}
+	return &DonationTribute{
+		Type:        *d.TributeType,
+		Name:        *d.TributeName,
+		Message:     d.TributeMessage,
+		NotifyEmail: d.TributeNotifyEmail,
 	}
 }
 
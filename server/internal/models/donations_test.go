@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestToPublicDonationResponse(t *testing.T) {
+	tributeType := TributeTypeMemory
+	tributeName := "Asha Rao"
+	email := "family@example.com"
+
+	donation := &Donation{
+		ID:                 uuid.New(),
+		UserID:             uuid.New(),
+		Name:               "Ravi Kumar",
+		IsAnonymous:        true,
+		TributeType:        &tributeType,
+		TributeName:        &tributeName,
+		TributeNotifyEmail: &email,
+		Phone:              "9876543210",
+	}
+
+	public := donation.ToPublicDonationResponse()
+	if public.Name != AnonymousDonorName {
+		t.Fatalf("expected anonymous name, got %q", public.Name)
+	}
+	if public.UserID != nil {
+		t.Fatalf("expected user ID to be hidden, got %v", public.UserID)
+	}
+	if public.Tribute == nil || public.Tribute.Name != tributeName {
+		t.Fatalf("expected tribute to be shown, got %+v", public.Tribute)
+	}
+	if public.Tribute.NotifyEmail != nil {
+		t.Fatal("expected honoree email to be hidden")
+	}
+
+	// The donor's own view is unchanged.
+	private := donation.ToDonationResponse()
+	if private.Name != donation.Name || private.UserID != donation.UserID {
+		t.Fatalf("expected private view to keep donor details, got %+v", private)
+	}
+	if private.Tribute.NotifyEmail == nil {
+		t.Fatal("expected private view to keep honoree email")
+	}
+	masked := donation.ToMaskedDonationResponse()
+	if masked.Phone != "******3210" {
+		t.Fatalf("expected masked phone, got %q", masked.Phone)
+	}
+	if donation.DonorRef() != uuid.Nil {
+		t.Fatal("expected anonymous donation to use the nil ledger donor")
+	}
+}
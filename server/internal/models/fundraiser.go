@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fundraiser is a supporter's personal page raising money for a parent
+// cause. Donations made through it count toward both the fundraiser and
+// the cause.
+type Fundraiser struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	CauseID       uuid.UUID  `json:"cause_id" db:"cause_id"`
+	CauseTitle    string     `json:"cause_title" db:"cause_title"`
+	OwnerID       uuid.UUID  `json:"owner_id" db:"owner_id"`
+	OwnerName     string     `json:"owner_name" db:"owner_name"`
+	Title         string     `json:"title" db:"title"`
+	Story         *string    `json:"story,omitempty" db:"story"`
+	GoalAmount    float64    `json:"goal_amount" db:"goal_amount"`
+	RaisedAmount  float64    `json:"raised_amount" db:"raised_amount"`
+	DonationCount int        `json:"donation_count" db:"donation_count"`
+	CoverImageURL *string    `json:"cover_image_url,omitempty" db:"cover_image_url"`
+	Deadline      *time.Time `json:"deadline,omitempty" db:"deadline"`
+	IsActive      bool       `json:"is_active" db:"is_active"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+
+	ProgressPercentage float64 `json:"progress_percentage"`
+}
+
+// SetProgress fills the derived progress field.
+func (f *Fundraiser) SetProgress() {
+	if f.GoalAmount > 0 {
+		f.ProgressPercentage = f.RaisedAmount / f.GoalAmount * 100
+	}
+}
+
+// AcceptsDonations is true while the fundraiser is open and before its
+// deadline. The parent cause must also be accepting donations.
+func (f *Fundraiser) AcceptsDonations(now time.Time) bool {
+	return f.IsActive && (f.Deadline == nil || f.Deadline.After(now))
+}
+
+type CreateFundraiserRequest struct {
+	CauseID       uuid.UUID  `json:"cause_id"`
+	Title         string     `json:"title"`
+	Story         *string    `json:"story,omitempty"`
+	GoalAmount    float64    `json:"goal_amount"`
+	CoverImageURL *string    `json:"cover_image_url,omitempty"`
+	Deadline      *time.Time `json:"deadline,omitempty"`
+}
+
+// UpdateFundraiserRequest changes only the fields that are set.
+type UpdateFundraiserRequest struct {
+	Title         *string    `json:"title,omitempty"`
+	Story         *string    `json:"story,omitempty"`
+	GoalAmount    *float64   `json:"goal_amount,omitempty"`
+	CoverImageURL *string    `json:"cover_image_url,omitempty"`
+	Deadline      *time.Time `json:"deadline,omitempty"`
+}
+
+type FundraiserLeaderboardEntry struct {
+	Rank          int       `json:"rank"`
+	FundraiserID  uuid.UUID `json:"fundraiser_id"`
+	Title         string    `json:"title"`
+	OwnerName     string    `json:"owner_name"`
+	GoalAmount    float64   `json:"goal_amount"`
+	RaisedAmount  float64   `json:"raised_amount"`
+	DonationCount int       `json:"donation_count"`
+}
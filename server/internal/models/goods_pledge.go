@@ -0,0 +1,132 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GoodsPledgeStatus string
+
+const (
+	GoodsPledgeStatusPledged   GoodsPledgeStatus = "pledged"
+	GoodsPledgeStatusAccepted  GoodsPledgeStatus = "accepted"
+	GoodsPledgeStatusRejected  GoodsPledgeStatus = "rejected"
+	GoodsPledgeStatusScheduled GoodsPledgeStatus = "scheduled"
+	GoodsPledgeStatusReceived  GoodsPledgeStatus = "received"
+	GoodsPledgeStatusCancelled GoodsPledgeStatus = "cancelled"
+)
+
+func (s GoodsPledgeStatus) IsValid() bool {
+	switch s {
+	case GoodsPledgeStatusPledged, GoodsPledgeStatusAccepted, GoodsPledgeStatusRejected,
+		GoodsPledgeStatusScheduled, GoodsPledgeStatusReceived, GoodsPledgeStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// goodsPledgeTransitions lists the statuses each status may move to. A
+// scheduled pledge may be rescheduled.
+var goodsPledgeTransitions = map[GoodsPledgeStatus][]GoodsPledgeStatus{
+	GoodsPledgeStatusPledged:   {GoodsPledgeStatusAccepted, GoodsPledgeStatusRejected, GoodsPledgeStatusCancelled},
+	GoodsPledgeStatusAccepted:  {GoodsPledgeStatusScheduled, GoodsPledgeStatusReceived, GoodsPledgeStatusCancelled},
+	GoodsPledgeStatusScheduled: {GoodsPledgeStatusScheduled, GoodsPledgeStatusReceived, GoodsPledgeStatusCancelled},
+}
+
+// CanTransitionGoodsPledge reports whether a pledge may move between two
+// statuses, with a reason suitable for returning to the caller.
+func CanTransitionGoodsPledge(from, to GoodsPledgeStatus) error {
+	for _, s := range goodsPledgeTransitions[from] {
+		if s == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot move a pledge from %s to %s", from, to)
+}
+
+type GoodsDeliveryMethod string
+
+const (
+	GoodsDeliveryDropOff GoodsDeliveryMethod = "drop_off"
+	GoodsDeliveryPickup  GoodsDeliveryMethod = "pickup"
+)
+
+func (m GoodsDeliveryMethod) IsValid() bool {
+	return m == GoodsDeliveryDropOff || m == GoodsDeliveryPickup
+}
+
+// GoodsPledge is a donor's offer of physical goods to a cause, either units
+// of one of its products or a free-form item. EstimatedUnitValue is the
+// product price at pledge time, or the donor's own estimate for free-form
+// items.
+type GoodsPledge struct {
+	ID                  uuid.UUID            `json:"id" db:"id"`
+	CauseID             uuid.UUID            `json:"cause_id" db:"cause_id"`
+	DonorID             uuid.UUID            `json:"donor_id" db:"donor_id"`
+	ProductID           *uuid.UUID           `json:"product_id,omitempty" db:"product_id"`
+	ItemName            string               `json:"item_name" db:"item_name"`
+	Description         *string              `json:"description,omitempty" db:"description"`
+	Quantity            int                  `json:"quantity" db:"quantity"`
+	Unit                *string              `json:"unit,omitempty" db:"unit"`
+	EstimatedUnitValue  *float64             `json:"estimated_unit_value,omitempty" db:"estimated_unit_value"`
+	Status              GoodsPledgeStatus    `json:"status" db:"status"`
+	RejectionReason     *string              `json:"rejection_reason,omitempty" db:"rejection_reason"`
+	DeliveryMethod      *GoodsDeliveryMethod `json:"delivery_method,omitempty" db:"delivery_method"`
+	DeliveryScheduledAt *time.Time           `json:"delivery_scheduled_at,omitempty" db:"delivery_scheduled_at"`
+	DeliveryNotes       *string              `json:"delivery_notes,omitempty" db:"delivery_notes"`
+	ReceivedQuantity    *int                 `json:"received_quantity,omitempty" db:"received_quantity"`
+	ReceivedAt          *time.Time           `json:"received_at,omitempty" db:"received_at"`
+	ProofImageID        *uuid.UUID           `json:"proof_image_id,omitempty" db:"proof_image_id"`
+	CreatedAt           time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+type CreateGoodsPledgeRequest struct {
+	CauseID   uuid.UUID  `json:"cause_id"`
+	ProductID *uuid.UUID `json:"product_id,omitempty"`
+	// ItemName, Unit and EstimatedUnitValue describe free-form items and
+	// are ignored for product pledges.
+	ItemName           string   `json:"item_name"`
+	Description        *string  `json:"description,omitempty"`
+	Quantity           int      `json:"quantity"`
+	Unit               *string  `json:"unit,omitempty"`
+	EstimatedUnitValue *float64 `json:"estimated_unit_value,omitempty"`
+}
+
+type RejectGoodsPledgeRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ScheduleGoodsDeliveryRequest struct {
+	Method      GoodsDeliveryMethod `json:"method"`
+	ScheduledAt time.Time           `json:"scheduled_at"`
+	Notes       *string             `json:"notes,omitempty"`
+}
+
+// ReceiveGoodsPledgeRequest records receipt. ProofImageID is an image
+// uploaded through a proof session for the same cause. ReceivedQuantity
+// defaults to the pledged quantity and may not exceed it.
+type ReceiveGoodsPledgeRequest struct {
+	ProofImageID     uuid.UUID `json:"proof_image_id"`
+	ReceivedQuantity *int      `json:"received_quantity,omitempty"`
+}
+
+// ReceivedGoods is a received pledge as shown publicly, without the donor.
+type ReceivedGoods struct {
+	PledgeID       uuid.UUID  `json:"pledge_id"`
+	ProductID      *uuid.UUID `json:"product_id,omitempty"`
+	ItemName       string     `json:"item_name"`
+	Quantity       int        `json:"quantity"`
+	Unit           *string    `json:"unit,omitempty"`
+	EstimatedValue *float64   `json:"estimated_value,omitempty"`
+	ReceivedAt     time.Time  `json:"received_at"`
+	ProofImageID   *uuid.UUID `json:"proof_image_id,omitempty"`
+	ProofImage     *string    `json:"proof_image,omitempty"`
+}
+
+type InKindSummary struct {
+	Items               []*ReceivedGoods `json:"items"`
+	TotalEstimatedValue float64          `json:"total_estimated_value"`
+}
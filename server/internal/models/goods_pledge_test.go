@@ -0,0 +1,32 @@
+package models
+
+import "testing"
+
+func TestCanTransitionGoodsPledge(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    GoodsPledgeStatus
+		to      GoodsPledgeStatus
+		allowed bool
+	}{
+		{"accept pledge", GoodsPledgeStatusPledged, GoodsPledgeStatusAccepted, true},
+		{"cannot receive before accepting", GoodsPledgeStatusPledged, GoodsPledgeStatusReceived, false},
+		{"receive accepted pledge", GoodsPledgeStatusAccepted, GoodsPledgeStatusReceived, true},
+		{"reschedule delivery", GoodsPledgeStatusScheduled, GoodsPledgeStatusScheduled, true},
+		{"cancel scheduled pledge", GoodsPledgeStatusScheduled, GoodsPledgeStatusCancelled, true},
+		{"received is terminal", GoodsPledgeStatusReceived, GoodsPledgeStatusCancelled, false},
+		{"rejected is terminal", GoodsPledgeStatusRejected, GoodsPledgeStatusAccepted, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CanTransitionGoodsPledge(tt.from, tt.to)
+			if tt.allowed && err != nil {
+				t.Fatalf("expected transition to be allowed, got %v", err)
+			}
+			if !tt.allowed && err == nil {
+				t.Fatalf("expected transition %s -> %s to be rejected", tt.from, tt.to)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MatchingCampaign is a sponsor's offer to match donations to one cause or
+// to every cause in a domain, at Ratio rupees per donated rupee, until
+// CapAmount has been matched or the window ends.
+type MatchingCampaign struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	SponsorUserID uuid.UUID  `json:"sponsor_user_id" db:"sponsor_user_id"`
+	SponsorName   string     `json:"sponsor_name" db:"sponsor_name"`
+	CauseID       *uuid.UUID `json:"cause_id,omitempty" db:"cause_id"`
+	DomainID      *uuid.UUID `json:"domain_id,omitempty" db:"domain_id"`
+	Ratio         float64    `json:"ratio" db:"ratio"`
+	CapAmount     float64    `json:"cap_amount" db:"cap_amount"`
+	MatchedAmount float64    `json:"matched_amount" db:"matched_amount"`
+	StartsAt      time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt        time.Time  `json:"ends_at" db:"ends_at"`
+	IsActive      bool       `json:"is_active" db:"is_active"`
+	CreatedBy     *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// RunningAt reports whether the campaign matches donations made at t: it is
+// active, t falls in [StartsAt, EndsAt) and budget is left.
+func (m *MatchingCampaign) RunningAt(t time.Time) bool {
+	return m.IsActive && !t.Before(m.StartsAt) && t.Before(m.EndsAt) && m.RemainingAmount() > 0
+}
+
+func (m *MatchingCampaign) RemainingAmount() float64 {
+	return math.Max(m.CapAmount-m.MatchedAmount, 0)
+}
+
+// MatchFor is the amount the campaign would add to a donation, before the
+// remaining budget is applied. It is rounded to whole paise.
+func (m *MatchingCampaign) MatchFor(amount float64) float64 {
+	return math.Round(amount*m.Ratio*100) / 100
+}
+
+type CreateMatchingCampaignRequest struct {
+	SponsorUserID uuid.UUID  `json:"sponsor_user_id"`
+	SponsorName   string     `json:"sponsor_name"`
+	CauseID       *uuid.UUID `json:"cause_id,omitempty"`
+	DomainID      *uuid.UUID `json:"domain_id,omitempty"`
+	Ratio         float64    `json:"ratio"`
+	CapAmount     float64    `json:"cap_amount"`
+	StartsAt      *time.Time `json:"starts_at,omitempty"`
+	EndsAt        time.Time  `json:"ends_at"`
+}
+
+// CauseMatchingSummary is a running campaign as shown on a cause.
+type CauseMatchingSummary struct {
+	CampaignID      uuid.UUID `json:"campaign_id"`
+	SponsorName     string    `json:"sponsor_name"`
+	Ratio           float64   `json:"ratio"`
+	CapAmount       float64   `json:"cap_amount"`
+	MatchedAmount   float64   `json:"matched_amount"`
+	RemainingAmount float64   `json:"remaining_amount"`
+	EndsAt          time.Time `json:"ends_at"`
+}
+
+func (m *MatchingCampaign) ToCauseSummary() *CauseMatchingSummary {
+	return &CauseMatchingSummary{
+		CampaignID:      m.ID,
+		SponsorName:     m.SponsorName,
+		Ratio:           m.Ratio,
+		CapAmount:       m.CapAmount,
+		MatchedAmount:   m.MatchedAmount,
+		RemainingAmount: m.RemainingAmount(),
+		EndsAt:          m.EndsAt,
+	}
+}
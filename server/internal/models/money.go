@@ -0,0 +1,92 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// Paise is an amount of money in whole paise. In JSON and in NUMERIC
+// columns it is written in rupees, such as 1250.5, and read from the
+// decimal text itself so no precision is lost to floating point.
+type Paise int64
+
+var errInvalidAmount = errors.New("amount must be a number of rupees with at most two decimal places")
+
+// ParsePaise reads a rupee amount such as "1250.50".
+func ParsePaise(s string) (Paise, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, errInvalidAmount
+	}
+	r.Mul(r, big.NewRat(100, 1))
+	if !r.IsInt() || !r.Num().IsInt64() {
+		return 0, errInvalidAmount
+	}
+	return Paise(r.Num().Int64()), nil
+}
+
+// ToPaise rounds a rupee amount read from the database to whole paise.
+func ToPaise(rupees float64) Paise {
+	return Paise(math.Round(rupees * 100))
+}
+
+// Rupees returns the amount in rupees, for columns and APIs that still
+// store a decimal.
+func (p Paise) Rupees() float64 {
+	return float64(p) / 100
+}
+
+func (p Paise) String() string {
+	return strconv.FormatFloat(p.Rupees(), 'f', 2, 64)
+}
+
+func (p *Paise) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	parsed, err := ParsePaise(string(data))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+func (p Paise) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// Scan reads a NUMERIC rupee column.
+func (p *Paise) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return p.scanText(v)
+	case []byte:
+		return p.scanText(string(v))
+	case int64:
+		*p = Paise(v * 100)
+		return nil
+	case float64:
+		*p = ToPaise(v)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into Paise", src)
+}
+
+func (p *Paise) scanText(s string) error {
+	parsed, err := ParsePaise(s)
+	if err != nil {
+		return fmt.Errorf("cannot scan %q into Paise: %w", s, err)
+	}
+	*p = parsed
+	return nil
+}
+
+// Value writes the amount in rupees for a NUMERIC column.
+func (p Paise) Value() (driver.Value, error) {
+	return p.String(), nil
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParsePaise(t *testing.T) {
+	valid := map[string]Paise{
+		"1250":     125000,
+		"1250.5":   125050,
+		"0.1":      10,
+		"19.99":    1999,
+		"59.970":   5997,
+		"1e3":      100000,
+		"-2.50":    -250,
+		"0.000000": 0,
+	}
+	for in, want := range valid {
+		got, err := ParsePaise(in)
+		if err != nil || got != want {
+			t.Errorf("ParsePaise(%q) = %d, %v, want %d", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"", "abc", "1.234", "0.005", "1e30"} {
+		if got, err := ParsePaise(in); err == nil {
+			t.Errorf("ParsePaise(%q) = %d, want error", in, got)
+		}
+	}
+}
+
+func TestPaiseJSON(t *testing.T) {
+	var req struct {
+		Amount Paise `json:"amount"`
+	}
+	if err := json.Unmarshal([]byte(`{"amount": 59.97}`), &req); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if req.Amount != 5997 {
+		t.Fatalf("Amount = %d, want 5997", req.Amount)
+	}
+
+	out, _ := json.Marshal(req)
+	if string(out) != `{"amount":59.97}` {
+		t.Errorf("Marshal = %s", out)
+	}
+
+	if err := json.Unmarshal([]byte(`{"amount": 10.001}`), &req); err == nil {
+		t.Error("Unmarshal accepted a fraction of a paisa")
+	}
+}
+
+func TestPaiseSQL(t *testing.T) {
+	// pgx hands NUMERIC columns over as text
+	for _, tc := range []struct {
+		src  any
+		want Paise
+	}{
+		{"16777217.01", 1677721701},
+		{[]byte("1250.50"), 125050},
+		{int64(12), 1200},
+		{float64(59.97), 5997},
+	} {
+		var got Paise
+		if err := got.Scan(tc.src); err != nil || got != tc.want {
+			t.Errorf("Scan(%#v) = %d, %v, want %d", tc.src, got, err, tc.want)
+		}
+	}
+	var p Paise
+	if err := p.Scan(nil); err == nil {
+		t.Error("Scan(nil) succeeded")
+	}
+
+	v, _ := Paise(1677721701).Value()
+	if v != "16777217.01" {
+		t.Errorf("Value() = %v, want 16777217.01", v)
+	}
+}
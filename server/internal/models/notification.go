@@ -0,0 +1,171 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationKind is something a user can be notified about. Each kind can
+// be turned off on its own.
+type NotificationKind string
+
+const (
+	// NotificationDonationReceipt thanks a donor and links their receipt.
+	NotificationDonationReceipt NotificationKind = "donation_receipt"
+	// NotificationDonationReceived tells an organization about a donation.
+	NotificationDonationReceived NotificationKind = "donation_received"
+	// NotificationMilestoneReached goes to a cause's donors and its
+	// organization.
+	NotificationMilestoneReached NotificationKind = "milestone_reached"
+	// NotificationCauseUpdate tells donors a cause they funded posted an
+	// update.
+	NotificationCauseUpdate NotificationKind = "cause_update"
+	// NotificationReceiptVerification tells an organization how a receipt
+	// it uploaded was verified.
+	NotificationReceiptVerification NotificationKind = "receipt_verification"
+	// NotificationDisputeUpdate goes to whoever opened a dispute and the
+	// organization it is about, when it's opened and when its status
+	// changes.
+	NotificationDisputeUpdate NotificationKind = "dispute_update"
+)
+
+// NotificationKindInfo describes a kind for the preferences screen.
+type NotificationKindInfo struct {
+	Kind        NotificationKind `json:"kind"`
+	Label       string           `json:"label"`
+	Description string           `json:"description"`
+}
+
+// NotificationKinds lists every kind, in the order they're shown.
+var NotificationKinds = []NotificationKindInfo{
+	{NotificationDonationReceipt, "Donation receipts", "A thank-you with a link to your receipt after each donation"},
+	{NotificationCauseUpdate, "Updates from causes you support", "When a cause you donated to posts an update"},
+	{NotificationMilestoneReached, "Milestones", "When a cause you donated to, or your organization's cause, reaches a milestone"},
+	{NotificationDonationReceived, "Donations received", "When your organization receives a donation"},
+	{NotificationReceiptVerification, "Receipt verification", "When a receipt your organization uploaded is verified or rejected"},
+	{NotificationDisputeUpdate, "Disputes", "When a dispute you opened, or one about your organization, is opened or changes"},
+}
+
+func (k NotificationKind) IsValid() bool {
+	for _, info := range NotificationKinds {
+		if k == info.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailRetrySchedule is how long to wait after each failed send. An email
+// that fails once more after the last wait is dead.
+var EmailRetrySchedule = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	6 * time.Hour,
+}
+
+// MaxEmailAttempts is the most times an email is tried.
+var MaxEmailAttempts = len(EmailRetrySchedule) + 1
+
+type EmailStatus string
+
+const (
+	EmailStatusPending EmailStatus = "pending"
+	EmailStatusSent    EmailStatus = "sent"
+	EmailStatusDead    EmailStatus = "dead"
+)
+
+// OutboxEmail is a notification email, queued to be sent or already sent.
+type OutboxEmail struct {
+	ID            uuid.UUID        `json:"id" db:"id"`
+	UserID        *uuid.UUID       `json:"user_id,omitempty" db:"user_id"`
+	Kind          NotificationKind `json:"kind" db:"kind"`
+	To            string           `json:"to" db:"to_address"`
+	Subject       string           `json:"subject" db:"subject"`
+	Body          string           `json:"body" db:"body"`
+	Status        EmailStatus      `json:"status" db:"status"`
+	Attempts      int              `json:"attempts" db:"attempts"`
+	NextAttemptAt *time.Time       `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastError     *string          `json:"last_error,omitempty" db:"last_error"`
+	SentAt        *time.Time       `json:"sent_at,omitempty" db:"sent_at"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+}
+
+// EmailRecipient is a user who wants a kind of notification email.
+type EmailRecipient struct {
+	UserID uuid.UUID
+	Name   string
+	Email  string
+}
+
+// NotificationPreferences is what a user has turned off.
+type NotificationPreferences struct {
+	UserID         uuid.UUID          `json:"user_id" db:"user_id"`
+	EmailDisabled  []NotificationKind `json:"email_disabled" db:"email_disabled"`
+	UnsubscribedAt *time.Time         `json:"unsubscribed_at,omitempty" db:"unsubscribed_at"`
+	UpdatedAt      time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// NotificationPreferencesResponse has one switch per kind.
+type NotificationPreferencesResponse struct {
+	Email map[NotificationKind]bool `json:"email"`
+	// Unsubscribed turns every notification email off, whatever Email
+	// says. Account emails such as password resets are always sent.
+	Unsubscribed bool                   `json:"unsubscribed"`
+	Kinds        []NotificationKindInfo `json:"kinds"`
+}
+
+// UpdateNotificationPreferencesRequest changes the kinds it mentions.
+type UpdateNotificationPreferencesRequest struct {
+	Email        map[NotificationKind]bool `json:"email"`
+	Unsubscribed *bool                     `json:"unsubscribed"`
+}
+
+type UnsubscribeRequest struct {
+	Token string `json:"token"`
+}
+
+// Notification is an entry in a user's in-app inbox.
+type Notification struct {
+	ID     uuid.UUID        `json:"id" db:"id"`
+	UserID uuid.UUID        `json:"user_id" db:"user_id"`
+	Kind   NotificationKind `json:"kind" db:"kind"`
+	Title  string           `json:"title" db:"title"`
+	Body   string           `json:"body" db:"body"`
+	// Link is a path in the web app, if the notification is about
+	// something that has a page.
+	Link      *string    `json:"link,omitempty" db:"link"`
+	ReadAt    *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type UnreadCountResponse struct {
+	Unread int `json:"unread"`
+}
+
+type NotificationEventType string
+
+const (
+	// NotificationEventCreated carries a new notification.
+	NotificationEventCreated NotificationEventType = "notification"
+	// NotificationEventUnread carries the unread count alone, after
+	// notifications are read.
+	NotificationEventUnread NotificationEventType = "unread"
+)
+
+// NotificationEvent is pushed to a user's open notification streams. Every
+// event has the current unread count, so a client that misses one catches
+// up with the next.
+type NotificationEvent struct {
+	Type         NotificationEventType `json:"type"`
+	Notification *Notification         `json:"notification,omitempty"`
+	Unread       int                   `json:"unread"`
+}
+
+// NotificationStreamAuth is the first message a client sends on the
+// notification stream, since browsers can't set headers on a websocket.
+type NotificationStreamAuth struct {
+	Token string `json:"token"`
+}
@@ -28,6 +28,12 @@ type Organization struct {
 	TrustScore         *float64  `json:"trust_score" db:"trust_score"`
 }
 
+// IsRegistered reports whether the organization has finished registering,
+// which needs the email address it signed up with to be verified.
+func (o *Organization) IsRegistered() bool {
+	return o.User != nil && o.User.IsVerified
+}
+
 // CreateOrganizationRequest represents the request payload for creating a organization
 type CreateOrganizationRequest struct {
 	Name               string  `json:"name" validate:"required,min=2,max=255"`
@@ -57,6 +63,9 @@ type OrganizationResponse struct {
 	IsApproved         bool      `json:"is_approved" db:"is_approved"`
 	Amount             float64   `json:"amount" db:"amount"`
 	TrustScore         *float64  `json:"trust_score" db:"trust_score"`
+	// MemberRole is the caller's role when they ask for their own
+	// organization.
+	MemberRole OrganizationMemberRole `json:"member_role,omitempty" db:"-"`
 }
 
 // ToOrganizationResponse converts a Organization to OrganizationResponse
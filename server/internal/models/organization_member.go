@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrganizationInvitationTTL is how long an invitation link stays valid.
+const OrganizationInvitationTTL = 7 * 24 * time.Hour
+
+// OrganizationMemberRole is a member's role in their organization. The
+// policy package decides what each role may do.
+type OrganizationMemberRole string
+
+const (
+	OrganizationRoleOwner      OrganizationMemberRole = "owner"
+	OrganizationRoleAdmin      OrganizationMemberRole = "admin"
+	OrganizationRoleFinance    OrganizationMemberRole = "finance"
+	OrganizationRoleFieldAgent OrganizationMemberRole = "field_agent"
+	OrganizationRoleViewer     OrganizationMemberRole = "viewer"
+)
+
+// IsValid reports whether r is one of the roles above.
+func (r OrganizationMemberRole) IsValid() bool {
+	switch r {
+	case OrganizationRoleOwner, OrganizationRoleAdmin, OrganizationRoleFinance,
+		OrganizationRoleFieldAgent, OrganizationRoleViewer:
+		return true
+	}
+	return false
+}
+
+// RequiresTwoFactor reports whether members with this role must use 2FA,
+// like the organization's own account.
+func (r OrganizationMemberRole) RequiresTwoFactor() bool {
+	return r == OrganizationRoleOwner || r == OrganizationRoleAdmin || r == OrganizationRoleFinance
+}
+
+// OrganizationMember links a user to the organization they act for.
+type OrganizationMember struct {
+	ID             uuid.UUID              `json:"id" db:"id"`
+	OrganizationID uuid.UUID              `json:"organization_id" db:"organization_id"`
+	UserID         uuid.UUID              `json:"user_id" db:"user_id"`
+	Role           OrganizationMemberRole `json:"role" db:"role"`
+	InvitedBy      *uuid.UUID             `json:"invited_by,omitempty" db:"invited_by"`
+	CreatedAt      time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at" db:"updated_at"`
+
+	// Filled in when listing members.
+	Name  string `json:"name,omitempty" db:"-"`
+	Email string `json:"email,omitempty" db:"-"`
+	// IsPrimary marks the organization's own account, which can't be
+	// removed or demoted.
+	IsPrimary bool `json:"is_primary" db:"-"`
+
+	// Filled in by authorization checks.
+	Organization *Organization `json:"-" db:"-"`
+}
+
+// OrganizationInvitation is an emailed offer to join an organization.
+type OrganizationInvitation struct {
+	ID             uuid.UUID              `json:"id" db:"id"`
+	OrganizationID uuid.UUID              `json:"organization_id" db:"organization_id"`
+	Email          string                 `json:"email" db:"email"`
+	Role           OrganizationMemberRole `json:"role" db:"role"`
+	TokenHash      string                 `json:"-" db:"token_hash"`
+	InvitedBy      *uuid.UUID             `json:"invited_by,omitempty" db:"invited_by"`
+	ExpiresAt      time.Time              `json:"expires_at" db:"expires_at"`
+	AcceptedAt     *time.Time             `json:"accepted_at,omitempty" db:"accepted_at"`
+	AcceptedBy     *uuid.UUID             `json:"accepted_by,omitempty" db:"accepted_by"`
+	RevokedAt      *time.Time             `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt      time.Time              `json:"created_at" db:"created_at"`
+}
+
+type OrganizationTeamResponse struct {
+	Members     []*OrganizationMember     `json:"members"`
+	Invitations []*OrganizationInvitation `json:"invitations"`
+}
+
+type InviteMemberRequest struct {
+	Email string                 `json:"email"`
+	Role  OrganizationMemberRole `json:"role"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role OrganizationMemberRole `json:"role"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Why unmasked donor data was read.
+const (
+	PIIPurposeDonationDetail = "donation_detail"
+	PIIPurposeCauseDonorList = "cause_donor_list"
+)
+
+// PIIAccessLog records a read of a donor's unmasked personal data by an NGO
+// or admin. ActorID is nil once the actor's account is deleted.
+type PIIAccessLog struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	ActorID      *uuid.UUID `json:"actor_id,omitempty" db:"actor_id"`
+	ActorRole    string     `json:"actor_role" db:"actor_role"`
+	ResourceType string     `json:"resource_type" db:"resource_type"`
+	ResourceID   uuid.UUID  `json:"resource_id" db:"resource_id"`
+	Purpose      string     `json:"purpose" db:"purpose"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Names left on pseudonymized records after a user is erased.
+const (
+	ErasedUserName  = "Deleted user"
+	ErasedDonorName = "Deleted donor"
+)
+
+// TaxRecordRetentionYears is how long donor details for 80G-eligible
+// donations are kept after erasure, covering the Income Tax reassessment
+// window.
+const TaxRecordRetentionYears = 8
+
+// DataExportSection is one file of a personal data export: every row of one
+// kind held about the user, as JSON objects.
+type DataExportSection struct {
+	Name string            `json:"name"`
+	Rows []json.RawMessage `json:"rows"`
+}
+
+type DataExportManifest struct {
+	UserID      uuid.UUID      `json:"user_id"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Format      string         `json:"format"`
+	Files       map[string]int `json:"files"`
+}
+
+type EraseAccountRequest struct {
+	// Confirm must be true; erasure cannot be undone.
+	Confirm bool `json:"confirm"`
+}
+
+// ErasureSummary reports what happened to each kind of record.
+type ErasureSummary struct {
+	RequestID                     uuid.UUID `json:"request_id"`
+	DonationsPseudonymized        int64     `json:"donations_pseudonymized"`
+	TaxRecordsRetained            int64     `json:"tax_records_retained"`
+	BloodRegistrationsDeleted     int64     `json:"blood_registrations_deleted"`
+	VolunteerRegistrationsDeleted int64     `json:"volunteer_registrations_deleted"`
+	ReviewsDeleted                int64     `json:"reviews_deleted"`
+	VotesDeleted                  int64     `json:"votes_deleted"`
+	GoodsPledgesCancelled         int64     `json:"goods_pledges_cancelled"`
+	FundraisersClosed             int64     `json:"fundraisers_closed"`
+	CompletedAt                   time.Time `json:"completed_at"`
+}
@@ -62,6 +62,7 @@ type CauseExecution struct {
 
 // UploadProofResponse returned after processing proof upload
 type UploadProofResponse struct {
+	ImageID       *uuid.UUID `json:"imageId,omitempty"`
 	Status        string `json:"status"`
 	Score         int    `json:"score,omitempty"`
 	IsDuplicate   bool   `json:"isDuplicate,omitempty"`
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Why a session was revoked.
+const (
+	SessionRevokedLogout       = "logout"
+	SessionRevokedByUser       = "revoked_by_user"
+	SessionRevokedEverywhere   = "logout_everywhere"
+	SessionRevokedTokenReuse   = "refresh_token_reuse"
+	SessionRevokedAccountErase = "account_erased"
+	SessionRevokedPassword     = "password_reset"
+	SessionRevokedTwoFactor    = "two_factor_required"
+	SessionRevokedMembership   = "organization_membership_changed"
+)
+
+// Session is one signed-in device.
+type Session struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	UserAgent     *string    `json:"user_agent,omitempty" db:"user_agent"`
+	IPAddress     *string    `json:"ip_address,omitempty" db:"ip_address"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt    time.Time  `json:"last_used_at" db:"last_used_at"`
+	ExpiresAt     time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	RevokedReason *string    `json:"revoked_reason,omitempty" db:"revoked_reason"`
+
+	// Current marks the session making the request.
+	Current bool `json:"current"`
+}
+
+// RefreshToken is stored by the SHA-256 hash of the token only.
+type RefreshToken struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	SessionID uuid.UUID  `json:"session_id" db:"session_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ClientInfo describes the device starting or refreshing a session.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
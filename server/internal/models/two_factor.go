@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// LoginChallengeTTL is how long a user has to enter their code after
+	// their password.
+	LoginChallengeTTL = 10 * time.Minute
+	// MaxLoginChallengeAttempts is how many wrong codes end a challenge.
+	MaxLoginChallengeAttempts = 5
+	// RecoveryCodeCount is how many recovery codes are issued at a time.
+	RecoveryCodeCount = 10
+)
+
+// RequiresTwoFactor reports whether accounts with this role must use 2FA.
+// Organizations trigger disbursements and admins approve verifications.
+func (r RoleType) RequiresTwoFactor() bool {
+	return r == RoleTypeOrganization || r == RoleTypeAdmin
+}
+
+// TwoFactor is a user's TOTP enrollment.
+type TwoFactor struct {
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	Secret       string     `json:"-" db:"secret"`
+	EnabledAt    *time.Time `json:"enabled_at,omitempty" db:"enabled_at"`
+	LastUsedStep *int64     `json:"-" db:"last_used_step"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+func (t *TwoFactor) Enabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
+
+// LoginChallenge is a sign-in that has passed the password check and waits
+// for a TOTP or recovery code.
+type LoginChallenge struct {
+	ID                 uuid.UUID  `json:"id" db:"id"`
+	UserID             uuid.UUID  `json:"user_id" db:"user_id"`
+	TokenHash          string     `json:"-" db:"token_hash"`
+	EnrollmentRequired bool       `json:"enrollment_required" db:"enrollment_required"`
+	Attempts           int        `json:"attempts" db:"attempts"`
+	ExpiresAt          time.Time  `json:"expires_at" db:"expires_at"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+}
+
+// LoginChallengeResponse is returned from sign-in instead of tokens when a
+// second factor is needed. EnrollmentRequired means the account must set up
+// 2FA before it can sign in.
+type LoginChallengeResponse struct {
+	Token              string    `json:"token"`
+	EnrollmentRequired bool      `json:"enrollment_required"`
+	ExpiresAt          time.Time `json:"expires_at"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type TwoFactorStatus struct {
+	Enabled                bool `json:"enabled"`
+	Required               bool `json:"required"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type LoginChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	// Code is a TOTP code or a recovery code.
+	Code string `json:"code"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -39,6 +39,17 @@ type LoginRequest struct {
 type AuthResponse struct {
 	User  User   `json:"user"`
 	Token string `json:"token"`
+
+	// RefreshToken is exchanged at /api/auth/refresh for a new access token
+	// once Token expires after ExpiresIn seconds.
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+
+	// Challenge is set instead of the tokens when the user still has to
+	// enter a second factor at /api/auth/2fa/challenge/verify.
+	Challenge *LoginChallengeResponse `json:"challenge,omitempty"`
+	// RecoveryCodes is set once, when 2FA enrollment completes at sign-in.
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
 }
 
 // UserResponse represents a user response without sensitive data
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity is an OAuth or OpenID Connect account linked to a user.
+type UserIdentity struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
+	Provider       string     `json:"provider" db:"provider"`
+	ProviderUserID string     `json:"-" db:"provider_user_id"`
+	Email          *string    `json:"email,omitempty" db:"email"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// OAuthProvider is a sign-in provider the server is configured for.
+type OAuthProvider struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+}
+
+// IdentitiesResponse lists how a user can sign in.
+type IdentitiesResponse struct {
+	HasPassword bool            `json:"has_password"`
+	Identities  []*UserIdentity `json:"identities"`
+	// Providers are the configured providers, linked or not.
+	Providers []OAuthProvider `json:"providers"`
+}
+
+// LinkIdentityRequest carries the link_token the provider's callback sent
+// the app back with.
+type LinkIdentityRequest struct {
+	Token string `json:"token"`
+}
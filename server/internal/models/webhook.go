@@ -0,0 +1,179 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEventType names something an organization's webhook endpoints can
+// subscribe to.
+type WebhookEventType string
+
+const (
+	WebhookDonationCompleted            WebhookEventType = "donation.completed"
+	WebhookMilestoneReached             WebhookEventType = "milestone.reached"
+	WebhookReceiptVerificationCompleted WebhookEventType = "receipt_verification.completed"
+	WebhookDisputeOpened                WebhookEventType = "dispute.opened"
+)
+
+// WebhookEventTypes lists every event an endpoint can subscribe to.
+var WebhookEventTypes = []WebhookEventType{
+	WebhookDonationCompleted,
+	WebhookMilestoneReached,
+	WebhookReceiptVerificationCompleted,
+	WebhookDisputeOpened,
+}
+
+func (t WebhookEventType) IsValid() bool {
+	for _, et := range WebhookEventTypes {
+		if t == et {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxWebhookEndpointsPerOrganization caps the endpoints an organization
+// registers.
+const MaxWebhookEndpointsPerOrganization = 10
+
+// WebhookRetrySchedule is how long to wait after each failed attempt. A
+// delivery that fails once more after the last wait is dead.
+var WebhookRetrySchedule = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+}
+
+// MaxWebhookAttempts is the most times a delivery is tried.
+var MaxWebhookAttempts = len(WebhookRetrySchedule) + 1
+
+// WebhookEndpoint is a URL an organization wants events sent to.
+type WebhookEndpoint struct {
+	ID             uuid.UUID          `json:"id" db:"id"`
+	OrganizationID uuid.UUID          `json:"organization_id" db:"organization_id"`
+	URL            string             `json:"url" db:"url"`
+	Description    string             `json:"description" db:"description"`
+	EventTypes     []WebhookEventType `json:"event_types" db:"event_types"`
+	// Secret signs every payload sent to the endpoint.
+	Secret    string     `json:"-" db:"secret"`
+	IsActive  bool       `json:"is_active" db:"is_active"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Subscribes reports whether the endpoint wants events of type t.
+func (e *WebhookEndpoint) Subscribes(t WebhookEventType) bool {
+	for _, et := range e.EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryDead deliveries ran out of attempts. They are only
+	// sent again if replayed.
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is one event sent, or still to be sent, to one endpoint.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `json:"id" db:"id"`
+	EndpointID     uuid.UUID             `json:"endpoint_id" db:"endpoint_id"`
+	EventID        uuid.UUID             `json:"event_id" db:"event_id"`
+	EventType      WebhookEventType      `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	LastStatusCode *int                  `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      *string               `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+}
+
+// WebhookEvent is the body POSTed to an endpoint. Data carries IDs and
+// amounts, never donor contact details.
+type WebhookEvent struct {
+	ID             uuid.UUID        `json:"id"`
+	Type           WebhookEventType `json:"type"`
+	OrganizationID uuid.UUID        `json:"organization_id"`
+	CreatedAt      time.Time        `json:"created_at"`
+	Data           any              `json:"data"`
+}
+
+type CreateWebhookEndpointRequest struct {
+	URL         string             `json:"url"`
+	Description string             `json:"description"`
+	EventTypes  []WebhookEventType `json:"event_types"`
+}
+
+// UpdateWebhookEndpointRequest changes only the fields that are set.
+type UpdateWebhookEndpointRequest struct {
+	URL         *string            `json:"url"`
+	Description *string            `json:"description"`
+	EventTypes  []WebhookEventType `json:"event_types"`
+	IsActive    *bool              `json:"is_active"`
+}
+
+// CreateWebhookEndpointResponse carries the signing secret, which is only
+// ever shown once.
+type CreateWebhookEndpointResponse struct {
+	Endpoint *WebhookEndpoint `json:"endpoint"`
+	Secret   string           `json:"secret"`
+}
+
+// WebhookDonationData is the data of a donation.completed event.
+type WebhookDonationData struct {
+	DonationID         uuid.UUID  `json:"donation_id"`
+	CauseID            uuid.UUID  `json:"cause_id"`
+	Amount             Paise      `json:"amount"`
+	IsAnonymous        bool       `json:"is_anonymous"`
+	FundraiserID       *uuid.UUID `json:"fundraiser_id,omitempty"`
+	MatchingCampaignID *uuid.UUID `json:"matching_campaign_id,omitempty"`
+	MatchedDonationID  *uuid.UUID `json:"matched_donation_id,omitempty"`
+	TxHash             *string    `json:"tx_hash,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// WebhookMilestoneData is the data of a milestone.reached event.
+type WebhookMilestoneData struct {
+	CauseID         uuid.UUID `json:"cause_id"`
+	MilestoneNumber int       `json:"milestone_number"`
+	DisbursementID  uuid.UUID `json:"disbursement_id"`
+	Amount          float64   `json:"amount"`
+	TransactionHash *string   `json:"transaction_hash,omitempty"`
+}
+
+// WebhookReceiptVerificationData is the data of a
+// receipt_verification.completed event.
+type WebhookReceiptVerificationData struct {
+	ReceiptJobID  uuid.UUID `json:"receipt_job_id"`
+	Status        string    `json:"status"`
+	ReceiptScore  *float64  `json:"receipt_score,omitempty"`
+	ErrorMessage  *string   `json:"error_message,omitempty"`
+	ClaimedAmount float64   `json:"claimed_amount"`
+}
+
+// WebhookDisputeData is the data of a dispute.opened event.
+type WebhookDisputeData struct {
+	DisputeID uuid.UUID  `json:"dispute_id"`
+	CauseID   *uuid.UUID `json:"cause_id,omitempty"`
+	Title     string     `json:"title"`
+	Priority  string     `json:"priority"`
+	CreatedAt time.Time  `json:"created_at"`
+}
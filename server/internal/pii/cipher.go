@@ -0,0 +1,113 @@
+// Package pii encrypts and masks donors' personal data.
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks an encrypted value: "enc:<key id>:<base64 nonce+ciphertext>".
+// Values without it are plaintext written before encryption was enabled and
+// are returned unchanged.
+const prefix = "enc:"
+
+// Cipher encrypts individual column values with AES-256-GCM.
+type Cipher struct {
+	keys KeyProvider
+}
+
+func NewCipher(keys KeyProvider) *Cipher {
+	return &Cipher{keys: keys}
+}
+
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	id, key, err := c.keys.CurrentKey()
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return prefix + id + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	id, encoded, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", errors.New("malformed encrypted value")
+	}
+	key, err := c.keys.Key(id)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// EncryptPtr and DecryptPtr handle nullable columns; nil stays nil.
+func (c *Cipher) EncryptPtr(plaintext *string) (*string, error) {
+	if plaintext == nil {
+		return nil, nil
+	}
+	value, err := c.Encrypt(*plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+func (c *Cipher) DecryptPtr(value *string) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	plaintext, err := c.Decrypt(*value)
+	if err != nil {
+		return nil, err
+	}
+	return &plaintext, nil
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
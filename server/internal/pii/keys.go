@@ -0,0 +1,111 @@
+package pii
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// KeyProvider supplies the AES-256 keys used to encrypt personal data.
+// New values are always encrypted with the current key; older keys are
+// kept so existing values can still be read after a rotation.
+type KeyProvider interface {
+	// CurrentKey returns the ID and key used for new values.
+	CurrentKey() (string, []byte, error)
+	// Key returns the key with the given ID.
+	Key(id string) ([]byte, error)
+}
+
+// StaticKeyProvider holds a fixed set of keys.
+type StaticKeyProvider struct {
+	currentID string
+	keys      map[string][]byte
+}
+
+func NewStaticKeyProvider(currentID string, keys map[string][]byte) (*StaticKeyProvider, error) {
+	for id, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("pii key %q must be 32 bytes, got %d", id, len(key))
+		}
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("pii key id %q must not contain ':'", id)
+		}
+	}
+	if _, ok := keys[currentID]; !ok {
+		return nil, fmt.Errorf("pii key %q not found", currentID)
+	}
+	return &StaticKeyProvider{currentID: currentID, keys: keys}, nil
+}
+
+func (p *StaticKeyProvider) CurrentKey() (string, []byte, error) {
+	return p.currentID, p.keys[p.currentID], nil
+}
+
+func (p *StaticKeyProvider) Key(id string) ([]byte, error) {
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("pii key %q not found", id)
+	}
+	return key, nil
+}
+
+// NewEnvKeyProvider reads keys from the environment:
+//
+//	PII_ENCRYPTION_KEY          base64 32-byte key for new values
+//	PII_ENCRYPTION_KEY_ID       its ID (default "v1")
+//	PII_ENCRYPTION_OLD_KEYS     retired keys as "id:base64,id:base64"
+//	PII_ALLOW_DEV_KEY           "true" to use a fixed development key when
+//	                            PII_ENCRYPTION_KEY is unset (local only)
+//
+// It is an error to leave PII_ENCRYPTION_KEY unset otherwise, so a
+// misconfigured server fails at startup instead of encrypting donor data
+// with a key that is in the source tree.
+func NewEnvKeyProvider() (*StaticKeyProvider, error) {
+	currentID := os.Getenv("PII_ENCRYPTION_KEY_ID")
+	if currentID == "" {
+		currentID = "v1"
+	}
+
+	keys := map[string][]byte{}
+
+	raw := os.Getenv("PII_ENCRYPTION_KEY")
+	allowDevKey, _ := strconv.ParseBool(os.Getenv("PII_ALLOW_DEV_KEY"))
+	if raw == "" && !allowDevKey {
+		return nil, errors.New("PII_ENCRYPTION_KEY is not set (set PII_ALLOW_DEV_KEY=true to use the development key locally)")
+	}
+	if raw == "" {
+		log.Println("Warning: PII_ENCRYPTION_KEY is not set, using the development key")
+		devKey := sha256.Sum256([]byte("dev-pii-encryption-key"))
+		currentID = "dev"
+		keys[currentID] = devKey[:]
+	} else {
+		key, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PII_ENCRYPTION_KEY: %w", err)
+		}
+		keys[currentID] = key
+	}
+
+	if old := os.Getenv("PII_ENCRYPTION_OLD_KEYS"); old != "" {
+		for _, entry := range strings.Split(old, ",") {
+			id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid PII_ENCRYPTION_OLD_KEYS entry %q", entry)
+			}
+			key, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("invalid PII_ENCRYPTION_OLD_KEYS key %q: %w", id, err)
+			}
+			if _, exists := keys[id]; !exists {
+				keys[id] = key
+			}
+		}
+	}
+
+	return NewStaticKeyProvider(currentID, keys)
+}
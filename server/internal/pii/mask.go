@@ -0,0 +1,55 @@
+package pii
+
+import "strings"
+
+// MaskPhone keeps the last four digits: "9876543210" -> "******3210".
+func MaskPhone(phone string) string {
+	return maskAllBut(phone, 4)
+}
+
+// MaskPAN keeps the last four characters: "ABCDE1234F" -> "******234F".
+func MaskPAN(pan string) string {
+	return maskAllBut(pan, 4)
+}
+
+// MaskAddress keeps only the last comma-separated part, usually the city or
+// state: "12 MG Road, Indiranagar, Bengaluru" -> "***, Bengaluru".
+func MaskAddress(address string) string {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return ""
+	}
+	i := strings.LastIndex(address, ",")
+	if i < 0 {
+		return "***"
+	}
+	return "***, " + strings.TrimSpace(address[i+1:])
+}
+
+func MaskPhonePtr(phone *string) *string {
+	return maskPtr(phone, MaskPhone)
+}
+
+func MaskPANPtr(pan *string) *string {
+	return maskPtr(pan, MaskPAN)
+}
+
+func MaskAddressPtr(address *string) *string {
+	return maskPtr(address, MaskAddress)
+}
+
+func maskAllBut(value string, keep int) string {
+	runes := []rune(strings.TrimSpace(value))
+	if len(runes) <= keep {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", len(runes)-keep) + string(runes[len(runes)-keep:])
+}
+
+func maskPtr(value *string, mask func(string) string) *string {
+	if value == nil {
+		return nil
+	}
+	masked := mask(*value)
+	return &masked
+}
@@ -0,0 +1,119 @@
+package pii
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func testProvider(t *testing.T, currentID string, keys map[string][]byte) *StaticKeyProvider {
+	t.Helper()
+	provider, err := NewStaticKeyProvider(currentID, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	cipher := NewCipher(testProvider(t, "v1", map[string][]byte{"v1": bytes.Repeat([]byte{1}, 32)}))
+
+	encrypted, err := cipher.Encrypt("ABCDE1234F")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(encrypted) || bytes.Contains([]byte(encrypted), []byte("ABCDE1234F")) {
+		t.Fatalf("expected an encrypted value, got %q", encrypted)
+	}
+
+	again, _ := cipher.Encrypt("ABCDE1234F")
+	if again == encrypted {
+		t.Fatal("expected a fresh nonce for every value")
+	}
+
+	decrypted, err := cipher.Decrypt(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted != "ABCDE1234F" {
+		t.Fatalf("expected round trip, got %q", decrypted)
+	}
+}
+
+func TestCipherDecryptsLegacyPlaintextAndRetiredKeys(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+
+	old := NewCipher(testProvider(t, "v1", map[string][]byte{"v1": oldKey}))
+	encrypted, err := old.Encrypt("9876543210")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := NewCipher(testProvider(t, "v2", map[string][]byte{"v1": oldKey, "v2": newKey}))
+	if got, err := rotated.Decrypt(encrypted); err != nil || got != "9876543210" {
+		t.Fatalf("expected value under retired key to decrypt, got %q, %v", got, err)
+	}
+
+	if got, err := rotated.Decrypt("9876543210"); err != nil || got != "9876543210" {
+		t.Fatalf("expected plaintext to pass through, got %q, %v", got, err)
+	}
+
+	withoutOld := NewCipher(testProvider(t, "v2", map[string][]byte{"v2": newKey}))
+	if _, err := withoutOld.Decrypt(encrypted); err == nil {
+		t.Fatal("expected decrypt to fail without the old key")
+	}
+}
+
+func TestEnvKeyProviderRequiresKey(t *testing.T) {
+	t.Setenv("PII_ENCRYPTION_KEY", "")
+	t.Setenv("PII_ENCRYPTION_KEY_ID", "")
+	t.Setenv("PII_ENCRYPTION_OLD_KEYS", "")
+
+	t.Setenv("PII_ALLOW_DEV_KEY", "")
+	if _, err := NewEnvKeyProvider(); err == nil {
+		t.Fatal("expected an error without PII_ENCRYPTION_KEY")
+	}
+
+	t.Setenv("PII_ALLOW_DEV_KEY", "true")
+	provider, err := NewEnvKeyProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _, _ := provider.CurrentKey(); id != "dev" {
+		t.Fatalf("expected the development key, got %q", id)
+	}
+
+	t.Setenv("PII_ALLOW_DEV_KEY", "")
+	t.Setenv("PII_ENCRYPTION_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{3}, 32)))
+	provider, err = NewEnvKeyProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, key, _ := provider.CurrentKey(); id != "v1" || key[0] != 3 {
+		t.Fatalf("expected key v1 from the environment, got %q", id)
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"phone", MaskPhone("9876543210"), "******3210"},
+		{"short phone", MaskPhone("123"), "***"},
+		{"pan", MaskPAN("ABCDE1234F"), "******234F"},
+		{"address", MaskAddress("12 MG Road, Indiranagar, Bengaluru"), "***, Bengaluru"},
+		{"address without parts", MaskAddress("12 MG Road"), "***"},
+		{"empty address", MaskAddress(""), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, tt.got)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package policy
+
+import (
+	"context"
+	"errors"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Authorizer loads subjects for policy decisions.
+type Authorizer struct {
+	members       repository.OrganizationMemberRepository
+	organizations repository.OrganizationRepository
+}
+
+func NewAuthorizer(members repository.OrganizationMemberRepository, organizations repository.OrganizationRepository) *Authorizer {
+	return &Authorizer{members: members, organizations: organizations}
+}
+
+// Subject loads the user's organization membership, if any, alongside their
+// account role.
+func (a *Authorizer) Subject(ctx context.Context, userID uuid.UUID, accountRole string) (*Subject, error) {
+	s := &Subject{UserID: userID, AccountRole: models.RoleType(accountRole)}
+
+	member, err := a.members.GetByUserID(ctx, userID)
+	if errors.Is(err, repository.ErrNotOrganizationMember) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if member.Organization, err = a.organizations.GetByOrganizationID(ctx, member.OrganizationID); err != nil {
+		return nil, err
+	}
+	s.Member = member
+	return s, nil
+}
@@ -0,0 +1,218 @@
+// Package policy decides who may do what. Handlers name the permission an
+// action needs and the resource it touches; the rules for granting it live
+// here so they can be tested in one place.
+package policy
+
+import (
+	"errors"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Permission is an action checked against the caller's account role, their
+// role in an organization, or their ownership of a resource.
+type Permission string
+
+const (
+	// Platform administration.
+	AdminDashboardView     Permission = "admin:dashboard"
+	ReviewModerate         Permission = "review:moderate"
+	CauseApprove           Permission = "cause:approve"
+	MatchingCampaignManage Permission = "matching_campaign:manage"
+	PIIAccessLogView       Permission = "pii_log:view"
+	// CauseModerate moves any cause between states as the platform, rather
+	// than as its organization.
+	CauseModerate Permission = "cause:moderate"
+	// DisputeResolve reviews disputes raised about causes.
+	DisputeResolve Permission = "dispute:resolve"
+
+	// Acting for an organization.
+	OrganizationView Permission = "organization:view"
+	MembersManage    Permission = "members:manage"
+	APIKeysManage    Permission = "api_keys:manage"
+	WebhooksManage   Permission = "webhooks:manage"
+	CauseCreate      Permission = "cause:create"
+	CauseUpdate      Permission = "cause:update"
+	CauseDelete      Permission = "cause:delete"
+	// CausePublish covers submitting a cause for review and the state
+	// changes an organization may make itself.
+	CausePublish         Permission = "cause:publish"
+	CauseViewUnpublished Permission = "cause:view_unpublished"
+	CauseReviewReply     Permission = "cause_review:reply"
+	UpdatePost           Permission = "update:post"
+	ProofUpload          Permission = "proof:upload"
+	PledgeManage         Permission = "pledge:manage"
+	DisbursementView     Permission = "disbursement:view"
+	// DisbursementApprove releases a disbursement to the organization.
+	DisbursementApprove Permission = "disbursement:approve"
+	// DonationRefund refunds a donation to its donor.
+	DonationRefund Permission = "donation:refund"
+	// DonorView shows a donation's contact details and PAN.
+	DonorView Permission = "donation:view_donor"
+)
+
+var (
+	// ErrForbidden is returned when the subject lacks a permission.
+	ErrForbidden = errors.New("you don't have permission to do this")
+	// ErrOrganizationUnverified is returned instead when the subject acts
+	// for an organization that has not verified its email yet.
+	ErrOrganizationUnverified = errors.New("verify your organization's email address first")
+)
+
+// Platform admins moderate, can look at any organization's causes, donors
+// and disbursements, and can approve disbursements and refund donations,
+// but don't act for organizations.
+var adminPermissions = permissionSet(
+	AdminDashboardView, ReviewModerate, CauseApprove, MatchingCampaignManage,
+	PIIAccessLogView, CauseModerate, DisputeResolve, CauseViewUnpublished,
+	DonorView, DisbursementView, DisbursementApprove, DonationRefund,
+)
+
+var organizationManagerPermissions = []Permission{
+	OrganizationView, MembersManage, APIKeysManage, WebhooksManage, CauseCreate, CauseUpdate, CauseDelete,
+	CausePublish, CauseViewUnpublished, CauseReviewReply, UpdatePost,
+	ProofUpload, PledgeManage, DisbursementView, DonorView,
+	DisbursementApprove, DonationRefund,
+}
+
+var memberPermissions = map[models.OrganizationMemberRole]map[Permission]bool{
+	models.OrganizationRoleOwner: permissionSet(organizationManagerPermissions...),
+	models.OrganizationRoleAdmin: permissionSet(organizationManagerPermissions...),
+	models.OrganizationRoleFinance: permissionSet(
+		OrganizationView, CauseViewUnpublished, DisbursementView, DonorView,
+		DisbursementApprove, DonationRefund,
+	),
+	models.OrganizationRoleFieldAgent: permissionSet(
+		OrganizationView, CauseViewUnpublished, UpdatePost, ProofUpload, PledgeManage,
+	),
+	models.OrganizationRoleViewer: permissionSet(OrganizationView, CauseViewUnpublished),
+}
+
+// An organization's registration is complete once its email is verified.
+// Until then its members can look at it but not act for it.
+var unregisteredPermissions = permissionSet(OrganizationView, CauseViewUnpublished)
+
+func registrationAllows(org *models.Organization, p Permission) bool {
+	return unregisteredPermissions[p] || (org != nil && org.IsRegistered())
+}
+
+// scopePermissions is what each API key scope lets a key do in its own
+// organization. Keys never hold platform or ownership permissions, nor
+// anything needed to manage members, keys or webhooks.
+var scopePermissions = map[models.APIKeyScope]map[Permission]bool{
+	models.ScopeDonationsRead:     permissionSet(DonorView),
+	models.ScopeCausesRead:        permissionSet(CauseViewUnpublished),
+	models.ScopeUpdatesWrite:      permissionSet(UpdatePost),
+	models.ScopeDisbursementsRead: permissionSet(DisbursementView),
+}
+
+// Anyone may do these to resources they own.
+var ownerPermissions = permissionSet(DonorView)
+
+func permissionSet(perms ...Permission) map[Permission]bool {
+	set := make(map[Permission]bool, len(perms))
+	for _, p := range perms {
+		set[p] = true
+	}
+	return set
+}
+
+// Subject is the caller a decision is made for.
+type Subject struct {
+	UserID      uuid.UUID
+	AccountRole models.RoleType
+	// Member is the caller's organization membership, with its
+	// organization, or nil.
+	Member *models.OrganizationMember
+	// APIKey is set, with its organization, instead of UserID when the
+	// caller is an organization's API key rather than a person.
+	APIKey *models.OrganizationAPIKey
+}
+
+// IsAdmin reports whether the subject is a platform admin.
+func (s *Subject) IsAdmin() bool {
+	return s != nil && s.AccountRole == models.RoleTypeAdmin
+}
+
+// Organization returns the organization the subject acts for, through
+// membership or an API key, or nil.
+func (s *Subject) Organization() *models.Organization {
+	switch {
+	case s == nil:
+		return nil
+	case s.APIKey != nil:
+		return s.APIKey.Organization
+	case s.Member != nil:
+		return s.Member.Organization
+	}
+	return nil
+}
+
+// Resource says whose thing is being acted on. A zero Resource asks whether
+// the subject holds the permission at all.
+type Resource struct {
+	// OrganizationID is the organization the resource belongs to.
+	OrganizationID uuid.UUID
+	// OwnerID is the user the resource belongs to, such as a donor.
+	OwnerID uuid.UUID
+}
+
+// OfOrganization is a resource belonging to an organization.
+func OfOrganization(organizationID uuid.UUID) Resource {
+	return Resource{OrganizationID: organizationID}
+}
+
+// Allowed reports whether s may perform p on res.
+func Allowed(s *Subject, p Permission, res Resource) bool {
+	if s == nil {
+		return false
+	}
+	if s.APIKey != nil {
+		return registrationAllows(s.APIKey.Organization, p) && keyAllowed(s.APIKey, p, res)
+	}
+	if s.IsAdmin() && adminPermissions[p] {
+		return true
+	}
+	if res.OwnerID != uuid.Nil && res.OwnerID == s.UserID && ownerPermissions[p] {
+		return true
+	}
+	if s.Member != nil && memberPermissions[s.Member.Role][p] {
+		if !registrationAllows(s.Member.Organization, p) {
+			return false
+		}
+		return res.OrganizationID == uuid.Nil || res.OrganizationID == s.Member.OrganizationID
+	}
+	return false
+}
+
+func keyAllowed(key *models.OrganizationAPIKey, p Permission, res Resource) bool {
+	if res.OrganizationID != uuid.Nil && res.OrganizationID != key.OrganizationID {
+		return false
+	}
+	for _, scope := range key.Scopes {
+		if scopePermissions[scope][p] {
+			return true
+		}
+	}
+	return false
+}
+
+// Check is Allowed as an error: nil, ErrOrganizationUnverified or
+// ErrForbidden.
+func Check(s *Subject, p Permission, res Resource) error {
+	if Allowed(s, p, res) {
+		return nil
+	}
+	if org := s.Organization(); org != nil && !org.IsRegistered() {
+		return ErrOrganizationUnverified
+	}
+	return ErrForbidden
+}
+
+// RolePermits reports whether members with role may perform p in their own
+// organization.
+func RolePermits(role models.OrganizationMemberRole, p Permission) bool {
+	return memberPermissions[role][p]
+}
@@ -0,0 +1,144 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestAllowed(t *testing.T) {
+	orgID := uuid.New()
+	otherOrgID := uuid.New()
+
+	org := &models.Organization{ID: orgID, User: &models.User{IsVerified: true}}
+	unverifiedOrg := &models.Organization{ID: orgID, User: &models.User{}}
+
+	member := func(role models.OrganizationMemberRole) *Subject {
+		return &Subject{
+			UserID:      uuid.New(),
+			AccountRole: models.RoleTypeUser,
+			Member:      &models.OrganizationMember{OrganizationID: orgID, Role: role, Organization: org},
+		}
+	}
+	unverifiedOwner := &Subject{
+		UserID:      uuid.New(),
+		AccountRole: models.RoleTypeOrganization,
+		Member:      &models.OrganizationMember{OrganizationID: orgID, Role: models.OrganizationRoleOwner, Organization: unverifiedOrg},
+	}
+	admin := &Subject{UserID: uuid.New(), AccountRole: models.RoleTypeAdmin}
+	donor := &Subject{UserID: uuid.New(), AccountRole: models.RoleTypeUser}
+	apiKey := func(scopes ...models.APIKeyScope) *Subject {
+		return &Subject{APIKey: &models.OrganizationAPIKey{OrganizationID: orgID, Scopes: scopes, Organization: org}}
+	}
+
+	tests := []struct {
+		name    string
+		subject *Subject
+		perm    Permission
+		res     Resource
+		allowed bool
+	}{
+		{"nobody", nil, OrganizationView, Resource{}, false},
+
+		{"owner manages members", member(models.OrganizationRoleOwner), MembersManage, OfOrganization(orgID), true},
+		{"admin member updates causes", member(models.OrganizationRoleAdmin), CauseUpdate, OfOrganization(orgID), true},
+		{"owner cannot touch another organization", member(models.OrganizationRoleOwner), CauseUpdate, OfOrganization(otherOrgID), false},
+		{"finance sees disbursements", member(models.OrganizationRoleFinance), DisbursementView, OfOrganization(orgID), true},
+		{"finance sees donors", member(models.OrganizationRoleFinance), DonorView, OfOrganization(orgID), true},
+		{"finance approves disbursements", member(models.OrganizationRoleFinance), DisbursementApprove, OfOrganization(orgID), true},
+		{"finance refunds donations", member(models.OrganizationRoleFinance), DonationRefund, OfOrganization(orgID), true},
+		{"owner refunds donations", member(models.OrganizationRoleOwner), DonationRefund, OfOrganization(orgID), true},
+		{"finance cannot refund another organization's donations", member(models.OrganizationRoleFinance), DonationRefund, OfOrganization(otherOrgID), false},
+		{"finance cannot edit causes", member(models.OrganizationRoleFinance), CauseUpdate, OfOrganization(orgID), false},
+		{"finance cannot manage webhooks", member(models.OrganizationRoleFinance), WebhooksManage, OfOrganization(orgID), false},
+		{"field agent uploads proofs", member(models.OrganizationRoleFieldAgent), ProofUpload, OfOrganization(orgID), true},
+		{"field agent posts updates", member(models.OrganizationRoleFieldAgent), UpdatePost, OfOrganization(orgID), true},
+		{"field agent cannot refund donations", member(models.OrganizationRoleFieldAgent), DonationRefund, OfOrganization(orgID), false},
+		{"viewer cannot approve disbursements", member(models.OrganizationRoleViewer), DisbursementApprove, OfOrganization(orgID), false},
+		{"field agent cannot see donors", member(models.OrganizationRoleFieldAgent), DonorView, OfOrganization(orgID), false},
+		{"viewer sees unpublished causes", member(models.OrganizationRoleViewer), CauseViewUnpublished, OfOrganization(orgID), true},
+		{"viewer cannot post updates", member(models.OrganizationRoleViewer), UpdatePost, OfOrganization(orgID), false},
+		{"unknown member role has nothing", member("director"), OrganizationView, OfOrganization(orgID), false},
+		{"members cannot moderate", member(models.OrganizationRoleOwner), CauseModerate, Resource{}, false},
+		{"route check passes for any organization", member(models.OrganizationRoleOwner), CauseCreate, Resource{}, true},
+		{"unverified organization sees itself", unverifiedOwner, OrganizationView, OfOrganization(orgID), true},
+		{"unverified organization cannot create causes", unverifiedOwner, CauseCreate, OfOrganization(orgID), false},
+		{"unverified organization cannot create API keys", unverifiedOwner, APIKeysManage, OfOrganization(orgID), false},
+		{"unverified organization's key is refused", &Subject{APIKey: &models.OrganizationAPIKey{
+			OrganizationID: orgID, Scopes: []models.APIKeyScope{models.ScopeUpdatesWrite}, Organization: unverifiedOrg,
+		}}, UpdatePost, OfOrganization(orgID), false},
+
+		{"platform admin moderates", admin, CauseModerate, Resource{}, true},
+		{"platform admin resolves disputes", admin, DisputeResolve, Resource{}, true},
+		{"owners cannot resolve disputes", member(models.OrganizationRoleOwner), DisputeResolve, OfOrganization(orgID), false},
+		{"platform admin sees disbursements", admin, DisbursementView, OfOrganization(otherOrgID), true},
+		{"platform admin approves disbursements", admin, DisbursementApprove, OfOrganization(otherOrgID), true},
+		{"platform admin refunds donations", admin, DonationRefund, OfOrganization(otherOrgID), true},
+		{"platform admin sees any organization's donors", admin, DonorView, OfOrganization(otherOrgID), true},
+		{"platform admin does not act for organizations", admin, CauseCreate, OfOrganization(orgID), false},
+
+		{"donor sees own donation", donor, DonorView, Resource{OrganizationID: orgID, OwnerID: donor.UserID}, true},
+		{"donor cannot see others' donations", donor, DonorView, Resource{OrganizationID: orgID, OwnerID: uuid.New()}, false},
+		{"ownership grants nothing else", donor, DonationRefund, Resource{OwnerID: donor.UserID}, false},
+		{"plain user has no admin access", donor, AdminDashboardView, Resource{}, false},
+
+		{"key reads donors", apiKey(models.ScopeDonationsRead), DonorView, OfOrganization(orgID), true},
+		{"key posts updates", apiKey(models.ScopeUpdatesWrite), UpdatePost, OfOrganization(orgID), true},
+		{"key without the scope is refused", apiKey(models.ScopeCausesRead), DonorView, OfOrganization(orgID), false},
+		{"key cannot reach another organization", apiKey(models.ScopeDonationsRead), DonorView, OfOrganization(otherOrgID), false},
+		{"key cannot manage keys", apiKey(models.APIKeyScopes...), APIKeysManage, OfOrganization(orgID), false},
+		{"key cannot manage webhooks", apiKey(models.APIKeyScopes...), WebhooksManage, OfOrganization(orgID), false},
+		{"key cannot refund donations", apiKey(models.APIKeyScopes...), DonationRefund, OfOrganization(orgID), false},
+		{"key cannot approve disbursements", apiKey(models.APIKeyScopes...), DisbursementApprove, OfOrganization(orgID), false},
+		{"key has no platform permissions", apiKey(models.APIKeyScopes...), CauseModerate, Resource{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Allowed(tt.subject, tt.perm, tt.res); got != tt.allowed {
+				t.Fatalf("Allowed(%s) = %v, want %v", tt.perm, got, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestCheckExplainsUnverifiedOrganization(t *testing.T) {
+	orgID := uuid.New()
+	owner := func(verified bool) *Subject {
+		return &Subject{UserID: uuid.New(), Member: &models.OrganizationMember{
+			OrganizationID: orgID,
+			Role:           models.OrganizationRoleOwner,
+			Organization:   &models.Organization{ID: orgID, User: &models.User{IsVerified: verified}},
+		}}
+	}
+
+	if err := Check(owner(false), CauseCreate, OfOrganization(orgID)); !errors.Is(err, ErrOrganizationUnverified) {
+		t.Errorf("Check() for an unverified organization = %v, want ErrOrganizationUnverified", err)
+	}
+	if err := Check(owner(true), CauseCreate, OfOrganization(orgID)); err != nil {
+		t.Errorf("Check() for a verified organization = %v, want nil", err)
+	}
+	if err := Check(owner(true), CauseModerate, Resource{}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Check() without the permission = %v, want ErrForbidden", err)
+	}
+}
+
+func TestKeyAllowedStaysInItsOrganization(t *testing.T) {
+	orgID, otherOrgID := uuid.New(), uuid.New()
+	key := &models.OrganizationAPIKey{OrganizationID: orgID, Scopes: models.APIKeyScopes}
+
+	for _, p := range []Permission{DonorView, CauseViewUnpublished, UpdatePost, DisbursementView} {
+		if !keyAllowed(key, p, OfOrganization(orgID)) {
+			t.Errorf("keyAllowed(%s) in its own organization = false, want true", p)
+		}
+		if keyAllowed(key, p, OfOrganization(otherOrgID)) {
+			t.Errorf("keyAllowed(%s) in another organization = true, want false", p)
+		}
+		if keyAllowed(key, p, Resource{OrganizationID: otherOrgID, OwnerID: uuid.New()}) {
+			t.Errorf("keyAllowed(%s) on another organization's donor = true, want false", p)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidAccountToken covers unknown, expired, used and superseded
+// tokens alike, so the response does not reveal which.
+var ErrInvalidAccountToken = errors.New("link is invalid or has expired")
+
+type AccountTokenRepository interface {
+	// Create stores a token and retires the user's earlier unused tokens for
+	// the same purpose, so only the latest link works.
+	Create(ctx context.Context, token *models.AccountToken) error
+	// VerifyEmail uses a live email verification token and marks the
+	// user's email verified, in one transaction. It fails with
+	// ErrInvalidAccountToken if the token was used, superseded or expired,
+	// or the account no longer uses the address it was sent to.
+	VerifyEmail(ctx context.Context, id uuid.UUID, now time.Time) (uuid.UUID, error)
+	// ResetPassword uses a live password reset token, sets the new password
+	// hash, marks the email verified and revokes every session, in one
+	// transaction. It fails like VerifyEmail.
+	ResetPassword(ctx context.Context, id uuid.UUID, passwordHash string, now time.Time) (uuid.UUID, error)
+	// CountSince counts tokens sent to the user for purpose since the given
+	// time.
+	CountSince(ctx context.Context, userID uuid.UUID, purpose models.AccountTokenPurpose, since time.Time) (int, error)
+}
+
+type accountTokenRepository struct {
+	db *sql.DB
+}
+
+func NewAccountTokenRepository(db *sql.DB) AccountTokenRepository {
+	return &accountTokenRepository{db: db}
+}
+
+func (r *accountTokenRepository) Create(ctx context.Context, token *models.AccountToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE account_tokens SET used_at = $3
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`, token.UserID, token.Purpose, token.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO account_tokens (id, user_id, purpose, email, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`,
+		token.ID,
+		token.UserID,
+		token.Purpose,
+		token.Email,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// consumeAccountToken marks a live token used and returns it.
+func consumeAccountToken(ctx context.Context, tx *sql.Tx, id uuid.UUID, purpose models.AccountTokenPurpose, now time.Time) (*models.AccountToken, error) {
+	token := &models.AccountToken{}
+	err := tx.QueryRowContext(ctx, `
+		UPDATE account_tokens SET used_at = $3
+		WHERE id = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > $3
+		RETURNING id, user_id, purpose, email, expires_at, used_at, created_at
+	`, id, purpose, now).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.Email,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidAccountToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// updateTokenUser applies set to the token's user, as long as the account
+// is active and still uses the address the token was sent to. $1 is the
+// user ID, $2 the address and $3 the time.
+func updateTokenUser(ctx context.Context, tx *sql.Tx, token *models.AccountToken, now time.Time, set string, args ...interface{}) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE users SET `+set+`, updated_at = $3
+		WHERE id = $1 AND LOWER(email) = LOWER($2) AND is_active = TRUE
+	`, append([]interface{}{token.UserID, token.Email, now}, args...)...)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrInvalidAccountToken
+	}
+	return nil
+}
+
+func (r *accountTokenRepository) VerifyEmail(ctx context.Context, id uuid.UUID, now time.Time) (uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	token, err := consumeAccountToken(ctx, tx, id, models.AccountTokenEmailVerification, now)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := updateTokenUser(ctx, tx, token, now, `is_verified = TRUE`); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, err
+	}
+	return token.UserID, nil
+}
+
+func (r *accountTokenRepository) ResetPassword(ctx context.Context, id uuid.UUID, passwordHash string, now time.Time) (uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	token, err := consumeAccountToken(ctx, tx, id, models.AccountTokenPasswordReset, now)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	// Following the link proves the user reads this inbox.
+	if err := updateTokenUser(ctx, tx, token, now, `password_hash = $4, is_verified = TRUE`, passwordHash); err != nil {
+		return uuid.Nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE auth_sessions SET revoked_at = $2, revoked_reason = $3
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`, token.UserID, now, models.SessionRevokedPassword)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, err
+	}
+	return token.UserID, nil
+}
+
+func (r *accountTokenRepository) CountSince(ctx context.Context, userID uuid.UUID, purpose models.AccountTokenPurpose, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM account_tokens
+		WHERE user_id = $1 AND purpose = $2 AND created_at >= $3
+	`, userID, purpose, since).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestAccountTokenResetPassword(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	if _, err := db.ExecContext(ctx, `INSERT INTO users (id, name, email, password_hash) VALUES ($1, 'Asha', 'asha@example.com', 'old')`, userID); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewAccountTokenRepository(db)
+	sessions := NewSessionRepository(db)
+
+	issue := func(t *testing.T) *models.AccountToken {
+		t.Helper()
+		now := time.Now()
+		token := &models.AccountToken{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Purpose:   models.AccountTokenPasswordReset,
+			Email:     "asha@example.com",
+			ExpiresAt: now.Add(time.Hour),
+			CreatedAt: now,
+		}
+		if err := repo.Create(ctx, token); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		return token
+	}
+	passwordHash := func(t *testing.T) string {
+		t.Helper()
+		var hash string
+		if err := db.QueryRowContext(ctx, `SELECT password_hash FROM users WHERE id = $1`, userID).Scan(&hash); err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+
+	t.Run("sets the password and revokes sessions once", func(t *testing.T) {
+		session := newTestSession(t, sessions, userID, hashOf('r'), time.Now().Add(time.Hour))
+		token := issue(t)
+
+		if _, err := repo.ResetPassword(ctx, token.ID, "new", time.Now()); err != nil {
+			t.Fatalf("ResetPassword() error = %v", err)
+		}
+		if got := passwordHash(t); got != "new" {
+			t.Errorf("password_hash = %q, want %q", got, "new")
+		}
+		if active, err := sessions.IsActive(ctx, session.ID); err != nil || active {
+			t.Errorf("IsActive() = %v, %v after a password reset, want false", active, err)
+		}
+
+		if _, err := repo.ResetPassword(ctx, token.ID, "again", time.Now()); !errors.Is(err, ErrInvalidAccountToken) {
+			t.Errorf("second ResetPassword() error = %v, want ErrInvalidAccountToken", err)
+		}
+	})
+
+	t.Run("rolls back when the address changed", func(t *testing.T) {
+		token := issue(t)
+		if _, err := db.ExecContext(ctx, `UPDATE users SET email = 'asha@example.org' WHERE id = $1`, userID); err != nil {
+			t.Fatal(err)
+		}
+		before := passwordHash(t)
+
+		if _, err := repo.ResetPassword(ctx, token.ID, "stolen", time.Now()); !errors.Is(err, ErrInvalidAccountToken) {
+			t.Fatalf("ResetPassword() error = %v, want ErrInvalidAccountToken", err)
+		}
+		if got := passwordHash(t); got != before {
+			t.Errorf("password_hash = %q, want it unchanged", got)
+		}
+		var usedAt *time.Time
+		if err := db.QueryRowContext(ctx, `SELECT used_at FROM account_tokens WHERE id = $1`, token.ID).Scan(&usedAt); err != nil {
+			t.Fatal(err)
+		}
+		if usedAt != nil {
+			t.Error("token marked used although the reset was rolled back")
+		}
+	})
+
+	t.Run("rejects expired tokens", func(t *testing.T) {
+		token := issue(t)
+		if _, err := repo.ResetPassword(ctx, token.ID, "late", token.ExpiresAt); !errors.Is(err, ErrInvalidAccountToken) {
+			t.Errorf("ResetPassword() at expiry error = %v, want ErrInvalidAccountToken", err)
+		}
+	})
+}
@@ -3,12 +3,16 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"server/internal/models"
+
+	"github.com/google/uuid"
 )
 
 type AdminRepository interface {
 	GetDashboardData(ctx context.Context) (*models.AdminDashboardData, error)
+	LogAction(ctx context.Context, adminUserID uuid.UUID, actionType, targetType string, targetID uuid.UUID, metadata map[string]interface{}) error
 }
 
 type adminRepository struct {
@@ -89,3 +93,20 @@ func (r *adminRepository) GetDashboardData(ctx context.Context) (*models.AdminDa
 		DonorNames:        donorNames,
 	}, nil
 }
+
+// LogAction records an admin decision in admin_action_logs.
+func (r *adminRepository) LogAction(ctx context.Context, adminUserID uuid.UUID, actionType, targetType string, targetID uuid.UUID, metadata map[string]interface{}) error {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO admin_action_logs (admin_user_id, action_type, target_type, target_id, metadata)
+		VALUES ($1, $2, $3, $4, $5::jsonb)
+	`, adminUserID, actionType, targetType, targetID, string(raw))
+	return err
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"server/internal/models"
+)
+
+type AnalyticsRepository interface {
+	GetDonationVolume(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error)
+	GetDonationCount(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error)
+	GetNewDonors(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error)
+	GetNewCauses(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error)
+	GetDisbursedAmount(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error)
+	GetVerificationPassRate(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error)
+	GetAvgReceiptScore(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error)
+
+	GetAdminAnalytics(ctx context.Context, filter models.AnalyticsFilter) (*models.AdminAnalyticsResponse, error)
+}
+
+type analyticsRepository struct {
+	db *sql.DB
+}
+
+func NewAnalyticsRepository(db *sql.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// Every series query receives the same parameters:
+//
+//	$1 interval ('day' | 'week' | 'month'), $2 from, $3 to,
+//	$4 domain_id, $5 aid_type_id, $6 organization_id
+//
+// Buckets are truncated in UTC whatever the session time zone, matching
+// models.AnalyticsInterval.Truncate. The aggregate is left-joined onto
+// generate_series so that empty buckets come back as zero and the result
+// can be plotted directly.
+const analyticsSeriesQuery = `
+	WITH buckets AS (
+		SELECT generate_series(
+			date_trunc($1::text, $2::timestamptz AT TIME ZONE 'UTC'),
+			date_trunc($1::text, $3::timestamptz AT TIME ZONE 'UTC'),
+			('1 ' || $1::text)::interval
+		) AT TIME ZONE 'UTC' AS bucket
+	),
+	agg AS (
+		%s
+	)
+	SELECT b.bucket, COALESCE(a.value, 0)::float8
+	FROM buckets b
+	LEFT JOIN agg a ON a.bucket = b.bucket
+	ORDER BY b.bucket ASC
+`
+
+const analyticsCauseFilter = `
+	($4::uuid IS NULL OR c.domain_id = $4::uuid)
+	AND ($5::uuid IS NULL OR c.aid_type_id = $5::uuid)
+	AND ($6::uuid IS NULL OR c.organization_id = $6::uuid)
+`
+
+// analyticsBucket is the UTC bucket a timestamptz column falls in.
+func analyticsBucket(column string) string {
+	return fmt.Sprintf(`date_trunc($1::text, %s AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'`, column)
+}
+
+func analyticsRange(column string) string {
+	return fmt.Sprintf(`
+		%[1]s >= date_trunc($1::text, $2::timestamptz AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+		AND %[1]s < (date_trunc($1::text, $3::timestamptz AT TIME ZONE 'UTC') + ('1 ' || $1::text)::interval) AT TIME ZONE 'UTC'
+	`, column)
+}
+
+func (r *analyticsRepository) querySeries(ctx context.Context, aggregate string, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error) {
+	query := fmt.Sprintf(analyticsSeriesQuery, aggregate)
+
+	rows, err := r.db.QueryContext(ctx, query,
+		string(filter.Interval),
+		filter.From,
+		filter.To,
+		filter.DomainID,
+		filter.AidTypeID,
+		filter.OrganizationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := make([]models.AnalyticsPoint, 0)
+	for rows.Next() {
+		var point models.AnalyticsPoint
+		if err := rows.Scan(&point.Bucket, &point.Value); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+
+	return points, rows.Err()
+}
+
+func (r *analyticsRepository) GetDonationVolume(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error) {
+	aggregate := `
+		SELECT ` + analyticsBucket("d.created_at") + ` AS bucket, SUM(d.amount) AS value
+		FROM donations d
+		JOIN causes c ON c.id = d.cause_id
+		WHERE d.status = 'paid'
+		AND ` + analyticsRange("d.created_at") + `
+		AND ` + analyticsCauseFilter + `
+		GROUP BY 1
+	`
+	return r.querySeries(ctx, aggregate, filter)
+}
+
+func (r *analyticsRepository) GetDonationCount(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error) {
+	aggregate := `
+		SELECT ` + analyticsBucket("d.created_at") + ` AS bucket, COUNT(*) AS value
+		FROM donations d
+		JOIN causes c ON c.id = d.cause_id
+		WHERE d.status = 'paid'
+		AND ` + analyticsRange("d.created_at") + `
+		AND ` + analyticsCauseFilter + `
+		GROUP BY 1
+	`
+	return r.querySeries(ctx, aggregate, filter)
+}
+
+// GetNewDonors counts donors in the bucket of their first paid donation
+// (within the filtered causes), not every donor active in that bucket.
+func (r *analyticsRepository) GetNewDonors(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error) {
+	aggregate := `
+		SELECT ` + analyticsBucket("f.first_donated_at") + ` AS bucket, COUNT(*) AS value
+		FROM (
+			SELECT d.user_id, MIN(d.created_at) AS first_donated_at
+			FROM donations d
+			JOIN causes c ON c.id = d.cause_id
+			WHERE d.status = 'paid'
+			AND ` + analyticsCauseFilter + `
+			GROUP BY d.user_id
+		) f
+		WHERE ` + analyticsRange("f.first_donated_at") + `
+		GROUP BY 1
+	`
+	return r.querySeries(ctx, aggregate, filter)
+}
+
+func (r *analyticsRepository) GetNewCauses(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error) {
+	aggregate := `
+		SELECT ` + analyticsBucket("c.created_at") + ` AS bucket, COUNT(*) AS value
+		FROM causes c
+		WHERE ` + analyticsRange("c.created_at") + `
+		AND ` + analyticsCauseFilter + `
+		GROUP BY 1
+	`
+	return r.querySeries(ctx, aggregate, filter)
+}
+
+func (r *analyticsRepository) GetDisbursedAmount(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error) {
+	aggregate := `
+		SELECT ` + analyticsBucket("ds.disbursed_at::timestamptz") + ` AS bucket, SUM(ds.amount) AS value
+		FROM disbursements ds
+		JOIN causes c ON c.id = ds.cause_id
+		WHERE ` + analyticsRange("ds.disbursed_at::timestamptz") + `
+		AND ` + analyticsCauseFilter + `
+		GROUP BY 1
+	`
+	return r.querySeries(ctx, aggregate, filter)
+}
+
+// GetVerificationPassRate returns the percentage of decided Execution
+// updates that were verified. Updates still pending are ignored.
+func (r *analyticsRepository) GetVerificationPassRate(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error) {
+	aggregate := `
+		SELECT
+			` + analyticsBucket("cu.created_at::timestamptz") + ` AS bucket,
+			100.0 * COUNT(*) FILTER (WHERE cu.verification_status = 'verified') / COUNT(*) AS value
+		FROM cause_updates cu
+		JOIN causes c ON c.id = cu.cause_id
+		WHERE cu.update_type = 'Execution'
+		AND cu.verification_status IN ('verified', 'review', 'rejected')
+		AND ` + analyticsRange("cu.created_at::timestamptz") + `
+		AND ` + analyticsCauseFilter + `
+		GROUP BY 1
+	`
+	return r.querySeries(ctx, aggregate, filter)
+}
+
+func (r *analyticsRepository) GetAvgReceiptScore(ctx context.Context, filter models.AnalyticsFilter) ([]models.AnalyticsPoint, error) {
+	aggregate := `
+		SELECT ` + analyticsBucket("cu.created_at::timestamptz") + ` AS bucket, AVG(cu.receipt_score_avg) AS value
+		FROM cause_updates cu
+		JOIN causes c ON c.id = cu.cause_id
+		WHERE cu.receipt_score_avg IS NOT NULL
+		AND ` + analyticsRange("cu.created_at::timestamptz") + `
+		AND ` + analyticsCauseFilter + `
+		GROUP BY 1
+	`
+	return r.querySeries(ctx, aggregate, filter)
+}
+
+func (r *analyticsRepository) GetAdminAnalytics(ctx context.Context, filter models.AnalyticsFilter) (*models.AdminAnalyticsResponse, error) {
+	var (
+		series models.AdminAnalyticsSeries
+		err    error
+	)
+
+	if series.DonationVolume, err = r.GetDonationVolume(ctx, filter); err != nil {
+		return nil, fmt.Errorf("donation volume: %w", err)
+	}
+	if series.DonationCount, err = r.GetDonationCount(ctx, filter); err != nil {
+		return nil, fmt.Errorf("donation count: %w", err)
+	}
+	if series.NewDonors, err = r.GetNewDonors(ctx, filter); err != nil {
+		return nil, fmt.Errorf("new donors: %w", err)
+	}
+	if series.NewCauses, err = r.GetNewCauses(ctx, filter); err != nil {
+		return nil, fmt.Errorf("new causes: %w", err)
+	}
+	if series.DisbursedAmount, err = r.GetDisbursedAmount(ctx, filter); err != nil {
+		return nil, fmt.Errorf("disbursed amount: %w", err)
+	}
+	if series.VerificationPassRate, err = r.GetVerificationPassRate(ctx, filter); err != nil {
+		return nil, fmt.Errorf("verification pass rate: %w", err)
+	}
+	if series.AvgReceiptScore, err = r.GetAvgReceiptScore(ctx, filter); err != nil {
+		return nil, fmt.Errorf("average receipt score: %w", err)
+	}
+
+	return &models.AdminAnalyticsResponse{
+		Interval:       filter.Interval,
+		From:           filter.From,
+		To:             filter.To,
+		DomainID:       filter.DomainID,
+		AidTypeID:      filter.AidTypeID,
+		OrganizationID: filter.OrganizationID,
+		Series:         series,
+	}, nil
+}
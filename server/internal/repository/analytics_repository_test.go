@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestAnalyticsBucketsInUTC(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	// A session time zone east of UTC moves late-evening UTC donations
+	// into the next day, and the last evening of a month into the next one.
+	db.SetMaxOpenConns(1)
+	if _, err := db.ExecContext(ctx, `SET TIME ZONE 'Asia/Kolkata'`); err != nil {
+		t.Fatal(err)
+	}
+
+	donorID, ownerID, orgID, causeID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	domainID, aidTypeID := uuid.New(), uuid.New()
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT INTO users (id, name, email) VALUES ($1, 'Asha Rao', 'asha@example.com')`, []interface{}{donorID}},
+		{`INSERT INTO users (id, name, email, role) VALUES ($1, 'Seva Trust', 'seva@example.com', 'organization')`, []interface{}{ownerID}},
+		{`INSERT INTO organizations (id, user_id, organization_name) VALUES ($1, $2, 'Seva Trust')`, []interface{}{orgID, ownerID}},
+		{`INSERT INTO cause_domains (id, name) VALUES ($1, 'Water')`, []interface{}{domainID}},
+		{`INSERT INTO cause_aid_types (id, name) VALUES ($1, 'Funds')`, []interface{}{aidTypeID}},
+		{`INSERT INTO causes (id, organization_id, title, domain_id, aid_type_id, goal_amount) VALUES ($1, $2, 'Clean water for Rampur', $3, $4, 100000)`, []interface{}{causeID, orgID, domainID, aidTypeID}},
+	}
+	for _, s := range statements {
+		if _, err := db.ExecContext(ctx, s.query, s.args...); err != nil {
+			t.Fatalf("seed failed: %v\n%s", err, s.query)
+		}
+	}
+
+	donations := NewDonationRepository(db, newTestCipher(t))
+	for _, d := range []struct {
+		at     string
+		amount models.Paise
+	}{
+		{"2025-01-31T20:00:00Z", 10000},
+		{"2025-02-01T00:30:00Z", 20000},
+		{"2025-02-28T23:00:00Z", 30000},
+	} {
+		donation := testDonation(donorID, causeID, d.amount)
+		donation.CreatedAt, _ = time.Parse(time.RFC3339, d.at)
+		if err := donations.Create(ctx, donation); err != nil {
+			t.Fatalf("Create() donation error = %v", err)
+		}
+	}
+
+	filter := models.AnalyticsFilter{
+		Interval: models.AnalyticsIntervalMonth,
+		From:     time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+	}
+	points, err := NewAnalyticsRepository(db).GetDonationVolume(ctx, filter)
+	if err != nil {
+		t.Fatalf("GetDonationVolume() error = %v", err)
+	}
+
+	want := []models.AnalyticsPoint{
+		{Bucket: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Value: 100},
+		{Bucket: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), Value: 500},
+		{Bucket: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Value: 0},
+	}
+	if len(points) != len(want) || len(points) != filter.BucketCount() {
+		t.Fatalf("got %d points, want %d (BucketCount %d)", len(points), len(want), filter.BucketCount())
+	}
+	for i, p := range points {
+		if !p.Bucket.Equal(want[i].Bucket) || p.Value != want[i].Value {
+			t.Errorf("point %d = %s %v, want %s %v", i, p.Bucket.UTC(), p.Value, want[i].Bucket, want[i].Value)
+		}
+	}
+}
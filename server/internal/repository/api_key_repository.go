@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.OrganizationAPIKey) error
+	// ListByOrganization lists keys that haven't been revoked, newest first.
+	ListByOrganization(ctx context.Context, organizationID uuid.UUID) ([]*models.OrganizationAPIKey, error)
+	CountActive(ctx context.Context, organizationID uuid.UUID) (int, error)
+	// GetActiveByPrefix returns the unrevoked, unexpired key with this
+	// prefix, or sql.ErrNoRows.
+	GetActiveByPrefix(ctx context.Context, prefix string) (*models.OrganizationAPIKey, error)
+	// Revoke returns sql.ErrNoRows if the organization has no active key
+	// with this ID.
+	Revoke(ctx context.Context, organizationID, keyID uuid.UUID) error
+	// TouchLastUsed records a use, at most once a minute per key.
+	TouchLastUsed(ctx context.Context, keyID uuid.UUID, at time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+const apiKeyColumns = `id, organization_id, name, prefix, key_hash, scopes, rate_limit_per_minute, created_by, last_used_at, expires_at, revoked_at, created_at`
+
+func scanAPIKey(row rowScanner) (*models.OrganizationAPIKey, error) {
+	k := &models.OrganizationAPIKey{}
+	var scopes string
+	err := row.Scan(
+		&k.ID,
+		&k.OrganizationID,
+		&k.Name,
+		&k.Prefix,
+		&k.KeyHash,
+		&scopes,
+		&k.RateLimitPerMinute,
+		&k.CreatedBy,
+		&k.LastUsedAt,
+		&k.ExpiresAt,
+		&k.RevokedAt,
+		&k.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	k.Scopes = []models.APIKeyScope{}
+	for _, s := range strings.Fields(scopes) {
+		k.Scopes = append(k.Scopes, models.APIKeyScope(s))
+	}
+	return k, nil
+}
+
+func joinScopes(scopes []models.APIKeyScope) string {
+	parts := make([]string, len(scopes))
+	for i, s := range scopes {
+		parts[i] = string(s)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.OrganizationAPIKey) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO organization_api_keys (
+			id, organization_id, name, prefix, key_hash, scopes, rate_limit_per_minute,
+			created_by, expires_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		key.ID,
+		key.OrganizationID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		joinScopes(key.Scopes),
+		key.RateLimitPerMinute,
+		key.CreatedBy,
+		key.ExpiresAt,
+		key.CreatedAt,
+	)
+	return err
+}
+
+func (r *apiKeyRepository) ListByOrganization(ctx context.Context, organizationID uuid.UUID) ([]*models.OrganizationAPIKey, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM organization_api_keys
+		WHERE organization_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*models.OrganizationAPIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (r *apiKeyRepository) CountActive(ctx context.Context, organizationID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM organization_api_keys
+		WHERE organization_id = $1 AND revoked_at IS NULL
+			AND (expires_at IS NULL OR expires_at > NOW())
+	`, organizationID).Scan(&count)
+	return count, err
+}
+
+func (r *apiKeyRepository) GetActiveByPrefix(ctx context.Context, prefix string) (*models.OrganizationAPIKey, error) {
+	return scanAPIKey(r.db.QueryRowContext(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM organization_api_keys
+		WHERE prefix = $1 AND revoked_at IS NULL
+			AND (expires_at IS NULL OR expires_at > NOW())
+	`, prefix))
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, organizationID, keyID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE organization_api_keys SET revoked_at = NOW()
+		WHERE id = $1 AND organization_id = $2 AND revoked_at IS NULL
+	`, keyID, organizationID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, keyID uuid.UUID, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE organization_api_keys SET last_used_at = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2::timestamptz - INTERVAL '1 minute')
+	`, keyID, at)
+	return err
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestCauseScheduledTransitionsAndStaleState(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	ownerID, orgID, domainID, aidTypeID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	for _, s := range []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT INTO users (id, name, email, role) VALUES ($1, 'Seva Trust', 'seva@example.com', 'organization')`, []interface{}{ownerID}},
+		{`INSERT INTO organizations (id, user_id, organization_name) VALUES ($1, $2, 'Seva Trust')`, []interface{}{orgID, ownerID}},
+		{`INSERT INTO cause_domains (id, name) VALUES ($1, 'Water')`, []interface{}{domainID}},
+		{`INSERT INTO cause_aid_types (id, name) VALUES ($1, 'Funds')`, []interface{}{aidTypeID}},
+	} {
+		if _, err := db.ExecContext(ctx, s.query, s.args...); err != nil {
+			t.Fatalf("seed failed: %v\n%s", err, s.query)
+		}
+	}
+
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	causes := []struct {
+		state     models.CauseState
+		goal      float64
+		collected float64
+		deadline  time.Time
+		want      models.CauseState
+	}{
+		{models.CauseStateLive, 1000, 1000, future, models.CauseStateFullyFunded},
+		{models.CauseStateLive, 1000, 400, past, models.CauseStateClosed},
+		{models.CauseStateLive, 1000, 1200, past, models.CauseStateFullyFunded},
+		{models.CauseStateFullyFunded, 2000, 1000, future, models.CauseStateLive},
+		{models.CauseStateFullyFunded, 2000, 1000, past, ""},
+		{models.CauseStateLive, 1000, 400, future, ""},
+		{models.CauseStateDraft, 1000, 0, past, ""},
+	}
+	ids := make([]uuid.UUID, len(causes))
+	for i, c := range causes {
+		ids[i] = uuid.New()
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO causes (id, organization_id, title, domain_id, aid_type_id, goal_amount, collected_amount, deadline, state)
+			VALUES ($1, $2, 'Clean water for Rampur', $3, $4, $5, $6, $7, $8)
+		`, ids[i], orgID, domainID, aidTypeID, c.goal, c.collected, c.deadline, c.state)
+		if err != nil {
+			t.Fatalf("seed cause %d failed: %v", i, err)
+		}
+	}
+
+	repo := NewCauseRepository(db)
+	due, err := repo.GetScheduledTransitions(ctx)
+	if err != nil {
+		t.Fatalf("GetScheduledTransitions() error = %v", err)
+	}
+	got := make(map[uuid.UUID]*models.ScheduledCauseTransition, len(due))
+	for _, d := range due {
+		got[d.CauseID] = d
+	}
+	for i, c := range causes {
+		d := got[ids[i]]
+		switch {
+		case c.want == "" && d != nil:
+			t.Errorf("cause %d scheduled %s -> %s, want nothing", i, d.From, d.To)
+		case c.want != "" && (d == nil || d.From != c.state || d.To != c.want || d.Reason == ""):
+			t.Errorf("cause %d scheduled %+v, want %s -> %s", i, d, c.state, c.want)
+		}
+	}
+
+	// The first transition wins; the second still expects the old state.
+	from := models.CauseStateLive
+	for i, to := range []models.CauseState{models.CauseStateFullyFunded, models.CauseStateClosed} {
+		err := repo.TransitionState(ctx, &models.CauseStateTransition{
+			ID:        uuid.New(),
+			CauseID:   ids[0],
+			FromState: &from,
+			ToState:   to,
+			ActorType: models.CauseStateActorSystem,
+			CreatedAt: time.Now(),
+		})
+		if i == 0 && err != nil {
+			t.Fatalf("TransitionState() error = %v", err)
+		}
+		if i == 1 && !errors.Is(err, ErrCauseStateChanged) {
+			t.Fatalf("stale TransitionState() error = %v, want ErrCauseStateChanged", err)
+		}
+	}
+	history, err := repo.GetStateTransitions(ctx, ids[0])
+	if err != nil {
+		t.Fatalf("GetStateTransitions() error = %v", err)
+	}
+	if len(history) != 1 || history[0].ToState != models.CauseStateFullyFunded {
+		t.Errorf("history = %+v, want only the move to fully_funded", history)
+	}
+}
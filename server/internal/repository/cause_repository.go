@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -12,6 +14,10 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrCauseStateChanged is returned by TransitionState when the cause has
+// left the state the transition starts from.
+var ErrCauseStateChanged = errors.New("cause state changed concurrently, please retry")
+
 type CauseRepository interface {
 	Create(ctx context.Context, cause *models.Cause) error
 	CreateCauseBlood(ctx context.Context, blood *models.CauseBlood) error
@@ -22,10 +28,10 @@ type CauseRepository interface {
 
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Cause, error)
 	GetByOrganizationID(ctx context.Context, id uuid.UUID) ([]*models.Cause, error)
-	GetByDomainID(ctx context.Context, id uuid.UUID) ([]*models.Cause, error)
-	GetByAidTypeID(ctx context.Context, id uuid.UUID) ([]*models.Cause, error)
-	GetAll(ctx context.Context) ([]*models.Cause, error)
-	GetAllPaginated(ctx context.Context, limit, offset int) ([]*models.Cause, int64, error)
+	GetByDomainID(ctx context.Context, id uuid.UUID, page models.CursorParams) (*models.Page[*models.Cause], error)
+	GetByAidTypeID(ctx context.Context, id uuid.UUID, page models.CursorParams) (*models.Page[*models.Cause], error)
+	GetAllPaginated(ctx context.Context, page models.CursorParams) (*models.Page[*models.Cause], error)
+	GetByState(ctx context.Context, state models.CauseState, page models.CursorParams) (*models.Page[*models.Cause], error)
 
 	// GetCauseExecution returns execution window and location for proof validation
 	GetCauseExecution(ctx context.Context, causeID uuid.UUID) (*models.CauseExecution, error)
@@ -50,7 +56,19 @@ type CauseRepository interface {
 	GetProofImageScoreAvg(ctx context.Context, sessionID uuid.UUID) (*float64, error)
 	// }
 
-	// Update(ctx context.Context, cause *models.Cause) error
+	// Update persists the editable fields of a cause and appends revision
+	// to its history in the same transaction.
+	Update(ctx context.Context, cause *models.Cause, revision *models.CauseRevision) error
+	GetRevisionsByCauseID(ctx context.Context, causeID uuid.UUID) ([]*models.CauseRevision, error)
+
+	// Lifecycle state machine
+	TransitionState(ctx context.Context, transition *models.CauseStateTransition) error
+	GetStateTransitions(ctx context.Context, causeID uuid.UUID) ([]*models.CauseStateTransition, error)
+	GetScheduledTransitions(ctx context.Context) ([]*models.ScheduledCauseTransition, error)
+
+	// Pre-publication review
+	CreatePublicationReview(ctx context.Context, review *models.CausePublicationReview) error
+	GetPublicationReviews(ctx context.Context, causeID uuid.UUID) ([]*models.CausePublicationReview, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 
 	GetDomains(ctx context.Context) ([]*models.CauseCategory, error)
@@ -73,12 +91,14 @@ func (c *causeRepository) Create(ctx context.Context, cause *models.Cause) error
 			id, organization_id, title, description, domain_id, aid_type_id,
 			collected_amount, goal_amount, deadline, is_active, cover_image_url, created_at,
 			execution_lat, execution_lng, execution_radius_meters, execution_start_time, execution_end_time, funding_status,
-			beneficiaries_count, execution_location, impact_goal, problem_statement, execution_plan, donor_count, updated_at
+			beneficiaries_count, execution_location, impact_goal, problem_statement, execution_plan, donor_count, updated_at,
+			state, state_changed_at, location_state, location_city
 		) VALUES (
 			$1, $2, $3, $4, $5, $6,
 			$7, $8, $9, $10, $11, $12,
 			$13, $14, $15, $16, $17, $18,
-			$19, $20, $21, $22, $23, $24, $25
+			$19, $20, $21, $22, $23, $24, $25,
+			$26, $27, $28, $29
 		)
 	`
 
@@ -108,6 +128,10 @@ func (c *causeRepository) Create(ctx context.Context, cause *models.Cause) error
 		cause.ExecutionPlan,
 		cause.DonorCount,
 		cause.UpdatedAt,
+		cause.State,
+		cause.StateChangedAt,
+		cause.LocationState,
+		cause.LocationCity,
 	)
 
 	return err
@@ -233,7 +257,7 @@ func GetCauseByColumnID(c *causeRepository, ctx context.Context, id uuid.UUID, c
 			c.id, c.title, c.description, c.collected_amount,
 			c.goal_amount, c.deadline, c.is_active, c.cover_image_url, c.created_at,
 			c.execution_lat, c.execution_lng, c.execution_radius_meters, c.execution_start_time, c.execution_end_time, c.funding_status,
-			c.beneficiaries_count, c.execution_location, c.impact_goal, c.problem_statement, c.execution_plan, c.donor_count, c.updated_at,
+			c.beneficiaries_count, c.execution_location, c.impact_goal, c.problem_statement, c.execution_plan, c.donor_count, c.updated_at, c.state, c.state_changed_at, c.location_state, c.location_city,
 			cd.id, cd.name, cd.description, cd.icon_url,
 			ca.id, ca.name, ca.description, ca.icon_url,
 			o.id, o.organization_name
@@ -273,6 +297,10 @@ func GetCauseByColumnID(c *causeRepository, ctx context.Context, id uuid.UUID, c
 		&cause.ExecutionPlan,
 		&cause.DonorCount,
 		&cause.UpdatedAt,
+		&cause.State,
+		&cause.StateChangedAt,
+		&cause.LocationState,
+		&cause.LocationCity,
 
 		&cause.Domain.ID,
 		&cause.Domain.Name,
@@ -295,16 +323,30 @@ func GetCauseByColumnID(c *causeRepository, ctx context.Context, id uuid.UUID, c
 		return nil, err
 	}
 
+	cause.DeriveFundingStatus()
 	return cause, nil
 }
 
+// publicCauseFilter hides causes that have not been approved yet.
+const publicCauseFilter = `c.state NOT IN ('draft', 'pending_review')`
+
 func GetCausesByColumnID(c *causeRepository, ctx context.Context, id uuid.UUID, column string) ([]*models.Cause, error) {
+	return getCausesWhere(c, ctx, fmt.Sprintf("%s = $1", column), id)
+}
+
+func getCausesWhere(c *causeRepository, ctx context.Context, where string, args ...interface{}) ([]*models.Cause, error) {
+	return queryCauses(c.db, ctx, where, "c.created_at DESC", args...)
+}
+
+// queryCauses loads full causes matching where. orderBy may carry a LIMIT
+// and OFFSET after the sort expression.
+func queryCauses(db *sql.DB, ctx context.Context, where string, orderBy string, args ...interface{}) ([]*models.Cause, error) {
 	query := fmt.Sprintf(`
 		SELECT 
 			c.id, c.title, c.description, c.collected_amount,
 			c.goal_amount, c.deadline, c.is_active, c.cover_image_url, c.created_at,
 			c.execution_lat, c.execution_lng, c.execution_radius_meters, c.execution_start_time, c.execution_end_time, c.funding_status,
-			c.beneficiaries_count, c.execution_location, c.impact_goal, c.problem_statement, c.execution_plan, c.donor_count, c.updated_at,
+			c.beneficiaries_count, c.execution_location, c.impact_goal, c.problem_statement, c.execution_plan, c.donor_count, c.updated_at, c.state, c.state_changed_at, c.location_state, c.location_city,
 			cd.id, cd.name, cd.description, cd.icon_url,
 			ca.id, ca.name, ca.description, ca.icon_url,
 			o.id, o.organization_name
@@ -312,14 +354,16 @@ func GetCausesByColumnID(c *causeRepository, ctx context.Context, id uuid.UUID,
 		LEFT JOIN cause_domains cd on cd.id = c.domain_id
 		LEFT JOIN cause_aid_types ca on ca.id = c.aid_type_id
 		LEFT JOIN organizations o on o.id = c.organization_id
-		WHERE %s = $1
-	`, column)
+		WHERE %s
+		ORDER BY %s
+	`, where, orderBy)
 
-	result, err := c.db.QueryContext(ctx, query, id)
+	result, err := db.QueryContext(ctx, query, args...)
 
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
+	defer result.Close()
 
 	var causesResult []*models.Cause = make([]*models.Cause, 0, 5)
 
@@ -353,6 +397,10 @@ func GetCausesByColumnID(c *causeRepository, ctx context.Context, id uuid.UUID,
 			&cause.ExecutionPlan,
 			&cause.DonorCount,
 			&cause.UpdatedAt,
+			&cause.State,
+			&cause.StateChangedAt,
+			&cause.LocationState,
+			&cause.LocationCity,
 
 			&cause.Domain.ID,
 			&cause.Domain.Name,
@@ -372,6 +420,7 @@ func GetCausesByColumnID(c *causeRepository, ctx context.Context, id uuid.UUID,
 			return nil, err
 		}
 
+		cause.DeriveFundingStatus()
 		causesResult = append(causesResult, cause)
 	}
 
@@ -382,6 +431,26 @@ func (c *causeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Ca
 	return GetCauseByColumnID(c, ctx, id, "id")
 }
 
+func (c *causeRepository) GetByState(ctx context.Context, state models.CauseState, page models.CursorParams) (*models.Page[*models.Cause], error) {
+	return getCausesPage(c, ctx, "c.state = $1", page, state)
+}
+
+// getCausesPage returns one page of causes matching where, newest first.
+func getCausesPage(c *causeRepository, ctx context.Context, where string, page models.CursorParams, args ...interface{}) (*models.Page[*models.Cause], error) {
+	pageWhere, orderBy, pageArgs := keyset(page, "c.created_at", "c.id", true, len(args)+1)
+
+	causes, err := queryCauses(c.db, ctx, where+" AND "+pageWhere, orderBy, append(args, pageArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.Paginate(causes, page, causeKey), nil
+}
+
+func causeKey(cause *models.Cause) (time.Time, uuid.UUID) {
+	return cause.CreatedAt, cause.ID
+}
+
 func (c *causeRepository) GetCauseExecution(ctx context.Context, causeID uuid.UUID) (*models.CauseExecution, error) {
 	query := `
 		SELECT id, execution_lat, execution_lng, execution_radius_meters, execution_start_time, execution_end_time
@@ -412,12 +481,12 @@ func (c *causeRepository) GetByOrganizationID(ctx context.Context, organizationI
 	return GetCausesByColumnID(c, ctx, organizationId, "organization_id")
 }
 
-func (c *causeRepository) GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*models.Cause, error) {
-	return GetCausesByColumnID(c, ctx, domainID, "domain_id")
+func (c *causeRepository) GetByDomainID(ctx context.Context, domainID uuid.UUID, page models.CursorParams) (*models.Page[*models.Cause], error) {
+	return getCausesPage(c, ctx, "c.domain_id = $1 AND "+publicCauseFilter, page, domainID)
 }
 
-func (c *causeRepository) GetByAidTypeID(ctx context.Context, aidTypeId uuid.UUID) ([]*models.Cause, error) {
-	return GetCausesByColumnID(c, ctx, aidTypeId, "aid_type_id")
+func (c *causeRepository) GetByAidTypeID(ctx context.Context, aidTypeId uuid.UUID, page models.CursorParams) (*models.Page[*models.Cause], error) {
+	return getCausesPage(c, ctx, "c.aid_type_id = $1 AND "+publicCauseFilter, page, aidTypeId)
 }
 
 func (c *causeRepository) GetProductsByCauseID(ctx context.Context, causeID uuid.UUID) ([]*models.CauseProduct, error) {
@@ -449,6 +518,7 @@ func (c *causeRepository) GetProductsByCauseID(ctx context.Context, causeID uuid
 		); err != nil {
 			return nil, err
 		}
+		p.SetProgress()
 		products = append(products, p)
 	}
 
@@ -837,98 +907,303 @@ func (c *causeRepository) CreateProduct(ctx context.Context, product *models.Cau
 	return err
 }
 
-func (c *causeRepository) GetAll(ctx context.Context) ([]*models.Cause, error) {
-	query := `
-		SELECT 
-			c.id, c.title, c.description, c.collected_amount,
-			c.goal_amount, c.deadline, c.is_active, c.cover_image_url, c.created_at,
-			c.execution_lat, c.execution_lng, c.execution_radius_meters, c.execution_start_time, c.execution_end_time, c.funding_status,
-			c.beneficiaries_count, c.execution_location, c.impact_goal, c.problem_statement, c.execution_plan, c.donor_count, c.updated_at,
-			cd.id, cd.name, cd.description, cd.icon_url,
-			ca.id, ca.name, ca.description, ca.icon_url,
-			o.id, o.organization_name
-		FROM causes c 
-		LEFT JOIN cause_domains cd on cd.id = c.domain_id
-		LEFT JOIN cause_aid_types ca on ca.id = c.aid_type_id
-		LEFT JOIN organizations o on o.id = c.organization_id
-		WHERE is_active = true
-		ORDER BY random()
-	`
+func (c *causeRepository) Update(ctx context.Context, cause *models.Cause, revision *models.CauseRevision) error {
+	changes, err := json.Marshal(revision.Changes)
+	if err != nil {
+		return err
+	}
 
-	limitQuery := `LIMIT $1`
-	limit := ctx.Value("limit")
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Causes created before revision tracking have no history yet; record
+	// their current state as revision 1 before applying the first edit.
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO cause_revisions (
+			cause_id, revision_number, title, description, goal_amount, deadline,
+			execution_plan, execution_lat, execution_lng, execution_radius_meters, created_at
+		)
+		SELECT
+			c.id, 1, c.title, c.description, c.goal_amount, c.deadline,
+			c.execution_plan, c.execution_lat, c.execution_lng, c.execution_radius_meters, c.created_at
+		FROM causes c
+		WHERE c.id = $1
+		  AND NOT EXISTS (SELECT 1 FROM cause_revisions WHERE cause_id = $1)
+	`, cause.ID)
+	if err != nil {
+		return err
+	}
 
-	var err error
-	var result *sql.Rows
+	// The goal guard is repeated here so a donation landing between the
+	// service check and this update cannot leave goal below collected.
+	result, err := tx.ExecContext(ctx, `
+		UPDATE causes
+		SET title = $2,
+			description = $3,
+			goal_amount = $4,
+			deadline = $5,
+			execution_plan = $6,
+			execution_lat = $7,
+			execution_lng = $8,
+			execution_radius_meters = $9,
+			updated_at = NOW()
+		WHERE id = $1
+		  AND ($4::numeric IS NULL OR $4::numeric >= collected_amount)
+	`,
+		cause.ID,
+		cause.Title,
+		cause.Description,
+		cause.GoalAmount,
+		cause.Deadline,
+		cause.ExecutionPlan,
+		cause.ExecutionLat,
+		cause.ExecutionLng,
+		cause.ExecutionRadiusMeters,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("goal amount cannot be lower than the amount already collected")
+	}
 
-	if limit == nil {
-		result, err = c.db.QueryContext(ctx, query)
-	} else {
-		result, err = c.db.QueryContext(ctx, query+limitQuery, limit)
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO cause_revisions (
+			id, cause_id, revision_number, edited_by, title, description, goal_amount, deadline,
+			execution_plan, execution_lat, execution_lng, execution_radius_meters, changes, reason
+		)
+		SELECT
+			$1, $2, COALESCE(MAX(revision_number), 0) + 1, $3, $4, $5, $6, $7,
+			$8, $9, $10, $11, $12::jsonb, $13
+		FROM cause_revisions
+		WHERE cause_id = $2
+		RETURNING revision_number, created_at
+	`,
+		revision.ID,
+		cause.ID,
+		revision.EditedBy,
+		cause.Title,
+		cause.Description,
+		cause.GoalAmount,
+		cause.Deadline,
+		cause.ExecutionPlan,
+		cause.ExecutionLat,
+		cause.ExecutionLng,
+		cause.ExecutionRadiusMeters,
+		string(changes),
+		revision.Reason,
+	).Scan(&revision.RevisionNumber, &revision.CreatedAt)
+	if err != nil {
+		return err
 	}
 
-	if err != nil && err != sql.ErrNoRows {
+	return tx.Commit()
+}
+
+func (c *causeRepository) GetRevisionsByCauseID(ctx context.Context, causeID uuid.UUID) ([]*models.CauseRevision, error) {
+	query := `
+		SELECT
+			id, cause_id, revision_number, edited_by, title, description, goal_amount, deadline,
+			execution_plan, execution_lat, execution_lng, execution_radius_meters, changes, reason, created_at
+		FROM cause_revisions
+		WHERE cause_id = $1
+		ORDER BY revision_number DESC
+	`
+
+	rows, err := c.db.QueryContext(ctx, query, causeID)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	var causesResult []*models.Cause = make([]*models.Cause, 0, 5)
+	revisions := make([]*models.CauseRevision, 0)
+	for rows.Next() {
+		rev := &models.CauseRevision{}
+		var changes []byte
 
-	for result.Next() {
-		cause := &models.Cause{
-			Domain:       models.CauseCategory{},
-			AidType:      models.CauseCategory{},
-			Organization: models.OrganizationInCause{},
+		if err := rows.Scan(
+			&rev.ID,
+			&rev.CauseID,
+			&rev.RevisionNumber,
+			&rev.EditedBy,
+			&rev.Title,
+			&rev.Description,
+			&rev.GoalAmount,
+			&rev.Deadline,
+			&rev.ExecutionPlan,
+			&rev.ExecutionLat,
+			&rev.ExecutionLng,
+			&rev.ExecutionRadiusMeters,
+			&changes,
+			&rev.Reason,
+			&rev.CreatedAt,
+		); err != nil {
+			return nil, err
 		}
 
-		err = result.Scan(
-			&cause.ID,
-			&cause.Title,
-			&cause.Description,
-			&cause.CollectedAmount,
-			&cause.GoalAmount,
-			&cause.Deadline,
-			&cause.IsActive,
-			&cause.CoverImageURL,
-			&cause.CreatedAt,
-			&cause.ExecutionLat,
-			&cause.ExecutionLng,
-			&cause.ExecutionRadiusMeters,
-			&cause.ExecutionStartTime,
-			&cause.ExecutionEndTime,
-			&cause.FundingStatus,
-			&cause.BeneficiariesCount,
-			&cause.ExecutionLocation,
-			&cause.ImpactGoal,
-			&cause.ProblemStatement,
-			&cause.ExecutionPlan,
-			&cause.DonorCount,
-			&cause.UpdatedAt,
+		rev.Changes = []models.CauseFieldChange{}
+		if len(changes) > 0 {
+			if err := json.Unmarshal(changes, &rev.Changes); err != nil {
+				return nil, err
+			}
+		}
 
-			&cause.Domain.ID,
-			&cause.Domain.Name,
-			&cause.Domain.Description,
-			&cause.Domain.IconURL,
+		revisions = append(revisions, rev)
+	}
 
-			&cause.AidType.ID,
-			&cause.AidType.Name,
-			&cause.AidType.Description,
-			&cause.AidType.IconURL,
+	return revisions, rows.Err()
+}
 
-			&cause.Organization.ID,
-			&cause.Organization.Name,
-		)
+// TransitionState moves a cause to transition.ToState only if it is still in
+// transition.FromState, and records the transition.
+func (c *causeRepository) TransitionState(ctx context.Context, transition *models.CauseStateTransition) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-		if err != nil {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE causes
+		SET state = $3, state_changed_at = $4
+		WHERE id = $1 AND state = $2
+	`, transition.CauseID, transition.FromState, transition.ToState, transition.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrCauseStateChanged
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO cause_state_transitions (id, cause_id, from_state, to_state, actor_type, actor_id, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`,
+		transition.ID,
+		transition.CauseID,
+		transition.FromState,
+		transition.ToState,
+		transition.ActorType,
+		transition.ActorID,
+		transition.Reason,
+		transition.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (c *causeRepository) GetStateTransitions(ctx context.Context, causeID uuid.UUID) ([]*models.CauseStateTransition, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT id, cause_id, from_state, to_state, actor_type, actor_id, reason, created_at
+		FROM cause_state_transitions
+		WHERE cause_id = $1
+		ORDER BY created_at DESC
+	`, causeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := make([]*models.CauseStateTransition, 0)
+	for rows.Next() {
+		t := &models.CauseStateTransition{}
+		if err := rows.Scan(
+			&t.ID,
+			&t.CauseID,
+			&t.FromState,
+			&t.ToState,
+			&t.ActorType,
+			&t.ActorID,
+			&t.Reason,
+			&t.CreatedAt,
+		); err != nil {
 			return nil, err
 		}
+		transitions = append(transitions, t)
+	}
 
-		causesResult = append(causesResult, cause)
+	return transitions, rows.Err()
+}
+
+func (c *causeRepository) CreatePublicationReview(ctx context.Context, review *models.CausePublicationReview) error {
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO cause_publication_reviews (id, cause_id, reviewer_id, decision, comments, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, review.ID, review.CauseID, review.ReviewerID, review.Decision, review.Comments, review.CreatedAt)
+	return err
+}
+
+func (c *causeRepository) GetPublicationReviews(ctx context.Context, causeID uuid.UUID) ([]*models.CausePublicationReview, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT id, cause_id, reviewer_id, decision, comments, created_at
+		FROM cause_publication_reviews
+		WHERE cause_id = $1
+		ORDER BY created_at DESC
+	`, causeID)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return causesResult, nil
+	reviews := make([]*models.CausePublicationReview, 0)
+	for rows.Next() {
+		r := &models.CausePublicationReview{}
+		if err := rows.Scan(&r.ID, &r.CauseID, &r.ReviewerID, &r.Decision, &r.Comments, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, r)
+	}
+
+	return reviews, rows.Err()
 }
 
-// func (r *causeRepository) Update(ctx context.Context, cause *models.Cause) error { }
+// GetScheduledTransitions finds causes whose funding or deadline means the
+// lifecycle job should move them: live causes that reached their goal or
+// passed their deadline, and fully funded causes whose goal was raised.
+func (c *causeRepository) GetScheduledTransitions(ctx context.Context) ([]*models.ScheduledCauseTransition, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT id, state,
+			CASE
+				WHEN state = 'live' AND goal_amount > 0 AND collected_amount >= goal_amount THEN 'fully_funded'
+				WHEN state = 'live' THEN 'closed'
+				ELSE 'live'
+			END
+		FROM causes
+		WHERE (state = 'live' AND goal_amount > 0 AND collected_amount >= goal_amount)
+		   OR (state = 'live' AND deadline IS NOT NULL AND deadline < NOW())
+		   OR (state = 'fully_funded' AND goal_amount > collected_amount
+		       AND (deadline IS NULL OR deadline >= NOW()))
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	due := make([]*models.ScheduledCauseTransition, 0)
+	for rows.Next() {
+		t := &models.ScheduledCauseTransition{}
+		if err := rows.Scan(&t.CauseID, &t.From, &t.To); err != nil {
+			return nil, err
+		}
+		switch t.To {
+		case models.CauseStateFullyFunded:
+			t.Reason = "goal amount reached"
+		case models.CauseStateClosed:
+			t.Reason = "deadline passed before goal was reached"
+		case models.CauseStateLive:
+			t.Reason = "goal raised above collected amount"
+		}
+		due = append(due, t)
+	}
+
+	return due, rows.Err()
+}
 
 func (c *causeRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `UPDATE causes SET is_active = false WHERE id = $1`
@@ -1054,87 +1329,6 @@ func (c *causeRepository) GetAidTypeByID(ctx context.Context, id uuid.UUID) (*mo
 }
 
 // GetAllPaginated returns causes with pagination support
-func (c *causeRepository) GetAllPaginated(ctx context.Context, limit, offset int) ([]*models.Cause, int64, error) {
-	// Count total causes
-	var total int64
-	countQuery := `SELECT COUNT(*) FROM causes WHERE is_active = true`
-	if err := c.db.QueryRowContext(ctx, countQuery).Scan(&total); err != nil {
-		return nil, 0, err
-	}
-
-	query := `
-SELECT 
-c.id, c.title, c.description, c.collected_amount,
-c.goal_amount, c.deadline, c.is_active, c.cover_image_url, c.created_at,
-c.execution_lat, c.execution_lng, c.execution_radius_meters, c.execution_start_time, c.execution_end_time, c.funding_status,
-c.beneficiaries_count, c.execution_location, c.impact_goal, c.problem_statement, c.execution_plan, c.donor_count, c.updated_at,
-cd.id, cd.name, cd.description, cd.icon_url,
-ca.id, ca.name, ca.description, ca.icon_url,
-o.id, o.organization_name
-FROM causes c 
-LEFT JOIN cause_domains cd on cd.id = c.domain_id
-LEFT JOIN cause_aid_types ca on ca.id = c.aid_type_id
-LEFT JOIN organizations o on o.id = c.organization_id
-WHERE is_active = true
-ORDER BY c.created_at DESC
-LIMIT $1 OFFSET $2
-`
-
-	result, err := c.db.QueryContext(ctx, query, limit, offset)
-	if err != nil && err != sql.ErrNoRows {
-		return nil, 0, err
-	}
-	defer result.Close()
-
-	var causesResult []*models.Cause = make([]*models.Cause, 0, limit)
-
-	for result.Next() {
-		cause := &models.Cause{
-			Domain:       models.CauseCategory{},
-			AidType:      models.CauseCategory{},
-			Organization: models.OrganizationInCause{},
-		}
-
-		err = result.Scan(
-			&cause.ID,
-			&cause.Title,
-			&cause.Description,
-			&cause.CollectedAmount,
-			&cause.GoalAmount,
-			&cause.Deadline,
-			&cause.IsActive,
-			&cause.CoverImageURL,
-			&cause.CreatedAt,
-			&cause.ExecutionLat,
-			&cause.ExecutionLng,
-			&cause.ExecutionRadiusMeters,
-			&cause.ExecutionStartTime,
-			&cause.ExecutionEndTime,
-			&cause.FundingStatus,
-			&cause.BeneficiariesCount,
-			&cause.ExecutionLocation,
-			&cause.ImpactGoal,
-			&cause.ProblemStatement,
-			&cause.ExecutionPlan,
-			&cause.DonorCount,
-			&cause.UpdatedAt,
-			&cause.Domain.ID,
-			&cause.Domain.Name,
-			&cause.Domain.Description,
-			&cause.Domain.IconURL,
-			&cause.AidType.ID,
-			&cause.AidType.Name,
-			&cause.AidType.Description,
-			&cause.AidType.IconURL,
-			&cause.Organization.ID,
-			&cause.Organization.Name,
-		)
-		if err != nil {
-			return nil, 0, err
-		}
-
-		causesResult = append(causesResult, cause)
-	}
-
-	return causesResult, total, nil
+func (c *causeRepository) GetAllPaginated(ctx context.Context, page models.CursorParams) (*models.Page[*models.Cause], error) {
+	return getCausesPage(c, ctx, "c.is_active = true AND "+publicCauseFilter, page)
 }
@@ -3,18 +3,34 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"server/internal/models"
 
 	"github.com/google/uuid"
 )
 
+var ErrReviewNotFound = errors.New("review not found")
+
 type CauseReviewRepository interface {
 	UserCanReviewCause(ctx context.Context, causeID uuid.UUID, userID uuid.UUID) (bool, error)
-	CreateReview(ctx context.Context, causeID uuid.UUID, userID uuid.UUID, reviewText string) (*models.CauseReviewResponse, error)
-	GetReviewsByCauseID(ctx context.Context, causeID uuid.UUID) (*models.CauseReviewsResponse, error)
+	CreateReview(ctx context.Context, causeID uuid.UUID, userID uuid.UUID, reviewText string, rating *int) (*models.CauseReviewResponse, error)
+	GetReviewByID(ctx context.Context, reviewID uuid.UUID) (*models.CauseReviewResponse, error)
+	GetReviewOrganizationID(ctx context.Context, reviewID uuid.UUID) (uuid.UUID, error)
+	UpdateReview(ctx context.Context, reviewID uuid.UUID, userID uuid.UUID, reviewText string, rating *int) (*models.CauseReviewResponse, error)
+	DeleteReview(ctx context.Context, reviewID uuid.UUID, userID uuid.UUID) error
+	GetReviewsByCauseID(ctx context.Context, causeID uuid.UUID, page models.CursorParams) (*models.CauseReviewsResponse, error)
 	GetReviewCountByCauseID(ctx context.Context, causeID uuid.UUID) (int, error)
+
+	ReportReview(ctx context.Context, reviewID uuid.UUID, reporterID uuid.UUID, reason string, details *string) error
+	GetReportedReviews(ctx context.Context, hidden bool, page models.CursorParams) (*models.Page[*models.ReportedCauseReview], error)
+	SetReviewHidden(ctx context.Context, reviewID uuid.UUID, adminID uuid.UUID, hidden bool, reason *string) error
+
+	UpsertReviewReply(ctx context.Context, reviewID uuid.UUID, organizationID uuid.UUID, responderID uuid.UUID, responseText string) (*models.CauseReviewReply, error)
+	DeleteReviewReply(ctx context.Context, reviewID uuid.UUID, organizationID uuid.UUID) error
 }
 
 type causeReviewRepository struct {
@@ -25,6 +41,79 @@ func NewCauseReviewRepository(db *sql.DB) CauseReviewRepository {
 	return &causeReviewRepository{db: db}
 }
 
+const causeReviewSelect = `
+	SELECT
+		cr.id,
+		cr.cause_id,
+		cr.user_id,
+		cr.review_text,
+		cr.rating,
+		cr.is_hidden,
+		cr.created_at,
+		cr.updated_at,
+		u.name,
+		rr.id,
+		rr.organization_id,
+		rr.response_text,
+		rr.created_at,
+		rr.updated_at
+	FROM cause_reviews cr
+	JOIN users u ON u.id = cr.user_id
+	LEFT JOIN cause_review_responses rr ON rr.review_id = cr.id
+`
+
+func scanCauseReview(scanner interface{ Scan(dest ...any) error }) (*models.CauseReviewResponse, error) {
+	rv := &models.CauseReviewResponse{}
+
+	var (
+		rating        sql.NullInt64
+		updatedAt     sql.NullTime
+		replyID       uuid.NullUUID
+		replyOrgID    uuid.NullUUID
+		replyText     sql.NullString
+		replyCreated  sql.NullTime
+		replyModified sql.NullTime
+	)
+
+	if err := scanner.Scan(
+		&rv.ID,
+		&rv.CauseID,
+		&rv.UserID,
+		&rv.ReviewText,
+		&rating,
+		&rv.IsHidden,
+		&rv.CreatedAt,
+		&updatedAt,
+		&rv.UserName,
+		&replyID,
+		&replyOrgID,
+		&replyText,
+		&replyCreated,
+		&replyModified,
+	); err != nil {
+		return nil, err
+	}
+
+	if rating.Valid {
+		v := int(rating.Int64)
+		rv.Rating = &v
+	}
+	if updatedAt.Valid {
+		rv.UpdatedAt = &updatedAt.Time
+	}
+	if replyID.Valid {
+		rv.Reply = &models.CauseReviewReply{
+			ID:             replyID.UUID,
+			OrganizationID: replyOrgID.UUID,
+			ResponseText:   replyText.String,
+			CreatedAt:      replyCreated.Time,
+			UpdatedAt:      replyModified.Time,
+		}
+	}
+
+	return rv, nil
+}
+
 func (r *causeReviewRepository) UserCanReviewCause(
 	ctx context.Context,
 	causeID uuid.UUID,
@@ -37,15 +126,15 @@ func (r *causeReviewRepository) UserCanReviewCause(
 		ctx,
 		`
 		SELECT EXISTS (
-			SELECT 1 
+			SELECT 1
 			FROM donations d
-			WHERE d.cause_id = $1 
+			WHERE d.cause_id = $1
 			  AND d.user_id = $2
 		)
 		AND NOT EXISTS (
-			SELECT 1 
+			SELECT 1
 			FROM cause_reviews cr
-			WHERE cr.cause_id = $1 
+			WHERE cr.cause_id = $1
 			  AND cr.user_id = $2
 		)
 		`,
@@ -60,67 +149,114 @@ func (r *causeReviewRepository) UserCanReviewCause(
 	return canReview, nil
 }
 
-func (r *causeReviewRepository) CreateReview(ctx context.Context, causeID uuid.UUID, userID uuid.UUID, reviewText string) (*models.CauseReviewResponse, error) {
-	var res models.CauseReviewResponse
+func (r *causeReviewRepository) CreateReview(ctx context.Context, causeID uuid.UUID, userID uuid.UUID, reviewText string, rating *int) (*models.CauseReviewResponse, error) {
+	var reviewID uuid.UUID
 	err := r.db.QueryRowContext(
 		ctx,
-		`INSERT INTO cause_reviews (cause_id, user_id, review_text)
-		 VALUES ($1, $2, $3)
+		`INSERT INTO cause_reviews (cause_id, user_id, review_text, rating)
+		 VALUES ($1, $2, $3, $4)
 		 ON CONFLICT (cause_id, user_id)
-		 DO UPDATE SET review_text = EXCLUDED.review_text
-		 RETURNING id, cause_id, user_id, review_text, created_at`,
+		 DO UPDATE SET review_text = EXCLUDED.review_text, rating = EXCLUDED.rating, updated_at = NOW()
+		 RETURNING id`,
 		causeID,
 		userID,
 		reviewText,
-	).Scan(
-		&res.ID,
-		&res.CauseID,
-		&res.UserID,
-		&res.ReviewText,
-		&res.CreatedAt,
-	)
+		rating,
+	).Scan(&reviewID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Attach user name for UI.
-	err = r.db.QueryRowContext(
+	return r.GetReviewByID(ctx, reviewID)
+}
+
+func (r *causeReviewRepository) GetReviewByID(ctx context.Context, reviewID uuid.UUID) (*models.CauseReviewResponse, error) {
+	review, err := scanCauseReview(r.db.QueryRowContext(ctx, causeReviewSelect+` WHERE cr.id = $1`, reviewID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+	return review, nil
+}
+
+func (r *causeReviewRepository) GetReviewOrganizationID(ctx context.Context, reviewID uuid.UUID) (uuid.UUID, error) {
+	var organizationID uuid.UUID
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT c.organization_id
+		 FROM cause_reviews cr
+		 JOIN causes c ON c.id = cr.cause_id
+		 WHERE cr.id = $1`,
+		reviewID,
+	).Scan(&organizationID)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, ErrReviewNotFound
+	}
+	return organizationID, err
+}
+
+func (r *causeReviewRepository) UpdateReview(ctx context.Context, reviewID uuid.UUID, userID uuid.UUID, reviewText string, rating *int) (*models.CauseReviewResponse, error) {
+	result, err := r.db.ExecContext(
 		ctx,
-		"SELECT name FROM users WHERE id = $1",
+		`UPDATE cause_reviews
+		 SET review_text = $3, rating = $4, updated_at = NOW()
+		 WHERE id = $1 AND user_id = $2`,
+		reviewID,
 		userID,
-	).Scan(&res.UserName)
+		reviewText,
+		rating,
+	)
 	if err != nil {
 		return nil, err
 	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, ErrReviewNotFound
+	}
 
-	return &res, nil
+	return r.GetReviewByID(ctx, reviewID)
 }
 
-func (r *causeReviewRepository) GetReviewsByCauseID(ctx context.Context, causeID uuid.UUID) (*models.CauseReviewsResponse, error) {
-	var count int
+func (r *causeReviewRepository) DeleteReview(ctx context.Context, reviewID uuid.UUID, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(
+		ctx,
+		"DELETE FROM cause_reviews WHERE id = $1 AND user_id = $2",
+		reviewID,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrReviewNotFound
+	}
+	return nil
+}
+
+// GetReviewsByCauseID returns the public review list. Hidden reviews are
+// left out of both the list and the count.
+func (r *causeReviewRepository) GetReviewsByCauseID(ctx context.Context, causeID uuid.UUID, page models.CursorParams) (*models.CauseReviewsResponse, error) {
+	var (
+		count     int
+		avgRating sql.NullFloat64
+	)
 	err := r.db.QueryRowContext(
 		ctx,
-		"SELECT COUNT(*) FROM cause_reviews WHERE cause_id = $1",
+		"SELECT COUNT(*), AVG(rating)::float8 FROM cause_reviews WHERE cause_id = $1 AND is_hidden = false",
 		causeID,
-	).Scan(&count)
+	).Scan(&count, &avgRating)
 	if err != nil {
 		return nil, err
 	}
 
+	pageWhere, orderBy, pageArgs := keyset(page, "cr.created_at", "cr.id", true, 2)
 	rows, err := r.db.QueryContext(
 		ctx,
-		`SELECT
-			cr.id,
-			cr.cause_id,
-			cr.user_id,
-			cr.review_text,
-			cr.created_at,
-			u.name
-		FROM cause_reviews cr
-		JOIN users u ON u.id = cr.user_id
-		WHERE cr.cause_id = $1
-		ORDER BY cr.created_at DESC`,
-		causeID,
+		causeReviewSelect+`
+		WHERE cr.cause_id = $1 AND cr.is_hidden = false AND `+pageWhere+`
+		ORDER BY `+orderBy,
+		append([]interface{}{causeID}, pageArgs...)...,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -132,15 +268,8 @@ func (r *causeReviewRepository) GetReviewsByCauseID(ctx context.Context, causeID
 
 	reviews := make([]*models.CauseReviewResponse, 0, 5)
 	for rows.Next() {
-		rv := &models.CauseReviewResponse{}
-		if err := rows.Scan(
-			&rv.ID,
-			&rv.CauseID,
-			&rv.UserID,
-			&rv.ReviewText,
-			&rv.CreatedAt,
-			&rv.UserName,
-		); err != nil {
+		rv, err := scanCauseReview(rows)
+		if err != nil {
 			return nil, err
 		}
 		reviews = append(reviews, rv)
@@ -150,17 +279,28 @@ func (r *causeReviewRepository) GetReviewsByCauseID(ctx context.Context, causeID
 		return nil, fmt.Errorf("failed to iterate reviews: %w", rows.Err())
 	}
 
-	return &models.CauseReviewsResponse{
-		Count:   count,
-		Reviews: reviews,
-	}, nil
+	reviewPage := models.Paginate(reviews, page, func(rv *models.CauseReviewResponse) (time.Time, uuid.UUID) {
+		return rv.CreatedAt, rv.ID
+	})
+
+	res := &models.CauseReviewsResponse{
+		Count:      count,
+		Reviews:    reviewPage.Items,
+		NextCursor: reviewPage.NextCursor,
+		PrevCursor: reviewPage.PrevCursor,
+	}
+	if avgRating.Valid {
+		res.AverageRating = &avgRating.Float64
+	}
+
+	return res, nil
 }
 
 func (r *causeReviewRepository) GetReviewCountByCauseID(ctx context.Context, causeID uuid.UUID) (int, error) {
 	var count int
 	err := r.db.QueryRowContext(
 		ctx,
-		"SELECT COUNT(*) FROM cause_reviews WHERE cause_id = $1",
+		"SELECT COUNT(*) FROM cause_reviews WHERE cause_id = $1 AND is_hidden = false",
 		causeID,
 	).Scan(&count)
 	if err != nil {
@@ -168,3 +308,247 @@ func (r *causeReviewRepository) GetReviewCountByCauseID(ctx context.Context, cau
 	}
 	return count, nil
 }
+
+func (r *causeReviewRepository) ReportReview(ctx context.Context, reviewID uuid.UUID, reporterID uuid.UUID, reason string, details *string) error {
+	result, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO cause_review_reports (review_id, reporter_id, reason, details)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (review_id, reporter_id) DO NOTHING`,
+		reviewID,
+		reporterID,
+		reason,
+		details,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("you have already reported this review")
+	}
+	return nil
+}
+
+// GetReportedReviews backs the admin moderation queue. With hidden=false it
+// returns visible reviews that have open reports, most recently reported
+// first; with hidden=true it returns hidden reviews, most recently hidden
+// first. The sort key must stay in step with ReportedCauseReview.QueueTime.
+func (r *causeReviewRepository) GetReportedReviews(ctx context.Context, hidden bool, page models.CursorParams) (*models.Page[*models.ReportedCauseReview], error) {
+	pageWhere, orderBy, pageArgs := keyset(page, "q.queue_at", "q.review_id", true, 2)
+
+	query := `
+		SELECT
+			q.review_id, q.cause_id, q.user_id, q.review_text, q.rating, q.is_hidden,
+			q.review_created_at, q.review_updated_at, q.user_name,
+			q.reply_id, q.reply_organization_id, q.reply_text, q.reply_created_at, q.reply_updated_at,
+			q.hidden_reason, q.hidden_at, q.open_reports, q.reasons, q.last_reported_at
+		FROM (
+			SELECT
+				cr.id AS review_id,
+				cr.cause_id,
+				cr.user_id,
+				cr.review_text,
+				cr.rating,
+				cr.is_hidden,
+				cr.created_at AS review_created_at,
+				cr.updated_at AS review_updated_at,
+				u.name AS user_name,
+				rr.id AS reply_id,
+				rr.organization_id AS reply_organization_id,
+				rr.response_text AS reply_text,
+				rr.created_at AS reply_created_at,
+				rr.updated_at AS reply_updated_at,
+				cr.hidden_reason,
+				cr.hidden_at,
+				COUNT(rep.id) FILTER (WHERE rep.resolved_at IS NULL) AS open_reports,
+				COALESCE(string_agg(DISTINCT rep.reason, ','), '') AS reasons,
+				MAX(rep.created_at) AS last_reported_at,
+				CASE
+					WHEN $1 THEN COALESCE(cr.hidden_at, cr.created_at)
+					ELSE COALESCE(MAX(rep.created_at), cr.created_at)
+				END AS queue_at
+			FROM cause_reviews cr
+			JOIN users u ON u.id = cr.user_id
+			LEFT JOIN cause_review_responses rr ON rr.review_id = cr.id
+			LEFT JOIN cause_review_reports rep ON rep.review_id = cr.id
+			WHERE cr.is_hidden = $1
+			GROUP BY cr.id, u.name, rr.id
+			HAVING $1 OR COUNT(rep.id) FILTER (WHERE rep.resolved_at IS NULL) > 0
+		) q
+		WHERE ` + pageWhere + `
+		ORDER BY ` + orderBy
+
+	rows, err := r.db.QueryContext(ctx, query, append([]interface{}{hidden}, pageArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	queue := make([]*models.ReportedCauseReview, 0)
+	for rows.Next() {
+		item := &models.ReportedCauseReview{Review: &models.CauseReviewResponse{}}
+
+		var (
+			rating         sql.NullInt64
+			updatedAt      sql.NullTime
+			replyID        uuid.NullUUID
+			replyOrgID     uuid.NullUUID
+			replyText      sql.NullString
+			replyCreated   sql.NullTime
+			replyModified  sql.NullTime
+			hiddenReason   sql.NullString
+			hiddenAt       sql.NullTime
+			reasons        string
+			lastReportedAt sql.NullTime
+		)
+
+		if err := rows.Scan(
+			&item.Review.ID,
+			&item.Review.CauseID,
+			&item.Review.UserID,
+			&item.Review.ReviewText,
+			&rating,
+			&item.Review.IsHidden,
+			&item.Review.CreatedAt,
+			&updatedAt,
+			&item.Review.UserName,
+			&replyID,
+			&replyOrgID,
+			&replyText,
+			&replyCreated,
+			&replyModified,
+			&hiddenReason,
+			&hiddenAt,
+			&item.OpenReports,
+			&reasons,
+			&lastReportedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if rating.Valid {
+			v := int(rating.Int64)
+			item.Review.Rating = &v
+		}
+		if updatedAt.Valid {
+			item.Review.UpdatedAt = &updatedAt.Time
+		}
+		if replyID.Valid {
+			item.Review.Reply = &models.CauseReviewReply{
+				ID:             replyID.UUID,
+				OrganizationID: replyOrgID.UUID,
+				ResponseText:   replyText.String,
+				CreatedAt:      replyCreated.Time,
+				UpdatedAt:      replyModified.Time,
+			}
+		}
+		if hiddenReason.Valid {
+			item.HiddenReason = &hiddenReason.String
+		}
+		if hiddenAt.Valid {
+			item.HiddenAt = &hiddenAt.Time
+		}
+		if lastReportedAt.Valid {
+			item.LastReportedAt = &lastReportedAt.Time
+		}
+		item.Reasons = []string{}
+		if reasons != "" {
+			item.Reasons = strings.Split(reasons, ",")
+		}
+
+		queue = append(queue, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.Paginate(queue, page, func(item *models.ReportedCauseReview) (time.Time, uuid.UUID) {
+		return item.QueueTime(), item.Review.ID
+	}), nil
+}
+
+// SetReviewHidden hides or restores a review. Either decision closes any
+// open reports against it.
+func (r *causeReviewRepository) SetReviewHidden(ctx context.Context, reviewID uuid.UUID, adminID uuid.UUID, hidden bool, reason *string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE cause_reviews
+		 SET is_hidden = $2,
+		     hidden_reason = CASE WHEN $2 THEN $4 ELSE NULL END,
+		     hidden_by = CASE WHEN $2 THEN $3::uuid ELSE NULL END,
+		     hidden_at = CASE WHEN $2 THEN NOW() ELSE NULL END
+		 WHERE id = $1`,
+		reviewID,
+		hidden,
+		adminID,
+		reason,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrReviewNotFound
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE cause_review_reports
+		 SET resolved_at = NOW(), resolved_by = $2
+		 WHERE review_id = $1 AND resolved_at IS NULL`,
+		reviewID,
+		adminID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *causeReviewRepository) UpsertReviewReply(ctx context.Context, reviewID uuid.UUID, organizationID uuid.UUID, responderID uuid.UUID, responseText string) (*models.CauseReviewReply, error) {
+	reply := &models.CauseReviewReply{}
+	err := r.db.QueryRowContext(
+		ctx,
+		`INSERT INTO cause_review_responses (review_id, organization_id, responder_id, response_text)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (review_id)
+		 DO UPDATE SET response_text = EXCLUDED.response_text, responder_id = EXCLUDED.responder_id, updated_at = NOW()
+		 RETURNING id, organization_id, response_text, created_at, updated_at`,
+		reviewID,
+		organizationID,
+		responderID,
+		responseText,
+	).Scan(
+		&reply.ID,
+		&reply.OrganizationID,
+		&reply.ResponseText,
+		&reply.CreatedAt,
+		&reply.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (r *causeReviewRepository) DeleteReviewReply(ctx context.Context, reviewID uuid.UUID, organizationID uuid.UUID) error {
+	result, err := r.db.ExecContext(
+		ctx,
+		"DELETE FROM cause_review_responses WHERE review_id = $1 AND organization_id = $2",
+		reviewID,
+		organizationID,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("response not found")
+	}
+	return nil
+}
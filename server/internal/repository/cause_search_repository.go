@@ -0,0 +1,427 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// fundingStatusExpr mirrors models.CauseState.FundingStatus so the funding
+// status facet agrees with what the API reports for each cause.
+const fundingStatusExpr = `CASE
+	WHEN c.state::text = 'live' THEN
+		CASE WHEN c.collected_amount <= 0 THEN 'Not Started' ELSE 'Active' END
+	WHEN c.state::text = 'fully_funded' THEN 'Fully Funded'
+	WHEN c.state::text IN ('executing', 'completed') THEN
+		CASE WHEN c.goal_amount > 0 AND c.collected_amount >= c.goal_amount THEN 'Fully Funded' ELSE 'Closed' END
+	WHEN c.state::text IN ('closed', 'suspended') THEN 'Closed'
+	ELSE 'Not Started'
+END`
+
+const trustBandExpr = `COALESCE((
+	SELECT CASE
+		WHEN ts.overall_score >= 75 THEN 'high'
+		WHEN ts.overall_score >= 50 THEN 'medium'
+		ELSE 'low'
+	END
+	FROM ngo_trust_scores ts
+	WHERE ts.organization_id = c.organization_id
+), 'unrated')`
+
+// Cities can be numerous; only the most common are returned as facets.
+const maxCityFacets = 50
+
+type CauseSearchRepository interface {
+	Search(ctx context.Context, filter *models.CauseSearchFilter) (*models.CauseSearchResult, error)
+
+	// GetNearby returns public causes within the query radius, closest first.
+	GetNearby(ctx context.Context, query *models.NearbyCausesQuery) (*models.Page[*models.NearbyCause], error)
+	// GetInBoundingBox returns up to box.Limit+1 map pins so callers can tell
+	// whether the result was truncated.
+	GetInBoundingBox(ctx context.Context, box *models.CauseBoundingBox) ([]*models.CauseMapPin, error)
+}
+
+type causeSearchRepository struct {
+	db *sql.DB
+}
+
+func NewCauseSearchRepository(db *sql.DB) CauseSearchRepository {
+	return &causeSearchRepository{db: db}
+}
+
+// searchWhere collects WHERE clauses and their positional arguments.
+type searchWhere struct {
+	clauses []string
+	args    []interface{}
+}
+
+func (w *searchWhere) arg(v interface{}) string {
+	w.args = append(w.args, v)
+	return fmt.Sprintf("$%d", len(w.args))
+}
+
+func (w *searchWhere) in(expr string, values []string) {
+	placeholders := make([]string, len(values))
+	for i, v := range values {
+		placeholders[i] = w.arg(v)
+	}
+	w.clauses = append(w.clauses, fmt.Sprintf("%s IN (%s)", expr, strings.Join(placeholders, ", ")))
+}
+
+func (w *searchWhere) String() string {
+	return strings.Join(w.clauses, " AND ")
+}
+
+// buildWhere applies every filter except the facet named by skip, which is
+// how facet counts stay useful for multi-select.
+func buildWhere(f *models.CauseSearchFilter, skip string) *searchWhere {
+	w := &searchWhere{clauses: []string{"c.is_active = true", publicCauseFilter}}
+
+	if f.Query != "" {
+		q := w.arg(f.Query)
+		w.clauses = append(w.clauses, fmt.Sprintf(
+			"(c.search_vector @@ websearch_to_tsquery('english', %[1]s) OR to_tsvector('english', o.organization_name) @@ websearch_to_tsquery('english', %[1]s))", q))
+	}
+
+	if len(f.DomainIDs) > 0 && skip != "domain" {
+		ids := make([]string, len(f.DomainIDs))
+		for i, id := range f.DomainIDs {
+			ids[i] = id.String()
+		}
+		w.in("c.domain_id", ids)
+	}
+	if len(f.AidTypeIDs) > 0 && skip != "aid_type" {
+		ids := make([]string, len(f.AidTypeIDs))
+		for i, id := range f.AidTypeIDs {
+			ids[i] = id.String()
+		}
+		w.in("c.aid_type_id", ids)
+	}
+	if len(f.FundingStatuses) > 0 && skip != "funding_status" {
+		w.in(fundingStatusExpr, f.FundingStatuses)
+	}
+	if len(f.Regions) > 0 && skip != "region" {
+		w.in("lower(c.location_state)", lowerAll(f.Regions))
+	}
+	if len(f.Cities) > 0 && skip != "city" {
+		w.in("lower(c.location_city)", lowerAll(f.Cities))
+	}
+	if len(f.TrustBands) > 0 && skip != "trust_band" {
+		bands := make([]string, len(f.TrustBands))
+		for i, b := range f.TrustBands {
+			bands[i] = string(b)
+		}
+		w.in(trustBandExpr, bands)
+	}
+
+	if f.DeadlineFrom != nil {
+		w.clauses = append(w.clauses, "c.deadline >= "+w.arg(*f.DeadlineFrom))
+	}
+	if f.DeadlineTo != nil {
+		w.clauses = append(w.clauses, "c.deadline <= "+w.arg(*f.DeadlineTo))
+	}
+
+	return w
+}
+
+func lowerAll(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strings.ToLower(v)
+	}
+	return out
+}
+
+// deadlineNever sorts causes with no upcoming deadline after every real one
+// when searching by ending_soon. It is far enough out to beat any deadline
+// but still encodes in a JSON cursor, unlike infinity.
+const deadlineNever = 1e12
+
+// searchSortKey returns the float8 expression results are ordered by, ties
+// broken by id, and whether larger values come first.
+func searchSortKey(f *models.CauseSearchFilter, w *searchWhere) (string, bool) {
+	switch f.Sort {
+	case models.CauseSearchSortEndingSoon:
+		// Upcoming deadlines first; causes without one or already past go last.
+		return fmt.Sprintf(`(CASE WHEN c.deadline IS NULL OR c.deadline < NOW() THEN %g
+			ELSE extract(epoch FROM c.deadline) END)::float8`, float64(deadlineNever)), false
+	case models.CauseSearchSortMostFunded:
+		return "c.collected_amount::float8", true
+	case models.CauseSearchSortClosestToGoal:
+		// Causes without a goal, or past it, go last.
+		return `COALESCE(CASE WHEN c.goal_amount > 0 AND c.collected_amount < c.goal_amount
+			THEN c.collected_amount::float8 / c.goal_amount::float8 END, -1)`, true
+	case models.CauseSearchSortRelevance:
+		if f.Query != "" {
+			q := w.arg(f.Query)
+			return fmt.Sprintf(`(ts_rank(c.search_vector, websearch_to_tsquery('english', %[1]s)) +
+				ts_rank(setweight(to_tsvector('english', coalesce(o.organization_name, '')), 'B'), websearch_to_tsquery('english', %[1]s)))::float8`, q), true
+		}
+	}
+	return "extract(epoch FROM c.created_at)::float8", true
+}
+
+// Search returns one page of matches. Every sort is a keyset over a numeric
+// key, so pages stay stable while causes are added.
+func (r *causeSearchRepository) Search(ctx context.Context, f *models.CauseSearchFilter) (*models.CauseSearchResult, error) {
+	result := &models.CauseSearchResult{}
+
+	w := buildWhere(f, "")
+	countQuery := `
+		SELECT COUNT(*)
+		FROM causes c
+		LEFT JOIN organizations o on o.id = c.organization_id
+		WHERE ` + w.String()
+	if err := r.db.QueryRowContext(ctx, countQuery, w.args...).Scan(&result.Total); err != nil {
+		return nil, err
+	}
+
+	keyExpr, desc := searchSortKey(f, w)
+	pageWhere, orderBy, pageArgs, err := valueKeyset(f.Page, keyExpr, "c.id", desc, len(w.args)+1)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT c.id, %s
+		FROM causes c
+		LEFT JOIN organizations o on o.id = c.organization_id
+		WHERE %s AND %s
+		ORDER BY %s
+	`, keyExpr, w.String(), pageWhere, orderBy), append(w.args, pageArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	keys := make(map[uuid.UUID]float64)
+	for rows.Next() {
+		var id uuid.UUID
+		var key float64
+		if err := rows.Scan(&id, &key); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+		keys[id] = key
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	causes, err := r.causesInOrder(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	result.Causes = models.PaginateByValue(causes, f.Page, func(cause *models.Cause) (float64, uuid.UUID) {
+		return keys[cause.ID], cause.ID
+	})
+
+	facets := []struct {
+		name   string
+		value  string
+		label  string
+		extra  string
+		target *[]models.FacetCount
+	}{
+		{"domain", "cd.id::text", "cd.name", "", &result.Facets.Domains},
+		{"aid_type", "ca.id::text", "ca.name", "", &result.Facets.AidTypes},
+		{"funding_status", fundingStatusExpr, fundingStatusExpr, "", &result.Facets.FundingStatuses},
+		{"region", "lower(c.location_state)", "c.location_state", "c.location_state IS NOT NULL", &result.Facets.Regions},
+		{"city", "lower(c.location_city)", "c.location_city", "c.location_city IS NOT NULL", &result.Facets.Cities},
+		{"trust_band", trustBandExpr, trustBandExpr, "", &result.Facets.TrustBands},
+	}
+
+	for _, facet := range facets {
+		counts, err := r.facetCounts(ctx, f, facet.name, facet.value, facet.label, facet.extra)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s facet: %w", facet.name, err)
+		}
+		*facet.target = counts
+	}
+
+	return result, nil
+}
+
+func (r *causeSearchRepository) facetCounts(ctx context.Context, f *models.CauseSearchFilter, name, valueExpr, labelExpr, extra string) ([]models.FacetCount, error) {
+	w := buildWhere(f, name)
+	if extra != "" {
+		w.clauses = append(w.clauses, extra)
+	}
+
+	limit := ""
+	if name == "city" {
+		limit = fmt.Sprintf("LIMIT %d", maxCityFacets)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s AS value, min(%s) AS label, COUNT(*)
+		FROM causes c
+		LEFT JOIN cause_domains cd on cd.id = c.domain_id
+		LEFT JOIN cause_aid_types ca on ca.id = c.aid_type_id
+		LEFT JOIN organizations o on o.id = c.organization_id
+		WHERE %s
+		GROUP BY 1
+		ORDER BY 3 DESC, 2
+		%s
+	`, valueExpr, labelExpr, w.String(), limit)
+
+	rows, err := r.db.QueryContext(ctx, query, w.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]models.FacetCount, 0)
+	for rows.Next() {
+		var fc models.FacetCount
+		if err := rows.Scan(&fc.Value, &fc.Label, &fc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, fc)
+	}
+
+	return counts, rows.Err()
+}
+
+// geoCauseFilter limits geo queries to public causes with a location, and
+// matches the predicate of the partial spatial indexes.
+const geoCauseFilter = `c.is_active = true AND ` + publicCauseFilter + `
+	AND c.execution_lat IS NOT NULL AND c.execution_lng IS NOT NULL`
+
+func (r *causeSearchRepository) GetNearby(ctx context.Context, query *models.NearbyCausesQuery) (*models.Page[*models.NearbyCause], error) {
+	const distance = "earth_distance(ll_to_earth($1, $2), ll_to_earth(c.execution_lat, c.execution_lng))"
+	pageWhere, orderBy, pageArgs, err := valueKeyset(query.Page, distance, "c.id", false, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	// earth_box is a cheap index-backed pre-filter; it is a little larger
+	// than the circle, so the exact distance is checked as well.
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, `+distance+`
+		FROM causes c
+		WHERE `+geoCauseFilter+`
+			AND earth_box(ll_to_earth($1, $2), $3) @> ll_to_earth(c.execution_lat, c.execution_lng)
+			AND `+distance+` <= $3
+			AND `+pageWhere+`
+		ORDER BY `+orderBy,
+		append([]interface{}{query.Lat, query.Lng, query.RadiusMeters}, pageArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	distances := make(map[uuid.UUID]float64)
+	for rows.Next() {
+		var id uuid.UUID
+		var d float64
+		if err := rows.Scan(&id, &d); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+		distances[id] = d
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	causes, err := r.causesInOrder(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	nearby := make([]*models.NearbyCause, 0, len(causes))
+	for _, cause := range causes {
+		nearby = append(nearby, &models.NearbyCause{
+			Cause:          cause,
+			DistanceMeters: distances[cause.ID],
+		})
+	}
+
+	return models.PaginateByValue(nearby, query.Page, func(n *models.NearbyCause) (float64, uuid.UUID) {
+		return n.DistanceMeters, n.Cause.ID
+	}), nil
+}
+
+// causesInOrder loads the causes with the given ids, in the order given.
+// Ids whose cause has since gone are skipped.
+func (r *causeSearchRepository) causesInOrder(ctx context.Context, ids []uuid.UUID) ([]*models.Cause, error) {
+	ordered := make([]*models.Cause, 0, len(ids))
+	if len(ids) == 0 {
+		return ordered, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	placeholders := make([]string, len(ids))
+	for i, id := range ids {
+		args[i] = id
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	causes, err := queryCauses(r.db, ctx, "c.id IN ("+strings.Join(placeholders, ", ")+")", "c.id", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*models.Cause, len(causes))
+	for _, cause := range causes {
+		byID[cause.ID] = cause
+	}
+	for _, id := range ids {
+		if cause, ok := byID[id]; ok {
+			ordered = append(ordered, cause)
+		}
+	}
+
+	return ordered, nil
+}
+
+func (r *causeSearchRepository) GetInBoundingBox(ctx context.Context, box *models.CauseBoundingBox) ([]*models.CauseMapPin, error) {
+	lngFilter := "c.execution_lng BETWEEN $3 AND $4"
+	if box.MinLng > box.MaxLng {
+		lngFilter = "(c.execution_lng >= $3 OR c.execution_lng <= $4)"
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, c.title, c.execution_lat, c.execution_lng, c.execution_radius_meters,
+			c.cover_image_url, c.collected_amount, c.goal_amount, c.state
+		FROM causes c
+		WHERE `+geoCauseFilter+`
+			AND c.execution_lat BETWEEN $1 AND $2
+			AND `+lngFilter+`
+		ORDER BY c.created_at DESC
+		LIMIT $5
+	`, box.MinLat, box.MaxLat, box.MinLng, box.MaxLng, box.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pins := make([]*models.CauseMapPin, 0)
+	for rows.Next() {
+		pin := &models.CauseMapPin{}
+		if err := rows.Scan(
+			&pin.ID,
+			&pin.Title,
+			&pin.Lat,
+			&pin.Lng,
+			&pin.ExecutionRadiusMeters,
+			&pin.CoverImageURL,
+			&pin.CollectedAmount,
+			&pin.GoalAmount,
+			&pin.State,
+		); err != nil {
+			return nil, err
+		}
+		pins = append(pins, pin)
+	}
+
+	return pins, rows.Err()
+}
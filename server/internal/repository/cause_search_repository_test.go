@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestSearchWhereFacets(t *testing.T) {
+	filter := &models.CauseSearchFilter{
+		Query:           "clean water",
+		DomainIDs:       []uuid.UUID{uuid.New(), uuid.New()},
+		FundingStatuses: []string{models.FundingStatusActive},
+		Cities:          []string{"Pune"},
+	}
+
+	all := buildWhere(filter, "")
+	if !strings.Contains(all.String(), publicCauseFilter) {
+		t.Errorf("search can reach unpublished causes: %s", all)
+	}
+	if !strings.Contains(all.String(), "c.domain_id IN ($2, $3)") || !strings.Contains(all.String(), "lower(c.location_city) IN ($5)") {
+		t.Errorf("where = %s", all)
+	}
+	if len(all.args) != 5 || all.args[4] != "pune" {
+		t.Errorf("args = %v, want the query, two domains, a status and the lowercased city", all.args)
+	}
+
+	// Counting the domain facet drops only the domain filter.
+	domains := buildWhere(filter, "domain")
+	if strings.Contains(domains.String(), "c.domain_id") {
+		t.Errorf("domain facet is filtered by domain: %s", domains)
+	}
+	if !strings.Contains(domains.String(), publicCauseFilter) || !strings.Contains(domains.String(), "websearch_to_tsquery") || !strings.Contains(domains.String(), "lower(c.location_city)") {
+		t.Errorf("domain facet dropped other filters: %s", domains)
+	}
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
+
 	"server/internal/models"
 
 	"github.com/google/uuid"
@@ -11,7 +13,7 @@ import (
 type DisbursementRepository interface {
 	Create(ctx context.Context, disbursement *models.Disbursement) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Disbursement, error)
-	GetByOrganizationID(ctx context.Context, organizationID uuid.UUID, limit, offset int) ([]*models.Disbursement, error)
+	GetByOrganizationID(ctx context.Context, organizationID uuid.UUID, page models.CursorParams) (*models.Page[*models.Disbursement], error)
 	GetByCauseID(ctx context.Context, causeID uuid.UUID) ([]*models.Disbursement, error)
 	GetByCauseAndMilestone(ctx context.Context, causeID uuid.UUID, milestone int) (*models.Disbursement, error)
 	CountByOrganizationID(ctx context.Context, organizationID uuid.UUID) (int, error)
@@ -70,19 +72,19 @@ func (r *disbursementRepository) GetByID(ctx context.Context, id uuid.UUID) (*mo
 	return disbursement, err
 }
 
-func (r *disbursementRepository) GetByOrganizationID(ctx context.Context, organizationID uuid.UUID, limit, offset int) ([]*models.Disbursement, error) {
+func (r *disbursementRepository) GetByOrganizationID(ctx context.Context, organizationID uuid.UUID, page models.CursorParams) (*models.Page[*models.Disbursement], error) {
+	pageWhere, orderBy, pageArgs := keyset(page, "d.disbursed_at", "d.id", true, 2)
+
 	query := `
 		SELECT 
 			d.id, d.organization_id, d.cause_id, d.milestone_number, d.amount, d.transaction_hash, d.disbursed_at, d.created_at,
 			c.id as cause_id, c.title as cause_title
 		FROM disbursements d
 		JOIN causes c ON d.cause_id = c.id
-		WHERE d.organization_id = $1
-		ORDER BY d.disbursed_at DESC
-		LIMIT $2 OFFSET $3
-	`
+		WHERE d.organization_id = $1 AND ` + pageWhere + `
+		ORDER BY ` + orderBy
 
-	rows, err := r.db.QueryContext(ctx, query, organizationID, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, append([]interface{}{organizationID}, pageArgs...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +115,13 @@ func (r *disbursementRepository) GetByOrganizationID(ctx context.Context, organi
 		disbursements = append(disbursements, d)
 	}
 
-	return disbursements, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.Paginate(disbursements, page, func(d *models.Disbursement) (time.Time, uuid.UUID) {
+		return d.DisbursedAt, d.ID
+	}), nil
 }
 
 func (r *disbursementRepository) GetByCauseID(ctx context.Context, causeID uuid.UUID) ([]*models.Disbursement, error) {
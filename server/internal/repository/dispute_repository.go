@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type DisputeRepository interface {
+	Create(ctx context.Context, dispute *models.Dispute) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Dispute, error)
+	// List returns disputes newest first, only those with status if it's
+	// set.
+	List(ctx context.Context, status models.DisputeStatus, page models.CursorParams) (*models.Page[*models.Dispute], error)
+	// Update saves a dispute's status, priority and resolution.
+	Update(ctx context.Context, dispute *models.Dispute) error
+}
+
+type disputeRepository struct {
+	db *sql.DB
+}
+
+func NewDisputeRepository(db *sql.DB) DisputeRepository {
+	return &disputeRepository{db: db}
+}
+
+const disputeColumns = `id, organization_id, cause_id, opened_by, title, description, evidence_url,
+	status, priority, resolution_notes, resolved_by, resolved_at, created_at, updated_at`
+
+func scanDispute(row rowScanner) (*models.Dispute, error) {
+	d := &models.Dispute{}
+	err := row.Scan(
+		&d.ID,
+		&d.OrganizationID,
+		&d.CauseID,
+		&d.OpenedBy,
+		&d.Title,
+		&d.Description,
+		&d.EvidenceURL,
+		&d.Status,
+		&d.Priority,
+		&d.ResolutionNotes,
+		&d.ResolvedBy,
+		&d.ResolvedAt,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (r *disputeRepository) Create(ctx context.Context, d *models.Dispute) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO disputes (
+			id, organization_id, cause_id, opened_by, title, description, evidence_url,
+			status, priority, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`,
+		d.ID,
+		d.OrganizationID,
+		d.CauseID,
+		d.OpenedBy,
+		d.Title,
+		d.Description,
+		d.EvidenceURL,
+		d.Status,
+		d.Priority,
+		d.CreatedAt,
+		d.UpdatedAt,
+	)
+	return err
+}
+
+func (r *disputeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Dispute, error) {
+	return scanDispute(r.db.QueryRowContext(ctx, `
+		SELECT `+disputeColumns+`
+		FROM disputes
+		WHERE id = $1
+	`, id))
+}
+
+func (r *disputeRepository) List(ctx context.Context, status models.DisputeStatus, page models.CursorParams) (*models.Page[*models.Dispute], error) {
+	pageWhere, orderBy, pageArgs := keyset(page, "created_at", "id", true, 2)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+disputeColumns+`
+		FROM disputes
+		WHERE ($1 = '' OR status::text = $1) AND `+pageWhere+`
+		ORDER BY `+orderBy,
+		append([]interface{}{string(status)}, pageArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var disputes []*models.Dispute
+	for rows.Next() {
+		d, err := scanDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+		disputes = append(disputes, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.Paginate(disputes, page, func(d *models.Dispute) (time.Time, uuid.UUID) {
+		return d.CreatedAt, d.ID
+	}), nil
+}
+
+func (r *disputeRepository) Update(ctx context.Context, d *models.Dispute) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE disputes
+		SET status = $2, priority = $3, resolution_notes = $4, resolved_by = $5,
+			resolved_at = $6, updated_at = $7
+		WHERE id = $1
+	`,
+		d.ID,
+		d.Status,
+		d.Priority,
+		d.ResolutionNotes,
+		d.ResolvedBy,
+		d.ResolvedAt,
+		d.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
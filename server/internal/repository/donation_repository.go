@@ -3,9 +3,12 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"server/internal/models"
+	"server/internal/pii"
 
 	"github.com/google/uuid"
 )
@@ -14,45 +17,171 @@ type DonationRepository interface {
 	Create(ctx context.Context, donation *models.Donation) error
 
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Donation, error)
-	GetByCauseID(ctx context.Context, id uuid.UUID) ([]*models.Donation, error)
+	GetByCauseID(ctx context.Context, id uuid.UUID, page models.CursorParams) (*models.Page[*models.Donation], error)
 	GetByPaymentID(ctx context.Context, id uuid.UUID) (*models.Donation, error)
-	GetByUserID(ctx context.Context, id uuid.UUID) ([]*models.Donation, error)
+	// GetByUserID lists a donor's donations, optionally only those to causeID.
+	GetByUserID(ctx context.Context, id uuid.UUID, causeID *uuid.UUID, page models.CursorParams) (*models.Page[*models.Donation], error)
+	GetByFundraiserID(ctx context.Context, fundraiserID uuid.UUID, page models.CursorParams) (*models.Page[*models.Donation], error)
+	GetItems(ctx context.Context, donationID uuid.UUID) ([]*models.DonationItem, error)
+
+	// MarkTributeNotified records when the tribute e-card was sent.
+	MarkTributeNotified(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// SetTxHash records the ledger transaction of a saved donation.
+	SetTxHash(ctx context.Context, id uuid.UUID, txHash string) error
+	// ClaimUnrecorded returns up to limit donations that are not on the
+	// ledger yet and are due a retry, and holds them for lease so other
+	// servers leave them alone.
+	ClaimUnrecorded(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]uuid.UUID, error)
+	// ClaimMilestoneRecording marks a donation as counted by the milestone
+	// tracker and reports whether it wasn't already, so that each donation
+	// is counted once.
+	ClaimMilestoneRecording(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+
+	// EncryptPlaintextPII encrypts the phone, billing address and PAN of up
+	// to limit donations saved before encryption was enabled, and returns
+	// how many rows it rewrote.
+	EncryptPlaintextPII(ctx context.Context, limit int) (int, error)
 
 	// Update(ctx context.Context, donation *models.Donation) error
 	// Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type donationRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	cipher *pii.Cipher
 }
 
-func NewDonationRepository(db *sql.DB) DonationRepository {
-	return &donationRepository{db: db}
+// NewDonationRepository stores phone, billing address and PAN encrypted
+// with cipher and decrypts them on read.
+func NewDonationRepository(db *sql.DB, cipher *pii.Cipher) DonationRepository {
+	return &donationRepository{db: db, cipher: cipher}
 }
 
+func (d *donationRepository) decryptPII(donation *models.Donation) error {
+	phone, err := d.cipher.Decrypt(donation.Phone)
+	if err != nil {
+		return err
+	}
+	billingAddress, err := d.cipher.DecryptPtr(donation.BillingAddress)
+	if err != nil {
+		return err
+	}
+	panNumber, err := d.cipher.DecryptPtr(donation.PanNumber)
+	if err != nil {
+		return err
+	}
+
+	donation.Phone = phone
+	donation.BillingAddress = billingAddress
+	donation.PanNumber = panNumber
+	return nil
+}
+
+// ErrProductOversubscribed is returned when a line item asks for more units
+// of a product than are still needed.
+var ErrProductOversubscribed = errors.New("product quantity exceeds what is still needed")
+
+// Create records the donation, its line items and the funded product
+// quantities in one transaction. The quantity guard lives in the UPDATE
+// itself so concurrent donations cannot push a product past its need.
 func (d *donationRepository) Create(ctx context.Context, donation *models.Donation) error {
+	phone, err := d.cipher.Encrypt(donation.Phone)
+	if err != nil {
+		return err
+	}
+	billingAddress, err := d.cipher.EncryptPtr(donation.BillingAddress)
+	if err != nil {
+		return err
+	}
+	panNumber, err := d.cipher.EncryptPtr(donation.PanNumber)
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO donations (
 			id, cause_id, user_id, name, phone, billing_address,
-			pincode, amount, status, pan_number, payment_id, tx_hash, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			pincode, amount, status, pan_number, payment_id, tx_hash, created_at,
+			matching_campaign_id, matched_donation_id, fundraiser_id,
+			is_anonymous, tribute_type, tribute_name, tribute_message, tribute_notify_email,
+			ledger_retry_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
 	`
 
-	_, err := d.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		donation.ID,
 		donation.CauseID,
 		donation.UserID,
 		donation.Name,
-		donation.Phone,
-		donation.BillingAddress,
+		phone,
+		billingAddress,
 		donation.Pincode,
 		donation.Amount,
 		donation.Status,
-		donation.PanNumber,
+		panNumber,
 		donation.PaymentID,
 		donation.TxHash,
 		donation.CreatedAt,
+		donation.MatchingCampaignID,
+		donation.MatchedDonationID,
+		donation.FundraiserID,
+		donation.IsAnonymous,
+		donation.TributeType,
+		donation.TributeName,
+		donation.TributeMessage,
+		donation.TributeNotifyEmail,
+		donation.LedgerRetryAt,
 	)
+	if err != nil {
+		return err
+	}
+
+	if donation.FundraiserID != nil {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE fundraisers
+			SET raised_amount = raised_amount + $1,
+				donation_count = donation_count + 1,
+				updated_at = NOW()
+			WHERE id = $2 AND cause_id = $3
+		`, donation.Amount, *donation.FundraiserID, donation.CauseID)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("fundraiser is not for this cause")
+		}
+	}
+
+	for _, item := range donation.Items {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE cause_products
+			SET quantity_funded = COALESCE(quantity_funded, 0) + $1
+			WHERE id = $2
+			  AND cause_id = $3
+			  AND COALESCE(quantity_funded, 0) + $1 <= quantity_needed
+		`, item.Quantity, item.ProductID, donation.CauseID)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return ErrProductOversubscribed
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO donation_items (id, donation_id, product_id, quantity, unit_price, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, item.ID, donation.ID, item.ProductID, item.Quantity, item.UnitPrice, item.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
 
 	query = `
 		UPDATE causes 
@@ -60,18 +189,110 @@ func (d *donationRepository) Create(ctx context.Context, donation *models.Donati
 		WHERE id = $2
 	`
 
-	_, err = d.db.ExecContext(ctx, query, donation.Amount, donation.CauseID)
+	if _, err = tx.ExecContext(ctx, query, donation.Amount, donation.CauseID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
 
+func (d *donationRepository) GetItems(ctx context.Context, donationID uuid.UUID) ([]*models.DonationItem, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT id, donation_id, product_id, quantity, unit_price, created_at
+		FROM donation_items
+		WHERE donation_id = $1
+		ORDER BY created_at ASC, id ASC
+	`, donationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*models.DonationItem, 0)
+	for rows.Next() {
+		item := &models.DonationItem{}
+		if err := rows.Scan(
+			&item.ID,
+			&item.DonationID,
+			&item.ProductID,
+			&item.Quantity,
+			&item.UnitPrice,
+			&item.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (d *donationRepository) MarkTributeNotified(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := d.db.ExecContext(ctx, `
+		UPDATE donations SET tribute_notified_at = $2 WHERE id = $1
+	`, id, at)
 	return err
 }
 
+func (d *donationRepository) SetTxHash(ctx context.Context, id uuid.UUID, txHash string) error {
+	_, err := d.db.ExecContext(ctx, `
+		UPDATE donations SET tx_hash = $2, ledger_retry_at = NULL WHERE id = $1
+	`, id, txHash)
+	return err
+}
+
+func (d *donationRepository) ClaimUnrecorded(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]uuid.UUID, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		UPDATE donations
+		SET ledger_retry_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM donations
+			WHERE tx_hash IS NULL AND ledger_retry_at <= $1
+			ORDER BY ledger_retry_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`,
+		now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (d *donationRepository) ClaimMilestoneRecording(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	result, err := d.db.ExecContext(ctx, `
+		UPDATE donations SET milestone_recorded_at = $2
+		WHERE id = $1 AND milestone_recorded_at IS NULL
+	`, id, at)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
 func GetDonationByColumnID(d *donationRepository, ctx context.Context, ID uuid.UUID, column string) (*models.Donation, error) {
 	query := fmt.Sprintf(`
 		SELECT
 			c.id, c.cause_id, c.user_id, c.name,
 			c.phone, c.billing_address, c.pincode,
 			c.amount, c.status, c.pan_number,
-			c.payment_id, c.tx_hash, c.created_at
+			c.payment_id, c.tx_hash, c.created_at,
+			c.matching_campaign_id, c.matched_donation_id, c.fundraiser_id,
+			c.is_anonymous, c.tribute_type, c.tribute_name, c.tribute_message,
+			c.tribute_notify_email, c.tribute_notified_at
 		FROM donations c
 		WHERE c.%s = $1
 		`, column)
@@ -92,28 +313,51 @@ func GetDonationByColumnID(d *donationRepository, ctx context.Context, ID uuid.U
 		&donation.PaymentID,
 		&donation.TxHash,
 		&donation.CreatedAt,
+		&donation.MatchingCampaignID,
+		&donation.MatchedDonationID,
+		&donation.FundraiserID,
+		&donation.IsAnonymous,
+		&donation.TributeType,
+		&donation.TributeName,
+		&donation.TributeMessage,
+		&donation.TributeNotifyEmail,
+		&donation.TributeNotifiedAt,
 	)
 
 	if err != nil {
 		return nil, err
 	}
+	if err := d.decryptPII(&donation); err != nil {
+		return nil, err
+	}
 
 	return &donation, nil
 }
 
-func GetDonationsByColumnID(d *donationRepository, ctx context.Context, ID uuid.UUID, column string) ([]*models.Donation, error) {
+func GetDonationsByColumnID(d *donationRepository, ctx context.Context, ID uuid.UUID, column string, page models.CursorParams) (*models.Page[*models.Donation], error) {
+	return getDonationsWhere(d, ctx, fmt.Sprintf("c.%s = $1", column), page, ID)
+}
+
+// getDonationsWhere returns one page of donations matching where, newest
+// first.
+func getDonationsWhere(d *donationRepository, ctx context.Context, where string, page models.CursorParams, args ...interface{}) (*models.Page[*models.Donation], error) {
+	pageWhere, orderBy, pageArgs := keyset(page, "c.created_at", "c.id", true, len(args)+1)
+
 	query := fmt.Sprintf(`
 		SELECT
 			c.id, c.cause_id, c.user_id, c.name,
 			c.phone, c.billing_address, c.pincode,
 			c.amount, c.status, c.pan_number,
-			c.payment_id, c.tx_hash, c.created_at
+			c.payment_id, c.tx_hash, c.created_at,
+			c.matching_campaign_id, c.matched_donation_id, c.fundraiser_id,
+			c.is_anonymous, c.tribute_type, c.tribute_name, c.tribute_message,
+			c.tribute_notify_email, c.tribute_notified_at
 		FROM donations c
-		WHERE c.%s = $1
-		ORDER BY created_at DESC
-		`, column)
+		WHERE %s AND %s
+		ORDER BY %s
+		`, where, pageWhere, orderBy)
 
-	result, err := d.db.QueryContext(ctx, query, ID)
+	result, err := d.db.QueryContext(ctx, query, append(args, pageArgs...)...)
 
 	var donationsResult []*models.Donation = make([]*models.Donation, 0, 5)
 
@@ -123,6 +367,7 @@ func GetDonationsByColumnID(d *donationRepository, ctx context.Context, ID uuid.
 		}
 		return nil, err
 	}
+	defer result.Close()
 
 	for result.Next() {
 		donation := &models.Donation{}
@@ -141,32 +386,55 @@ func GetDonationsByColumnID(d *donationRepository, ctx context.Context, ID uuid.
 			&donation.PaymentID,
 			&donation.TxHash,
 			&donation.CreatedAt,
+			&donation.MatchingCampaignID,
+			&donation.MatchedDonationID,
+			&donation.FundraiserID,
+			&donation.IsAnonymous,
+			&donation.TributeType,
+			&donation.TributeName,
+			&donation.TributeMessage,
+			&donation.TributeNotifyEmail,
+			&donation.TributeNotifiedAt,
 		)
 
 		if err != nil {
 			return nil, err
 		}
+		if err := d.decryptPII(donation); err != nil {
+			return nil, err
+		}
 
 		donationsResult = append(donationsResult, donation)
 	}
 
-	return donationsResult, nil
+	return models.Paginate(donationsResult, page, donationKey), nil
+}
+
+func donationKey(d *models.Donation) (time.Time, uuid.UUID) {
+	return d.CreatedAt, d.ID
 }
 
 func (d *donationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Donation, error) {
 	return GetDonationByColumnID(d, ctx, id, "id")
 }
 
-func (d *donationRepository) GetByCauseID(ctx context.Context, id uuid.UUID) ([]*models.Donation, error) {
-	return GetDonationsByColumnID(d, ctx, id, "cause_id")
+func (d *donationRepository) GetByCauseID(ctx context.Context, id uuid.UUID, page models.CursorParams) (*models.Page[*models.Donation], error) {
+	return GetDonationsByColumnID(d, ctx, id, "cause_id", page)
 }
 
 func (d *donationRepository) GetByPaymentID(ctx context.Context, id uuid.UUID) (*models.Donation, error) {
 	return GetDonationByColumnID(d, ctx, id, "payment_id")
 }
 
-func (d *donationRepository) GetByUserID(ctx context.Context, id uuid.UUID) ([]*models.Donation, error) {
-	return GetDonationsByColumnID(d, ctx, id, "user_id")
+func (d *donationRepository) GetByUserID(ctx context.Context, id uuid.UUID, causeID *uuid.UUID, page models.CursorParams) (*models.Page[*models.Donation], error) {
+	if causeID != nil {
+		return getDonationsWhere(d, ctx, "c.user_id = $1 AND c.cause_id = $2", page, id, *causeID)
+	}
+	return GetDonationsByColumnID(d, ctx, id, "user_id", page)
+}
+
+func (d *donationRepository) GetByFundraiserID(ctx context.Context, fundraiserID uuid.UUID, page models.CursorParams) (*models.Page[*models.Donation], error) {
+	return GetDonationsByColumnID(d, ctx, fundraiserID, "fundraiser_id", page)
 }
 
 // // func (r *donationRepository) Update(ctx context.Context, donation *models.Donation) error { }
@@ -178,3 +446,89 @@ func (d *donationRepository) GetByUserID(ctx context.Context, id uuid.UUID) ([]*
 //
 // 	return err
 // }
+
+// encryptLegacy encrypts a value still stored as plaintext. Ciphertext and
+// the empty phone left by erasure are kept as they are.
+func (d *donationRepository) encryptLegacy(value string) (string, error) {
+	if value == "" || pii.IsEncrypted(value) {
+		return value, nil
+	}
+	return d.cipher.Encrypt(value)
+}
+
+func (d *donationRepository) encryptLegacyPtr(value *string) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	encrypted, err := d.encryptLegacy(*value)
+	if err != nil {
+		return nil, err
+	}
+	return &encrypted, nil
+}
+
+func (d *donationRepository) EncryptPlaintextPII(ctx context.Context, limit int) (int, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, phone, billing_address, pan_number
+		FROM donations
+		WHERE (phone <> '' AND phone NOT LIKE 'enc:%')
+			OR billing_address NOT LIKE 'enc:%'
+			OR pan_number NOT LIKE 'enc:%'
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	type plaintextRow struct {
+		id             uuid.UUID
+		phone          string
+		billingAddress *string
+		panNumber      *string
+	}
+	var pending []plaintextRow
+	for rows.Next() {
+		var row plaintextRow
+		if err := rows.Scan(&row.id, &row.phone, &row.billingAddress, &row.panNumber); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pending = append(pending, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, row := range pending {
+		phone, err := d.encryptLegacy(row.phone)
+		if err != nil {
+			return 0, err
+		}
+		billingAddress, err := d.encryptLegacyPtr(row.billingAddress)
+		if err != nil {
+			return 0, err
+		}
+		panNumber, err := d.encryptLegacyPtr(row.panNumber)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE donations SET phone = $2, billing_address = $3, pan_number = $4 WHERE id = $1
+		`, row.id, phone, billingAddress, panNumber); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(pending), nil
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type FundraiserRepository interface {
+	Create(ctx context.Context, fundraiser *models.Fundraiser) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Fundraiser, error)
+	Update(ctx context.Context, fundraiser *models.Fundraiser) error
+
+	GetByCauseID(ctx context.Context, causeID uuid.UUID, page models.CursorParams) (*models.Page[*models.Fundraiser], error)
+	GetByOwnerID(ctx context.Context, ownerID uuid.UUID, page models.CursorParams) (*models.Page[*models.Fundraiser], error)
+
+	// GetLeaderboard ranks a cause's fundraisers by amount raised.
+	GetLeaderboard(ctx context.Context, causeID uuid.UUID, limit int) ([]*models.FundraiserLeaderboardEntry, error)
+}
+
+type fundraiserRepository struct {
+	db *sql.DB
+}
+
+func NewFundraiserRepository(db *sql.DB) FundraiserRepository {
+	return &fundraiserRepository{db: db}
+}
+
+const fundraiserSelect = `
+	SELECT
+		f.id, f.cause_id, c.title, f.owner_id, u.name, f.title, f.story,
+		f.goal_amount, f.raised_amount, f.donation_count, f.cover_image_url,
+		f.deadline, f.is_active, f.created_at, f.updated_at
+	FROM fundraisers f
+	JOIN causes c ON c.id = f.cause_id
+	JOIN users u ON u.id = f.owner_id`
+
+func scanFundraiser(row rowScanner) (*models.Fundraiser, error) {
+	f := &models.Fundraiser{}
+	err := row.Scan(
+		&f.ID,
+		&f.CauseID,
+		&f.CauseTitle,
+		&f.OwnerID,
+		&f.OwnerName,
+		&f.Title,
+		&f.Story,
+		&f.GoalAmount,
+		&f.RaisedAmount,
+		&f.DonationCount,
+		&f.CoverImageURL,
+		&f.Deadline,
+		&f.IsActive,
+		&f.CreatedAt,
+		&f.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	f.SetProgress()
+	return f, nil
+}
+
+func (r *fundraiserRepository) Create(ctx context.Context, f *models.Fundraiser) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO fundraisers (
+			id, cause_id, owner_id, title, story, goal_amount, cover_image_url,
+			deadline, is_active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`,
+		f.ID,
+		f.CauseID,
+		f.OwnerID,
+		f.Title,
+		f.Story,
+		f.GoalAmount,
+		f.CoverImageURL,
+		f.Deadline,
+		f.IsActive,
+		f.CreatedAt,
+		f.UpdatedAt,
+	)
+	return err
+}
+
+func (r *fundraiserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Fundraiser, error) {
+	return scanFundraiser(r.db.QueryRowContext(ctx, fundraiserSelect+` WHERE f.id = $1`, id))
+}
+
+// Update saves the owner-editable fields. Totals are only changed by
+// donations.
+func (r *fundraiserRepository) Update(ctx context.Context, f *models.Fundraiser) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE fundraisers
+		SET title = $2,
+			story = $3,
+			goal_amount = $4,
+			cover_image_url = $5,
+			deadline = $6,
+			is_active = $7,
+			updated_at = $8
+		WHERE id = $1
+	`,
+		f.ID,
+		f.Title,
+		f.Story,
+		f.GoalAmount,
+		f.CoverImageURL,
+		f.Deadline,
+		f.IsActive,
+		f.UpdatedAt,
+	)
+	return err
+}
+
+func (r *fundraiserRepository) GetByCauseID(ctx context.Context, causeID uuid.UUID, page models.CursorParams) (*models.Page[*models.Fundraiser], error) {
+	return r.getFundraisersWhere(ctx, "f.cause_id = $1 AND f.is_active", page, causeID)
+}
+
+func (r *fundraiserRepository) GetByOwnerID(ctx context.Context, ownerID uuid.UUID, page models.CursorParams) (*models.Page[*models.Fundraiser], error) {
+	return r.getFundraisersWhere(ctx, "f.owner_id = $1", page, ownerID)
+}
+
+func (r *fundraiserRepository) getFundraisersWhere(ctx context.Context, where string, page models.CursorParams, args ...interface{}) (*models.Page[*models.Fundraiser], error) {
+	pageWhere, orderBy, pageArgs := keyset(page, "f.created_at", "f.id", true, len(args)+1)
+
+	query := fmt.Sprintf(`%s WHERE %s AND %s ORDER BY %s`, fundraiserSelect, where, pageWhere, orderBy)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, pageArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fundraisers := make([]*models.Fundraiser, 0)
+	for rows.Next() {
+		f, err := scanFundraiser(rows)
+		if err != nil {
+			return nil, err
+		}
+		fundraisers = append(fundraisers, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.Paginate(fundraisers, page, func(f *models.Fundraiser) (time.Time, uuid.UUID) {
+		return f.CreatedAt, f.ID
+	}), nil
+}
+
+func (r *fundraiserRepository) GetLeaderboard(ctx context.Context, causeID uuid.UUID, limit int) ([]*models.FundraiserLeaderboardEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			RANK() OVER (ORDER BY f.raised_amount DESC),
+			f.id, f.title, u.name, f.goal_amount, f.raised_amount, f.donation_count
+		FROM fundraisers f
+		JOIN users u ON u.id = f.owner_id
+		WHERE f.cause_id = $1 AND f.raised_amount > 0
+		ORDER BY f.raised_amount DESC, f.donation_count DESC, f.created_at ASC
+		LIMIT $2
+	`, causeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*models.FundraiserLeaderboardEntry, 0)
+	for rows.Next() {
+		e := &models.FundraiserLeaderboardEntry{}
+		if err := rows.Scan(
+			&e.Rank,
+			&e.FundraiserID,
+			&e.Title,
+			&e.OwnerName,
+			&e.GoalAmount,
+			&e.RaisedAmount,
+			&e.DonationCount,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
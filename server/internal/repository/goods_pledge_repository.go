@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrGoodsPledgeChanged is returned when a pledge's status changed between
+// being read and being updated.
+var ErrGoodsPledgeChanged = errors.New("pledge was updated by someone else, reload and try again")
+
+type GoodsPledgeRepository interface {
+	Create(ctx context.Context, pledge *models.GoodsPledge) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.GoodsPledge, error)
+	// GetByCauseID lists a cause's pledges, newest first, optionally only
+	// those in one status.
+	GetByCauseID(ctx context.Context, causeID uuid.UUID, status *models.GoodsPledgeStatus, page models.CursorParams) (*models.Page[*models.GoodsPledge], error)
+	GetByDonorID(ctx context.Context, donorID uuid.UUID, page models.CursorParams) (*models.Page[*models.GoodsPledge], error)
+
+	// Update saves the pledge's status and delivery fields if it is still
+	// in status from.
+	Update(ctx context.Context, pledge *models.GoodsPledge, from models.GoodsPledgeStatus) error
+	// MarkReceived saves a received pledge and, for product pledges, adds
+	// the received quantity to the product's funded quantity.
+	MarkReceived(ctx context.Context, pledge *models.GoodsPledge, from models.GoodsPledgeStatus) error
+
+	GetReceivedByCauseID(ctx context.Context, causeID uuid.UUID) ([]*models.ReceivedGoods, error)
+}
+
+type goodsPledgeRepository struct {
+	db *sql.DB
+}
+
+func NewGoodsPledgeRepository(db *sql.DB) GoodsPledgeRepository {
+	return &goodsPledgeRepository{db: db}
+}
+
+const goodsPledgeColumns = `
+	id, cause_id, donor_id, product_id, item_name, description, quantity, unit,
+	estimated_unit_value, status, rejection_reason, delivery_method,
+	delivery_scheduled_at, delivery_notes, received_quantity, received_at,
+	proof_image_id, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanGoodsPledge(row rowScanner) (*models.GoodsPledge, error) {
+	p := &models.GoodsPledge{}
+	err := row.Scan(
+		&p.ID,
+		&p.CauseID,
+		&p.DonorID,
+		&p.ProductID,
+		&p.ItemName,
+		&p.Description,
+		&p.Quantity,
+		&p.Unit,
+		&p.EstimatedUnitValue,
+		&p.Status,
+		&p.RejectionReason,
+		&p.DeliveryMethod,
+		&p.DeliveryScheduledAt,
+		&p.DeliveryNotes,
+		&p.ReceivedQuantity,
+		&p.ReceivedAt,
+		&p.ProofImageID,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *goodsPledgeRepository) Create(ctx context.Context, pledge *models.GoodsPledge) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO goods_pledges (
+			id, cause_id, donor_id, product_id, item_name, description, quantity, unit,
+			estimated_unit_value, status, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`,
+		pledge.ID,
+		pledge.CauseID,
+		pledge.DonorID,
+		pledge.ProductID,
+		pledge.ItemName,
+		pledge.Description,
+		pledge.Quantity,
+		pledge.Unit,
+		pledge.EstimatedUnitValue,
+		pledge.Status,
+		pledge.CreatedAt,
+		pledge.UpdatedAt,
+	)
+	return err
+}
+
+func (r *goodsPledgeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.GoodsPledge, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+goodsPledgeColumns+` FROM goods_pledges WHERE id = $1`, id)
+	return scanGoodsPledge(row)
+}
+
+func (r *goodsPledgeRepository) GetByCauseID(ctx context.Context, causeID uuid.UUID, status *models.GoodsPledgeStatus, page models.CursorParams) (*models.Page[*models.GoodsPledge], error) {
+	if status != nil {
+		return r.getPledgesWhere(ctx, "cause_id = $1 AND status = $2", page, causeID, *status)
+	}
+	return r.getPledgesWhere(ctx, "cause_id = $1", page, causeID)
+}
+
+func (r *goodsPledgeRepository) GetByDonorID(ctx context.Context, donorID uuid.UUID, page models.CursorParams) (*models.Page[*models.GoodsPledge], error) {
+	return r.getPledgesWhere(ctx, "donor_id = $1", page, donorID)
+}
+
+func (r *goodsPledgeRepository) getPledgesWhere(ctx context.Context, where string, page models.CursorParams, args ...interface{}) (*models.Page[*models.GoodsPledge], error) {
+	pageWhere, orderBy, pageArgs := keyset(page, "created_at", "id", true, len(args)+1)
+
+	query := fmt.Sprintf(`SELECT %s FROM goods_pledges WHERE %s AND %s ORDER BY %s`,
+		goodsPledgeColumns, where, pageWhere, orderBy)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, pageArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pledges := make([]*models.GoodsPledge, 0)
+	for rows.Next() {
+		p, err := scanGoodsPledge(rows)
+		if err != nil {
+			return nil, err
+		}
+		pledges = append(pledges, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.Paginate(pledges, page, func(p *models.GoodsPledge) (time.Time, uuid.UUID) {
+		return p.CreatedAt, p.ID
+	}), nil
+}
+
+func (r *goodsPledgeRepository) Update(ctx context.Context, pledge *models.GoodsPledge, from models.GoodsPledgeStatus) error {
+	return updateGoodsPledge(ctx, r.db, pledge, from)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func updateGoodsPledge(ctx context.Context, db execer, pledge *models.GoodsPledge, from models.GoodsPledgeStatus) error {
+	result, err := db.ExecContext(ctx, `
+		UPDATE goods_pledges
+		SET status = $3,
+			rejection_reason = $4,
+			delivery_method = $5,
+			delivery_scheduled_at = $6,
+			delivery_notes = $7,
+			received_quantity = $8,
+			received_at = $9,
+			proof_image_id = $10,
+			updated_at = $11
+		WHERE id = $1 AND status = $2
+	`,
+		pledge.ID,
+		from,
+		pledge.Status,
+		pledge.RejectionReason,
+		pledge.DeliveryMethod,
+		pledge.DeliveryScheduledAt,
+		pledge.DeliveryNotes,
+		pledge.ReceivedQuantity,
+		pledge.ReceivedAt,
+		pledge.ProofImageID,
+		pledge.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrGoodsPledgeChanged
+	}
+	return nil
+}
+
+func (r *goodsPledgeRepository) MarkReceived(ctx context.Context, pledge *models.GoodsPledge, from models.GoodsPledgeStatus) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The proof photo must come from a proof session for the same cause.
+	var ok bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM proof_images pi
+			JOIN proof_sessions ps ON ps.id = pi.session_id
+			WHERE pi.id = $1 AND ps.cause_id = $2
+		)
+	`, pledge.ProofImageID, pledge.CauseID).Scan(&ok)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("proof image was not uploaded for this cause")
+	}
+
+	if err := updateGoodsPledge(ctx, tx, pledge, from); err != nil {
+		return err
+	}
+
+	// Goods arriving after a product is fully funded are still recorded on
+	// the pledge but cannot push progress past the quantity needed.
+	if pledge.ProductID != nil && pledge.ReceivedQuantity != nil {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE cause_products
+			SET quantity_funded = LEAST(quantity_needed, COALESCE(quantity_funded, 0) + $1)
+			WHERE id = $2 AND cause_id = $3
+		`, *pledge.ReceivedQuantity, *pledge.ProductID, pledge.CauseID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *goodsPledgeRepository) GetReceivedByCauseID(ctx context.Context, causeID uuid.UUID) ([]*models.ReceivedGoods, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			g.id, g.product_id, g.item_name, g.received_quantity, g.unit,
+			g.estimated_unit_value * g.received_quantity, g.received_at,
+			g.proof_image_id, pi.ipfs_cid
+		FROM goods_pledges g
+		LEFT JOIN proof_images pi ON pi.id = g.proof_image_id
+		WHERE g.cause_id = $1 AND g.status = 'received'
+		ORDER BY g.received_at DESC, g.id DESC
+	`, causeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*models.ReceivedGoods, 0)
+	for rows.Next() {
+		g := &models.ReceivedGoods{}
+		if err := rows.Scan(
+			&g.PledgeID,
+			&g.ProductID,
+			&g.ItemName,
+			&g.Quantity,
+			&g.Unit,
+			&g.EstimatedValue,
+			&g.ReceivedAt,
+			&g.ProofImageID,
+			&g.ProofImage,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, g)
+	}
+
+	return items, rows.Err()
+}
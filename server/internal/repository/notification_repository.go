@@ -0,0 +1,384 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepository interface {
+	// GetPreferences returns the user's preferences, which are empty if
+	// they've never changed them.
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferences, error)
+	SavePreferences(ctx context.Context, prefs *models.NotificationPreferences) error
+	// Unsubscribe turns every notification email off for the user.
+	Unsubscribe(ctx context.Context, userID uuid.UUID, at time.Time) error
+
+	// GetEmailRecipient returns the user if they want emails of kind, or
+	// sql.ErrNoRows.
+	GetEmailRecipient(ctx context.Context, userID uuid.UUID, kind models.NotificationKind) (*models.EmailRecipient, error)
+	// ListCauseDonorRecipients lists everyone who has donated to the cause
+	// and wants emails of kind.
+	ListCauseDonorRecipients(ctx context.Context, causeID uuid.UUID, kind models.NotificationKind) ([]*models.EmailRecipient, error)
+
+	EnqueueEmails(ctx context.Context, emails []*models.OutboxEmail) error
+	// ClaimDueEmails takes up to limit pending emails that are due and
+	// holds them for lease so that no other server sends them meanwhile.
+	ClaimDueEmails(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.OutboxEmail, error)
+	// RecordEmailAttempt saves the outcome of sending an email.
+	RecordEmailAttempt(ctx context.Context, email *models.OutboxEmail) error
+
+	// ListCauseDonorIDs lists the active users who have donated to the
+	// cause, whatever their email preferences.
+	ListCauseDonorIDs(ctx context.Context, causeID uuid.UUID) ([]uuid.UUID, error)
+	CreateNotifications(ctx context.Context, notifications []*models.Notification) error
+	// ListNotifications lists the user's notifications newest first.
+	ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, page models.CursorParams) (*models.Page[*models.Notification], error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	// MarkNotificationRead returns sql.ErrNoRows if the user has no such
+	// notification. Marking a read notification again keeps when it was
+	// first read.
+	MarkNotificationRead(ctx context.Context, userID, notificationID uuid.UUID, at time.Time) error
+	MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID, at time.Time) error
+}
+
+type notificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) GetPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferences, error) {
+	prefs := &models.NotificationPreferences{UserID: userID, EmailDisabled: []models.NotificationKind{}}
+	var disabled string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT email_disabled, unsubscribed_at, updated_at
+		FROM notification_preferences
+		WHERE user_id = $1
+	`, userID).Scan(&disabled, &prefs.UnsubscribedAt, &prefs.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return prefs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, kind := range strings.Fields(disabled) {
+		prefs.EmailDisabled = append(prefs.EmailDisabled, models.NotificationKind(kind))
+	}
+	return prefs, nil
+}
+
+func (r *notificationRepository) SavePreferences(ctx context.Context, prefs *models.NotificationPreferences) error {
+	disabled := make([]string, len(prefs.EmailDisabled))
+	for i, kind := range prefs.EmailDisabled {
+		disabled[i] = string(kind)
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO notification_preferences (user_id, email_disabled, unsubscribed_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET email_disabled = EXCLUDED.email_disabled,
+			unsubscribed_at = EXCLUDED.unsubscribed_at,
+			updated_at = EXCLUDED.updated_at
+	`, prefs.UserID, strings.Join(disabled, " "), prefs.UnsubscribedAt, prefs.UpdatedAt)
+	return err
+}
+
+func (r *notificationRepository) Unsubscribe(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO notification_preferences (user_id, unsubscribed_at, updated_at)
+		VALUES ($1, $2, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET unsubscribed_at = COALESCE(notification_preferences.unsubscribed_at, EXCLUDED.unsubscribed_at),
+			updated_at = EXCLUDED.updated_at
+	`, userID, at)
+	return err
+}
+
+// wantsEmail is the condition for user u, with preferences p left joined,
+// wanting emails of the kind in $2.
+const wantsEmail = `u.is_active AND u.erased_at IS NULL
+	AND p.unsubscribed_at IS NULL
+	AND ' ' || COALESCE(p.email_disabled, '') || ' ' NOT LIKE '% ' || $2::text || ' %'`
+
+func (r *notificationRepository) GetEmailRecipient(ctx context.Context, userID uuid.UUID, kind models.NotificationKind) (*models.EmailRecipient, error) {
+	recipient := &models.EmailRecipient{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT u.id, u.name, u.email
+		FROM users u
+		LEFT JOIN notification_preferences p ON p.user_id = u.id
+		WHERE u.id = $1 AND `+wantsEmail,
+		userID, string(kind)).Scan(&recipient.UserID, &recipient.Name, &recipient.Email)
+	if err != nil {
+		return nil, err
+	}
+	return recipient, nil
+}
+
+func (r *notificationRepository) ListCauseDonorRecipients(ctx context.Context, causeID uuid.UUID, kind models.NotificationKind) ([]*models.EmailRecipient, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.name, u.email
+		FROM users u
+		LEFT JOIN notification_preferences p ON p.user_id = u.id
+		WHERE u.id IN (
+			SELECT user_id FROM donations WHERE cause_id = $1 AND status = 'paid'
+		) AND `+wantsEmail,
+		causeID, string(kind))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipients := []*models.EmailRecipient{}
+	for rows.Next() {
+		recipient := &models.EmailRecipient{}
+		if err := rows.Scan(&recipient.UserID, &recipient.Name, &recipient.Email); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, rows.Err()
+}
+
+const outboxEmailColumns = `id, user_id, kind, to_address, subject, body, status, attempts, next_attempt_at, last_error, sent_at, created_at`
+
+func scanOutboxEmail(row rowScanner) (*models.OutboxEmail, error) {
+	e := &models.OutboxEmail{}
+	err := row.Scan(
+		&e.ID,
+		&e.UserID,
+		&e.Kind,
+		&e.To,
+		&e.Subject,
+		&e.Body,
+		&e.Status,
+		&e.Attempts,
+		&e.NextAttemptAt,
+		&e.LastError,
+		&e.SentAt,
+		&e.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *notificationRepository) EnqueueEmails(ctx context.Context, emails []*models.OutboxEmail) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, e := range emails {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO email_outbox (id, user_id, kind, to_address, subject, body, status, next_attempt_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`,
+			e.ID,
+			e.UserID,
+			e.Kind,
+			e.To,
+			e.Subject,
+			e.Body,
+			e.Status,
+			e.NextAttemptAt,
+			e.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *notificationRepository) ClaimDueEmails(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.OutboxEmail, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE email_outbox
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM email_outbox
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+outboxEmailColumns,
+		now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	emails := []*models.OutboxEmail{}
+	for rows.Next() {
+		e, err := scanOutboxEmail(rows)
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, e)
+	}
+	return emails, rows.Err()
+}
+
+func (r *notificationRepository) RecordEmailAttempt(ctx context.Context, e *models.OutboxEmail) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE email_outbox
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, sent_at = $6
+		WHERE id = $1
+	`,
+		e.ID,
+		e.Status,
+		e.Attempts,
+		e.NextAttemptAt,
+		e.LastError,
+		e.SentAt,
+	)
+	return err
+}
+
+func (r *notificationRepository) ListCauseDonorIDs(ctx context.Context, causeID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id
+		FROM users u
+		WHERE u.id IN (
+			SELECT user_id FROM donations WHERE cause_id = $1 AND status = 'paid'
+		) AND u.is_active AND u.erased_at IS NULL
+	`, causeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+const notificationColumns = `id, user_id, kind, title, body, link, read_at, created_at`
+
+func scanNotification(row rowScanner) (*models.Notification, error) {
+	n := &models.Notification{}
+	err := row.Scan(
+		&n.ID,
+		&n.UserID,
+		&n.Kind,
+		&n.Title,
+		&n.Body,
+		&n.Link,
+		&n.ReadAt,
+		&n.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (r *notificationRepository) CreateNotifications(ctx context.Context, notifications []*models.Notification) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, n := range notifications {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO notifications (id, user_id, kind, title, body, link, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`,
+			n.ID,
+			n.UserID,
+			n.Kind,
+			n.Title,
+			n.Body,
+			n.Link,
+			n.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *notificationRepository) ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, page models.CursorParams) (*models.Page[*models.Notification], error) {
+	pageWhere, orderBy, pageArgs := keyset(page, "created_at", "id", true, 3)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+notificationColumns+`
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL) AND `+pageWhere+`
+		ORDER BY `+orderBy,
+		append([]interface{}{userID, unreadOnly}, pageArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*models.Notification
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.Paginate(notifications, page, func(n *models.Notification) (time.Time, uuid.UUID) {
+		return n.CreatedAt, n.ID
+	}), nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL
+	`, userID).Scan(&count)
+	return count, err
+}
+
+func (r *notificationRepository) MarkNotificationRead(ctx context.Context, userID, notificationID uuid.UUID, at time.Time) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, $3)
+		WHERE id = $1 AND user_id = $2
+	`, notificationID, userID, at)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *notificationRepository) MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE notifications SET read_at = $2 WHERE user_id = $1 AND read_at IS NULL
+	`, userID, at)
+	return err
+}
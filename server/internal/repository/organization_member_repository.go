@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotOrganizationMember is returned for users who don't belong to an
+	// organization, or not to the one asked about.
+	ErrNotOrganizationMember = errors.New("not a member of an organization")
+	// ErrAlreadyMember is returned when accepting an invitation while
+	// already belonging to an organization.
+	ErrAlreadyMember = errors.New("this account already belongs to an organization")
+	// ErrInvalidInvitation covers unknown, expired, revoked and accepted
+	// invitations, and invitations sent to a different email address.
+	ErrInvalidInvitation = errors.New("invitation is invalid or has expired")
+)
+
+type OrganizationMemberRepository interface {
+	// GetByUserID returns the user's membership, or ErrNotOrganizationMember.
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*models.OrganizationMember, error)
+	// ListByOrganization lists members with their names and emails, owners
+	// first.
+	ListByOrganization(ctx context.Context, organizationID uuid.UUID) ([]*models.OrganizationMember, error)
+	// UpdateRole changes the member's role. Moving them into a role that
+	// must use 2FA also revokes their sessions, in the same transaction.
+	UpdateRole(ctx context.Context, organizationID, userID uuid.UUID, role models.OrganizationMemberRole) error
+	Remove(ctx context.Context, organizationID, userID uuid.UUID) error
+
+	// CreateInvitation stores an invitation and revokes earlier pending ones
+	// to the same address, so only the latest link works.
+	CreateInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error
+	ListPendingInvitations(ctx context.Context, organizationID uuid.UUID) ([]*models.OrganizationInvitation, error)
+	// RevokeInvitation returns sql.ErrNoRows if there is no pending
+	// invitation with this ID.
+	RevokeInvitation(ctx context.Context, organizationID, invitationID uuid.UUID) error
+	// CountInvitationsSince counts invitations the organization has sent
+	// since the given time.
+	CountInvitationsSince(ctx context.Context, organizationID uuid.UUID, since time.Time) (int, error)
+	// AcceptInvitation adds the user to the organization if the invitation
+	// is pending and was sent to email, and revokes the user's sessions so
+	// they sign in again as a member.
+	AcceptInvitation(ctx context.Context, tokenHash string, userID uuid.UUID, email string) (*models.OrganizationMember, error)
+}
+
+type organizationMemberRepository struct {
+	db *sql.DB
+}
+
+func NewOrganizationMemberRepository(db *sql.DB) OrganizationMemberRepository {
+	return &organizationMemberRepository{db: db}
+}
+
+const organizationMemberColumns = `m.id, m.organization_id, m.user_id, m.role, m.invited_by, m.created_at, m.updated_at`
+
+func scanOrganizationMember(row rowScanner, extra ...interface{}) (*models.OrganizationMember, error) {
+	m := &models.OrganizationMember{}
+	dest := append([]interface{}{
+		&m.ID,
+		&m.OrganizationID,
+		&m.UserID,
+		&m.Role,
+		&m.InvitedBy,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (r *organizationMemberRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.OrganizationMember, error) {
+	m, err := scanOrganizationMember(r.db.QueryRowContext(ctx, `
+		SELECT `+organizationMemberColumns+`
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.user_id = $1 AND u.is_active
+	`, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotOrganizationMember
+	}
+	return m, err
+}
+
+func (r *organizationMemberRepository) ListByOrganization(ctx context.Context, organizationID uuid.UUID) ([]*models.OrganizationMember, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+organizationMemberColumns+`, u.name, u.email, o.user_id = m.user_id
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		JOIN organizations o ON o.id = m.organization_id
+		WHERE m.organization_id = $1
+		ORDER BY m.role, m.created_at
+	`, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*models.OrganizationMember{}
+	for rows.Next() {
+		var name, email string
+		var isPrimary bool
+		m, err := scanOrganizationMember(rows, &name, &email, &isPrimary)
+		if err != nil {
+			return nil, err
+		}
+		m.Name, m.Email, m.IsPrimary = name, email, isPrimary
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func (r *organizationMemberRepository) UpdateRole(ctx context.Context, organizationID, userID uuid.UUID, role models.OrganizationMemberRole) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE organization_members SET role = $3, updated_at = NOW()
+		WHERE organization_id = $1 AND user_id = $2
+	`, organizationID, userID, role)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotOrganizationMember
+	}
+
+	// Sessions started without 2FA must not carry over into a role that
+	// requires it.
+	if role.RequiresTwoFactor() {
+		if _, err := revokeAllSessions(ctx, tx, userID, models.SessionRevokedMembership); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *organizationMemberRepository) Remove(ctx context.Context, organizationID, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2
+	`, organizationID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrNotOrganizationMember
+	}
+	return nil
+}
+
+func (r *organizationMemberRepository) CreateInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE organization_invitations SET revoked_at = $3
+		WHERE organization_id = $1 AND LOWER(email) = LOWER($2)
+			AND accepted_at IS NULL AND revoked_at IS NULL
+	`, invitation.OrganizationID, invitation.Email, invitation.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO organization_invitations (
+			id, organization_id, email, role, token_hash, invited_by, expires_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`,
+		invitation.ID,
+		invitation.OrganizationID,
+		invitation.Email,
+		invitation.Role,
+		invitation.TokenHash,
+		invitation.InvitedBy,
+		invitation.ExpiresAt,
+		invitation.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const organizationInvitationColumns = `id, organization_id, email, role, token_hash, invited_by, expires_at, accepted_at, accepted_by, revoked_at, created_at`
+
+func scanOrganizationInvitation(row rowScanner) (*models.OrganizationInvitation, error) {
+	inv := &models.OrganizationInvitation{}
+	err := row.Scan(
+		&inv.ID,
+		&inv.OrganizationID,
+		&inv.Email,
+		&inv.Role,
+		&inv.TokenHash,
+		&inv.InvitedBy,
+		&inv.ExpiresAt,
+		&inv.AcceptedAt,
+		&inv.AcceptedBy,
+		&inv.RevokedAt,
+		&inv.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+func (r *organizationMemberRepository) ListPendingInvitations(ctx context.Context, organizationID uuid.UUID) ([]*models.OrganizationInvitation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+organizationInvitationColumns+`
+		FROM organization_invitations
+		WHERE organization_id = $1 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC
+	`, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []*models.OrganizationInvitation{}
+	for rows.Next() {
+		inv, err := scanOrganizationInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, inv)
+	}
+	return invitations, rows.Err()
+}
+
+func (r *organizationMemberRepository) RevokeInvitation(ctx context.Context, organizationID, invitationID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE organization_invitations SET revoked_at = NOW()
+		WHERE id = $1 AND organization_id = $2 AND accepted_at IS NULL AND revoked_at IS NULL
+	`, invitationID, organizationID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *organizationMemberRepository) CountInvitationsSince(ctx context.Context, organizationID uuid.UUID, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM organization_invitations WHERE organization_id = $1 AND created_at >= $2
+	`, organizationID, since).Scan(&count)
+	return count, err
+}
+
+func (r *organizationMemberRepository) AcceptInvitation(ctx context.Context, tokenHash string, userID uuid.UUID, email string) (*models.OrganizationMember, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	inv, err := scanOrganizationInvitation(tx.QueryRowContext(ctx, `
+		SELECT `+organizationInvitationColumns+`
+		FROM organization_invitations
+		WHERE token_hash = $1 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+		FOR UPDATE
+	`, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidInvitation
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The link only works for the account it was sent to.
+	if !strings.EqualFold(inv.Email, email) {
+		return nil, ErrInvalidInvitation
+	}
+
+	m, err := scanOrganizationMember(tx.QueryRowContext(ctx, `
+		INSERT INTO organization_members AS m (organization_id, user_id, role, invited_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO NOTHING
+		RETURNING `+organizationMemberColumns,
+		inv.OrganizationID, userID, inv.Role, inv.InvitedBy,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAlreadyMember
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE organization_invitations SET accepted_at = NOW(), accepted_by = $2 WHERE id = $1
+	`, inv.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := revokeAllSessions(ctx, tx, userID, models.SessionRevokedMembership); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func newTestOrganization(name, email string) *models.Organization {
+	user := &models.User{ID: uuid.New(), Name: name, Email: email, Provider: "email", IsActive: true}
+	return &models.Organization{ID: uuid.New(), UserID: user.ID, User: user, OrganizationName: name}
+}
+
+func TestOrganizationCreate(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewOrganizationRepository(db)
+	members := NewOrganizationMemberRepository(db)
+
+	org := newTestOrganization("Seva Trust", "seva@example.com")
+	if err := repo.Create(ctx, org); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	owner, err := members.GetByUserID(ctx, org.UserID)
+	if err != nil {
+		t.Fatalf("GetByUserID() error = %v", err)
+	}
+	if owner.OrganizationID != org.ID || owner.Role != models.OrganizationRoleOwner {
+		t.Errorf("owner membership = %s as %s, want %s as owner", owner.OrganizationID, owner.Role, org.ID)
+	}
+
+	// Reusing the organization ID fails after the user is inserted; none of
+	// it may be kept.
+	clash := newTestOrganization("Seva Trust Copy", "copy@example.com")
+	clash.ID = org.ID
+	if err := repo.Create(ctx, clash); err == nil {
+		t.Fatal("Create() with a duplicate organization ID succeeded")
+	}
+	var id uuid.UUID
+	if err := db.QueryRowContext(ctx, `SELECT id FROM users WHERE id = $1`, clash.UserID).Scan(&id); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("user of the failed organization: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestMembershipChangesRevokeSessions(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	org := newTestOrganization("Seva Trust", "seva@example.com")
+	if err := NewOrganizationRepository(db).Create(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	members := NewOrganizationMemberRepository(db)
+	sessions := NewSessionRepository(db)
+
+	userID := uuid.New()
+	if _, err := db.ExecContext(ctx, `INSERT INTO users (id, name, email) VALUES ($1, 'Asha', 'asha@example.com')`, userID); err != nil {
+		t.Fatal(err)
+	}
+	hash := byte('a')
+	signIn := func(t *testing.T) *models.Session {
+		t.Helper()
+		hash++
+		return newTestSession(t, sessions, userID, hashOf(hash), time.Now().Add(time.Hour))
+	}
+	isActive := func(t *testing.T, session *models.Session) bool {
+		t.Helper()
+		active, err := sessions.IsActive(ctx, session.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return active
+	}
+
+	t.Run("accepting an invitation", func(t *testing.T) {
+		session := signIn(t)
+		now := time.Now()
+		invitation := &models.OrganizationInvitation{
+			ID:             uuid.New(),
+			OrganizationID: org.ID,
+			Email:          "asha@example.com",
+			Role:           models.OrganizationRoleViewer,
+			TokenHash:      hashOf('i'),
+			ExpiresAt:      now.Add(time.Hour),
+			CreatedAt:      now,
+		}
+		if err := members.CreateInvitation(ctx, invitation); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := members.AcceptInvitation(ctx, invitation.TokenHash, userID, "asha@example.com"); err != nil {
+			t.Fatalf("AcceptInvitation() error = %v", err)
+		}
+		if isActive(t, session) {
+			t.Error("session still active after joining an organization")
+		}
+	})
+
+	t.Run("role without 2FA", func(t *testing.T) {
+		session := signIn(t)
+		if err := members.UpdateRole(ctx, org.ID, userID, models.OrganizationRoleFieldAgent); err != nil {
+			t.Fatalf("UpdateRole() error = %v", err)
+		}
+		if !isActive(t, session) {
+			t.Error("session revoked by a move to field agent")
+		}
+	})
+
+	for _, role := range []models.OrganizationMemberRole{models.OrganizationRoleFinance, models.OrganizationRoleAdmin, models.OrganizationRoleOwner} {
+		t.Run(string(role), func(t *testing.T) {
+			session := signIn(t)
+			if err := members.UpdateRole(ctx, org.ID, userID, role); err != nil {
+				t.Fatalf("UpdateRole() error = %v", err)
+			}
+			if isActive(t, session) {
+				t.Errorf("session still active after a move to %s", role)
+			}
+		})
+	}
+}
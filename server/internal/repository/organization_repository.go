@@ -16,6 +16,9 @@ type OrganizationRepository interface {
 	CreatePrimaryContact(ctx context.Context, organizationID uuid.UUID, name, role, email, phone string) error
 	GetByEmail(ctx context.Context, email string) (*models.Organization, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Organization, error)
+	// GetByOrganizationID looks an organization up by its own ID rather
+	// than its account's user ID.
+	GetByOrganizationID(ctx context.Context, id uuid.UUID) (*models.Organization, error)
 	GetByProviderID(ctx context.Context, provider, providerID string) (*models.Organization, error)
 	AddToAmount(ctx context.Context, organizationID uuid.UUID, amount float64) error
 	UpdateTrustScore(ctx context.Context, organizationID uuid.UUID) error
@@ -33,13 +36,19 @@ func NewOrganizationRepository(db *sql.DB) OrganizationRepository {
 }
 
 func (r *organizationRepository) Create(ctx context.Context, organization *models.Organization) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	userQuery := `
 		INSERT INTO users (
 			id, name, email, password_hash, provider, provider_id, avatar_url, is_active, is_verified, role
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
-	_, err := r.db.ExecContext(ctx, userQuery,
+	_, err = tx.ExecContext(ctx, userQuery,
 		organization.User.ID,
 		organization.User.Name,
 		organization.User.Email,
@@ -51,6 +60,9 @@ func (r *organizationRepository) Create(ctx context.Context, organization *model
 		organization.User.IsVerified,
 		string(models.RoleTypeOrganization),
 	)
+	if err != nil {
+		return err
+	}
 
 	organizationQuery := `
 		INSERT INTO organizations (
@@ -58,19 +70,31 @@ func (r *organizationRepository) Create(ctx context.Context, organization *model
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
-	_, err = r.db.ExecContext(ctx, organizationQuery,
+	_, err = tx.ExecContext(ctx, organizationQuery,
 		organization.ID,
 		organization.User.ID,
 		organization.OrganizationName,
 		organization.RegistrationNumber,
-		organization.OrganizationType,		
+		organization.OrganizationType,
 		organization.About,
 		organization.WebsiteUrl,
 		organization.IsApproved,
 		organization.Address,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	// The organization's own account is its first owner.
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, $3)
+	`, organization.ID, organization.User.ID, models.OrganizationRoleOwner)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *organizationRepository) CreatePrimaryContact(ctx context.Context, organizationID uuid.UUID, name, role, email, phone string) error {
@@ -192,6 +216,62 @@ func (r *organizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*mo
 	return organization, nil
 }
 
+func (r *organizationRepository) GetByOrganizationID(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	query := `
+		SELECT
+		u.id as user_id, u.name, u.email, u.password_hash, u.provider, u.provider_id, u.avatar_url, u.is_active, u.is_verified, u.created_at, u.updated_at, u.role,
+		o.id as id, o.organization_name, o.registration_number, o.organization_type, o.about, o.website_url, o.address, o.is_approved, COALESCE(o.amount, 0) as amount, o.trust_score
+		FROM organizations o
+		JOIN users u ON u.id = o.user_id
+		WHERE o.id = $1
+	`
+
+	organization := &models.Organization{
+		User: &models.User{},
+	}
+	var trustScore sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&organization.User.ID,
+		&organization.User.Name,
+		&organization.User.Email,
+		&organization.User.PasswordHash,
+		&organization.User.Provider,
+		&organization.User.ProviderID,
+		&organization.User.AvatarURL,
+		&organization.User.IsActive,
+		&organization.User.IsVerified,
+		&organization.User.CreatedAt,
+		&organization.User.UpdatedAt,
+		&organization.User.Role,
+
+		&organization.ID,
+		&organization.OrganizationName,
+		&organization.RegistrationNumber,
+		&organization.OrganizationType,
+		&organization.About,
+		&organization.WebsiteUrl,
+		&organization.Address,
+		&organization.IsApproved,
+		&organization.Amount,
+		&trustScore,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("organization not found")
+		}
+		return nil, err
+	}
+
+	organization.UserID = organization.User.ID
+	if trustScore.Valid {
+		v := trustScore.Float64
+		organization.TrustScore = &v
+	}
+
+	return organization, nil
+}
+
 func (r *organizationRepository) GetByProviderID(ctx context.Context, provider, providerID string) (*models.Organization, error) {
 	query := `
 		SELECT 
@@ -275,6 +355,7 @@ func (r *organizationRepository) UpdateTrustScore(ctx context.Context, organizat
 						FROM cause_reviews cr
 						JOIN causes c ON c.id = cr.cause_id
 						WHERE c.organization_id = organizations.id
+						  AND cr.is_hidden = false
 					), 0))
 					+
 					(0.2 * COALESCE((
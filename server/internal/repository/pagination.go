@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"fmt"
+
+	"server/internal/models"
+)
+
+// keyset builds the WHERE fragment and ORDER BY ... LIMIT for one page of a
+// list ordered by (tsCol, idCol). desc is the order the list is shown in;
+// prev pages are queried in the opposite order and flipped back by
+// models.Paginate. Placeholders start at $argStart. One extra row is
+// fetched so callers can tell whether another page exists.
+func keyset(p models.CursorParams, tsCol, idCol string, desc bool, argStart int) (where string, orderBy string, args []interface{}) {
+	var key interface{}
+	if p.Cursor != nil {
+		key = p.Cursor.Timestamp
+	}
+	return orderedKeyset(p, tsCol, idCol, key, desc, argStart)
+}
+
+// valueKeyset is keyset for a list ordered by a float8 expression, such as
+// a rank or a distance, paired with models.PaginateByValue. The expression
+// must give the same value for a row on every page. Cursors from
+// timestamp-ordered lists carry no value and are rejected.
+func valueKeyset(p models.CursorParams, expr, idCol string, desc bool, argStart int) (where string, orderBy string, args []interface{}, err error) {
+	var key interface{}
+	if p.Cursor != nil {
+		if p.Cursor.Value == nil {
+			return "", "", nil, models.ErrInvalidCursor
+		}
+		key = *p.Cursor.Value
+	}
+	where, orderBy, args = orderedKeyset(p, expr, idCol, key, desc, argStart)
+	return where, orderBy, args, nil
+}
+
+func orderedKeyset(p models.CursorParams, keyExpr, idCol string, key interface{}, desc bool, argStart int) (where string, orderBy string, args []interface{}) {
+	forward := !p.Backward()
+	descending := desc == forward
+
+	where = "TRUE"
+	if p.Cursor != nil {
+		op := ">"
+		if descending {
+			op = "<"
+		}
+		where = fmt.Sprintf("(%s, %s) %s ($%d, $%d)", keyExpr, idCol, op, argStart, argStart+1)
+		args = append(args, key, p.Cursor.ID)
+	}
+
+	dir := "ASC"
+	if descending {
+		dir = "DESC"
+	}
+	orderBy = fmt.Sprintf("%s %s, %s %s LIMIT $%d", keyExpr, dir, idCol, dir, argStart+len(args))
+	args = append(args, p.Limit+1)
+
+	return where, orderBy, args
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type PIIAccessLogRepository interface {
+	Create(ctx context.Context, entry *models.PIIAccessLog) error
+	// List returns access log entries newest first, optionally only those
+	// for one resource.
+	List(ctx context.Context, resourceID *uuid.UUID, page models.CursorParams) (*models.Page[*models.PIIAccessLog], error)
+}
+
+type piiAccessLogRepository struct {
+	db *sql.DB
+}
+
+func NewPIIAccessLogRepository(db *sql.DB) PIIAccessLogRepository {
+	return &piiAccessLogRepository{db: db}
+}
+
+func (r *piiAccessLogRepository) Create(ctx context.Context, entry *models.PIIAccessLog) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO pii_access_logs (id, actor_id, actor_role, resource_type, resource_id, purpose, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		entry.ID,
+		entry.ActorID,
+		entry.ActorRole,
+		entry.ResourceType,
+		entry.ResourceID,
+		entry.Purpose,
+		entry.CreatedAt,
+	)
+	return err
+}
+
+func (r *piiAccessLogRepository) List(ctx context.Context, resourceID *uuid.UUID, page models.CursorParams) (*models.Page[*models.PIIAccessLog], error) {
+	where := "TRUE"
+	args := []interface{}{}
+	if resourceID != nil {
+		where = "resource_id = $1"
+		args = append(args, *resourceID)
+	}
+	pageWhere, orderBy, pageArgs := keyset(page, "created_at", "id", true, len(args)+1)
+
+	query := fmt.Sprintf(`
+		SELECT id, actor_id, actor_role, resource_type, resource_id, purpose, created_at
+		FROM pii_access_logs
+		WHERE %s AND %s
+		ORDER BY %s
+	`, where, pageWhere, orderBy)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, pageArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*models.PIIAccessLog, 0)
+	for rows.Next() {
+		entry := &models.PIIAccessLog{}
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.ActorID,
+			&entry.ActorRole,
+			&entry.ResourceType,
+			&entry.ResourceID,
+			&entry.Purpose,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.Paginate(entries, page, func(e *models.PIIAccessLog) (time.Time, uuid.UUID) {
+		return e.CreatedAt, e.ID
+	}), nil
+}
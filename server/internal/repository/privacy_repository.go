@@ -0,0 +1,379 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/models"
+	"server/internal/pii"
+
+	"github.com/google/uuid"
+)
+
+// ErrAlreadyErased is returned when erasing an account that has already
+// been erased.
+var ErrAlreadyErased = errors.New("account has already been erased")
+
+type PrivacyRepository interface {
+	// ExportUserData returns every record held about the user, one section
+	// per kind of record.
+	ExportUserData(ctx context.Context, userID uuid.UUID) ([]*models.DataExportSection, error)
+	// EraseUser pseudonymizes the user and their donations, keeps tax
+	// records for 80G donations, and deletes everything else personal, in
+	// one transaction.
+	EraseUser(ctx context.Context, userID uuid.UUID, requestedAt time.Time) (*models.ErasureSummary, error)
+}
+
+type privacyRepository struct {
+	db     *sql.DB
+	cipher *pii.Cipher
+}
+
+func NewPrivacyRepository(db *sql.DB, cipher *pii.Cipher) PrivacyRepository {
+	return &privacyRepository{db: db, cipher: cipher}
+}
+
+type exportSection struct {
+	name  string
+	query string
+	// encrypted lists columns stored with pii.Cipher.
+	encrypted []string
+}
+
+// Each query selects one JSON object per row for user $1.
+var exportSections = []exportSection{
+	{
+		name: "profile",
+		query: `SELECT row_to_json(t) FROM (
+			SELECT id, name, email, provider, avatar_url, role, is_active, is_verified, created_at, updated_at
+			FROM users WHERE id = $1
+		) t`,
+	},
+	{
+		name:      "donations",
+		query:     `SELECT row_to_json(t) FROM (SELECT * FROM donations WHERE user_id = $1 ORDER BY created_at) t`,
+		encrypted: []string{"phone", "billing_address", "pan_number"},
+	},
+	{
+		name: "donation_items",
+		query: `SELECT row_to_json(t) FROM (
+			SELECT i.* FROM donation_items i
+			JOIN donations d ON d.id = i.donation_id
+			WHERE d.user_id = $1 ORDER BY i.created_at
+		) t`,
+	},
+	{
+		name:  "blood_registrations",
+		query: `SELECT row_to_json(t) FROM (SELECT * FROM cause_blood WHERE user_id = $1 ORDER BY created_at) t`,
+	},
+	{
+		name:  "volunteer_registrations",
+		query: `SELECT row_to_json(t) FROM (SELECT * FROM cause_volunteer WHERE user_id = $1 ORDER BY created_at) t`,
+	},
+	{
+		name:  "reviews",
+		query: `SELECT row_to_json(t) FROM (SELECT * FROM cause_reviews WHERE user_id = $1 ORDER BY created_at) t`,
+	},
+	{
+		name:  "review_reports",
+		query: `SELECT row_to_json(t) FROM (SELECT * FROM cause_review_reports WHERE reporter_id = $1 ORDER BY created_at) t`,
+	},
+	{
+		name: "linked_accounts",
+		query: `SELECT row_to_json(t) FROM (
+			SELECT provider, email, last_used_at, created_at
+			FROM user_identities WHERE user_id = $1 ORDER BY created_at
+		) t`,
+	},
+	{
+		name:  "votes",
+		query: `SELECT row_to_json(t) FROM (SELECT * FROM cause_votes WHERE user_id = $1 ORDER BY created_at) t`,
+	},
+	{
+		name:  "goods_pledges",
+		query: `SELECT row_to_json(t) FROM (SELECT * FROM goods_pledges WHERE donor_id = $1 ORDER BY created_at) t`,
+	},
+	{
+		name:  "fundraisers",
+		query: `SELECT row_to_json(t) FROM (SELECT * FROM fundraisers WHERE owner_id = $1 ORDER BY created_at) t`,
+	},
+	{
+		name: "notification_preferences",
+		query: `SELECT row_to_json(t) FROM (
+			SELECT email_disabled, unsubscribed_at, updated_at
+			FROM notification_preferences WHERE user_id = $1
+		) t`,
+	},
+	{
+		name: "notification_emails",
+		query: `SELECT row_to_json(t) FROM (
+			SELECT kind, to_address, subject, body, status, sent_at, created_at
+			FROM email_outbox WHERE user_id = $1 ORDER BY created_at
+		) t`,
+	},
+	{
+		name: "notifications",
+		query: `SELECT row_to_json(t) FROM (
+			SELECT kind, title, body, link, read_at, created_at
+			FROM notifications WHERE user_id = $1 ORDER BY created_at
+		) t`,
+	},
+	{
+		// Who outside the donor has read their unmasked donation details.
+		name: "donation_access_log",
+		query: `SELECT row_to_json(t) FROM (
+			SELECT l.resource_id AS donation_id, l.actor_role, l.purpose, l.created_at
+			FROM pii_access_logs l
+			JOIN donations d ON d.id = l.resource_id
+			WHERE l.resource_type = 'donation' AND d.user_id = $1
+			ORDER BY l.created_at
+		) t`,
+	},
+}
+
+func (r *privacyRepository) ExportUserData(ctx context.Context, userID uuid.UUID) ([]*models.DataExportSection, error) {
+	sections := make([]*models.DataExportSection, 0, len(exportSections))
+	for _, s := range exportSections {
+		rows, err := r.exportRows(ctx, s, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", s.name, err)
+		}
+		sections = append(sections, &models.DataExportSection{Name: s.name, Rows: rows})
+	}
+	return sections, nil
+}
+
+func (r *privacyRepository) exportRows(ctx context.Context, s exportSection, userID uuid.UUID) ([]json.RawMessage, error) {
+	rows, err := r.db.QueryContext(ctx, s.query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]json.RawMessage, 0)
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		if len(s.encrypted) > 0 {
+			if raw, err = r.decryptJSON(raw, s.encrypted); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, json.RawMessage(raw))
+	}
+
+	return result, rows.Err()
+}
+
+func (r *privacyRepository) decryptJSON(raw []byte, columns []string) ([]byte, error) {
+	var row map[string]interface{}
+	if err := json.Unmarshal(raw, &row); err != nil {
+		return nil, err
+	}
+	for _, column := range columns {
+		value, ok := row[column].(string)
+		if !ok {
+			continue
+		}
+		plaintext, err := r.cipher.Decrypt(value)
+		if err != nil {
+			return nil, err
+		}
+		row[column] = plaintext
+	}
+	return json.Marshal(row)
+}
+
+func (r *privacyRepository) EraseUser(ctx context.Context, userID uuid.UUID, requestedAt time.Time) (*models.ErasureSummary, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var erasedAt *time.Time
+	err = tx.QueryRowContext(ctx, `SELECT erased_at FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&erasedAt)
+	if err != nil {
+		return nil, err
+	}
+	if erasedAt != nil {
+		return nil, ErrAlreadyErased
+	}
+
+	summary := &models.ErasureSummary{RequestID: uuid.New(), CompletedAt: time.Now()}
+
+	steps := []struct {
+		count *int64
+		query string
+		args  []interface{}
+	}{
+		{
+			// Tax records first, while donations still hold the details.
+			count: &summary.TaxRecordsRetained,
+			query: `
+				INSERT INTO donation_tax_records (
+					donation_id, donor_name, pan_number, billing_address, pincode,
+					amount, donated_at, retain_until
+				)
+				SELECT id, name, pan_number, billing_address, pincode, amount, created_at,
+					created_at + make_interval(years => $2)
+				FROM donations
+				WHERE user_id = $1 AND status = 'paid' AND pan_number IS NOT NULL
+				ON CONFLICT (donation_id) DO NOTHING`,
+			args: []interface{}{userID, models.TaxRecordRetentionYears},
+		},
+		{
+			// Donations stay for the ledger, the cause totals and the NGO's
+			// books, without anything identifying the donor.
+			count: &summary.DonationsPseudonymized,
+			query: `
+				UPDATE donations
+				SET name = $2,
+					phone = '',
+					billing_address = NULL,
+					pincode = NULL,
+					pan_number = NULL,
+					is_anonymous = TRUE,
+					tribute_type = NULL,
+					tribute_name = NULL,
+					tribute_message = NULL,
+					tribute_notify_email = NULL
+				WHERE user_id = $1`,
+			args: []interface{}{userID, models.ErasedDonorName},
+		},
+		{
+			count: &summary.BloodRegistrationsDeleted,
+			query: `DELETE FROM cause_blood WHERE user_id = $1`,
+			args:  []interface{}{userID},
+		},
+		{
+			count: &summary.VolunteerRegistrationsDeleted,
+			query: `DELETE FROM cause_volunteer WHERE user_id = $1`,
+			args:  []interface{}{userID},
+		},
+		{
+			query: `DELETE FROM cause_review_reports WHERE reporter_id = $1`,
+			args:  []interface{}{userID},
+		},
+		{
+			// Reports and NGO responses on these reviews cascade.
+			count: &summary.ReviewsDeleted,
+			query: `DELETE FROM cause_reviews WHERE user_id = $1`,
+			args:  []interface{}{userID},
+		},
+		{
+			count: &summary.VotesDeleted,
+			query: `DELETE FROM cause_votes WHERE user_id = $1`,
+			args:  []interface{}{userID},
+		},
+		{
+			count: &summary.GoodsPledgesCancelled,
+			query: `
+				UPDATE goods_pledges
+				SET status = 'cancelled', updated_at = NOW()
+				WHERE donor_id = $1 AND status IN ('pledged', 'accepted', 'scheduled')`,
+			args: []interface{}{userID},
+		},
+		{
+			query: `UPDATE goods_pledges SET delivery_notes = NULL WHERE donor_id = $1`,
+			args:  []interface{}{userID},
+		},
+		{
+			count: &summary.FundraisersClosed,
+			query: `
+				UPDATE fundraisers
+				SET is_active = FALSE, story = NULL, cover_image_url = NULL, updated_at = NOW()
+				WHERE owner_id = $1 AND is_active`,
+			args: []interface{}{userID},
+		},
+		{
+			query: `
+				UPDATE users
+				SET name = $2,
+					email = 'deleted-' || id || '@erased.invalid',
+					password_hash = NULL,
+					provider_id = NULL,
+					avatar_url = NULL,
+					is_active = FALSE,
+					is_verified = FALSE,
+					erased_at = $3,
+					updated_at = $3
+				WHERE id = $1`,
+			args: []interface{}{userID, models.ErasedUserName, summary.CompletedAt},
+		},
+		{
+			query: `DELETE FROM two_factor_recovery_codes WHERE user_id = $1`,
+			args:  []interface{}{userID},
+		},
+		{
+			query: `DELETE FROM user_two_factor WHERE user_id = $1`,
+			args:  []interface{}{userID},
+		},
+		{
+			query: `DELETE FROM user_identities WHERE user_id = $1`,
+			args:  []interface{}{userID},
+		},
+		{
+			// Leaves any organization team the user was on.
+			query: `DELETE FROM organization_members WHERE user_id = $1`,
+			args:  []interface{}{userID},
+		},
+		{
+			// Drops notification emails, sent or not, and their settings.
+			query: `DELETE FROM email_outbox WHERE user_id = $1`,
+			args:  []interface{}{userID},
+		},
+		{
+			query: `DELETE FROM notification_preferences WHERE user_id = $1`,
+			args:  []interface{}{userID},
+		},
+		{
+			// Empties the in-app inbox.
+			query: `DELETE FROM notifications WHERE user_id = $1`,
+			args:  []interface{}{userID},
+		},
+		{
+			// Signs the account out everywhere.
+			query: `
+				UPDATE auth_sessions
+				SET revoked_at = $2, revoked_reason = $3
+				WHERE user_id = $1 AND revoked_at IS NULL`,
+			args: []interface{}{userID, summary.CompletedAt, models.SessionRevokedAccountErase},
+		},
+	}
+
+	for _, step := range steps {
+		result, err := tx.ExecContext(ctx, step.query, step.args...)
+		if err != nil {
+			return nil, err
+		}
+		if step.count != nil {
+			if *step.count, err = result.RowsAffected(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	raw, err := json.Marshal(summary)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO data_erasure_requests (id, user_id, summary, requested_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, summary.RequestID, userID, raw, requestedAt, summary.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
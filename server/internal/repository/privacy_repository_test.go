@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"server/internal/models"
+	"server/internal/pii"
+
+	"github.com/google/uuid"
+)
+
+type privacyFixture struct {
+	db        *sql.DB
+	cipher    *pii.Cipher
+	donorID   uuid.UUID
+	otherID   uuid.UUID
+	causeID   uuid.UUID
+	taxable   *models.Donation
+	plain     *models.Donation
+	untouched *models.Donation
+}
+
+func newPrivacyFixture(t *testing.T) *privacyFixture {
+	t.Helper()
+	db := newTestDB(t)
+	ctx := context.Background()
+	f := &privacyFixture{db: db, cipher: newTestCipher(t), donorID: uuid.New(), otherID: uuid.New(), causeID: uuid.New()}
+
+	ownerID, orgID, domainID, aidTypeID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT INTO users (id, name, email, provider, provider_id, avatar_url) VALUES ($1, 'Asha Rao', 'asha@example.com', 'google', 'g-123', 'https://example.com/a.png')`, []interface{}{f.donorID}},
+		{`INSERT INTO users (id, name, email) VALUES ($1, 'Vikram', 'vikram@example.com')`, []interface{}{f.otherID}},
+		{`INSERT INTO users (id, name, email, role) VALUES ($1, 'Seva Trust', 'seva@example.com', 'organization')`, []interface{}{ownerID}},
+		{`INSERT INTO organizations (id, user_id, organization_name) VALUES ($1, $2, 'Seva Trust')`, []interface{}{orgID, ownerID}},
+		{`INSERT INTO cause_domains (id, name) VALUES ($1, 'Water')`, []interface{}{domainID}},
+		{`INSERT INTO cause_aid_types (id, name) VALUES ($1, 'Funds')`, []interface{}{aidTypeID}},
+		{`INSERT INTO causes (id, organization_id, title, domain_id, aid_type_id, goal_amount) VALUES ($1, $2, 'Clean water for Rampur', $3, $4, 100000)`, []interface{}{f.causeID, orgID, domainID, aidTypeID}},
+		{`INSERT INTO cause_votes (cause_id, user_id, vote_value) VALUES ($1, $2, 1)`, []interface{}{f.causeID, f.donorID}},
+		{`INSERT INTO user_identities (user_id, provider, provider_user_id, email) VALUES ($1, 'google', 'g-123', 'asha@example.com')`, []interface{}{f.donorID}},
+		{`INSERT INTO auth_sessions (user_id, expires_at) VALUES ($1, NOW() + INTERVAL '30 days')`, []interface{}{f.donorID}},
+	}
+	for _, s := range statements {
+		if _, err := db.ExecContext(ctx, s.query, s.args...); err != nil {
+			t.Fatalf("seed failed: %v\n%s", err, s.query)
+		}
+	}
+
+	donations := NewDonationRepository(db, f.cipher)
+	address, pan := "12 MG Road, Indiranagar, Bengaluru", "ABCDE1234F"
+	f.taxable = testDonation(f.donorID, f.causeID, 500000)
+	f.taxable.BillingAddress, f.taxable.PanNumber = &address, &pan
+	f.plain = testDonation(f.donorID, f.causeID, 25000)
+	f.untouched = testDonation(f.otherID, f.causeID, 100000)
+	for _, d := range []*models.Donation{f.taxable, f.plain, f.untouched} {
+		if err := donations.Create(ctx, d); err != nil {
+			t.Fatalf("Create() donation error = %v", err)
+		}
+	}
+	return f
+}
+
+func testDonation(userID, causeID uuid.UUID, amount models.Paise) *models.Donation {
+	txHash := "0x" + strings.Repeat("ab", 32)
+	return &models.Donation{
+		ID:        uuid.New(),
+		CauseID:   causeID,
+		UserID:    userID,
+		Name:      "Asha Rao",
+		Phone:     "9876543210",
+		Amount:    amount,
+		Status:    models.DonationStatusCompleted,
+		TxHash:    &txHash,
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestPrivacyExportDecryptsDonorData(t *testing.T) {
+	f := newPrivacyFixture(t)
+	repo := NewPrivacyRepository(f.db, f.cipher)
+
+	sections, err := repo.ExportUserData(context.Background(), f.donorID)
+	if err != nil {
+		t.Fatalf("ExportUserData() error = %v", err)
+	}
+
+	byName := map[string][]json.RawMessage{}
+	for _, s := range sections {
+		byName[s.Name] = s.Rows
+	}
+	if len(byName["profile"]) != 1 || len(byName["votes"]) != 1 || len(byName["linked_accounts"]) != 1 {
+		t.Errorf("profile, votes, linked_accounts = %d, %d, %d rows, want 1 each",
+			len(byName["profile"]), len(byName["votes"]), len(byName["linked_accounts"]))
+	}
+	if got := len(byName["donations"]); got != 2 {
+		t.Fatalf("donations = %d rows, want only the donor's 2", got)
+	}
+	for _, raw := range byName["donations"] {
+		var row map[string]interface{}
+		if err := json.Unmarshal(raw, &row); err != nil {
+			t.Fatal(err)
+		}
+		if row["phone"] != "9876543210" {
+			t.Errorf("exported phone = %v, want the decrypted number", row["phone"])
+		}
+		if pan, ok := row["pan_number"].(string); ok && pan != "ABCDE1234F" {
+			t.Errorf("exported pan_number = %q, want the decrypted PAN", pan)
+		}
+	}
+}
+
+func TestPrivacyEraseUser(t *testing.T) {
+	f := newPrivacyFixture(t)
+	repo := NewPrivacyRepository(f.db, f.cipher)
+	ctx := context.Background()
+
+	summary, err := repo.EraseUser(ctx, f.donorID, time.Now())
+	if err != nil {
+		t.Fatalf("EraseUser() error = %v", err)
+	}
+	if summary.DonationsPseudonymized != 2 || summary.TaxRecordsRetained != 1 || summary.VotesDeleted != 1 {
+		t.Errorf("summary = %+v, want 2 donations pseudonymized, 1 tax record, 1 vote", summary)
+	}
+
+	t.Run("donations are kept without the donor's details", func(t *testing.T) {
+		rows, err := f.db.QueryContext(ctx, `
+			SELECT id, name, phone, billing_address, pan_number, is_anonymous, amount, tx_hash
+			FROM donations WHERE user_id = $1`, f.donorID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		kept := map[uuid.UUID]float64{}
+		for rows.Next() {
+			var (
+				id                   uuid.UUID
+				name, phone          string
+				address, pan, txHash *string
+				anonymous            bool
+				amount               float64
+			)
+			if err := rows.Scan(&id, &name, &phone, &address, &pan, &anonymous, &amount, &txHash); err != nil {
+				t.Fatal(err)
+			}
+			if name != models.ErasedDonorName || phone != "" || address != nil || pan != nil || !anonymous {
+				t.Errorf("donation %s still identifies the donor: name=%q phone=%q address=%v pan=%v anonymous=%v",
+					id, name, phone, address, pan, anonymous)
+			}
+			if txHash == nil {
+				t.Errorf("donation %s lost its ledger transaction", id)
+			}
+			kept[id] = amount
+		}
+		if kept[f.taxable.ID] != 5000 || kept[f.plain.ID] != 250 {
+			t.Errorf("kept donations = %v, want both ledger rows with their amounts", kept)
+		}
+	})
+
+	t.Run("tax record keeps the PAN for 80G", func(t *testing.T) {
+		var name, pan string
+		var retainUntil time.Time
+		err := f.db.QueryRowContext(ctx, `
+			SELECT donor_name, pan_number, retain_until FROM donation_tax_records WHERE donation_id = $1
+		`, f.taxable.ID).Scan(&name, &pan, &retainUntil)
+		if err != nil {
+			t.Fatalf("tax record for %s: %v", f.taxable.ID, err)
+		}
+		if !pii.IsEncrypted(pan) {
+			t.Error("tax record PAN is not encrypted")
+		}
+		if plaintext, _ := f.cipher.Decrypt(pan); plaintext != "ABCDE1234F" || name != "Asha Rao" {
+			t.Errorf("tax record = %q, %q, want the donor's name and PAN", name, plaintext)
+		}
+		if !retainUntil.After(time.Now().AddDate(models.TaxRecordRetentionYears-1, 0, 0)) {
+			t.Errorf("retain_until = %v, want about %d years out", retainUntil, models.TaxRecordRetentionYears)
+		}
+
+		var count int
+		f.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM donation_tax_records WHERE donation_id = $1`, f.plain.ID).Scan(&count)
+		if count != 0 {
+			t.Error("donation without a PAN got a tax record")
+		}
+	})
+
+	t.Run("user is pseudonymized and signed out", func(t *testing.T) {
+		var name, email string
+		var providerID, avatar *string
+		var erasedAt *time.Time
+		err := f.db.QueryRowContext(ctx, `
+			SELECT name, email, provider_id, avatar_url, erased_at FROM users WHERE id = $1
+		`, f.donorID).Scan(&name, &email, &providerID, &avatar, &erasedAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != models.ErasedUserName || !strings.HasSuffix(email, "@erased.invalid") || providerID != nil || avatar != nil || erasedAt == nil {
+			t.Errorf("user = %q %q provider_id=%v avatar=%v erased_at=%v", name, email, providerID, avatar, erasedAt)
+		}
+
+		for table, query := range map[string]string{
+			"cause_votes":     `SELECT COUNT(*) FROM cause_votes WHERE user_id = $1`,
+			"user_identities": `SELECT COUNT(*) FROM user_identities WHERE user_id = $1`,
+			"live sessions":   `SELECT COUNT(*) FROM auth_sessions WHERE user_id = $1 AND revoked_at IS NULL`,
+		} {
+			var count int
+			if err := f.db.QueryRowContext(ctx, query, f.donorID).Scan(&count); err != nil {
+				t.Fatal(err)
+			}
+			if count != 0 {
+				t.Errorf("%s: %d rows left, want 0", table, count)
+			}
+		}
+	})
+
+	t.Run("other donors are untouched", func(t *testing.T) {
+		got, err := NewDonationRepository(f.db, f.cipher).GetByID(ctx, f.untouched.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Name != "Asha Rao" || got.Phone != "9876543210" || got.IsAnonymous {
+			t.Errorf("other donor's donation changed: %+v", got)
+		}
+	})
+
+	if _, err := repo.EraseUser(ctx, f.donorID, time.Now()); !errors.Is(err, ErrAlreadyErased) {
+		t.Errorf("second EraseUser() error = %v, want ErrAlreadyErased", err)
+	}
+}
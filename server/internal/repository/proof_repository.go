@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"server/internal/models"
 
@@ -17,7 +18,7 @@ type ProofSessionRepository interface {
 type ProofImageRepository interface {
 	Create(ctx context.Context, img *models.ProofImage) error
 	ExistsBySessionIDAndHash(ctx context.Context, sessionID uuid.UUID, imageHash string) (bool, error)
-	GetBySessionID(ctx context.Context, sessionID uuid.UUID) ([]*models.ProofImage, error)
+	GetBySessionID(ctx context.Context, sessionID uuid.UUID, page models.CursorParams) (*models.Page[*models.ProofImage], error)
 	UpdateAIResultsAndMedia(ctx context.Context, imageID uuid.UUID, mediaPath string, finalScore *float64, validationStatus *string) error
 }
 
@@ -107,17 +108,19 @@ func (r *proofImageRepository) ExistsBySessionIDAndHash(ctx context.Context, ses
 	return true, nil
 }
 
-func (r *proofImageRepository) GetBySessionID(ctx context.Context, sessionID uuid.UUID) ([]*models.ProofImage, error) {
+func (r *proofImageRepository) GetBySessionID(ctx context.Context, sessionID uuid.UUID, page models.CursorParams) (*models.Page[*models.ProofImage], error) {
+	// Oldest first, in upload order.
+	pageWhere, orderBy, pageArgs := keyset(page, "created_at", "id", false, 2)
+
 	query := `
 		SELECT
 			id, session_id, image_hash, ipfs_cid, latitude, longitude, timestamp,
 			metadata_score, final_score, verification_status, created_at
 		FROM proof_images
-		WHERE session_id = $1
-		ORDER BY created_at ASC
-	`
+		WHERE session_id = $1 AND ` + pageWhere + `
+		ORDER BY ` + orderBy
 
-	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	rows, err := r.db.QueryContext(ctx, query, append([]interface{}{sessionID}, pageArgs...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -143,7 +146,13 @@ func (r *proofImageRepository) GetBySessionID(ctx context.Context, sessionID uui
 		}
 		results = append(results, img)
 	}
-	return results, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.Paginate(results, page, func(img *models.ProofImage) (time.Time, uuid.UUID) {
+		return img.CreatedAt, img.ID
+	}), nil
 }
 
 func (r *proofImageRepository) UpdateAIResultsAndMedia(
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidRefreshToken covers unknown, expired and revoked tokens.
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused is returned when an already used refresh token
+	// is presented again. The session has been revoked by then.
+	ErrRefreshTokenReused = errors.New("refresh token was already used, session revoked")
+)
+
+type SessionRepository interface {
+	// Create stores a new session and its first refresh token.
+	Create(ctx context.Context, session *models.Session, token *models.RefreshToken) error
+	// Rotate marks the refresh token with oldHash used and stores next in
+	// the same session, returning the session. Reuse of a used token
+	// revokes the session and returns ErrRefreshTokenReused.
+	Rotate(ctx context.Context, oldHash string, next *models.RefreshToken, client models.ClientInfo) (*models.Session, error)
+
+	GetByRefreshToken(ctx context.Context, tokenHash string) (*models.Session, error)
+	// IsActive reports whether the session exists, is not revoked and has
+	// not expired.
+	IsActive(ctx context.Context, id uuid.UUID) (bool, error)
+	ListActive(ctx context.Context, userID uuid.UUID) ([]*models.Session, error)
+
+	Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID, reason string) error
+	RevokeAll(ctx context.Context, userID uuid.UUID, reason string) (int64, error)
+}
+
+type sessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+const sessionColumns = `
+	s.id, s.user_id, s.user_agent, s.ip_address, s.created_at, s.last_used_at,
+	s.expires_at, s.revoked_at, s.revoked_reason`
+
+func scanSession(row rowScanner) (*models.Session, error) {
+	s := &models.Session{}
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.UserAgent,
+		&s.IPAddress,
+		&s.CreatedAt,
+		&s.LastUsedAt,
+		&s.ExpiresAt,
+		&s.RevokedAt,
+		&s.RevokedReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func insertRefreshToken(ctx context.Context, db execer, token *models.RefreshToken) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO refresh_tokens (id, session_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, token.ID, token.SessionID, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	return err
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *models.Session, token *models.RefreshToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO auth_sessions (id, user_id, user_agent, ip_address, created_at, last_used_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		session.ID,
+		session.UserID,
+		session.UserAgent,
+		session.IPAddress,
+		session.CreatedAt,
+		session.LastUsedAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertRefreshToken(ctx, tx, token); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *sessionRepository) Rotate(ctx context.Context, oldHash string, next *models.RefreshToken, client models.ClientInfo) (*models.Session, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Locking the token serializes concurrent refreshes with the same token:
+	// the second one sees used_at set and is treated as reuse.
+	var (
+		tokenID   uuid.UUID
+		usedAt    *time.Time
+		expiresAt time.Time
+	)
+	row := tx.QueryRowContext(ctx, `
+		SELECT rt.id, rt.used_at, rt.expires_at, `+sessionColumns+`
+		FROM refresh_tokens rt
+		JOIN auth_sessions s ON s.id = rt.session_id
+		WHERE rt.token_hash = $1
+		FOR UPDATE OF rt, s
+	`, oldHash)
+
+	session := &models.Session{}
+	err = row.Scan(
+		&tokenID,
+		&usedAt,
+		&expiresAt,
+		&session.ID,
+		&session.UserID,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.CreatedAt,
+		&session.LastUsedAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+		&session.RevokedReason,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if session.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if usedAt != nil {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE auth_sessions SET revoked_at = $2, revoked_reason = $3 WHERE id = $1
+		`, session.ID, now, models.SessionRevokedTokenReuse)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if !expiresAt.After(now) || !session.ExpiresAt.After(now) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE refresh_tokens SET used_at = $2 WHERE id = $1`, tokenID, now); err != nil {
+		return nil, err
+	}
+
+	next.SessionID = session.ID
+	if err := insertRefreshToken(ctx, tx, next); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE auth_sessions
+		SET last_used_at = $2,
+			expires_at = $3,
+			user_agent = COALESCE(NULLIF($4, ''), user_agent),
+			ip_address = COALESCE(NULLIF($5, ''), ip_address)
+		WHERE id = $1
+	`, session.ID, now, next.ExpiresAt, client.UserAgent, client.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	session.LastUsedAt = now
+	session.ExpiresAt = next.ExpiresAt
+	return session, nil
+}
+
+func (r *sessionRepository) GetByRefreshToken(ctx context.Context, tokenHash string) (*models.Session, error) {
+	return scanSession(r.db.QueryRowContext(ctx, `
+		SELECT `+sessionColumns+`
+		FROM refresh_tokens rt
+		JOIN auth_sessions s ON s.id = rt.session_id
+		WHERE rt.token_hash = $1
+	`, tokenHash))
+}
+
+func (r *sessionRepository) IsActive(ctx context.Context, id uuid.UUID) (bool, error) {
+	var active bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM auth_sessions
+			WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		)
+	`, id).Scan(&active)
+	return active, err
+}
+
+func (r *sessionRepository) ListActive(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+sessionColumns+`
+		FROM auth_sessions s
+		WHERE s.user_id = $1 AND s.revoked_at IS NULL AND s.expires_at > NOW()
+		ORDER BY s.last_used_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]*models.Session, 0)
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID, reason string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE auth_sessions
+		SET revoked_at = NOW(), revoked_reason = $3
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`, id, userID, reason)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *sessionRepository) RevokeAll(ctx context.Context, userID uuid.UUID, reason string) (int64, error) {
+	return revokeAllSessions(ctx, r.db, userID, reason)
+}
+
+// revokeAllSessions signs the user out everywhere, so other repositories
+// can do it in their own transactions.
+func revokeAllSessions(ctx context.Context, ex execer, userID uuid.UUID, reason string) (int64, error) {
+	result, err := ex.ExecContext(ctx, `
+		UPDATE auth_sessions
+		SET revoked_at = NOW(), revoked_reason = $2
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`, userID, reason)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
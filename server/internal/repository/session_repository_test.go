@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func newTestSession(t *testing.T, repo SessionRepository, userID uuid.UUID, tokenHash string, expiresAt time.Time) *models.Session {
+	t.Helper()
+	now := time.Now()
+	session := &models.Session{ID: uuid.New(), UserID: userID, CreatedAt: now, LastUsedAt: now, ExpiresAt: now.Add(time.Hour)}
+	token := &models.RefreshToken{ID: uuid.New(), SessionID: session.ID, TokenHash: tokenHash, ExpiresAt: expiresAt, CreatedAt: now}
+	if err := repo.Create(context.Background(), session, token); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return session
+}
+
+func nextToken(hash string) *models.RefreshToken {
+	return &models.RefreshToken{ID: uuid.New(), TokenHash: hash, ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now()}
+}
+
+func hashOf(c byte) string {
+	hash := make([]byte, 64)
+	for i := range hash {
+		hash[i] = c
+	}
+	return string(hash)
+}
+
+func TestSessionRotate(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	if _, err := db.ExecContext(ctx, `INSERT INTO users (id, name, email) VALUES ($1, 'Asha', 'asha@example.com')`, userID); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewSessionRepository(db)
+
+	t.Run("rotates and extends the session", func(t *testing.T) {
+		session := newTestSession(t, repo, userID, hashOf('a'), time.Now().Add(time.Hour))
+		next := nextToken(hashOf('b'))
+
+		got, err := repo.Rotate(ctx, hashOf('a'), next, models.ClientInfo{UserAgent: "Firefox"})
+		if err != nil {
+			t.Fatalf("Rotate() error = %v", err)
+		}
+		if got.ID != session.ID || next.SessionID != session.ID {
+			t.Errorf("Rotate() session = %s, next token in %s, want %s", got.ID, next.SessionID, session.ID)
+		}
+		if !got.ExpiresAt.Equal(next.ExpiresAt) {
+			t.Errorf("session expires at %v, want it extended to %v", got.ExpiresAt, next.ExpiresAt)
+		}
+		if _, err := repo.Rotate(ctx, hashOf('b'), nextToken(hashOf('c')), models.ClientInfo{}); err != nil {
+			t.Errorf("Rotate() with the new token error = %v", err)
+		}
+	})
+
+	t.Run("reuse revokes the session", func(t *testing.T) {
+		session := newTestSession(t, repo, userID, hashOf('d'), time.Now().Add(time.Hour))
+		if _, err := repo.Rotate(ctx, hashOf('d'), nextToken(hashOf('e')), models.ClientInfo{}); err != nil {
+			t.Fatalf("Rotate() error = %v", err)
+		}
+
+		if _, err := repo.Rotate(ctx, hashOf('d'), nextToken(hashOf('f')), models.ClientInfo{}); !errors.Is(err, ErrRefreshTokenReused) {
+			t.Fatalf("Rotate() with a used token error = %v, want ErrRefreshTokenReused", err)
+		}
+		if active, _ := repo.IsActive(ctx, session.ID); active {
+			t.Error("session still active after token reuse")
+		}
+		var reason string
+		db.QueryRowContext(ctx, `SELECT revoked_reason FROM auth_sessions WHERE id = $1`, session.ID).Scan(&reason)
+		if reason != models.SessionRevokedTokenReuse {
+			t.Errorf("revoked_reason = %q, want %q", reason, models.SessionRevokedTokenReuse)
+		}
+		if _, err := repo.Rotate(ctx, hashOf('e'), nextToken(hashOf('g')), models.ClientInfo{}); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("Rotate() with the newer token error = %v, want ErrInvalidRefreshToken", err)
+		}
+	})
+
+	t.Run("expired and unknown tokens are invalid", func(t *testing.T) {
+		newTestSession(t, repo, userID, hashOf('h'), time.Now().Add(-time.Minute))
+		if _, err := repo.Rotate(ctx, hashOf('h'), nextToken(hashOf('i')), models.ClientInfo{}); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("Rotate() with an expired token error = %v, want ErrInvalidRefreshToken", err)
+		}
+		if _, err := repo.Rotate(ctx, hashOf('z'), nextToken(hashOf('j')), models.ClientInfo{}); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("Rotate() with an unknown token error = %v, want ErrInvalidRefreshToken", err)
+		}
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		session := newTestSession(t, repo, userID, hashOf('k'), time.Now().Add(time.Hour))
+		if err := repo.Revoke(ctx, uuid.New(), session.ID, models.SessionRevokedByUser); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Revoke() as another user error = %v, want sql.ErrNoRows", err)
+		}
+		if err := repo.Revoke(ctx, userID, session.ID, models.SessionRevokedByUser); err != nil {
+			t.Fatalf("Revoke() error = %v", err)
+		}
+		if _, err := repo.Rotate(ctx, hashOf('k'), nextToken(hashOf('l')), models.ClientInfo{}); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("Rotate() after revoke error = %v, want ErrInvalidRefreshToken", err)
+		}
+	})
+}
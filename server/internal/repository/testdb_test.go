@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"server/internal/pii"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// newTestDB starts a throwaway Postgres with every migration applied. Tests
+// that use it are skipped when Docker is not available.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	migrations, err := filepath.Glob("../../db/migrations/*.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(migrations)
+
+	ctx := context.Background()
+	container, err := postgres.Run(ctx, "postgres:latest",
+		postgres.WithDatabase("charitylight"),
+		postgres.WithUsername("user"),
+		postgres.WithPassword("password"),
+		postgres.WithOrderedInitScripts(migrations...),
+		postgres.BasicWaitStrategies(),
+	)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("could not start postgres container: %v", err)
+	}
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newTestCipher(t *testing.T) *pii.Cipher {
+	t.Helper()
+	keys, err := pii.NewStaticKeyProvider("test", map[string][]byte{"test": bytes.Repeat([]byte{7}, 32)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pii.NewCipher(keys)
+}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"server/internal/models"
+	"server/internal/pii"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTwoFactorAlreadyEnabled is returned when starting enrollment for a
+	// user who already has 2FA on.
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrInvalidLoginChallenge covers unknown, expired, completed and
+	// exhausted sign-in challenges.
+	ErrInvalidLoginChallenge = errors.New("sign-in has expired, sign in again")
+)
+
+type TwoFactorRepository interface {
+	// Get returns sql.ErrNoRows if the user has never started enrollment.
+	Get(ctx context.Context, userID uuid.UUID) (*models.TwoFactor, error)
+	// SavePending stores a new secret awaiting confirmation, replacing any
+	// earlier unconfirmed one.
+	SavePending(ctx context.Context, userID uuid.UUID, secret string) error
+	// Enable confirms enrollment at step and replaces the recovery codes.
+	Enable(ctx context.Context, userID uuid.UUID, step int64, codeHashes []string) error
+	Disable(ctx context.Context, userID uuid.UUID) error
+
+	// UseStep records step as used and reports false if it, or a later
+	// step, was already used.
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	// UseRecoveryCode marks a recovery code used and reports false if it
+	// doesn't exist or was used before.
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error)
+
+	CreateChallenge(ctx context.Context, challenge *models.LoginChallenge) error
+	// GetOpenChallenge returns a challenge that is neither completed,
+	// expired nor out of attempts, or ErrInvalidLoginChallenge.
+	GetOpenChallenge(ctx context.Context, tokenHash string) (*models.LoginChallenge, error)
+	// RecordChallengeAttempt counts one code entered against the challenge.
+	RecordChallengeAttempt(ctx context.Context, id uuid.UUID) error
+	CompleteChallenge(ctx context.Context, id uuid.UUID) error
+}
+
+type twoFactorRepository struct {
+	db     *sql.DB
+	cipher *pii.Cipher
+}
+
+func NewTwoFactorRepository(db *sql.DB, cipher *pii.Cipher) TwoFactorRepository {
+	return &twoFactorRepository{db: db, cipher: cipher}
+}
+
+func (r *twoFactorRepository) Get(ctx context.Context, userID uuid.UUID) (*models.TwoFactor, error) {
+	tf := &models.TwoFactor{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT user_id, secret, enabled_at, last_used_step, created_at, updated_at
+		FROM user_two_factor
+		WHERE user_id = $1
+	`, userID).Scan(
+		&tf.UserID,
+		&tf.Secret,
+		&tf.EnabledAt,
+		&tf.LastUsedStep,
+		&tf.CreatedAt,
+		&tf.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if tf.Secret, err = r.cipher.Decrypt(tf.Secret); err != nil {
+		return nil, err
+	}
+	return tf, nil
+}
+
+func (r *twoFactorRepository) SavePending(ctx context.Context, userID uuid.UUID, secret string) error {
+	encrypted, err := r.cipher.Encrypt(secret)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_two_factor (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = NULL, updated_at = NOW()
+		WHERE user_two_factor.enabled_at IS NULL
+	`, userID, encrypted)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrTwoFactorAlreadyEnabled
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) Enable(ctx context.Context, userID uuid.UUID, step int64, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE user_two_factor
+		SET enabled_at = NOW(), last_used_step = $2, updated_at = NOW()
+		WHERE user_id = $1 AND enabled_at IS NULL
+	`, userID, step)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrTwoFactorAlreadyEnabled
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *twoFactorRepository) Disable(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM two_factor_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_two_factor WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *twoFactorRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE user_two_factor
+		SET last_used_step = $2, updated_at = NOW()
+		WHERE user_id = $1 AND (last_used_step IS NULL OR last_used_step < $2)
+	`, userID, step)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+func (r *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func replaceRecoveryCodes(ctx context.Context, db execer, userID uuid.UUID, codeHashes []string) error {
+	if _, err := db.ExecContext(ctx, `DELETE FROM two_factor_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, hash := range codeHashes {
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO two_factor_recovery_codes (user_id, code_hash) VALUES ($1, $2)
+		`, userID, hash)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE two_factor_recovery_codes
+		SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+func (r *twoFactorRepository) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM two_factor_recovery_codes WHERE user_id = $1 AND used_at IS NULL
+	`, userID).Scan(&count)
+	return count, err
+}
+
+func (r *twoFactorRepository) CreateChallenge(ctx context.Context, challenge *models.LoginChallenge) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO login_challenges (id, user_id, token_hash, enrollment_required, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`,
+		challenge.ID,
+		challenge.UserID,
+		challenge.TokenHash,
+		challenge.EnrollmentRequired,
+		challenge.ExpiresAt,
+		challenge.CreatedAt,
+	)
+	return err
+}
+
+func (r *twoFactorRepository) GetOpenChallenge(ctx context.Context, tokenHash string) (*models.LoginChallenge, error) {
+	c := &models.LoginChallenge{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, user_id, token_hash, enrollment_required, attempts, expires_at, completed_at, created_at
+		FROM login_challenges
+		WHERE token_hash = $1 AND completed_at IS NULL AND expires_at > NOW() AND attempts < $2
+	`, tokenHash, models.MaxLoginChallengeAttempts).Scan(
+		&c.ID,
+		&c.UserID,
+		&c.TokenHash,
+		&c.EnrollmentRequired,
+		&c.Attempts,
+		&c.ExpiresAt,
+		&c.CompletedAt,
+		&c.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidLoginChallenge
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *twoFactorRepository) RecordChallengeAttempt(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE login_challenges
+		SET attempts = attempts + 1
+		WHERE id = $1 AND completed_at IS NULL AND expires_at > NOW() AND attempts < $2
+	`, id, models.MaxLoginChallengeAttempts)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrInvalidLoginChallenge
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) CompleteChallenge(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE login_challenges SET completed_at = NOW()
+		WHERE id = $1 AND completed_at IS NULL
+	`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrInvalidLoginChallenge
+	}
+	return nil
+}
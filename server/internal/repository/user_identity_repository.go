@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrIdentityLinked is returned when linking a provider account that is
+// already linked, or a second account from the same provider.
+var ErrIdentityLinked = errors.New("this account is already linked")
+
+type UserIdentityRepository interface {
+	// Create returns ErrIdentityLinked if the provider account, or another
+	// account from the same provider for this user, is already linked.
+	Create(ctx context.Context, identity *models.UserIdentity) error
+	// CreateWithUser creates a user and links their first identity in one
+	// transaction, so a failed link leaves no account behind.
+	CreateWithUser(ctx context.Context, user *models.User, identity *models.UserIdentity) error
+	// GetByProviderUserID returns sql.ErrNoRows if the account isn't linked.
+	GetByProviderUserID(ctx context.Context, provider, providerUserID string) (*models.UserIdentity, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserIdentity, error)
+	// Delete returns sql.ErrNoRows if the user has no identity from provider.
+	Delete(ctx context.Context, userID uuid.UUID, provider string) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+type userIdentityRepository struct {
+	db *sql.DB
+}
+
+func NewUserIdentityRepository(db *sql.DB) UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+const userIdentityColumns = `id, user_id, provider, provider_user_id, email, last_used_at, created_at`
+
+func scanUserIdentity(row rowScanner) (*models.UserIdentity, error) {
+	i := &models.UserIdentity{}
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.ProviderUserID,
+		&i.Email,
+		&i.LastUsedAt,
+		&i.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	return insertUserIdentity(ctx, r.db, identity)
+}
+
+func (r *userIdentityRepository) CreateWithUser(ctx context.Context, user *models.User, identity *models.UserIdentity) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertUser(ctx, tx, user); err != nil {
+		return err
+	}
+	if err := insertUserIdentity(ctx, tx, identity); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertUserIdentity(ctx context.Context, db execer, identity *models.UserIdentity) error {
+	result, err := db.ExecContext(ctx, `
+		INSERT INTO user_identities (id, user_id, provider, provider_user_id, email, last_used_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT DO NOTHING
+	`,
+		identity.ID,
+		identity.UserID,
+		identity.Provider,
+		identity.ProviderUserID,
+		identity.Email,
+		identity.LastUsedAt,
+		identity.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrIdentityLinked
+	}
+	return nil
+}
+
+func (r *userIdentityRepository) GetByProviderUserID(ctx context.Context, provider, providerUserID string) (*models.UserIdentity, error) {
+	return scanUserIdentity(r.db.QueryRowContext(ctx, `
+		SELECT `+userIdentityColumns+`
+		FROM user_identities
+		WHERE provider = $1 AND provider_user_id = $2
+	`, provider, providerUserID))
+}
+
+func (r *userIdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserIdentity, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+userIdentityColumns+`
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := []*models.UserIdentity{}
+	for rows.Next() {
+		i, err := scanUserIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, i)
+	}
+	return identities, rows.Err()
+}
+
+func (r *userIdentityRepository) Delete(ctx context.Context, userID uuid.UUID, provider string) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM user_identities WHERE user_id = $1 AND provider = $2
+	`, userID, provider)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *userIdentityRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE user_identities SET last_used_at = $2 WHERE id = $1`, id, at)
+	return err
+}
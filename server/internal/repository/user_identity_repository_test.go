@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateWithUserRollsBackFailedLink(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	users, identities := NewUserRepository(db), NewUserIdentityRepository(db)
+
+	newUser := func(email string) *models.User {
+		return &models.User{ID: uuid.New(), Name: "Asha Rao", Email: email, Provider: "github", IsActive: true, IsVerified: true, Role: string(models.RoleTypeUser)}
+	}
+	newIdentity := func(userID uuid.UUID) *models.UserIdentity {
+		now := time.Now()
+		return &models.UserIdentity{ID: uuid.New(), UserID: userID, Provider: "github", ProviderUserID: "12345", LastUsedAt: &now, CreatedAt: now}
+	}
+
+	first := newUser("asha@example.com")
+	if err := identities.CreateWithUser(ctx, first, newIdentity(first.ID)); err != nil {
+		t.Fatalf("CreateWithUser() error = %v", err)
+	}
+
+	// The same GitHub account can't be linked again, and the user created
+	// for it must go with the failed link.
+	second := newUser("asha.rao@example.com")
+	if err := identities.CreateWithUser(ctx, second, newIdentity(second.ID)); !errors.Is(err, ErrIdentityLinked) {
+		t.Fatalf("CreateWithUser() for a linked account error = %v, want ErrIdentityLinked", err)
+	}
+	if _, err := users.GetByEmail(ctx, second.Email); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("failed link left user %s behind: %v", second.Email, err)
+	}
+
+	linked, err := identities.GetByProviderUserID(ctx, "github", "12345")
+	if err != nil || linked.UserID != first.ID {
+		t.Errorf("GitHub account linked to %v (%v), want %s", linked, err, first.ID)
+	}
+}
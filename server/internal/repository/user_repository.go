@@ -29,12 +29,16 @@ func NewUserRepository(db *sql.DB) UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	return insertUser(ctx, r.db, user)
+}
+
+func insertUser(ctx context.Context, db execer, user *models.User) error {
 	query := `
 		INSERT INTO users (id, name, email, password_hash, provider, provider_id, avatar_url, is_active, is_verified, role)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := db.ExecContext(ctx, query,
 		user.ID,
 		user.Name,
 		user.Email,
@@ -0,0 +1,360 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"server/internal/models"
+	"server/internal/pii"
+
+	"github.com/google/uuid"
+)
+
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	ListEndpoints(ctx context.Context, organizationID uuid.UUID) ([]*models.WebhookEndpoint, error)
+	CountEndpoints(ctx context.Context, organizationID uuid.UUID) (int, error)
+	// GetEndpoint returns sql.ErrNoRows if the organization has no endpoint
+	// with this ID.
+	GetEndpoint(ctx context.Context, organizationID, endpointID uuid.UUID) (*models.WebhookEndpoint, error)
+	// GetEndpointByID looks an endpoint up for sending, whatever its
+	// organization.
+	GetEndpointByID(ctx context.Context, endpointID uuid.UUID) (*models.WebhookEndpoint, error)
+	UpdateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	// DeleteEndpoint deletes the endpoint and its delivery log, or returns
+	// sql.ErrNoRows.
+	DeleteEndpoint(ctx context.Context, organizationID, endpointID uuid.UUID) error
+	// ListSubscribedEndpoints lists the organization's active endpoints
+	// that want eventType.
+	ListSubscribedEndpoints(ctx context.Context, organizationID uuid.UUID, eventType models.WebhookEventType) ([]*models.WebhookEndpoint, error)
+
+	CreateDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error
+	// ClaimDueDeliveries takes up to limit pending deliveries that are due
+	// and whose endpoint is active, and holds them for lease so that no
+	// other server sends them meanwhile.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error)
+	// RecordAttempt saves the outcome of sending a delivery.
+	RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+	// ListDeliveries lists an endpoint's deliveries, newest first.
+	ListDeliveries(ctx context.Context, endpointID uuid.UUID, page models.CursorParams) (*models.Page[*models.WebhookDelivery], error)
+	// ReplayDelivery queues a delivery to be sent again from its first
+	// attempt, or returns sql.ErrNoRows.
+	ReplayDelivery(ctx context.Context, endpointID, deliveryID uuid.UUID, now time.Time) (*models.WebhookDelivery, error)
+}
+
+type webhookRepository struct {
+	db     *sql.DB
+	cipher *pii.Cipher
+}
+
+func NewWebhookRepository(db *sql.DB, cipher *pii.Cipher) WebhookRepository {
+	return &webhookRepository{db: db, cipher: cipher}
+}
+
+const webhookEndpointColumns = `id, organization_id, url, description, event_types, secret, is_active, created_by, created_at, updated_at`
+
+func (r *webhookRepository) scanEndpoint(row rowScanner) (*models.WebhookEndpoint, error) {
+	e := &models.WebhookEndpoint{}
+	var eventTypes string
+	err := row.Scan(
+		&e.ID,
+		&e.OrganizationID,
+		&e.URL,
+		&e.Description,
+		&eventTypes,
+		&e.Secret,
+		&e.IsActive,
+		&e.CreatedBy,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	e.EventTypes = []models.WebhookEventType{}
+	for _, t := range strings.Fields(eventTypes) {
+		e.EventTypes = append(e.EventTypes, models.WebhookEventType(t))
+	}
+	if e.Secret, err = r.cipher.Decrypt(e.Secret); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *webhookRepository) listEndpoints(ctx context.Context, query string, args ...interface{}) ([]*models.WebhookEndpoint, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	endpoints := []*models.WebhookEndpoint{}
+	for rows.Next() {
+		e, err := r.scanEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, e)
+	}
+	return endpoints, rows.Err()
+}
+
+func joinEventTypes(types []models.WebhookEventType) string {
+	parts := make([]string, len(types))
+	for i, t := range types {
+		parts[i] = string(t)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (r *webhookRepository) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	secret, err := r.cipher.Encrypt(endpoint.Secret)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO webhook_endpoints (
+			id, organization_id, url, description, event_types, secret, is_active,
+			created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		endpoint.ID,
+		endpoint.OrganizationID,
+		endpoint.URL,
+		endpoint.Description,
+		joinEventTypes(endpoint.EventTypes),
+		secret,
+		endpoint.IsActive,
+		endpoint.CreatedBy,
+		endpoint.CreatedAt,
+		endpoint.UpdatedAt,
+	)
+	return err
+}
+
+func (r *webhookRepository) ListEndpoints(ctx context.Context, organizationID uuid.UUID) ([]*models.WebhookEndpoint, error) {
+	return r.listEndpoints(ctx, `
+		SELECT `+webhookEndpointColumns+`
+		FROM webhook_endpoints
+		WHERE organization_id = $1
+		ORDER BY created_at DESC
+	`, organizationID)
+}
+
+func (r *webhookRepository) CountEndpoints(ctx context.Context, organizationID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM webhook_endpoints WHERE organization_id = $1
+	`, organizationID).Scan(&count)
+	return count, err
+}
+
+func (r *webhookRepository) GetEndpoint(ctx context.Context, organizationID, endpointID uuid.UUID) (*models.WebhookEndpoint, error) {
+	return r.scanEndpoint(r.db.QueryRowContext(ctx, `
+		SELECT `+webhookEndpointColumns+`
+		FROM webhook_endpoints
+		WHERE id = $1 AND organization_id = $2
+	`, endpointID, organizationID))
+}
+
+func (r *webhookRepository) GetEndpointByID(ctx context.Context, endpointID uuid.UUID) (*models.WebhookEndpoint, error) {
+	return r.scanEndpoint(r.db.QueryRowContext(ctx, `
+		SELECT `+webhookEndpointColumns+`
+		FROM webhook_endpoints
+		WHERE id = $1
+	`, endpointID))
+}
+
+func (r *webhookRepository) UpdateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_endpoints
+		SET url = $3, description = $4, event_types = $5, is_active = $6, updated_at = $7
+		WHERE id = $1 AND organization_id = $2
+	`,
+		endpoint.ID,
+		endpoint.OrganizationID,
+		endpoint.URL,
+		endpoint.Description,
+		joinEventTypes(endpoint.EventTypes),
+		endpoint.IsActive,
+		endpoint.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *webhookRepository) DeleteEndpoint(ctx context.Context, organizationID, endpointID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM webhook_endpoints WHERE id = $1 AND organization_id = $2
+	`, endpointID, organizationID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *webhookRepository) ListSubscribedEndpoints(ctx context.Context, organizationID uuid.UUID, eventType models.WebhookEventType) ([]*models.WebhookEndpoint, error) {
+	return r.listEndpoints(ctx, `
+		SELECT `+webhookEndpointColumns+`
+		FROM webhook_endpoints
+		WHERE organization_id = $1
+		  AND is_active
+		  AND ' ' || event_types || ' ' LIKE '% ' || $2::text || ' %'
+	`, organizationID, string(eventType))
+}
+
+const webhookDeliveryColumns = `id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at, last_status_code, last_error, delivered_at, created_at`
+
+func scanWebhookDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	d := &models.WebhookDelivery{}
+	var payload []byte
+	err := row.Scan(
+		&d.ID,
+		&d.EndpointID,
+		&d.EventID,
+		&d.EventType,
+		&payload,
+		&d.Status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&d.LastAttemptAt,
+		&d.LastStatusCode,
+		&d.LastError,
+		&d.DeliveredAt,
+		&d.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	return d, nil
+}
+
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, d := range deliveries {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO webhook_deliveries (id, endpoint_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`,
+			d.ID,
+			d.EndpointID,
+			d.EventID,
+			d.EventType,
+			string(d.Payload),
+			d.Status,
+			d.NextAttemptAt,
+			d.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhook_endpoints e ON e.id = d.endpoint_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND e.is_active
+			ORDER BY d.next_attempt_at
+			LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING `+webhookDeliveryColumns,
+		now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *webhookRepository) RecordAttempt(ctx context.Context, d *models.WebhookDelivery) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_attempt_at = $5,
+		    last_status_code = $6, last_error = $7, delivered_at = $8
+		WHERE id = $1
+	`,
+		d.ID,
+		d.Status,
+		d.Attempts,
+		d.NextAttemptAt,
+		d.LastAttemptAt,
+		d.LastStatusCode,
+		d.LastError,
+		d.DeliveredAt,
+	)
+	return err
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, endpointID uuid.UUID, page models.CursorParams) (*models.Page[*models.WebhookDelivery], error) {
+	pageWhere, orderBy, pageArgs := keyset(page, "created_at", "id", true, 2)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries
+		WHERE endpoint_id = $1 AND `+pageWhere+`
+		ORDER BY `+orderBy,
+		append([]interface{}{endpointID}, pageArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.Paginate(deliveries, page, func(d *models.WebhookDelivery) (time.Time, uuid.UUID) {
+		return d.CreatedAt, d.ID
+	}), nil
+}
+
+func (r *webhookRepository) ReplayDelivery(ctx context.Context, endpointID, deliveryID uuid.UUID, now time.Time) (*models.WebhookDelivery, error) {
+	return scanWebhookDelivery(r.db.QueryRowContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = $3,
+		    last_error = NULL, last_status_code = NULL, delivered_at = NULL
+		WHERE id = $1 AND endpoint_id = $2
+		RETURNING `+webhookDeliveryColumns,
+		deliveryID, endpointID, now))
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"server/internal/config"
 	"server/internal/handlers"
+	"server/internal/middleware"
 	"server/internal/services"
 
 	"log"
@@ -18,8 +19,8 @@ func (s *Server) registerPaymentRoutes(r chi.Router) {
 		log.Println("warning: RAZORPAY_KEY_ID/RAZORPAY_KEY_SECRET not set; payment endpoints may not work")
 	}
 	ps := services.NewPaymentService(rzp.KeyID, rzp.KeySecret)
-	ph := handlers.NewPaymentHandler(ps, rzp.KeyID)
+	ph := handlers.NewPaymentHandler(ps, s.accountService, rzp.KeyID)
 
-	r.Post("/api/payment/create-order", ph.CreateOrder)
+	r.With(middleware.OptionalAuthMiddleware(s.jwtService, s.apiKeyService)).Post("/api/payment/create-order", ph.CreateOrder)
 	r.Post("/api/payment/verify", ph.VerifyPayment)
 }
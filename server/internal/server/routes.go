@@ -12,7 +12,7 @@ import (
 	"github.com/go-chi/cors"
 )
 
-func (s *Server) RegisterRoutes(authHandler *handlers.AuthHandler, causeHandler *handlers.CauseHandler, donationHandler *handlers.DonationHandler, proofHandler *handlers.ProofHandler, disbursementHandler *handlers.DisbursementHandler, adminHandler *handlers.AdminHandler) http.Handler {
+func (s *Server) RegisterRoutes(authHandler *handlers.AuthHandler, causeHandler *handlers.CauseHandler, donationHandler *handlers.DonationHandler, proofHandler *handlers.ProofHandler, disbursementHandler *handlers.DisbursementHandler, adminHandler *handlers.AdminHandler, goodsPledgeHandler *handlers.GoodsPledgeHandler, fundraiserHandler *handlers.FundraiserHandler, privacyHandler *handlers.PrivacyHandler, organizationMemberHandler *handlers.OrganizationMemberHandler, apiKeyHandler *handlers.APIKeyHandler, webhookHandler *handlers.WebhookHandler, disputeHandler *handlers.DisputeHandler, notificationHandler *handlers.NotificationHandler) http.Handler {
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
 
@@ -48,6 +48,24 @@ func (s *Server) RegisterRoutes(authHandler *handlers.AuthHandler, causeHandler
 	// Register admin routes
 	adminHandler.RegisterRoutes(r)
 
+	// Register in-kind goods pledge routes
+	goodsPledgeHandler.RegisterRoutes(r)
+
+	// Register peer-to-peer fundraiser routes
+	fundraiserHandler.RegisterRoutes(r)
+
+	// Register personal data export and erasure routes
+	privacyHandler.RegisterRoutes(r)
+
+	// Register organization team and invitation routes
+	organizationMemberHandler.RegisterRoutes(r)
+
+	// Register organization API key management routes
+	apiKeyHandler.RegisterRoutes(r)
+	webhookHandler.RegisterRoutes(r)
+	disputeHandler.RegisterRoutes(r)
+	notificationHandler.RegisterRoutes(r)
+
 	// Serve static files for uploads
 	r.Handle("/uploads/*", http.StripPrefix("/uploads/", http.FileServer(http.Dir("uploads"))))
 
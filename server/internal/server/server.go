@@ -15,6 +15,8 @@ import (
 	"server/internal/config"
 	"server/internal/database"
 	"server/internal/handlers"
+	"server/internal/pii"
+	"server/internal/policy"
 	"server/internal/repository"
 	"server/internal/services"
 )
@@ -22,6 +24,10 @@ import (
 type Server struct {
 	port int
 	db   database.Service
+
+	jwtService     services.JWTService
+	apiKeyService  services.APIKeyService
+	accountService services.AccountService
 }
 
 func NewServer() *http.Server {
@@ -42,25 +48,78 @@ func NewServer() *http.Server {
 		log.Fatal(err)
 	}
 
+	// Donor phone, address and PAN are encrypted at rest
+	piiKeys, err := pii.NewEnvKeyProvider()
+	if err != nil {
+		log.Fatal(err)
+	}
+	piiCipher := pii.NewCipher(piiKeys)
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(sqlDB)
 	organizationRepo := repository.NewOrganizationRepository(sqlDB)
 	causeRepo := repository.NewCauseRepository(sqlDB)
+	causeSearchRepo := repository.NewCauseSearchRepository(sqlDB)
 	causeVoteRepo := repository.NewCauseVoteRepository(sqlDB)
 	causeReviewRepo := repository.NewCauseReviewRepository(sqlDB)
-	donationRepo := repository.NewDonationRepository(sqlDB)
+	donationRepo := repository.NewDonationRepository(sqlDB, piiCipher)
 	proofSessionRepo := repository.NewProofSessionRepository(sqlDB)
 	proofImageRepo := repository.NewProofImageRepository(sqlDB)
 	disbursementRepo := repository.NewDisbursementRepository(sqlDB)
 	adminRepo := repository.NewAdminRepository(sqlDB)
+	analyticsRepo := repository.NewAnalyticsRepository(sqlDB)
+	goodsPledgeRepo := repository.NewGoodsPledgeRepository(sqlDB)
+	matchingCampaignRepo := repository.NewMatchingCampaignRepository(sqlDB)
+	fundraiserRepo := repository.NewFundraiserRepository(sqlDB)
+	piiAccessLogRepo := repository.NewPIIAccessLogRepository(sqlDB)
+	privacyRepo := repository.NewPrivacyRepository(sqlDB, piiCipher)
+	sessionRepo := repository.NewSessionRepository(sqlDB)
+	accountTokenRepo := repository.NewAccountTokenRepository(sqlDB)
+	twoFactorRepo := repository.NewTwoFactorRepository(sqlDB, piiCipher)
+	organizationMemberRepo := repository.NewOrganizationMemberRepository(sqlDB)
+	apiKeyRepo := repository.NewAPIKeyRepository(sqlDB)
+	userIdentityRepo := repository.NewUserIdentityRepository(sqlDB)
+	webhookRepo := repository.NewWebhookRepository(sqlDB, piiCipher)
+	disputeRepo := repository.NewDisputeRepository(sqlDB)
+	notificationRepo := repository.NewNotificationRepository(sqlDB)
 
 	// Initialize services
-	jwtService := services.NewJWTService()
-	authService := services.NewAuthService(userRepo, organizationRepo, jwtService)
-	causeService := services.NewCauseService(causeRepo, organizationRepo)
+	mailer, err := services.NewMailerFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, organizationRepo)
+	jwtService := services.NewJWTService(sessionRepo)
+	sessionService := services.NewSessionService(sessionRepo, userRepo, jwtService)
+	twoFactorService := services.NewTwoFactorService(twoFactorRepo, userRepo, organizationMemberRepo, sessionService)
+	authService := services.NewAuthService(userRepo, organizationRepo, userIdentityRepo, twoFactorService)
+	identityService := services.NewIdentityService(userIdentityRepo, userRepo)
+	accountService := services.NewAccountService(accountTokenRepo, userRepo, mailer)
+	organizationMemberService := services.NewOrganizationMemberService(organizationMemberRepo, organizationRepo, userRepo, mailer)
+	webhookService := services.NewWebhookService(webhookRepo)
+	notificationService := services.NewNotificationService(notificationRepo, organizationMemberRepo, causeRepo, mailer)
+	causeService := services.NewCauseService(causeRepo, organizationRepo, causeSearchRepo, matchingCampaignRepo, webhookService, notificationService)
 	causeVoteService := services.NewCauseVoteService(causeVoteRepo)
-	causeReviewService := services.NewCauseReviewService(causeReviewRepo)
+	causeReviewService := services.NewCauseReviewService(causeReviewRepo, organizationRepo)
 	proofService := services.NewProofService(proofSessionRepo, proofImageRepo, causeRepo)
+	causeLifecycleService := services.NewCauseLifecycleService(causeRepo)
+	goodsPledgeService := services.NewGoodsPledgeService(goodsPledgeRepo, causeRepo)
+	matchingCampaignService := services.NewMatchingCampaignService(matchingCampaignRepo, causeRepo, userRepo)
+	fundraiserService := services.NewFundraiserService(fundraiserRepo, causeRepo, donationRepo)
+	privacyService := services.NewPrivacyService(privacyRepo, userRepo)
+	disputeService := services.NewDisputeService(disputeRepo, causeRepo, webhookService, notificationService)
+
+	// Background jobs run until the HTTP server shuts down
+	background, stopBackground := context.WithCancel(context.Background())
+
+	// Move causes on goal completion and deadline expiry in the background
+	go causeLifecycleService.Start(background)
+
+	// Send and retry organizations' webhook deliveries in the background
+	go webhookService.Start(background)
+
+	// Send and retry queued notification emails in the background
+	go notificationService.Start(background)
 
 	// Initialize blockchain services
 	chainService, err := blockchain.NewDonationChainService(
@@ -81,7 +140,15 @@ func NewServer() *http.Server {
 		// Continue without tracker if not configured
 	}
 
-	donationService := services.NewDonationService(donationRepo, *chainService, trackerService, causeRepo)
+	// A nil *MilestoneTrackerService would make a non-nil interface
+	var tracker services.MilestoneTracker
+	if trackerService != nil {
+		tracker = trackerService
+	}
+	donationService := services.NewDonationService(donationRepo, chainService, tracker, causeRepo, matchingCampaignRepo, fundraiserRepo, mailer, webhookService, notificationService)
+
+	// Retry ledger writes for donations saved while the chain was unreachable
+	go donationService.Start(background)
 
 	// Start milestone tracker event listener if tracker service is available
 	if trackerService != nil {
@@ -91,13 +158,15 @@ func NewServer() *http.Server {
 			disbursementRepo,
 			organizationRepo,
 			causeRepo,
+			webhookService,
+			notificationService,
 		)
 		if err != nil {
 			log.Printf("Warning: Failed to initialize event listener: %v", err)
 		} else {
 			// Start listening for events in a goroutine
 			go func() {
-				if err := eventListener.Start(context.Background()); err != nil {
+				if err := eventListener.Start(background); err != nil {
 					log.Printf("Event listener error: %v", err)
 				}
 			}()
@@ -106,13 +175,22 @@ func NewServer() *http.Server {
 	}
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService, jwtService)
+	authorizer := policy.NewAuthorizer(organizationMemberRepo, organizationRepo)
+	authHandler := handlers.NewAuthHandler(authService, sessionService, accountService, twoFactorService, identityService, authorizer, jwtService, apiKeyService)
 	ipfsService := services.NewIPFSService()
-	causeHandler := handlers.NewCauseHandler(causeService, authService, jwtService, causeVoteService, causeReviewService, ipfsService)
-	donationHandler := handlers.NewDonationHandler(donationService, authService, jwtService)
-	proofHandler := handlers.NewProofHandler(jwtService, proofService, organizationRepo, causeRepo)
-	disbursementHandler := handlers.NewDisbursementHandler(disbursementRepo, organizationRepo, jwtService)
-	adminHandler := handlers.NewAdminHandler(adminRepo, jwtService)
+	causeHandler := handlers.NewCauseHandler(causeService, authorizer, jwtService, apiKeyService, causeVoteService, causeReviewService, ipfsService, causeLifecycleService)
+	donationHandler := handlers.NewDonationHandler(donationService, causeService, authService, accountService, piiAccessLogRepo, authorizer, jwtService, apiKeyService)
+	proofHandler := handlers.NewProofHandler(jwtService, proofService, authorizer, causeRepo)
+	disbursementHandler := handlers.NewDisbursementHandler(disbursementRepo, authorizer, jwtService, apiKeyService)
+	adminHandler := handlers.NewAdminHandler(adminRepo, analyticsRepo, piiAccessLogRepo, causeReviewService, causeLifecycleService, matchingCampaignService, disputeService, authorizer, jwtService)
+	goodsPledgeHandler := handlers.NewGoodsPledgeHandler(goodsPledgeService, authorizer, jwtService)
+	fundraiserHandler := handlers.NewFundraiserHandler(fundraiserService, jwtService)
+	privacyHandler := handlers.NewPrivacyHandler(privacyService, jwtService)
+	organizationMemberHandler := handlers.NewOrganizationMemberHandler(organizationMemberService, jwtService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService, authorizer, jwtService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService, authorizer, jwtService)
+	disputeHandler := handlers.NewDisputeHandler(disputeService, jwtService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService, jwtService)
 
 	// Configure OAuth
 	config.ConfigureOAuth()
@@ -120,6 +198,10 @@ func NewServer() *http.Server {
 	server := &Server{
 		port: port,
 		db:   dbService,
+
+		jwtService:     jwtService,
+		apiKeyService:  apiKeyService,
+		accountService: accountService,
 	}
 
 	// Routes will be registered via RegisterRoutes
@@ -127,11 +209,12 @@ func NewServer() *http.Server {
 	// Declare Server config
 	httpServer := &http.Server{
 		Addr:         fmt.Sprintf(":%d", port),
-		Handler:      server.RegisterRoutes(authHandler, causeHandler, donationHandler, proofHandler, disbursementHandler, adminHandler),
+		Handler:      server.RegisterRoutes(authHandler, causeHandler, donationHandler, proofHandler, disbursementHandler, adminHandler, goodsPledgeHandler, fundraiserHandler, privacyHandler, organizationMemberHandler, apiKeyHandler, webhookHandler, disputeHandler, notificationHandler),
 		IdleTimeout:  time.Minute,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 30 * time.Second,
 	}
+	httpServer.RegisterOnShutdown(stopBackground)
 
 	return httpServer
 }
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultUnverifiedDonationLimit is the largest single donation, in rupees,
+// accepted from a donor who has not verified their email. Override with
+// UNVERIFIED_DONATION_LIMIT.
+const DefaultUnverifiedDonationLimit = 5000.0
+
+// At most this many emails of one kind are sent to an account per hour,
+// whatever the per-IP limits on the endpoints.
+const maxAccountEmailsPerHour = 3
+
+var (
+	// ErrEmailNotVerified is returned for actions that need a verified
+	// email address.
+	ErrEmailNotVerified = errors.New("verify your email address first")
+	// ErrTooManyEmails is returned when an account has been sent too many
+	// verification emails recently.
+	ErrTooManyEmails = errors.New("too many emails sent recently, try again later")
+)
+
+// AccountService handles email verification and password resets, and the
+// limits placed on accounts until their email is verified.
+type AccountService interface {
+	// SendVerificationEmail emails the user a link to verify their address.
+	SendVerificationEmail(ctx context.Context, userID uuid.UUID) error
+	VerifyEmail(ctx context.Context, token string) (*models.User, error)
+
+	// RequestPasswordReset emails a reset link if an email account with this
+	// address exists. It reports success either way, so callers can't probe
+	// which addresses are registered.
+	RequestPasswordReset(ctx context.Context, email string) error
+	// ResetPassword sets a new password and signs the user out everywhere.
+	ResetPassword(ctx context.Context, token string, password string) error
+
+	// CheckDonationAllowed refuses donations above the unverified limit from
+	// users who have not verified their email.
+	CheckDonationAllowed(ctx context.Context, userID uuid.UUID, amount float64) error
+	// RequireVerified refuses users who have not verified their email.
+	RequireVerified(ctx context.Context, userID uuid.UUID) error
+}
+
+type accountService struct {
+	tokenRepo       repository.AccountTokenRepository
+	userRepo        repository.UserRepository
+	mailer          Mailer
+	signer          *accountTokenSigner
+	frontendURL     string
+	unverifiedLimit float64
+}
+
+func NewAccountService(
+	tokenRepo repository.AccountTokenRepository,
+	userRepo repository.UserRepository,
+	mailer Mailer,
+) *accountService {
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:5173"
+	}
+
+	limit := DefaultUnverifiedDonationLimit
+	if v, err := strconv.ParseFloat(os.Getenv("UNVERIFIED_DONATION_LIMIT"), 64); err == nil && v >= 0 {
+		limit = v
+	}
+
+	return &accountService{
+		tokenRepo:       tokenRepo,
+		userRepo:        userRepo,
+		mailer:          mailer,
+		signer:          newAccountTokenSigner(accountTokenSecret()),
+		frontendURL:     strings.TrimRight(frontendURL, "/"),
+		unverifiedLimit: limit,
+	}
+}
+
+func (s *accountService) SendVerificationEmail(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsVerified {
+		return errors.New("email address is already verified")
+	}
+
+	link, err := s.issue(ctx, user, models.AccountTokenEmailVerification, "/verify-email")
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Hi %s,\n\nConfirm your email address for CharityLight by opening this link:\n\n%s\n\nThe link expires in 48 hours. If you didn't create an account, you can ignore this email.\n",
+		user.Name, link,
+	)
+	return s.mailer.Send(ctx, user.Email, "Verify your email address", body)
+}
+
+func (s *accountService) VerifyEmail(ctx context.Context, token string) (*models.User, error) {
+	id, err := s.verify(token, models.AccountTokenEmailVerification)
+	if err != nil {
+		return nil, err
+	}
+
+	// The link verifies the address it was sent to, not whatever the
+	// account uses now.
+	userID, err := s.tokenRepo.VerifyEmail(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepo.GetByID(ctx, userID)
+}
+
+func (s *accountService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, strings.TrimSpace(email))
+	// OAuth-only accounts have no password to reset.
+	if err != nil || user.PasswordHash == nil {
+		return nil
+	}
+
+	link, err := s.issue(ctx, user, models.AccountTokenPasswordReset, "/reset-password")
+	if errors.Is(err, ErrTooManyEmails) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Hi %s,\n\nSomeone asked to reset the password for your CharityLight account. To choose a new password, open this link:\n\n%s\n\nThe link expires in 1 hour and works once. If you didn't ask for this, ignore this email; your password has not changed.\n",
+		user.Name, link,
+	)
+	return s.mailer.Send(ctx, user.Email, "Reset your password", body)
+}
+
+func (s *accountService) ResetPassword(ctx context.Context, token string, password string) error {
+	if len(password) < 6 {
+		return errors.New("password must be at least 6 characters long")
+	}
+
+	id, err := s.verify(token, models.AccountTokenPasswordReset)
+	if err != nil {
+		return err
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	_, err = s.tokenRepo.ResetPassword(ctx, id, string(hashed), time.Now())
+	return err
+}
+
+func (s *accountService) CheckDonationAllowed(ctx context.Context, userID uuid.UUID, amount float64) error {
+	if amount <= s.unverifiedLimit {
+		return nil
+	}
+	if err := s.RequireVerified(ctx, userID); err != nil {
+		return fmt.Errorf("%w to donate more than ₹%.0f at once", err, s.unverifiedLimit)
+	}
+	return nil
+}
+
+func (s *accountService) RequireVerified(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsVerified {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// issue stores a new token for user and returns the frontend link that
+// carries it.
+func (s *accountService) issue(ctx context.Context, user *models.User, purpose models.AccountTokenPurpose, path string) (string, error) {
+	now := time.Now()
+	sent, err := s.tokenRepo.CountSince(ctx, user.ID, purpose, now.Add(-time.Hour))
+	if err != nil {
+		return "", err
+	}
+	if sent >= maxAccountEmailsPerHour {
+		return "", ErrTooManyEmails
+	}
+
+	token := &models.AccountToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Purpose:   purpose,
+		Email:     user.Email,
+		ExpiresAt: now.Add(purpose.TTL()),
+		CreatedAt: now,
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return "", fmt.Errorf("failed to create token: %w", err)
+	}
+
+	return s.frontendURL + path + "?token=" + url.QueryEscape(s.signer.Sign(token)), nil
+}
+
+// verify checks the signature and expiry before the token is looked up.
+func (s *accountService) verify(token string, purpose models.AccountTokenPurpose) (uuid.UUID, error) {
+	id, err := s.signer.Verify(token, purpose, time.Now())
+	if err != nil {
+		return uuid.Nil, repository.ErrInvalidAccountToken
+	}
+	return id, nil
+}
+
+// accountTokenSecret is the key emailed links and other short-lived account
+// tokens are signed with.
+func accountTokenSecret() []byte {
+	secret := os.Getenv("ACCOUNT_TOKEN_SECRET")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	if secret == "" {
+		secret = "your-secret-key" // Default for development
+	}
+	return []byte(secret)
+}
+
+// accountTokenSigner produces the tokens put in email links:
+// base64url(id || expiry) "." base64url(HMAC-SHA256(purpose || id || expiry)).
+type accountTokenSigner struct {
+	secret []byte
+}
+
+func newAccountTokenSigner(secret []byte) *accountTokenSigner {
+	return &accountTokenSigner{secret: secret}
+}
+
+func (s *accountTokenSigner) Sign(token *models.AccountToken) string {
+	payload := make([]byte, 24)
+	copy(payload, token.ID[:])
+	binary.BigEndian.PutUint64(payload[16:], uint64(token.ExpiresAt.Unix()))
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(s.mac(token.Purpose, payload))
+}
+
+// Verify returns the token ID if the signature matches purpose and the
+// token has not expired.
+func (s *accountTokenSigner) Verify(token string, purpose models.AccountTokenPurpose, now time.Time) (uuid.UUID, error) {
+	encodedPayload, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, errors.New("malformed token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != 24 {
+		return uuid.Nil, errors.New("malformed token")
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil {
+		return uuid.Nil, errors.New("malformed token")
+	}
+	if !hmac.Equal(mac, s.mac(purpose, payload)) {
+		return uuid.Nil, errors.New("bad token signature")
+	}
+
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0)
+	if !now.Before(expiresAt) {
+		return uuid.Nil, errors.New("token has expired")
+	}
+
+	id, err := uuid.FromBytes(payload[:16])
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+func (s *accountTokenSigner) mac(purpose models.AccountTokenPurpose, payload []byte) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(purpose))
+	h.Write([]byte{0})
+	h.Write(payload)
+	return h.Sum(nil)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type testAccountService struct {
+	*accountService
+	users  *fakeUserRepo
+	tokens *fakeAccountTokenRepo
+	mailer *fakeMailer
+}
+
+func newTestAccountService(users ...*models.User) *testAccountService {
+	userRepo := newFakeUserRepo(users...)
+	tokens := newFakeAccountTokenRepo(userRepo)
+	mailer := &fakeMailer{}
+	return &testAccountService{
+		accountService: NewAccountService(tokens, userRepo, mailer),
+		users:          userRepo,
+		tokens:         tokens,
+		mailer:         mailer,
+	}
+}
+
+func passwordUser(t *testing.T) *models.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashed := string(hash)
+	user := donorUser()
+	user.PasswordHash = &hashed
+	user.IsActive = true
+	return user
+}
+
+func TestVerificationEmailLimit(t *testing.T) {
+	user := donorUser()
+	s := newTestAccountService(user)
+	ctx := context.Background()
+
+	for i := 0; i < maxAccountEmailsPerHour; i++ {
+		if err := s.SendVerificationEmail(ctx, user.ID); err != nil {
+			t.Fatalf("SendVerificationEmail() #%d error = %v", i+1, err)
+		}
+	}
+	if err := s.SendVerificationEmail(ctx, user.ID); !errors.Is(err, ErrTooManyEmails) {
+		t.Fatalf("SendVerificationEmail() over the limit error = %v, want ErrTooManyEmails", err)
+	}
+	if got := len(s.mailer.sent); got != maxAccountEmailsPerHour {
+		t.Errorf("sent %d emails, want %d", got, maxAccountEmailsPerHour)
+	}
+
+	// Only the latest link works.
+	if _, err := s.VerifyEmail(ctx, s.mailer.lastToken(t)); err != nil {
+		t.Fatalf("VerifyEmail() with the latest link error = %v", err)
+	}
+}
+
+func TestPasswordResetLimitIsSilent(t *testing.T) {
+	user := passwordUser(t)
+	s := newTestAccountService(user)
+	ctx := context.Background()
+
+	for i := 0; i <= maxAccountEmailsPerHour; i++ {
+		if err := s.RequestPasswordReset(ctx, user.Email); err != nil {
+			t.Fatalf("RequestPasswordReset() #%d error = %v, want nil", i+1, err)
+		}
+	}
+	if got := len(s.mailer.sent); got != maxAccountEmailsPerHour {
+		t.Errorf("sent %d emails, want %d", got, maxAccountEmailsPerHour)
+	}
+
+	// Unknown addresses look the same and send nothing.
+	if err := s.RequestPasswordReset(ctx, "nobody@example.com"); err != nil {
+		t.Errorf("RequestPasswordReset() for an unknown address error = %v, want nil", err)
+	}
+	if got := len(s.mailer.sent); got != maxAccountEmailsPerHour {
+		t.Errorf("sent %d emails after an unknown address, want %d", got, maxAccountEmailsPerHour)
+	}
+}
+
+func TestVerifyEmailIsSingleUse(t *testing.T) {
+	user := donorUser()
+	s := newTestAccountService(user)
+	ctx := context.Background()
+
+	if err := s.SendVerificationEmail(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	token := s.mailer.lastToken(t)
+
+	verified, err := s.VerifyEmail(ctx, token)
+	if err != nil {
+		t.Fatalf("VerifyEmail() error = %v", err)
+	}
+	if !verified.IsVerified {
+		t.Error("user not verified after VerifyEmail()")
+	}
+	if _, err := s.VerifyEmail(ctx, token); !errors.Is(err, repository.ErrInvalidAccountToken) {
+		t.Errorf("second VerifyEmail() error = %v, want ErrInvalidAccountToken", err)
+	}
+}
+
+func TestResetPasswordIsSingleUse(t *testing.T) {
+	user := passwordUser(t)
+	s := newTestAccountService(user)
+	ctx := context.Background()
+
+	if err := s.ResetPassword(ctx, "ignored", "short"); err == nil {
+		t.Error("ResetPassword() accepted a 5 character password")
+	}
+
+	if err := s.RequestPasswordReset(ctx, user.Email); err != nil {
+		t.Fatal(err)
+	}
+	token := s.mailer.lastToken(t)
+
+	if err := s.ResetPassword(ctx, token, "new-password"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	stored, _ := s.users.GetByID(ctx, user.ID)
+	if bcrypt.CompareHashAndPassword([]byte(*stored.PasswordHash), []byte("new-password")) != nil {
+		t.Error("password not changed")
+	}
+
+	if err := s.ResetPassword(ctx, token, "another-password"); !errors.Is(err, repository.ErrInvalidAccountToken) {
+		t.Errorf("second ResetPassword() error = %v, want ErrInvalidAccountToken", err)
+	}
+}
+
+func TestExpiredAccountTokens(t *testing.T) {
+	user := passwordUser(t)
+	ctx := context.Background()
+
+	t.Run("expired signature", func(t *testing.T) {
+		s := newTestAccountService(user)
+		token := &models.AccountToken{
+			ID:        uuid.New(),
+			UserID:    user.ID,
+			Purpose:   models.AccountTokenEmailVerification,
+			Email:     user.Email,
+			ExpiresAt: time.Now().Add(-time.Second),
+			CreatedAt: time.Now().Add(-time.Hour),
+		}
+		if err := s.tokens.Create(ctx, token); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.VerifyEmail(ctx, s.signer.Sign(token)); !errors.Is(err, repository.ErrInvalidAccountToken) {
+			t.Errorf("VerifyEmail() error = %v, want ErrInvalidAccountToken", err)
+		}
+	})
+
+	t.Run("expired in storage", func(t *testing.T) {
+		s := newTestAccountService(user)
+		if err := s.RequestPasswordReset(ctx, user.Email); err != nil {
+			t.Fatal(err)
+		}
+		s.tokens.expire()
+		if err := s.ResetPassword(ctx, s.mailer.lastToken(t), "new-password"); !errors.Is(err, repository.ErrInvalidAccountToken) {
+			t.Errorf("ResetPassword() error = %v, want ErrInvalidAccountToken", err)
+		}
+	})
+
+	t.Run("wrong purpose", func(t *testing.T) {
+		s := newTestAccountService(user)
+		if err := s.RequestPasswordReset(ctx, user.Email); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.VerifyEmail(ctx, s.mailer.lastToken(t)); !errors.Is(err, repository.ErrInvalidAccountToken) {
+			t.Errorf("VerifyEmail() with a reset token error = %v, want ErrInvalidAccountToken", err)
+		}
+	})
+}
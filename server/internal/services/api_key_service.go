@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// apiKeyPrefixBytes random bytes, hex encoded, identify a key.
+const apiKeyPrefixBytes = 6
+
+var (
+	// ErrInvalidAPIKey covers malformed, unknown, revoked and expired keys.
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	// ErrTooManyAPIKeys is returned when an organization already holds
+	// models.MaxAPIKeysPerOrganization active keys.
+	ErrTooManyAPIKeys = fmt.Errorf("an organization can have at most %d active API keys", models.MaxAPIKeysPerOrganization)
+)
+
+// APIKeyService issues and checks the keys organizations use to call the
+// API from their own systems.
+type APIKeyService interface {
+	// Create issues a key. The key itself is in the response and is not
+	// stored, so it can't be shown again.
+	Create(ctx context.Context, organizationID, createdBy uuid.UUID, req *models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error)
+	List(ctx context.Context, organizationID uuid.UUID) ([]*models.OrganizationAPIKey, error)
+	Revoke(ctx context.Context, organizationID, keyID uuid.UUID) error
+	// Authenticate returns the active key, with its organization, that key
+	// is, or ErrInvalidAPIKey.
+	Authenticate(ctx context.Context, key string) (*models.OrganizationAPIKey, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepo       repository.APIKeyRepository
+	organizationRepo repository.OrganizationRepository
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, organizationRepo repository.OrganizationRepository) *apiKeyService {
+	return &apiKeyService{
+		apiKeyRepo:       apiKeyRepo,
+		organizationRepo: organizationRepo,
+	}
+}
+
+func (s *apiKeyService) Create(ctx context.Context, organizationID, createdBy uuid.UUID, req *models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, errors.New("name is required and must be at most 100 characters")
+	}
+	if len(req.Scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	scopes := []models.APIKeyScope{}
+	for _, scope := range req.Scopes {
+		if !scope.IsValid() {
+			return nil, fmt.Errorf("unknown scope %q", scope)
+		}
+		if !containsScope(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	rateLimit := req.RateLimitPerMinute
+	if rateLimit == 0 {
+		rateLimit = models.DefaultAPIKeyRateLimit
+	}
+	if rateLimit < 1 || rateLimit > models.MaxAPIKeyRateLimit {
+		return nil, fmt.Errorf("rate limit must be between 1 and %d requests per minute", models.MaxAPIKeyRateLimit)
+	}
+	if req.ExpiresInDays < 0 {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	active, err := s.apiKeyRepo.CountActive(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if active >= models.MaxAPIKeysPerOrganization {
+		return nil, ErrTooManyAPIKeys
+	}
+
+	prefixBytes := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	secret, err := randomToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	prefix := hex.EncodeToString(prefixBytes)
+	key := models.APIKeyPrefix + prefix + "_" + secret
+
+	now := time.Now()
+	apiKey := &models.OrganizationAPIKey{
+		ID:                 uuid.New(),
+		OrganizationID:     organizationID,
+		Name:               name,
+		Prefix:             prefix,
+		KeyHash:            hashToken(key),
+		Scopes:             scopes,
+		RateLimitPerMinute: rateLimit,
+		CreatedBy:          &createdBy,
+		CreatedAt:          now,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, req.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return &models.CreateAPIKeyResponse{APIKey: apiKey, Key: key}, nil
+}
+
+func containsScope(scopes []models.APIKeyScope, scope models.APIKeyScope) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *apiKeyService) List(ctx context.Context, organizationID uuid.UUID) ([]*models.OrganizationAPIKey, error) {
+	return s.apiKeyRepo.ListByOrganization(ctx, organizationID)
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, organizationID, keyID uuid.UUID) error {
+	return s.apiKeyRepo.Revoke(ctx, organizationID, keyID)
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (*models.OrganizationAPIKey, error) {
+	prefix, ok := parseAPIKeyPrefix(key)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.apiKeyRepo.GetActiveByPrefix(ctx, prefix)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(hashToken(key))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey.Organization, err = s.organizationRepo.GetByOrganizationID(ctx, apiKey.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Losing a last-used timestamp isn't worth failing the request over.
+	if err := s.apiKeyRepo.TouchLastUsed(ctx, apiKey.ID, time.Now()); err != nil {
+		log.Printf("Warning: failed to record use of API key %s: %v", apiKey.Prefix, err)
+	}
+	return apiKey, nil
+}
+
+// parseAPIKeyPrefix returns the prefix of a key shaped like
+// clk_<prefix>_<secret>.
+func parseAPIKeyPrefix(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, models.APIKeyPrefix)
+	prefixLen := hex.EncodedLen(apiKeyPrefixBytes)
+	if !ok || len(rest) <= prefixLen+1 || rest[prefixLen] != '_' {
+		return "", false
+	}
+	prefix := rest[:prefixLen]
+	if _, err := hex.DecodeString(prefix); err != nil {
+		return "", false
+	}
+	return prefix, true
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func newTestAPIKeyService(t *testing.T) (*apiKeyService, *fakeAPIKeyRepo, *models.Organization) {
+	t.Helper()
+	org := &models.Organization{ID: uuid.New(), OrganizationName: "Seva Trust"}
+	keys := newFakeAPIKeyRepo()
+	return NewAPIKeyService(keys, newFakeOrganizationRepo(org)), keys, org
+}
+
+func createTestAPIKey(t *testing.T, s *apiKeyService, organizationID uuid.UUID) *models.CreateAPIKeyResponse {
+	t.Helper()
+	created, err := s.Create(context.Background(), organizationID, uuid.New(), &models.CreateAPIKeyRequest{
+		Name:   "Reporting",
+		Scopes: []models.APIKeyScope{models.ScopeDonationsRead},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return created
+}
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("accepts the issued key", func(t *testing.T) {
+		s, keys, org := newTestAPIKeyService(t)
+		created := createTestAPIKey(t, s, org.ID)
+		if stored := keys.keys[created.APIKey.ID]; stored.KeyHash == created.Key || stored.KeyHash == "" {
+			t.Fatal("key stored in plaintext, want only its hash")
+		}
+
+		got, err := s.Authenticate(ctx, created.Key)
+		if err != nil {
+			t.Fatalf("Authenticate() error = %v", err)
+		}
+		if got.ID != created.APIKey.ID || got.Organization == nil || got.Organization.ID != org.ID {
+			t.Errorf("Authenticate() = key %s of %v, want key %s with its organization", got.ID, got.Organization, created.APIKey.ID)
+		}
+		if keys.keys[got.ID].LastUsedAt == nil {
+			t.Error("last use not recorded")
+		}
+	})
+
+	t.Run("rejects a wrong secret with a known prefix", func(t *testing.T) {
+		s, _, org := newTestAPIKeyService(t)
+		created := createTestAPIKey(t, s, org.ID)
+		forged := created.Key[:len(created.Key)-4] + "0000"
+		if forged == created.Key {
+			forged = created.Key[:len(created.Key)-4] + "1111"
+		}
+		if _, err := s.Authenticate(ctx, forged); !errors.Is(err, ErrInvalidAPIKey) {
+			t.Errorf("Authenticate() error = %v, want ErrInvalidAPIKey", err)
+		}
+	})
+
+	t.Run("rejects revoked keys", func(t *testing.T) {
+		s, _, org := newTestAPIKeyService(t)
+		created := createTestAPIKey(t, s, org.ID)
+		if err := s.Revoke(ctx, org.ID, created.APIKey.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Authenticate(ctx, created.Key); !errors.Is(err, ErrInvalidAPIKey) {
+			t.Errorf("Authenticate() error = %v, want ErrInvalidAPIKey", err)
+		}
+	})
+
+	t.Run("rejects expired keys", func(t *testing.T) {
+		s, keys, org := newTestAPIKeyService(t)
+		created := createTestAPIKey(t, s, org.ID)
+		keys.update(created.APIKey.ID, func(k *models.OrganizationAPIKey) {
+			expired := time.Now().Add(-time.Second)
+			k.ExpiresAt = &expired
+		})
+		if _, err := s.Authenticate(ctx, created.Key); !errors.Is(err, ErrInvalidAPIKey) {
+			t.Errorf("Authenticate() error = %v, want ErrInvalidAPIKey", err)
+		}
+	})
+
+	t.Run("rejects malformed keys", func(t *testing.T) {
+		s, _, _ := newTestAPIKeyService(t)
+		for _, key := range []string{"", "clk_", "clk_zzzzzzzzzzzz_secret", "clk_0123456789ab", "not-a-key"} {
+			if _, err := s.Authenticate(ctx, key); !errors.Is(err, ErrInvalidAPIKey) {
+				t.Errorf("Authenticate(%q) error = %v, want ErrInvalidAPIKey", key, err)
+			}
+		}
+	})
+}
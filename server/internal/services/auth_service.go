@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"server/internal/models"
@@ -23,17 +26,28 @@ type AuthService interface {
 	CreateOrUpdateOAuthUser(ctx context.Context, provider, providerID, name, email, avatarURL string) (*models.AuthResponse, error)
 }
 
+var (
+	// ErrAccountExists is returned when a provider account that isn't
+	// linked to anyone reports an email that already has an account.
+	ErrAccountExists = errors.New("an account with this email already exists; sign in and link this provider from your profile")
+	// ErrOAuthEmailMissing is returned when a provider shares no email for
+	// a new account.
+	ErrOAuthEmailMissing = errors.New("the provider didn't share an email address")
+)
+
 type authService struct {
 	userRepo         repository.UserRepository
 	organizationRepo repository.OrganizationRepository
-	jwtService       JWTService
+	identityRepo     repository.UserIdentityRepository
+	twoFactorService TwoFactorService
 }
 
-func NewAuthService(userRepo repository.UserRepository, organizationRepo repository.OrganizationRepository, jwtService JWTService) AuthService {
+func NewAuthService(userRepo repository.UserRepository, organizationRepo repository.OrganizationRepository, identityRepo repository.UserIdentityRepository, twoFactorService TwoFactorService) AuthService {
 	return &authService{
 		userRepo:         userRepo,
 		organizationRepo: organizationRepo,
-		jwtService:       jwtService,
+		identityRepo:     identityRepo,
+		twoFactorService: twoFactorService,
 	}
 }
 
@@ -69,16 +83,7 @@ func (a *authService) RegisterUser(ctx context.Context, req *models.CreateUserRe
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	// Generate JWT token
-	token, err := a.jwtService.GenerateToken(user.ID, user.Email, user.Role)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
-	}
-
-	return &models.AuthResponse{
-		User:  *user,
-		Token: token,
-	}, nil
+	return a.twoFactorService.SignIn(ctx, user)
 }
 
 func (a *authService) RegisterOrganization(ctx context.Context, req *models.CreateOrganizationRequest) (*models.AuthResponse, error) {
@@ -138,16 +143,7 @@ func (a *authService) RegisterOrganization(ctx context.Context, req *models.Crea
 		return nil, fmt.Errorf("failed to create primary contact: %w", err)
 	}
 
-	// Generate JWT token
-	token, err := a.jwtService.GenerateToken(user.ID, user.Email, user.Role)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
-	}
-
-	return &models.AuthResponse{
-		User:  *user,
-		Token: token,
-	}, nil
+	return a.twoFactorService.SignIn(ctx, user)
 }
 
 func (a *authService) Login(ctx context.Context, req *models.LoginRequest) (*models.AuthResponse, error) {
@@ -167,16 +163,7 @@ func (a *authService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 		return nil, fmt.Errorf("Invalid credentials")
 	}
 
-	// Generate JWT token
-	token, err := a.jwtService.GenerateToken(user.ID, user.Email, user.Role)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to generate token: %w", err)
-	}
-
-	return &models.AuthResponse{
-		User:  *user,
-		Token: token,
-	}, nil
+	return a.twoFactorService.SignIn(ctx, user)
 }
 
 func (a *authService) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
@@ -188,70 +175,36 @@ func (a *authService) GetOrganizationByID(ctx context.Context, organizationID uu
 	return org, err
 }
 
+// CreateOrUpdateOAuthUser signs in the user a provider account is linked
+// to, creating a user if neither the account nor its email is known. An
+// email that already belongs to a user is not linked automatically: they
+// sign in as before and link the provider themselves, so an address at some
+// provider isn't enough to take over an account.
 func (a *authService) CreateOrUpdateOAuthUser(ctx context.Context, provider, providerID, name, email, avatarURL string) (*models.AuthResponse, error) {
-	// Check if user exists by provider ID
-	user, err := a.userRepo.GetByProviderID(ctx, provider, providerID)
-	if err == nil && user != nil {
-		// User exists, update if needed
-		updated := false
-		if user.Name != name {
-			user.Name = name
-			updated = true
-		}
-		if user.Email != email {
-			user.Email = email
-			updated = true
-		}
-		if (user.AvatarURL == nil && avatarURL != "") || (user.AvatarURL != nil && *user.AvatarURL != avatarURL) {
-			user.AvatarURL = stringPtr(avatarURL)
-			updated = true
-		}
-
-		if updated {
-			if err := a.userRepo.Update(ctx, user); err != nil {
-				return nil, fmt.Errorf("failed to update user: %w", err)
-			}
-		}
-
-		// Generate JWT token
-		token, err := a.jwtService.GenerateToken(user.ID, user.Email, user.Role)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate token: %w", err)
-		}
-
-		return &models.AuthResponse{
-			User:  *user,
-			Token: token,
-		}, nil
+	identity, err := a.identityRepo.GetByProviderUserID(ctx, provider, providerID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
 	}
-
-	// Check if user exists by email (different provider)
-	existingUser, err := a.userRepo.GetByEmail(ctx, email)
-	if err == nil && existingUser != nil {
-		// User exists with different provider, update to include OAuth info
-		existingUser.Provider = provider
-		existingUser.ProviderID = stringPtr(providerID)
-		existingUser.AvatarURL = stringPtr(avatarURL)
-		existingUser.UpdatedAt = time.Now()
-
-		if err := a.userRepo.Update(ctx, existingUser); err != nil {
-			return nil, fmt.Errorf("failed to update user: %w", err)
-		}
-
-		// Generate JWT token
-		token, err := a.jwtService.GenerateToken(existingUser.ID, existingUser.Email, existingUser.Role)
+	if identity != nil {
+		user, err := a.userRepo.GetByID(ctx, identity.UserID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate token: %w", err)
+			return nil, err
+		}
+		if err := a.identityRepo.TouchLastUsed(ctx, identity.ID, time.Now()); err != nil {
+			log.Printf("Warning: failed to record %s sign-in for user %v: %v", provider, user.ID, err)
 		}
+		return a.signInOAuthUser(ctx, user, avatarURL)
+	}
 
-		return &models.AuthResponse{
-			User:  *existingUser,
-			Token: token,
-		}, nil
+	if email == "" {
+		return nil, ErrOAuthEmailMissing
+	}
+	if existingUser, err := a.userRepo.GetByEmail(ctx, email); err == nil && existingUser != nil {
+		return nil, ErrAccountExists
 	}
 
 	// Create new user
-	user = &models.User{
+	user := &models.User{
 		ID:         uuid.New(),
 		Name:       name,
 		Email:      email,
@@ -265,21 +218,41 @@ func (a *authService) CreateOrUpdateOAuthUser(ctx context.Context, provider, pro
 		Role:       string(models.RoleTypeUser),
 	}
 
-	// Save user to database
-	if err := a.userRepo.Create(ctx, user); err != nil {
+	// Save the user and their provider account together, so a failed link
+	// doesn't leave an account that the next sign-in would refuse.
+	if err := a.identityRepo.CreateWithUser(ctx, user, newIdentity(user.ID, provider, providerID, email)); err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	// Generate JWT token
-	token, err := a.jwtService.GenerateToken(user.ID, user.Email, user.Role)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+	return a.twoFactorService.SignIn(ctx, user)
+}
+
+// signInOAuthUser fills in a missing avatar from the provider. Name and
+// email stay as the user set them, since several providers may be linked.
+func (a *authService) signInOAuthUser(ctx context.Context, user *models.User, avatarURL string) (*models.AuthResponse, error) {
+	if user.AvatarURL == nil && avatarURL != "" {
+		user.AvatarURL = stringPtr(avatarURL)
+		if err := a.userRepo.Update(ctx, user); err != nil {
+			return nil, fmt.Errorf("failed to update user: %w", err)
+		}
 	}
+	return a.twoFactorService.SignIn(ctx, user)
+}
 
-	return &models.AuthResponse{
-		User:  *user,
-		Token: token,
-	}, nil
+func newIdentity(userID uuid.UUID, provider, providerID, email string) *models.UserIdentity {
+	now := time.Now()
+	identity := &models.UserIdentity{
+		ID:             uuid.New(),
+		UserID:         userID,
+		Provider:       provider,
+		ProviderUserID: providerID,
+		LastUsedAt:     &now,
+		CreatedAt:      now,
+	}
+	if email != "" {
+		identity.Email = &email
+	}
+	return identity
 }
 
 // Helper function to create string pointer
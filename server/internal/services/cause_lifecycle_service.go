@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const defaultCauseLifecycleInterval = time.Minute
+
+type CauseLifecycleService interface {
+	// Transition moves a cause to a new state after checking the transition
+	// is legal for the given actor. actorID is nil for system transitions.
+	Transition(ctx context.Context, causeID uuid.UUID, to models.CauseState, actor models.CauseStateActor, actorID *uuid.UUID, reason *string) (*models.Cause, error)
+	GetHistory(ctx context.Context, causeID uuid.UUID) ([]*models.CauseStateTransition, error)
+
+	// RunScheduledTransitions applies goal and deadline driven transitions
+	// once and returns how many causes were moved.
+	RunScheduledTransitions(ctx context.Context) (int, error)
+	// Start runs RunScheduledTransitions periodically until ctx is done.
+	Start(ctx context.Context)
+
+	// SubmitForReview sends a draft to the admin publication queue.
+	SubmitForReview(ctx context.Context, causeID uuid.UUID, userID uuid.UUID) (*models.Cause, error)
+	// ReviewPublication approves (pending_review -> live) or rejects
+	// (pending_review -> draft) a submitted cause and records the decision.
+	ReviewPublication(ctx context.Context, causeID uuid.UUID, adminID uuid.UUID, decision models.CausePublicationDecision, comments *string) (*models.Cause, *models.CausePublicationReview, error)
+	GetPendingReview(ctx context.Context, page models.CursorParams) (*models.Page[*models.Cause], error)
+	GetPublicationReviews(ctx context.Context, causeID uuid.UUID) ([]*models.CausePublicationReview, error)
+}
+
+type causeLifecycleService struct {
+	causeRepo repository.CauseRepository
+	interval  time.Duration
+}
+
+func NewCauseLifecycleService(causeRepo repository.CauseRepository) *causeLifecycleService {
+	interval := defaultCauseLifecycleInterval
+	if raw := os.Getenv("CAUSE_LIFECYCLE_INTERVAL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			interval = d
+		}
+	}
+
+	return &causeLifecycleService{
+		causeRepo: causeRepo,
+		interval:  interval,
+	}
+}
+
+func (s *causeLifecycleService) Transition(
+	ctx context.Context,
+	causeID uuid.UUID,
+	to models.CauseState,
+	actor models.CauseStateActor,
+	actorID *uuid.UUID,
+	reason *string,
+) (*models.Cause, error) {
+	cause, err := s.causeRepo.GetByID(ctx, causeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := models.CanTransitionCause(cause.State, to, actor); err != nil {
+		return nil, err
+	}
+
+	from := cause.State
+	transition := &models.CauseStateTransition{
+		ID:        uuid.New(),
+		CauseID:   causeID,
+		FromState: &from,
+		ToState:   to,
+		ActorType: actor,
+		ActorID:   actorID,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.causeRepo.TransitionState(ctx, transition); err != nil {
+		return nil, err
+	}
+
+	cause.State = to
+	cause.StateChangedAt = transition.CreatedAt
+	return cause, nil
+}
+
+func (s *causeLifecycleService) GetHistory(ctx context.Context, causeID uuid.UUID) ([]*models.CauseStateTransition, error) {
+	return s.causeRepo.GetStateTransitions(ctx, causeID)
+}
+
+func (s *causeLifecycleService) RunScheduledTransitions(ctx context.Context) (int, error) {
+	due, err := s.causeRepo.GetScheduledTransitions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, t := range due {
+		reason := t.Reason
+		if _, err := s.Transition(ctx, t.CauseID, t.To, models.CauseStateActorSystem, nil, &reason); err != nil {
+			log.Printf("[LIFECYCLE] Failed to move cause %v from %s to %s: %v", t.CauseID, t.From, t.To, err)
+			continue
+		}
+		log.Printf("[LIFECYCLE] Cause %v moved from %s to %s (%s)", t.CauseID, t.From, t.To, reason)
+		moved++
+	}
+
+	return moved, nil
+}
+
+func (s *causeLifecycleService) SubmitForReview(ctx context.Context, causeID uuid.UUID, userID uuid.UUID) (*models.Cause, error) {
+	return s.Transition(ctx, causeID, models.CauseStatePendingReview, models.CauseStateActorOrganization, &userID, nil)
+}
+
+func (s *causeLifecycleService) ReviewPublication(
+	ctx context.Context,
+	causeID uuid.UUID,
+	adminID uuid.UUID,
+	decision models.CausePublicationDecision,
+	comments *string,
+) (*models.Cause, *models.CausePublicationReview, error) {
+	var to models.CauseState
+	switch decision {
+	case models.CausePublicationApproved:
+		to = models.CauseStateLive
+	case models.CausePublicationRejected:
+		if comments == nil || *comments == "" {
+			return nil, nil, fmt.Errorf("comments are required when rejecting a cause")
+		}
+		to = models.CauseStateDraft
+	default:
+		return nil, nil, fmt.Errorf("invalid publication decision")
+	}
+
+	current, err := s.causeRepo.GetByID(ctx, causeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if current.State != models.CauseStatePendingReview {
+		return nil, nil, fmt.Errorf("cause is not awaiting review (state: %s)", current.State)
+	}
+
+	cause, err := s.Transition(ctx, causeID, to, models.CauseStateActorAdmin, &adminID, comments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	review := &models.CausePublicationReview{
+		ID:         uuid.New(),
+		CauseID:    causeID,
+		ReviewerID: &adminID,
+		Decision:   decision,
+		Comments:   comments,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.causeRepo.CreatePublicationReview(ctx, review); err != nil {
+		return nil, nil, err
+	}
+
+	return cause, review, nil
+}
+
+func (s *causeLifecycleService) GetPendingReview(ctx context.Context, page models.CursorParams) (*models.Page[*models.Cause], error) {
+	return s.causeRepo.GetByState(ctx, models.CauseStatePendingReview, page)
+}
+
+func (s *causeLifecycleService) GetPublicationReviews(ctx context.Context, causeID uuid.UUID) ([]*models.CausePublicationReview, error) {
+	return s.causeRepo.GetPublicationReviews(ctx, causeID)
+}
+
+func (s *causeLifecycleService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunScheduledTransitions(ctx); err != nil {
+			log.Printf("[LIFECYCLE] Scheduled transition run failed: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Println("Stopping cause lifecycle job")
+			return
+		}
+	}
+}
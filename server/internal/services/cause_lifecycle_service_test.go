@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func newTestLifecycleService(causes ...*models.Cause) (*causeLifecycleService, *fakeCauseRepo) {
+	repo := newFakeCauseRepo(causes...)
+	return NewCauseLifecycleService(repo), repo
+}
+
+func causeIn(state models.CauseState) *models.Cause {
+	cause := liveCause()
+	cause.State = state
+	return cause
+}
+
+func TestLifecycleTransition(t *testing.T) {
+	tests := []struct {
+		name  string
+		from  models.CauseState
+		to    models.CauseState
+		actor models.CauseStateActor
+		ok    bool
+	}{
+		{"organization starts execution", models.CauseStateLive, models.CauseStateExecuting, models.CauseStateActorOrganization, true},
+		{"organization completes", models.CauseStateExecuting, models.CauseStateCompleted, models.CauseStateActorOrganization, true},
+		{"organization closes a draft", models.CauseStateDraft, models.CauseStateClosed, models.CauseStateActorOrganization, true},
+		{"admin suspends", models.CauseStateLive, models.CauseStateSuspended, models.CauseStateActorAdmin, true},
+		{"admin lifts a suspension", models.CauseStateSuspended, models.CauseStateLive, models.CauseStateActorAdmin, true},
+		{"organization cannot suspend", models.CauseStateLive, models.CauseStateSuspended, models.CauseStateActorOrganization, false},
+		{"system cannot start execution", models.CauseStateLive, models.CauseStateExecuting, models.CauseStateActorSystem, false},
+		{"completed is terminal", models.CauseStateCompleted, models.CauseStateLive, models.CauseStateActorAdmin, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cause := causeIn(tt.from)
+			service, repo := newTestLifecycleService(cause)
+			actorID := uuid.New()
+			reason := "test"
+
+			got, err := service.Transition(context.Background(), cause.ID, tt.to, tt.actor, &actorID, &reason)
+			stored, _ := repo.GetByID(context.Background(), cause.ID)
+			if !tt.ok {
+				if err == nil {
+					t.Fatalf("Transition(%s -> %s) by %s succeeded", tt.from, tt.to, tt.actor)
+				}
+				if stored.State != tt.from || len(repo.transitions) != 0 {
+					t.Errorf("refused transition left state %s and %d history rows", stored.State, len(repo.transitions))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transition() error = %v", err)
+			}
+			if got.State != tt.to || stored.State != tt.to {
+				t.Errorf("state = %s, stored %s, want %s", got.State, stored.State, tt.to)
+			}
+			if len(repo.transitions) != 1 {
+				t.Fatalf("recorded %d transitions, want 1", len(repo.transitions))
+			}
+			rec := repo.transitions[0]
+			if *rec.FromState != tt.from || rec.ToState != tt.to || rec.ActorType != tt.actor || *rec.ActorID != actorID || !got.StateChangedAt.Equal(rec.CreatedAt) {
+				t.Errorf("recorded %s -> %s by %s %v at %v", *rec.FromState, rec.ToState, rec.ActorType, *rec.ActorID, rec.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestLifecycleTransitionStaleState(t *testing.T) {
+	cause := causeIn(models.CauseStateLive)
+	service, repo := newTestLifecycleService(cause)
+	// An admin suspends the cause after the service read it as live.
+	repo.beforeTransition = func() { repo.setState(cause.ID, models.CauseStateSuspended) }
+
+	_, err := service.Transition(context.Background(), cause.ID, models.CauseStateExecuting, models.CauseStateActorOrganization, nil, nil)
+	if !errors.Is(err, repository.ErrCauseStateChanged) {
+		t.Fatalf("Transition() error = %v, want ErrCauseStateChanged", err)
+	}
+	if stored, _ := repo.GetByID(context.Background(), cause.ID); stored.State != models.CauseStateSuspended {
+		t.Errorf("state = %s, want the admin's suspended", stored.State)
+	}
+	if len(repo.transitions) != 0 {
+		t.Errorf("recorded %d transitions, want none", len(repo.transitions))
+	}
+}
+
+func TestLifecycleScheduledTransitions(t *testing.T) {
+	goal := func(v float32) *float32 { return &v }
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+
+	funded := causeIn(models.CauseStateLive)
+	funded.GoalAmount, funded.CollectedAmount = goal(1000), 1000
+	expired := causeIn(models.CauseStateLive)
+	expired.GoalAmount, expired.CollectedAmount, expired.Deadline = goal(1000), 400, &past
+	// Funded beats expired: the goal was reached before the deadline ran out.
+	fundedAndExpired := causeIn(models.CauseStateLive)
+	fundedAndExpired.GoalAmount, fundedAndExpired.CollectedAmount, fundedAndExpired.Deadline = goal(1000), 1200, &past
+	raised := causeIn(models.CauseStateFullyFunded)
+	raised.GoalAmount, raised.CollectedAmount, raised.Deadline = goal(2000), 1000, &future
+	raisedTooLate := causeIn(models.CauseStateFullyFunded)
+	raisedTooLate.GoalAmount, raisedTooLate.CollectedAmount, raisedTooLate.Deadline = goal(2000), 1000, &past
+	running := causeIn(models.CauseStateLive)
+	running.GoalAmount, running.CollectedAmount, running.Deadline = goal(1000), 400, &future
+	draft := causeIn(models.CauseStateDraft)
+	draft.Deadline = &past
+
+	service, repo := newTestLifecycleService(funded, expired, fundedAndExpired, raised, raisedTooLate, running, draft)
+	moved, err := service.RunScheduledTransitions(context.Background())
+	if err != nil {
+		t.Fatalf("RunScheduledTransitions() error = %v", err)
+	}
+	if moved != 4 {
+		t.Errorf("moved %d causes, want 4", moved)
+	}
+
+	for i, want := range []struct {
+		cause *models.Cause
+		state models.CauseState
+	}{
+		{funded, models.CauseStateFullyFunded},
+		{expired, models.CauseStateClosed},
+		{fundedAndExpired, models.CauseStateFullyFunded},
+		{raised, models.CauseStateLive},
+		{raisedTooLate, models.CauseStateFullyFunded},
+		{running, models.CauseStateLive},
+		{draft, models.CauseStateDraft},
+	} {
+		if stored, _ := repo.GetByID(context.Background(), want.cause.ID); stored.State != want.state {
+			t.Errorf("cause %d is %s, want %s", i, stored.State, want.state)
+		}
+	}
+	for _, rec := range repo.transitions {
+		if rec.ActorType != models.CauseStateActorSystem || rec.ActorID != nil || rec.Reason == nil || *rec.Reason == "" {
+			t.Errorf("scheduled transition by %s %v with reason %v, want the system with a reason", rec.ActorType, rec.ActorID, rec.Reason)
+		}
+	}
+}
+
+func TestLifecycleScheduledTransitionSkipsStaleCause(t *testing.T) {
+	goal := float32(1000)
+	cause := causeIn(models.CauseStateLive)
+	cause.GoalAmount, cause.CollectedAmount = &goal, 1000
+	service, repo := newTestLifecycleService(cause)
+	repo.beforeTransition = func() { repo.setState(cause.ID, models.CauseStateSuspended) }
+
+	moved, err := service.RunScheduledTransitions(context.Background())
+	if err != nil || moved != 0 {
+		t.Fatalf("RunScheduledTransitions() = %d, %v, want 0 moved and no error", moved, err)
+	}
+	if stored, _ := repo.GetByID(context.Background(), cause.ID); stored.State != models.CauseStateSuspended {
+		t.Errorf("state = %s, want suspended", stored.State)
+	}
+}
+
+func TestLifecycleSubmitForReview(t *testing.T) {
+	for _, tt := range []struct {
+		from models.CauseState
+		ok   bool
+	}{
+		{models.CauseStateDraft, true},
+		{models.CauseStatePendingReview, false},
+		{models.CauseStateLive, false},
+	} {
+		t.Run(string(tt.from), func(t *testing.T) {
+			cause := causeIn(tt.from)
+			service, repo := newTestLifecycleService(cause)
+			userID := uuid.New()
+
+			got, err := service.SubmitForReview(context.Background(), cause.ID, userID)
+			if !tt.ok {
+				if err == nil {
+					t.Fatalf("SubmitForReview() from %s succeeded", tt.from)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SubmitForReview() error = %v", err)
+			}
+			if got.State != models.CauseStatePendingReview {
+				t.Errorf("state = %s, want pending_review", got.State)
+			}
+			if rec := repo.transitions[0]; rec.ActorType != models.CauseStateActorOrganization || *rec.ActorID != userID {
+				t.Errorf("submitted by %s %v, want the organization's user", rec.ActorType, rec.ActorID)
+			}
+		})
+	}
+}
+
+func TestLifecycleReviewPublication(t *testing.T) {
+	comments := "Add the beneficiary list"
+	empty := ""
+	tests := []struct {
+		name      string
+		from      models.CauseState
+		decision  models.CausePublicationDecision
+		comments  *string
+		wantState models.CauseState
+		wantErr   bool
+	}{
+		{"approve", models.CauseStatePendingReview, models.CausePublicationApproved, nil, models.CauseStateLive, false},
+		{"reject back to draft", models.CauseStatePendingReview, models.CausePublicationRejected, &comments, models.CauseStateDraft, false},
+		{"reject without comments", models.CauseStatePendingReview, models.CausePublicationRejected, nil, models.CauseStatePendingReview, true},
+		{"reject with empty comments", models.CauseStatePendingReview, models.CausePublicationRejected, &empty, models.CauseStatePendingReview, true},
+		{"unknown decision", models.CauseStatePendingReview, "maybe", nil, models.CauseStatePendingReview, true},
+		{"draft not submitted", models.CauseStateDraft, models.CausePublicationApproved, nil, models.CauseStateDraft, true},
+		{"already live", models.CauseStateLive, models.CausePublicationApproved, nil, models.CauseStateLive, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cause := causeIn(tt.from)
+			service, repo := newTestLifecycleService(cause)
+			adminID := uuid.New()
+
+			_, review, err := service.ReviewPublication(context.Background(), cause.ID, adminID, tt.decision, tt.comments)
+			if stored, _ := repo.GetByID(context.Background(), cause.ID); stored.State != tt.wantState {
+				t.Errorf("state = %s, want %s", stored.State, tt.wantState)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ReviewPublication() succeeded")
+				}
+				if len(repo.reviews) != 0 {
+					t.Errorf("recorded %d reviews for a refused decision", len(repo.reviews))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReviewPublication() error = %v", err)
+			}
+			if len(repo.reviews) != 1 || repo.reviews[0] != review {
+				t.Fatalf("reviews = %v, want the returned review", repo.reviews)
+			}
+			if review.Decision != tt.decision || *review.ReviewerID != adminID || review.Comments != tt.comments {
+				t.Errorf("review = %s by %v with %v", review.Decision, *review.ReviewerID, review.Comments)
+			}
+			if rec := repo.transitions[0]; rec.ActorType != models.CauseStateActorAdmin || rec.Reason != tt.comments {
+				t.Errorf("transition by %s with reason %v, want the admin with the comments", rec.ActorType, rec.Reason)
+			}
+		})
+	}
+}
+
+func TestLifecyclePendingReviewQueue(t *testing.T) {
+	pending := causeIn(models.CauseStatePendingReview)
+	service, _ := newTestLifecycleService(pending, causeIn(models.CauseStateDraft), causeIn(models.CauseStateLive))
+
+	page, err := service.GetPendingReview(context.Background(), models.CursorParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetPendingReview() error = %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != pending.ID {
+		t.Errorf("queue = %d causes, want only the submitted one", len(page.Items))
+	}
+}
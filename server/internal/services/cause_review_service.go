@@ -2,6 +2,9 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"time"
 
 	"server/internal/models"
 	"server/internal/repository"
@@ -9,34 +12,218 @@ import (
 	"github.com/google/uuid"
 )
 
+// Reviewers may edit or delete their review for this long after posting it.
+const reviewEditWindow = 7 * 24 * time.Hour
+
 type CauseReviewService interface {
 	UserCanReviewCause(ctx context.Context, causeID uuid.UUID, userID uuid.UUID) (bool, error)
-	CreateReview(ctx context.Context, causeID uuid.UUID, userID uuid.UUID, reviewText string) (*models.CauseReviewResponse, error)
-	GetReviewsByCauseID(ctx context.Context, causeID uuid.UUID) (*models.CauseReviewsResponse, error)
+	CreateReview(ctx context.Context, causeID uuid.UUID, userID uuid.UUID, reviewText string, rating *int) (*models.CauseReviewResponse, error)
+	GetReviewByID(ctx context.Context, reviewID uuid.UUID) (*models.CauseReviewResponse, error)
+	UpdateReview(ctx context.Context, reviewID uuid.UUID, userID uuid.UUID, req *models.UpdateCauseReviewRequest) (*models.CauseReviewResponse, error)
+	DeleteReview(ctx context.Context, reviewID uuid.UUID, userID uuid.UUID) error
+	GetReviewsByCauseID(ctx context.Context, causeID uuid.UUID, page models.CursorParams) (*models.CauseReviewsResponse, error)
 	GetReviewCountByCauseID(ctx context.Context, causeID uuid.UUID) (int, error)
+
+	ReportReview(ctx context.Context, reviewID uuid.UUID, reporterID uuid.UUID, req *models.ReportCauseReviewRequest) error
+	GetModerationQueue(ctx context.Context, hidden bool, page models.CursorParams) (*models.Page[*models.ReportedCauseReview], error)
+	HideReview(ctx context.Context, reviewID uuid.UUID, adminID uuid.UUID, reason *string) error
+	RestoreReview(ctx context.Context, reviewID uuid.UUID, adminID uuid.UUID) error
+
+	ReplyToReview(ctx context.Context, reviewID uuid.UUID, organizationID uuid.UUID, responderID uuid.UUID, responseText string) (*models.CauseReviewReply, error)
+	DeleteReply(ctx context.Context, reviewID uuid.UUID, organizationID uuid.UUID) error
 }
 
 type causeReviewService struct {
-	repo repository.CauseReviewRepository
+	repo    repository.CauseReviewRepository
+	orgRepo repository.OrganizationRepository
 }
 
-func NewCauseReviewService(repo repository.CauseReviewRepository) *causeReviewService {
-	return &causeReviewService{repo: repo}
+func NewCauseReviewService(repo repository.CauseReviewRepository, orgRepo repository.OrganizationRepository) *causeReviewService {
+	return &causeReviewService{repo: repo, orgRepo: orgRepo}
+}
+
+func validateRating(rating *int) error {
+	if rating != nil && (*rating < 1 || *rating > 5) {
+		return fmt.Errorf("rating must be between 1 and 5")
+	}
+	return nil
 }
 
 func (s *causeReviewService) UserCanReviewCause(ctx context.Context, causeID uuid.UUID, userID uuid.UUID) (bool, error) {
 	return s.repo.UserCanReviewCause(ctx, causeID, userID)
 }
 
-func (s *causeReviewService) CreateReview(ctx context.Context, causeID uuid.UUID, userID uuid.UUID, reviewText string) (*models.CauseReviewResponse, error) {
-	return s.repo.CreateReview(ctx, causeID, userID, reviewText)
+func (s *causeReviewService) CreateReview(ctx context.Context, causeID uuid.UUID, userID uuid.UUID, reviewText string, rating *int) (*models.CauseReviewResponse, error) {
+	if err := validateRating(rating); err != nil {
+		return nil, err
+	}
+
+	review, err := s.repo.CreateReview(ctx, causeID, userID, reviewText, rating)
+	if err != nil {
+		return nil, err
+	}
+
+	if rating != nil {
+		s.refreshTrustScore(review.ID)
+	}
+
+	return review, nil
+}
+
+func (s *causeReviewService) GetReviewByID(ctx context.Context, reviewID uuid.UUID) (*models.CauseReviewResponse, error) {
+	return s.repo.GetReviewByID(ctx, reviewID)
+}
+
+func (s *causeReviewService) UpdateReview(ctx context.Context, reviewID uuid.UUID, userID uuid.UUID, req *models.UpdateCauseReviewRequest) (*models.CauseReviewResponse, error) {
+	review, err := s.ownEditableReview(ctx, reviewID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewText := review.ReviewText
+	if req.ReviewText != nil {
+		reviewText = *req.ReviewText
+		if len(reviewText) < 5 {
+			return nil, fmt.Errorf("review_text is required (min 5 chars)")
+		}
+	}
+
+	rating := review.Rating
+	if req.Rating != nil {
+		if err := validateRating(req.Rating); err != nil {
+			return nil, err
+		}
+		rating = req.Rating
+	}
+
+	updated, err := s.repo.UpdateReview(ctx, reviewID, userID, reviewText, rating)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Rating != nil {
+		s.refreshTrustScore(reviewID)
+	}
+
+	return updated, nil
+}
+
+func (s *causeReviewService) DeleteReview(ctx context.Context, reviewID uuid.UUID, userID uuid.UUID) error {
+	if _, err := s.ownEditableReview(ctx, reviewID, userID); err != nil {
+		return err
+	}
+
+	// Resolve the organization before the row disappears.
+	orgID, orgErr := s.repo.GetReviewOrganizationID(ctx, reviewID)
+
+	if err := s.repo.DeleteReview(ctx, reviewID, userID); err != nil {
+		return err
+	}
+
+	if orgErr == nil {
+		go s.updateTrustScore(orgID)
+	}
+	return nil
+}
+
+func (s *causeReviewService) ownEditableReview(ctx context.Context, reviewID uuid.UUID, userID uuid.UUID) (*models.CauseReviewResponse, error) {
+	review, err := s.repo.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != userID {
+		return nil, fmt.Errorf("not authorized to modify this review")
+	}
+	if review.IsHidden {
+		return nil, fmt.Errorf("this review has been hidden by a moderator")
+	}
+	if time.Since(review.CreatedAt) > reviewEditWindow {
+		return nil, fmt.Errorf("reviews can only be changed within %d days of posting", int(reviewEditWindow.Hours()/24))
+	}
+	return review, nil
 }
 
-func (s *causeReviewService) GetReviewsByCauseID(ctx context.Context, causeID uuid.UUID) (*models.CauseReviewsResponse, error) {
-	return s.repo.GetReviewsByCauseID(ctx, causeID)
+func (s *causeReviewService) GetReviewsByCauseID(ctx context.Context, causeID uuid.UUID, page models.CursorParams) (*models.CauseReviewsResponse, error) {
+	return s.repo.GetReviewsByCauseID(ctx, causeID, page)
 }
 
 func (s *causeReviewService) GetReviewCountByCauseID(ctx context.Context, causeID uuid.UUID) (int, error) {
 	return s.repo.GetReviewCountByCauseID(ctx, causeID)
 }
 
+func (s *causeReviewService) ReportReview(ctx context.Context, reviewID uuid.UUID, reporterID uuid.UUID, req *models.ReportCauseReviewRequest) error {
+	if !models.IsValidReviewReportReason(req.Reason) {
+		return fmt.Errorf("invalid report reason")
+	}
+
+	review, err := s.repo.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if review.UserID == reporterID {
+		return fmt.Errorf("you cannot report your own review")
+	}
+
+	return s.repo.ReportReview(ctx, reviewID, reporterID, req.Reason, req.Details)
+}
+
+func (s *causeReviewService) GetModerationQueue(ctx context.Context, hidden bool, page models.CursorParams) (*models.Page[*models.ReportedCauseReview], error) {
+	return s.repo.GetReportedReviews(ctx, hidden, page)
+}
+
+func (s *causeReviewService) HideReview(ctx context.Context, reviewID uuid.UUID, adminID uuid.UUID, reason *string) error {
+	if err := s.repo.SetReviewHidden(ctx, reviewID, adminID, true, reason); err != nil {
+		return err
+	}
+	s.refreshTrustScore(reviewID)
+	return nil
+}
+
+func (s *causeReviewService) RestoreReview(ctx context.Context, reviewID uuid.UUID, adminID uuid.UUID) error {
+	if err := s.repo.SetReviewHidden(ctx, reviewID, adminID, false, nil); err != nil {
+		return err
+	}
+	s.refreshTrustScore(reviewID)
+	return nil
+}
+
+func (s *causeReviewService) ReplyToReview(ctx context.Context, reviewID uuid.UUID, organizationID uuid.UUID, responderID uuid.UUID, responseText string) (*models.CauseReviewReply, error) {
+	reviewOrgID, err := s.repo.GetReviewOrganizationID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if reviewOrgID != organizationID {
+		return nil, fmt.Errorf("not authorized to respond to this review")
+	}
+
+	return s.repo.UpsertReviewReply(ctx, reviewID, organizationID, responderID, responseText)
+}
+
+func (s *causeReviewService) DeleteReply(ctx context.Context, reviewID uuid.UUID, organizationID uuid.UUID) error {
+	return s.repo.DeleteReviewReply(ctx, reviewID, organizationID)
+}
+
+// refreshTrustScore recalculates the owning organization's trust score in
+// the background, since ratings feed into it.
+func (s *causeReviewService) refreshTrustScore(reviewID uuid.UUID) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		orgID, err := s.repo.GetReviewOrganizationID(ctx, reviewID)
+		if err != nil {
+			log.Printf("Warning: failed to resolve organization for review %s: %v", reviewID, err)
+			return
+		}
+		s.updateTrustScore(orgID)
+	}()
+}
+
+func (s *causeReviewService) updateTrustScore(orgID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := s.orgRepo.UpdateTrustScore(ctx, orgID); err != nil {
+		log.Printf("Warning: failed to update trust score for organization %s: %v", orgID, err)
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"server/internal/models"
+)
+
+func TestCauseSearchFilter(t *testing.T) {
+	later := time.Now().Add(24 * time.Hour)
+	earlier := time.Now()
+
+	tests := []struct {
+		name     string
+		filter   models.CauseSearchFilter
+		wantSort models.CauseSearchSort
+		wantErr  bool
+	}{
+		{name: "browsing sorts newest first", filter: models.CauseSearchFilter{}, wantSort: models.CauseSearchSortNewest},
+		{name: "a query sorts by relevance", filter: models.CauseSearchFilter{Query: "water"}, wantSort: models.CauseSearchSortRelevance},
+		{name: "an explicit sort is kept", filter: models.CauseSearchFilter{Query: "water", Sort: models.CauseSearchSortEndingSoon}, wantSort: models.CauseSearchSortEndingSoon},
+		{name: "all facets", filter: models.CauseSearchFilter{
+			FundingStatuses: []string{models.FundingStatusActive, models.FundingStatusFullyFunded},
+			TrustBands:      []models.TrustBand{models.TrustBandHigh, models.TrustBandUnrated},
+			DeadlineFrom:    &earlier,
+			DeadlineTo:      &later,
+		}, wantSort: models.CauseSearchSortNewest},
+		{name: "unknown sort", filter: models.CauseSearchFilter{Sort: "cheapest"}, wantErr: true},
+		{name: "unknown funding status", filter: models.CauseSearchFilter{FundingStatuses: []string{"Paused"}}, wantErr: true},
+		{name: "unknown trust band", filter: models.CauseSearchFilter{TrustBands: []models.TrustBand{"excellent"}}, wantErr: true},
+		{name: "deadline range reversed", filter: models.CauseSearchFilter{DeadlineFrom: &later, DeadlineTo: &earlier}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := &fakeSearchRepo{}
+			service := NewCauseService(newFakeCauseRepo(), nil, search, nil, nopWebhooks{}, nopNotifier{})
+
+			_, err := service.Search(context.Background(), &tt.filter)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Search() accepted an invalid filter")
+				}
+				if len(search.searched) != 0 {
+					t.Error("an invalid filter reached the repository")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if len(search.searched) != 1 || search.searched[0].Sort != tt.wantSort {
+				t.Errorf("repository searched %+v, want sort %s", search.searched, tt.wantSort)
+			}
+		})
+	}
+}
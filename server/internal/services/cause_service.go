@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	// new {
 	"fmt"
 	// }
@@ -23,13 +24,18 @@ type CauseService interface {
 	CheckBloodDonationEligibility(ctx context.Context, userID uuid.UUID) (*models.BloodDonationEligibilityResponse, error)
 
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Cause, error)
-	GetByOrganizationID(ctx context.Context, id uuid.UUID) ([]*models.Cause, error)
-	GetByDomainID(ctx context.Context, id uuid.UUID) ([]*models.Cause, error)
-	GetByAidTypeID(ctx context.Context, id uuid.UUID) ([]*models.Cause, error)
-	GetAll(ctx context.Context) ([]*models.Cause, error)
-	GetAllPaginated(ctx context.Context, limit, offset int) ([]*models.Cause, int64, error)
-
-	// Update(ctx context.Context, cause *models.Cause) error
+	// GetByOrganizationID lists an organization's causes. Drafts and causes
+	// awaiting review are only included when includeUnpublished is set.
+	GetByOrganizationID(ctx context.Context, id uuid.UUID, includeUnpublished bool) ([]*models.Cause, error)
+	GetByDomainID(ctx context.Context, id uuid.UUID, page models.CursorParams) (*models.Page[*models.Cause], error)
+	GetByAidTypeID(ctx context.Context, id uuid.UUID, page models.CursorParams) (*models.Page[*models.Cause], error)
+	GetAllPaginated(ctx context.Context, page models.CursorParams) (*models.Page[*models.Cause], error)
+	Search(ctx context.Context, filter *models.CauseSearchFilter) (*models.CauseSearchResult, error)
+	GetNearby(ctx context.Context, query *models.NearbyCausesQuery) (*models.Page[*models.NearbyCause], error)
+	GetInBoundingBox(ctx context.Context, box *models.CauseBoundingBox) (*models.CauseMapResponse, error)
+
+	Update(ctx context.Context, causeID uuid.UUID, editorID uuid.UUID, req *models.UpdateCauseRequest) (*models.Cause, *models.CauseRevision, error)
+	GetRevisions(ctx context.Context, causeID uuid.UUID) ([]*models.CauseRevision, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 
 	// Structured updates
@@ -46,14 +52,22 @@ type CauseService interface {
 }
 
 type causeService struct {
-	causeRepo repository.CauseRepository
-	orgRepo   repository.OrganizationRepository
+	causeRepo    repository.CauseRepository
+	orgRepo      repository.OrganizationRepository
+	searchRepo   repository.CauseSearchRepository
+	matchingRepo repository.MatchingCampaignRepository
+	webhooks     WebhookPublisher
+	notifier     Notifier
 }
 
-func NewCauseService(causeRepo repository.CauseRepository, orgRepo repository.OrganizationRepository) *causeService {
+func NewCauseService(causeRepo repository.CauseRepository, orgRepo repository.OrganizationRepository, searchRepo repository.CauseSearchRepository, matchingRepo repository.MatchingCampaignRepository, webhooks WebhookPublisher, notifier Notifier) *causeService {
 	return &causeService{
-		causeRepo: causeRepo,
-		orgRepo:   orgRepo,
+		causeRepo:    causeRepo,
+		orgRepo:      orgRepo,
+		searchRepo:   searchRepo,
+		matchingRepo: matchingRepo,
+		webhooks:     webhooks,
+		notifier:     notifier,
 	}
 }
 
@@ -88,6 +102,9 @@ func (c *causeService) StartReceiptVerificationJob(
 		if err != nil {
 			errMsg := err.Error()
 			_ = c.causeRepo.UpdateReceiptVerificationJobResult(aiCtx, organizationID, jobID, "error", nil, &errMsg)
+			// The AI service's error can name internal hosts; don't send it out.
+			failed := "receipt verification could not be completed"
+			c.publishReceiptVerification(aiCtx, job, "error", nil, &failed)
 			return
 		}
 
@@ -108,11 +125,27 @@ func (c *causeService) StartReceiptVerificationJob(
 		}
 
 		_ = c.causeRepo.UpdateReceiptVerificationJobResult(aiCtx, organizationID, jobID, status, score, nil)
+		c.publishReceiptVerification(aiCtx, job, status, score, nil)
 	}()
 
 	return jobID, nil
 }
 
+// publishReceiptVerification tells the organization's webhooks and members
+// how a receipt verification job finished.
+func (c *causeService) publishReceiptVerification(ctx context.Context, job *models.ReceiptVerificationJob, status string, score *float64, errMsg *string) {
+	job.Status = status
+	job.ReceiptScore = score
+	c.notifier.ReceiptVerified(ctx, job)
+	c.webhooks.Publish(ctx, job.OrganizationID, models.WebhookReceiptVerificationCompleted, &models.WebhookReceiptVerificationData{
+		ReceiptJobID:  job.ID,
+		Status:        status,
+		ReceiptScore:  score,
+		ErrorMessage:  errMsg,
+		ClaimedAmount: job.ClaimedAmount,
+	})
+}
+
 func (c *causeService) GetReceiptVerificationStatus(
 	ctx context.Context,
 	organizationID uuid.UUID,
@@ -152,6 +185,12 @@ func (c *causeService) Create(ctx context.Context, req *models.CreateCauseReques
 
 	now := req.CreatedAt
 
+	// New causes stay private until an admin approves them.
+	state := models.CauseStatePendingReview
+	if req.SaveAsDraft {
+		state = models.CauseStateDraft
+	}
+
 	cause := &models.Cause{
 		ID:                    uuid.New(),
 		Organization:          *organization,
@@ -174,11 +213,15 @@ func (c *causeService) Create(ctx context.Context, req *models.CreateCauseReques
 
 		BeneficiariesCount: valueOrDefaultInt(req.BeneficiariesCount, 0),
 		ExecutionLocation:  req.ExecutionLocation,
+		LocationState:      req.LocationState,
+		LocationCity:       req.LocationCity,
 		ImpactGoal:         req.ImpactGoal,
 		ProblemStatement:   req.ProblemStatement,
 		ExecutionPlan:      req.ExecutionPlan,
 		DonorCount:         0,
 		UpdatedAt:          now,
+		State:              state,
+		StateChangedAt:     now,
 	}
 
 	err = c.causeRepo.Create(ctx, cause)
@@ -207,6 +250,7 @@ func (c *causeService) Create(ctx context.Context, req *models.CreateCauseReques
 			if err := c.causeRepo.CreateProduct(ctx, product); err != nil {
 				return nil, err
 			}
+			product.SetProgress()
 			cause.Products = append(cause.Products, product)
 		}
 	}
@@ -232,7 +276,7 @@ func (c *causeService) CreateCauseBlood(ctx context.Context, userID uuid.UUID, r
 		return nil, err
 	}
 	if !eligibility.Eligible {
-		return nil, fmt.Errorf(eligibility.EligibilityMessage)
+		return nil, errors.New(eligibility.EligibilityMessage)
 	}
 	if req.Age <= 0 {
 		return nil, fmt.Errorf("age must be greater than zero")
@@ -451,52 +495,258 @@ func (c *causeService) GetByID(ctx context.Context, id uuid.UUID) (*models.Cause
 	if updates, err := c.causeRepo.GetUpdatesByCauseID(ctx, id); err == nil {
 		cause.Updates = updates
 	}
+	if campaigns, err := c.matchingRepo.GetRunningForCause(ctx, cause.ID, cause.Domain.ID, time.Now()); err == nil {
+		for _, m := range campaigns {
+			cause.Matching = append(cause.Matching, m.ToCauseSummary())
+		}
+	}
 
 	return cause, nil
 }
 
-func (c *causeService) GetByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]*models.Cause, error) {
+func (c *causeService) GetByOrganizationID(ctx context.Context, organizationID uuid.UUID, includeUnpublished bool) ([]*models.Cause, error) {
 	causesResult, err := c.causeRepo.GetByOrganizationID(ctx, organizationID)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return causesResult, nil
+	if includeUnpublished {
+		return causesResult, nil
+	}
+
+	public := make([]*models.Cause, 0, len(causesResult))
+	for _, cause := range causesResult {
+		if cause.State.IsPublic() {
+			public = append(public, cause)
+		}
+	}
+
+	return public, nil
 }
 
-func (c *causeService) GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*models.Cause, error) {
-	causesResult, err := c.causeRepo.GetByDomainID(ctx, domainID)
+func (c *causeService) GetByDomainID(ctx context.Context, domainID uuid.UUID, page models.CursorParams) (*models.Page[*models.Cause], error) {
+	return c.causeRepo.GetByDomainID(ctx, domainID, page)
+}
 
-	if err != nil {
-		return nil, err
+func (c *causeService) GetByAidTypeID(ctx context.Context, aidTypeID uuid.UUID, page models.CursorParams) (*models.Page[*models.Cause], error) {
+	return c.causeRepo.GetByAidTypeID(ctx, aidTypeID, page)
+}
+
+func (c *causeService) GetAllPaginated(ctx context.Context, page models.CursorParams) (*models.Page[*models.Cause], error) {
+	return c.causeRepo.GetAllPaginated(ctx, page)
+}
+
+// Limits for geo discovery, to keep a single request cheap.
+const (
+	defaultNearbyRadiusMeters = 10000
+	maxNearbyRadiusMeters     = 200000
+	maxNearbyLimit            = 100
+	defaultMapPinLimit        = 500
+	maxMapPinLimit            = 1000
+)
+
+func validLatLng(lat, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
+
+func (c *causeService) GetNearby(ctx context.Context, query *models.NearbyCausesQuery) (*models.Page[*models.NearbyCause], error) {
+	if !validLatLng(query.Lat, query.Lng) {
+		return nil, fmt.Errorf("lat must be between -90 and 90 and lng between -180 and 180")
+	}
+	if query.RadiusMeters == 0 {
+		query.RadiusMeters = defaultNearbyRadiusMeters
+	}
+	if query.RadiusMeters < 0 || query.RadiusMeters > maxNearbyRadiusMeters {
+		return nil, fmt.Errorf("radius must be between 0 and %d meters", maxNearbyRadiusMeters)
+	}
+	if query.Page.Limit > maxNearbyLimit {
+		query.Page.Limit = maxNearbyLimit
 	}
 
-	return causesResult, nil
+	return c.searchRepo.GetNearby(ctx, query)
 }
 
-func (c *causeService) GetByAidTypeID(ctx context.Context, aidTypeID uuid.UUID) ([]*models.Cause, error) {
-	causesResult, err := c.causeRepo.GetByAidTypeID(ctx, aidTypeID)
+func (c *causeService) GetInBoundingBox(ctx context.Context, box *models.CauseBoundingBox) (*models.CauseMapResponse, error) {
+	if !validLatLng(box.MinLat, box.MinLng) || !validLatLng(box.MaxLat, box.MaxLng) {
+		return nil, fmt.Errorf("bounding box coordinates are out of range")
+	}
+	if box.MinLat > box.MaxLat {
+		return nil, fmt.Errorf("min_lat must not be greater than max_lat")
+	}
+	if box.Limit <= 0 {
+		box.Limit = defaultMapPinLimit
+	}
+	if box.Limit > maxMapPinLimit {
+		box.Limit = maxMapPinLimit
+	}
 
+	pins, err := c.searchRepo.GetInBoundingBox(ctx, box)
 	if err != nil {
 		return nil, err
 	}
 
-	return causesResult, nil
+	response := &models.CauseMapResponse{Pins: pins}
+	if len(pins) > box.Limit {
+		response.Pins = pins[:box.Limit]
+		response.Truncated = true
+	}
+
+	return response, nil
 }
 
-func (c *causeService) GetAll(ctx context.Context) ([]*models.Cause, error) {
-	causesResult, err := c.causeRepo.GetAll(ctx)
+func (c *causeService) Search(ctx context.Context, filter *models.CauseSearchFilter) (*models.CauseSearchResult, error) {
+	if filter.Sort == "" {
+		filter.Sort = models.CauseSearchSortNewest
+		if filter.Query != "" {
+			filter.Sort = models.CauseSearchSortRelevance
+		}
+	}
+	if !filter.Sort.IsValid() {
+		return nil, fmt.Errorf("invalid sort: %s", filter.Sort)
+	}
+	for _, status := range filter.FundingStatuses {
+		if !models.IsValidFundingStatus(status) {
+			return nil, fmt.Errorf("invalid funding_status: %s", status)
+		}
+	}
+	for _, band := range filter.TrustBands {
+		if !band.IsValid() {
+			return nil, fmt.Errorf("invalid trust_band: %s", band)
+		}
+	}
+	if filter.DeadlineFrom != nil && filter.DeadlineTo != nil && filter.DeadlineTo.Before(*filter.DeadlineFrom) {
+		return nil, fmt.Errorf("deadline_to must be after deadline_from")
+	}
+
+	return c.searchRepo.Search(ctx, filter)
+}
 
+// Update applies an edit to a cause and records it as a new revision.
+//
+// Once donations have arrived the goal may not drop below the collected
+// amount and the deadline may only be extended. The geo-fence is locked
+// once the execution window has started, because proof uploads are
+// validated against it.
+func (c *causeService) Update(ctx context.Context, causeID uuid.UUID, editorID uuid.UUID, req *models.UpdateCauseRequest) (*models.Cause, *models.CauseRevision, error) {
+	cause, err := c.causeRepo.GetByID(ctx, causeID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return causesResult, nil
+	now := time.Now()
+	hasDonations := cause.CollectedAmount > 0 || cause.DonorCount > 0
+	changes := make([]models.CauseFieldChange, 0)
+
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return nil, nil, errors.New("title cannot be empty")
+		}
+		if title != cause.Title {
+			changes = append(changes, models.CauseFieldChange{Field: "title", From: cause.Title, To: title})
+			cause.Title = title
+		}
+	}
+
+	if req.Description != nil && valueOrDefaultString(cause.Description, "") != *req.Description {
+		changes = append(changes, models.CauseFieldChange{Field: "description", From: cause.Description, To: *req.Description})
+		cause.Description = req.Description
+	}
+
+	if req.GoalAmount != nil {
+		goal := *req.GoalAmount
+		if goal <= 0 {
+			return nil, nil, errors.New("goal amount must be greater than zero")
+		}
+		if hasDonations && goal < cause.CollectedAmount {
+			return nil, nil, fmt.Errorf("goal amount cannot be lower than the amount already collected (%.2f)", cause.CollectedAmount)
+		}
+		if cause.GoalAmount == nil || *cause.GoalAmount != goal {
+			changes = append(changes, models.CauseFieldChange{Field: "goal_amount", From: cause.GoalAmount, To: goal})
+			cause.GoalAmount = &goal
+		}
+	}
+
+	if req.Deadline != nil {
+		deadline := *req.Deadline
+		if !deadline.After(now) {
+			return nil, nil, errors.New("deadline must be in the future")
+		}
+		if hasDonations && cause.Deadline != nil && deadline.Before(*cause.Deadline) {
+			return nil, nil, errors.New("deadline can only be extended after donations have been received")
+		}
+		if cause.Deadline == nil || !cause.Deadline.Equal(deadline) {
+			changes = append(changes, models.CauseFieldChange{Field: "deadline", From: cause.Deadline, To: deadline})
+			cause.Deadline = &deadline
+		}
+	}
+
+	if req.ExecutionPlan != nil && valueOrDefaultString(cause.ExecutionPlan, "") != *req.ExecutionPlan {
+		changes = append(changes, models.CauseFieldChange{Field: "execution_plan", From: cause.ExecutionPlan, To: *req.ExecutionPlan})
+		cause.ExecutionPlan = req.ExecutionPlan
+	}
+
+	geoFenceChanges := make([]models.CauseFieldChange, 0, 3)
+	if req.ExecutionLat != nil && (cause.ExecutionLat == nil || *cause.ExecutionLat != *req.ExecutionLat) {
+		if *req.ExecutionLat < -90 || *req.ExecutionLat > 90 {
+			return nil, nil, errors.New("execution_lat must be between -90 and 90")
+		}
+		geoFenceChanges = append(geoFenceChanges, models.CauseFieldChange{Field: "execution_lat", From: cause.ExecutionLat, To: *req.ExecutionLat})
+		cause.ExecutionLat = req.ExecutionLat
+	}
+	if req.ExecutionLng != nil && (cause.ExecutionLng == nil || *cause.ExecutionLng != *req.ExecutionLng) {
+		if *req.ExecutionLng < -180 || *req.ExecutionLng > 180 {
+			return nil, nil, errors.New("execution_lng must be between -180 and 180")
+		}
+		geoFenceChanges = append(geoFenceChanges, models.CauseFieldChange{Field: "execution_lng", From: cause.ExecutionLng, To: *req.ExecutionLng})
+		cause.ExecutionLng = req.ExecutionLng
+	}
+	if req.ExecutionRadiusMeters != nil && (cause.ExecutionRadiusMeters == nil || *cause.ExecutionRadiusMeters != *req.ExecutionRadiusMeters) {
+		if *req.ExecutionRadiusMeters <= 0 {
+			return nil, nil, errors.New("execution_radius_meters must be greater than zero")
+		}
+		geoFenceChanges = append(geoFenceChanges, models.CauseFieldChange{Field: "execution_radius_meters", From: cause.ExecutionRadiusMeters, To: *req.ExecutionRadiusMeters})
+		cause.ExecutionRadiusMeters = req.ExecutionRadiusMeters
+	}
+	if len(geoFenceChanges) > 0 {
+		if cause.ExecutionStartTime != nil && !cause.ExecutionStartTime.After(now) {
+			return nil, nil, errors.New("the execution geo-fence cannot be changed after execution has started")
+		}
+		changes = append(changes, geoFenceChanges...)
+	}
+
+	if len(changes) == 0 {
+		return nil, nil, errors.New("no changes to apply")
+	}
+
+	revision := &models.CauseRevision{
+		ID:       uuid.New(),
+		CauseID:  cause.ID,
+		EditedBy: &editorID,
+		Changes:  changes,
+		Reason:   req.Reason,
+	}
+
+	if err := c.causeRepo.Update(ctx, cause, revision); err != nil {
+		return nil, nil, err
+	}
+
+	revision.Title = cause.Title
+	revision.Description = cause.Description
+	revision.GoalAmount = cause.GoalAmount
+	revision.Deadline = cause.Deadline
+	revision.ExecutionPlan = cause.ExecutionPlan
+	revision.ExecutionLat = cause.ExecutionLat
+	revision.ExecutionLng = cause.ExecutionLng
+	revision.ExecutionRadiusMeters = cause.ExecutionRadiusMeters
+	cause.UpdatedAt = revision.CreatedAt
+
+	return cause, revision, nil
 }
 
-func (c *causeService) GetAllPaginated(ctx context.Context, limit, offset int) ([]*models.Cause, int64, error) {
-	return c.causeRepo.GetAllPaginated(ctx, limit, offset)
+func (c *causeService) GetRevisions(ctx context.Context, causeID uuid.UUID) ([]*models.CauseRevision, error) {
+	return c.causeRepo.GetRevisionsByCauseID(ctx, causeID)
 }
 
 func (c *causeService) Delete(ctx context.Context, id uuid.UUID) error {
@@ -711,6 +961,8 @@ func (c *causeService) CreateUpdate(ctx context.Context, causeID uuid.UUID, req
 		}()
 	}
 
+	c.notifier.CauseUpdatePosted(ctx, causeID, update)
+
 	return update, nil
 }
 
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func fundedCause() *models.Cause {
+	cause := liveCause()
+	goal := float32(10000)
+	deadline := time.Now().Add(30 * 24 * time.Hour)
+	lat, lng, radius := 12.97, 77.59, 500
+	cause.GoalAmount = &goal
+	cause.Deadline = &deadline
+	cause.CollectedAmount = 4000
+	cause.DonorCount = 3
+	cause.ExecutionLat, cause.ExecutionLng, cause.ExecutionRadiusMeters = &lat, &lng, &radius
+	return cause
+}
+
+func TestCauseUpdateRules(t *testing.T) {
+	ptr := func(v float32) *float32 { return &v }
+	at := func(d time.Duration) *time.Time { t := time.Now().Add(d); return &t }
+	lat := 13.01
+
+	tests := []struct {
+		name    string
+		setup   func(*models.Cause)
+		req     models.UpdateCauseRequest
+		wantErr string
+	}{
+		{name: "goal above collected", req: models.UpdateCauseRequest{GoalAmount: ptr(4000)}},
+		{name: "goal below collected", req: models.UpdateCauseRequest{GoalAmount: ptr(3999)}, wantErr: "lower than the amount already collected"},
+		{
+			name:  "goal below collected before donations",
+			setup: func(c *models.Cause) { c.CollectedAmount, c.DonorCount = 0, 0 },
+			req:   models.UpdateCauseRequest{GoalAmount: ptr(100)},
+		},
+		{name: "deadline extended", req: models.UpdateCauseRequest{Deadline: at(60 * 24 * time.Hour)}},
+		{name: "deadline shortened", req: models.UpdateCauseRequest{Deadline: at(24 * time.Hour)}, wantErr: "can only be extended"},
+		{
+			name:  "deadline shortened before donations",
+			setup: func(c *models.Cause) { c.CollectedAmount, c.DonorCount = 0, 0 },
+			req:   models.UpdateCauseRequest{Deadline: at(24 * time.Hour)},
+		},
+		{name: "deadline in the past", req: models.UpdateCauseRequest{Deadline: at(-time.Hour)}, wantErr: "must be in the future"},
+		{
+			name:  "geo-fence before execution",
+			setup: func(c *models.Cause) { c.ExecutionStartTime = at(time.Hour) },
+			req:   models.UpdateCauseRequest{ExecutionLat: &lat},
+		},
+		{
+			name:    "geo-fence after execution started",
+			setup:   func(c *models.Cause) { c.ExecutionStartTime = at(-time.Hour) },
+			req:     models.UpdateCauseRequest{ExecutionLat: &lat},
+			wantErr: "geo-fence cannot be changed",
+		},
+		{name: "nothing changed", req: models.UpdateCauseRequest{GoalAmount: ptr(10000)}, wantErr: "no changes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cause := fundedCause()
+			if tt.setup != nil {
+				tt.setup(cause)
+			}
+			causes := newFakeCauseRepo(cause)
+			service := NewCauseService(causes, nil, nil, nil, nopWebhooks{}, nopNotifier{})
+
+			_, _, err := service.Update(context.Background(), cause.ID, uuid.New(), &tt.req)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Update() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %q", err, tt.wantErr)
+			}
+			if len(causes.revisions[cause.ID]) != 0 {
+				t.Errorf("a rejected edit saved %d revisions", len(causes.revisions[cause.ID]))
+			}
+		})
+	}
+}
+
+func TestCauseUpdateRecordsRevision(t *testing.T) {
+	cause := fundedCause()
+	causes := newFakeCauseRepo(cause)
+	service := NewCauseService(causes, nil, nil, nil, nopWebhooks{}, nopNotifier{})
+	editor := uuid.New()
+	title := "Clean water for Rampur and Sitapur"
+	goal := float32(15000)
+	reason := "Second village joined"
+
+	updated, revision, err := service.Update(context.Background(), cause.ID, editor, &models.UpdateCauseRequest{
+		Title:      &title,
+		GoalAmount: &goal,
+		Reason:     &reason,
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if updated.Title != title || *updated.GoalAmount != goal {
+		t.Errorf("updated cause = %q %v, want %q %v", updated.Title, *updated.GoalAmount, title, goal)
+	}
+	if revision.RevisionNumber != 2 {
+		t.Errorf("RevisionNumber = %d, want 2 after the baseline", revision.RevisionNumber)
+	}
+	if revision.EditedBy == nil || *revision.EditedBy != editor || revision.Reason == nil || *revision.Reason != reason {
+		t.Errorf("revision by %v for %v, want %s for %q", revision.EditedBy, revision.Reason, editor, reason)
+	}
+	// The revision is a snapshot of the cause after the edit, not only the
+	// fields that changed.
+	if revision.Title != title || *revision.GoalAmount != goal || !revision.Deadline.Equal(*cause.Deadline) || *revision.ExecutionRadiusMeters != *cause.ExecutionRadiusMeters {
+		t.Errorf("revision snapshot = %q %v %v %v", revision.Title, *revision.GoalAmount, revision.Deadline, *revision.ExecutionRadiusMeters)
+	}
+	if len(revision.Changes) != 2 || revision.Changes[0].Field != "title" || revision.Changes[1].Field != "goal_amount" {
+		t.Errorf("Changes = %+v, want title and goal_amount", revision.Changes)
+	}
+
+	baseline := causes.revisions[cause.ID][0]
+	if baseline.RevisionNumber != 1 || baseline.Title != cause.Title || *baseline.GoalAmount != *cause.GoalAmount {
+		t.Errorf("baseline revision = %d %q %v, want the cause before the edit", baseline.RevisionNumber, baseline.Title, *baseline.GoalAmount)
+	}
+}
+
+func TestCauseCreateStaysPrivate(t *testing.T) {
+	for _, tt := range []struct {
+		draft bool
+		want  models.CauseState
+	}{
+		{true, models.CauseStateDraft},
+		{false, models.CauseStatePendingReview},
+	} {
+		causes := newFakeCauseRepo()
+		service := NewCauseService(causes, nil, nil, nil, nopWebhooks{}, nopNotifier{})
+		orgID := uuid.New()
+		ctx := context.WithValue(context.Background(), "organizationID", orgID)
+
+		cause, err := service.Create(ctx, &models.CreateCauseRequest{
+			Title:       "Clean water for Rampur",
+			DomainID:    uuid.New(),
+			AidTypeID:   uuid.New(),
+			SaveAsDraft: tt.draft,
+		})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if cause.State != tt.want {
+			t.Errorf("Create(draft=%v) state = %s, want %s", tt.draft, cause.State, tt.want)
+		}
+
+		public, _ := service.GetByOrganizationID(context.Background(), orgID, false)
+		own, _ := service.GetByOrganizationID(context.Background(), orgID, true)
+		if len(public) != 0 || len(own) != 1 {
+			t.Errorf("%s cause: %d public and %d for the organization, want 0 and 1", cause.State, len(public), len(own))
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// DisputeService lets people raise disputes about causes for the platform
+// to review.
+type DisputeService interface {
+	// Open returns sql.ErrNoRows if the cause doesn't exist or isn't
+	// public.
+	Open(ctx context.Context, userID uuid.UUID, req *models.OpenDisputeRequest) (*models.Dispute, error)
+
+	// List and Update are for the platform. List returns disputes newest
+	// first, only those with status if it's set.
+	List(ctx context.Context, status models.DisputeStatus, page models.CursorParams) (*models.Page[*models.Dispute], error)
+	// Update returns sql.ErrNoRows if there's no such dispute.
+	Update(ctx context.Context, disputeID, adminID uuid.UUID, req *models.UpdateDisputeRequest) (*models.Dispute, error)
+}
+
+type disputeService struct {
+	disputeRepo repository.DisputeRepository
+	causeRepo   repository.CauseRepository
+	webhooks    WebhookPublisher
+	notifier    Notifier
+}
+
+func NewDisputeService(disputeRepo repository.DisputeRepository, causeRepo repository.CauseRepository, webhooks WebhookPublisher, notifier Notifier) *disputeService {
+	return &disputeService{
+		disputeRepo: disputeRepo,
+		causeRepo:   causeRepo,
+		webhooks:    webhooks,
+		notifier:    notifier,
+	}
+}
+
+func (s *disputeService) Open(ctx context.Context, userID uuid.UUID, req *models.OpenDisputeRequest) (*models.Dispute, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" || len(title) > 255 {
+		return nil, errors.New("title is required and must be at most 255 characters")
+	}
+	description := strings.TrimSpace(req.Description)
+	if description == "" || len(description) > 5000 {
+		return nil, errors.New("description is required and must be at most 5000 characters")
+	}
+	var evidenceURL *string
+	if req.EvidenceURL != nil && strings.TrimSpace(*req.EvidenceURL) != "" {
+		raw := strings.TrimSpace(*req.EvidenceURL)
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, errors.New("evidence_url must be an http or https URL")
+		}
+		evidenceURL = &raw
+	}
+
+	cause, err := s.causeRepo.GetByID(ctx, req.CauseID)
+	if err != nil {
+		return nil, err
+	}
+	if !cause.State.IsPublic() {
+		return nil, sql.ErrNoRows
+	}
+
+	now := time.Now()
+	dispute := &models.Dispute{
+		ID:             uuid.New(),
+		OrganizationID: cause.Organization.ID,
+		CauseID:        &cause.ID,
+		OpenedBy:       &userID,
+		Title:          title,
+		Description:    description,
+		EvidenceURL:    evidenceURL,
+		Status:         models.DisputeStatusOpen,
+		Priority:       models.DisputePriorityMedium,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.disputeRepo.Create(ctx, dispute); err != nil {
+		return nil, fmt.Errorf("failed to open dispute: %w", err)
+	}
+
+	s.webhooks.Publish(ctx, dispute.OrganizationID, models.WebhookDisputeOpened, &models.WebhookDisputeData{
+		DisputeID: dispute.ID,
+		CauseID:   dispute.CauseID,
+		Title:     dispute.Title,
+		Priority:  string(dispute.Priority),
+		CreatedAt: dispute.CreatedAt,
+	})
+	s.notifier.DisputeUpdated(ctx, dispute)
+	return dispute, nil
+}
+
+func (s *disputeService) List(ctx context.Context, status models.DisputeStatus, page models.CursorParams) (*models.Page[*models.Dispute], error) {
+	if status != "" && !status.IsValid() {
+		return nil, fmt.Errorf("unknown dispute status %q", status)
+	}
+	return s.disputeRepo.List(ctx, status, page)
+}
+
+func (s *disputeService) Update(ctx context.Context, disputeID, adminID uuid.UUID, req *models.UpdateDisputeRequest) (*models.Dispute, error) {
+	if req.Status != nil && !req.Status.IsValid() {
+		return nil, fmt.Errorf("unknown dispute status %q", *req.Status)
+	}
+	if req.Priority != nil && !req.Priority.IsValid() {
+		return nil, fmt.Errorf("unknown dispute priority %q", *req.Priority)
+	}
+	if req.ResolutionNotes != nil && len(*req.ResolutionNotes) > 5000 {
+		return nil, errors.New("resolution_notes must be at most 5000 characters")
+	}
+
+	dispute, err := s.disputeRepo.GetByID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	statusChanged := req.Status != nil && *req.Status != dispute.Status
+	if statusChanged {
+		dispute.Status = *req.Status
+		// Reopening a decided dispute clears who decided it.
+		dispute.ResolvedBy, dispute.ResolvedAt = nil, nil
+		if dispute.Status.IsClosed() {
+			dispute.ResolvedBy, dispute.ResolvedAt = &adminID, &now
+		}
+	}
+	if req.Priority != nil {
+		dispute.Priority = *req.Priority
+	}
+	if req.ResolutionNotes != nil {
+		notes := strings.TrimSpace(*req.ResolutionNotes)
+		dispute.ResolutionNotes = &notes
+		if notes == "" {
+			dispute.ResolutionNotes = nil
+		}
+	}
+	dispute.UpdatedAt = now
+
+	if err := s.disputeRepo.Update(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	if statusChanged {
+		s.notifier.DisputeUpdated(ctx, dispute)
+	}
+	return dispute, nil
+}
@@ -2,13 +2,16 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
-	"server/internal/blockchain"
+	"net/mail"
 	"server/internal/blockchain/contracts"
 	"server/internal/models"
 	"server/internal/repository"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,9 +21,9 @@ type DonationService interface {
 	Create(ctx context.Context, req *models.CreateDonationRequest) (*models.Donation, error)
 
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Donation, error)
-	GetByCauseID(ctx context.Context, id uuid.UUID) ([]*models.Donation, error)
+	GetByCauseID(ctx context.Context, id uuid.UUID, page models.CursorParams) (*models.Page[*models.Donation], error)
 	GetByPaymentID(ctx context.Context, id uuid.UUID) (*models.Donation, error)
-	GetByUserID(ctx context.Context, id uuid.UUID) ([]*models.Donation, error)
+	GetByUserID(ctx context.Context, id uuid.UUID, causeID *uuid.UUID, page models.CursorParams) (*models.Page[*models.Donation], error)
 
 	GetFromChainByID(ctx context.Context, id uuid.UUID) (*contracts.DonationLedgerDonation, error)
 	GetFromChainByCauseID(ctx context.Context, id uuid.UUID) ([]*contracts.DonationLedgerDonation, error)
@@ -29,28 +32,105 @@ type DonationService interface {
 	// Delete(ctx context.Context, id uuid.UUID) error
 }
 
+// DonationLedger writes donations to the on-chain donation ledger, with
+// amounts in paise. *blockchain.DonationChainService implements it.
+type DonationLedger interface {
+	RecordDonation(ctx context.Context, donationID, causeID, donorID uuid.UUID, amount *big.Int, paymentRef string) (string, error)
+	GetDonation(ctx context.Context, donationID uuid.UUID) (*contracts.DonationLedgerDonation, error)
+	GetDonationsByCause(ctx context.Context, causeID uuid.UUID) ([][16]byte, error)
+}
+
+// MilestoneTracker counts donations toward a cause's on-chain milestones.
+// *blockchain.MilestoneTrackerService implements it. Amounts are in paise.
+// RecordDonation adds to the cause's total, so each donation must reach it
+// only once.
+type MilestoneTracker interface {
+	EnsureCauseRegistered(ctx context.Context, causeID uuid.UUID, goal, initialCollected *big.Int) error
+	RecordDonation(ctx context.Context, causeID uuid.UUID, amount *big.Int) (string, error)
+}
+
+// Donations are saved before they are written to the ledger. The request
+// that saves one holds it for ledgerLease while it tries; after that the
+// ledger sync retries it every ledgerSyncInterval until it succeeds.
+const (
+	ledgerSyncInterval = time.Minute
+	ledgerLease        = 5 * time.Minute
+	ledgerBatchSize    = 20
+)
+
+// Once a donation is on the ledger its hash must be saved, or the ledger
+// sync would try to record it again. Saving is retried txHashSaveAttempts
+// times, txHashRetryDelay apart.
+const (
+	txHashSaveAttempts = 3
+	txHashRetryDelay   = 100 * time.Millisecond
+)
+
+// piiBackfillBatchSize is how many donations encryptPlaintextPII rewrites
+// per transaction.
+const piiBackfillBatchSize = 100
+
 type donationService struct {
 	donationRepo   repository.DonationRepository
-	chainService   blockchain.DonationChainService
-	trackerService *blockchain.MilestoneTrackerService
+	chainService   DonationLedger
+	tracker        MilestoneTracker
 	causeRepo      repository.CauseRepository
+	matchingRepo   repository.MatchingCampaignRepository
+	fundraiserRepo repository.FundraiserRepository
+	mailer         Mailer
+	webhooks       WebhookPublisher
+	notifier       Notifier
+	ledgerWake     chan struct{}
 }
 
 func NewDonationService(
 	donationRepo repository.DonationRepository,
-	chainService blockchain.DonationChainService,
-	trackerService *blockchain.MilestoneTrackerService,
+	chainService DonationLedger,
+	tracker MilestoneTracker,
 	causeRepo repository.CauseRepository,
+	matchingRepo repository.MatchingCampaignRepository,
+	fundraiserRepo repository.FundraiserRepository,
+	mailer Mailer,
+	webhooks WebhookPublisher,
+	notifier Notifier,
 ) *donationService {
 	return &donationService{
 		donationRepo:   donationRepo,
 		chainService:   chainService,
-		trackerService: trackerService,
+		tracker:        tracker,
 		causeRepo:      causeRepo,
+		matchingRepo:   matchingRepo,
+		fundraiserRepo: fundraiserRepo,
+		mailer:         mailer,
+		webhooks:       webhooks,
+		notifier:       notifier,
+		ledgerWake:     make(chan struct{}, 1),
 	}
 }
 
 func (c *donationService) Create(ctx context.Context, req *models.CreateDonationRequest) (*models.Donation, error) {
+	// Get cause for state check and milestone tracking
+	cause, err := c.causeRepo.GetByID(ctx, req.CauseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cause: %w", err)
+	}
+	if !cause.State.AcceptsDonations() {
+		return nil, fmt.Errorf("cause is not accepting donations (state: %s)", cause.State)
+	}
+
+	if req.FundraiserID != nil {
+		if err := c.checkFundraiser(ctx, *req.FundraiserID, cause.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Line items are checked up front for a clear error; the repository
+	// repeats the quantity check atomically when saving.
+	items, err := c.buildDonationItems(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
 	donation := &models.Donation{
 		ID:             uuid.New(),
 		CauseID:        req.CauseID,
@@ -64,67 +144,461 @@ func (c *donationService) Create(ctx context.Context, req *models.CreateDonation
 		PanNumber:      req.PanNumber,
 		PaymentID:      req.PaymentID,
 		CreatedAt:      time.Now(),
+		FundraiserID:   req.FundraiserID,
+		IsAnonymous:    req.IsAnonymous,
+		Items:          items,
 	}
+	if err := setTribute(donation, req.Tribute); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		item.DonationID = donation.ID
+		item.CreatedAt = donation.CreatedAt
+	}
+
+	if err := c.record(ctx, cause, donation); err != nil {
+		return nil, err
+	}
+
+	// Matching never fails the donor's donation; problems are logged.
+	c.applyMatching(ctx, cause, donation)
+
+	if donation.TributeNotifyEmail != nil {
+		c.sendTributeCard(ctx, cause, donation)
+	}
+
+	return donation, nil
+}
+
+// record saves a donation and then writes it to the ledger and the
+// milestone tracker. Saving comes first so a donation that fails its checks
+// never reaches the chain. If the ledger write fails the donation stays
+// saved without a tx_hash and the ledger sync retries it.
+func (c *donationService) record(ctx context.Context, cause *models.Cause, donation *models.Donation) error {
+	retryAt := donation.CreatedAt.Add(ledgerLease)
+	donation.LedgerRetryAt = &retryAt
+
+	if err := c.donationRepo.Create(ctx, donation); err != nil {
+		return err
+	}
+
+	if err := c.recordOnChain(ctx, donation); err != nil {
+		log.Printf("Warning: failed to record donation %v on the ledger, will retry: %v", donation.ID, err)
+	}
+
+	c.announce(ctx, cause, donation)
+	return nil
+}
+
+// recordLater saves a donation and leaves its ledger write to the ledger
+// sync, so the caller doesn't wait on the chain.
+func (c *donationService) recordLater(ctx context.Context, cause *models.Cause, donation *models.Donation) error {
+	retryAt := donation.CreatedAt
+	donation.LedgerRetryAt = &retryAt
 
-	// Record in DonationLedger (for record-keeping)
+	if err := c.donationRepo.Create(ctx, donation); err != nil {
+		return err
+	}
+
+	c.nudgeLedger()
+	c.announce(ctx, cause, donation)
+	return nil
+}
+
+// announce sends the donation's webhook and notifications.
+func (c *donationService) announce(ctx context.Context, cause *models.Cause, donation *models.Donation) {
+	if cause != nil {
+		c.webhooks.Publish(ctx, cause.Organization.ID, models.WebhookDonationCompleted, &models.WebhookDonationData{
+			DonationID:         donation.ID,
+			CauseID:            donation.CauseID,
+			Amount:             donation.Amount,
+			IsAnonymous:        donation.IsAnonymous,
+			FundraiserID:       donation.FundraiserID,
+			MatchingCampaignID: donation.MatchingCampaignID,
+			MatchedDonationID:  donation.MatchedDonationID,
+			TxHash:             donation.TxHash,
+			CreatedAt:          donation.CreatedAt,
+		})
+		c.notifier.DonationCompleted(ctx, cause, donation)
+	}
+}
+
+// recordOnChain writes a saved donation to the DonationLedger and, once
+// that has succeeded, to the milestone tracker.
+func (c *donationService) recordOnChain(ctx context.Context, donation *models.Donation) error {
 	txHash, err := c.chainService.RecordDonation(
 		ctx,
 		donation.ID,
 		donation.CauseID,
-		donation.UserID,
+		donation.DonorRef(),
 		big.NewInt(int64(donation.Amount)),
 		*donation.PaymentID,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	donation.TxHash = &txHash
+	donation.LedgerRetryAt = nil
+	saveErr := c.saveTxHash(ctx, donation.ID, txHash)
 
-	// Get cause for milestone tracking
+	// The donation is on the ledger whether or not its hash was saved, so
+	// it counts toward the cause's milestones either way.
+	c.recordMilestone(ctx, donation)
+
+	if saveErr != nil {
+		return fmt.Errorf("donation is on the ledger in %s but saving the hash failed: %w", txHash, saveErr)
+	}
+	return nil
+}
+
+// saveTxHash saves a donation's ledger transaction, retrying a few times.
+// It outlives ctx, because the donation is already on the ledger.
+func (c *donationService) saveTxHash(ctx context.Context, donationID uuid.UUID, txHash string) error {
+	ctx = context.WithoutCancel(ctx)
+	var err error
+	for attempt := 1; attempt <= txHashSaveAttempts; attempt++ {
+		if err = c.donationRepo.SetTxHash(ctx, donationID, txHash); err == nil {
+			return nil
+		}
+		if attempt < txHashSaveAttempts {
+			time.Sleep(time.Duration(attempt) * txHashRetryDelay)
+		}
+	}
+	return err
+}
+
+// recordMilestone adds a donation to its cause's total on the milestone
+// tracker. The donation is marked as counted first, so a ledger retry
+// never counts it again; if the tracker then fails, it isn't counted.
+func (c *donationService) recordMilestone(ctx context.Context, donation *models.Donation) {
+	if c.tracker == nil {
+		return
+	}
+	// Read after the donation was saved, so the collected amount includes it
 	cause, err := c.causeRepo.GetByID(ctx, donation.CauseID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get cause: %w", err)
+		log.Printf("Warning: failed to load cause %v for the milestone tracker: %v", donation.CauseID, err)
+		return
+	}
+	if cause.GoalAmount == nil {
+		return
 	}
 
-	// If tracker service is available, record donation for milestone tracking
-	if c.trackerService != nil && cause != nil && cause.GoalAmount != nil {
-		// Calculate total collected amount for this cause from database
-		// This ensures the contract starts with the correct baseline when registering
-		collectedInDB := float32(cause.CollectedAmount) // Already includes all donations
-		
-		// Convert rupees to integers for contract (database stores with 2 decimals)
-		goalAmount := big.NewInt(int64(*cause.GoalAmount))
-		collectedAmount := big.NewInt(int64(collectedInDB))
-
-		err := c.trackerService.EnsureCauseRegistered(
-			ctx,
-			donation.CauseID,
-			goalAmount,
-			collectedAmount,
-		)
+	claimed, err := c.donationRepo.ClaimMilestoneRecording(ctx, donation.ID, time.Now())
+	if err != nil {
+		log.Printf("Warning: failed to mark donation %v for the milestone tracker: %v", donation.ID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	// The tracker counts in paise, like the ledger. A cause registered now
+	// starts from what it had collected before this donation, which
+	// RecordDonation then adds
+	goalAmount := big.NewInt(int64(models.ToPaise(float64(*cause.GoalAmount))))
+	collectedAmount := big.NewInt(int64(models.ToPaise(float64(cause.CollectedAmount)) - donation.Amount))
+
+	err = c.tracker.EnsureCauseRegistered(
+		ctx,
+		donation.CauseID,
+		goalAmount,
+		collectedAmount,
+	)
+	if err != nil {
+		log.Printf("Warning: Failed to ensure cause registration: %v", err)
+		// Continue anyway - we'll try again on next donation
+	}
+
+	// Record the donation amount for milestone calculation
+	donationAmount := big.NewInt(int64(donation.Amount))
+
+	_, err = c.tracker.RecordDonation(ctx, donation.CauseID, donationAmount)
+	if err != nil {
+		log.Printf("Warning: Failed to record donation %v in milestone tracker: %v", donation.ID, err)
+		// Don't fail the whole donation if milestone tracking fails
+	} else {
+		log.Printf("Successfully recorded donation in milestone tracker for cause %v", donation.CauseID)
+	}
+}
+
+// Start retries ledger writes for saved donations until ctx is done.
+func (c *donationService) Start(ctx context.Context) {
+	c.encryptPlaintextPII(ctx)
+
+	ticker := time.NewTicker(ledgerSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		c.syncLedger(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-c.ledgerWake:
+		case <-ctx.Done():
+			log.Println("Stopping donation ledger sync")
+			return
+		}
+	}
+}
+
+// encryptPlaintextPII encrypts donor details saved before encryption was
+// enabled, a batch at a time. Once every row is done it costs one query,
+// so it runs on each start.
+func (c *donationService) encryptPlaintextPII(ctx context.Context) {
+	total := 0
+	for ctx.Err() == nil {
+		n, err := c.donationRepo.EncryptPlaintextPII(ctx, piiBackfillBatchSize)
 		if err != nil {
-			log.Printf("Warning: Failed to ensure cause registration: %v", err)
-			// Continue anyway - we'll try again on next donation
+			log.Printf("Warning: failed to encrypt plaintext donor details: %v", err)
+			return
+		}
+		total += n
+		if n < piiBackfillBatchSize {
+			break
 		}
+	}
+	if total > 0 {
+		log.Printf("Encrypted plaintext donor details on %d donations", total)
+	}
+}
 
-		// Record the donation amount for milestone calculation
-		donationAmount := big.NewInt(int64(donation.Amount))
+// nudgeLedger wakes the ledger sync without waiting for its next tick.
+func (c *donationService) nudgeLedger() {
+	select {
+	case c.ledgerWake <- struct{}{}:
+	default:
+	}
+}
 
-		_, err = c.trackerService.RecordDonation(ctx, donation.CauseID, donationAmount)
+// syncLedger records due donations on the ledger a batch at a time until
+// none are left.
+func (c *donationService) syncLedger(ctx context.Context) {
+	for ctx.Err() == nil {
+		ids, err := c.donationRepo.ClaimUnrecorded(ctx, time.Now(), ledgerLease, ledgerBatchSize)
 		if err != nil {
-			log.Printf("Warning: Failed to record donation in milestone tracker: %v", err)
-			// Don't fail the whole donation if milestone tracking fails
-		} else {
-			log.Printf("Successfully recorded donation in milestone tracker for cause %v", donation.CauseID)
+			log.Printf("Warning: failed to claim donations for the ledger: %v", err)
+			return
+		}
+
+		for _, id := range ids {
+			donation, err := c.donationRepo.GetByID(ctx, id)
+			if err != nil {
+				log.Printf("Warning: failed to load donation %v for the ledger: %v", id, err)
+				continue
+			}
+			if err := c.recordOnChain(ctx, donation); err != nil {
+				log.Printf("Warning: failed to record donation %v on the ledger, will retry: %v", id, err)
+			}
+		}
+
+		if len(ids) < ledgerBatchSize {
+			return
 		}
 	}
+}
 
-	if err := c.donationRepo.Create(ctx, donation); err != nil {
-		return nil, err
+// applyMatching creates a sponsor donation for each running matching
+// campaign covering the cause. Each campaign's budget is reserved before
+// its sponsor donation is saved and released again if saving fails. The
+// sponsor donations reach the ledger through the ledger sync, so the donor
+// doesn't wait on a chain write per campaign.
+func (c *donationService) applyMatching(ctx context.Context, cause *models.Cause, donation *models.Donation) {
+	if c.matchingRepo == nil {
+		return
 	}
 
-	return donation, nil
+	campaigns, err := c.matchingRepo.GetRunningForCause(ctx, cause.ID, cause.Domain.ID, donation.CreatedAt)
+	if err != nil {
+		log.Printf("Warning: failed to load matching campaigns for cause %v: %v", cause.ID, err)
+		return
+	}
+
+	for _, campaign := range campaigns {
+		if !campaign.RunningAt(donation.CreatedAt) {
+			continue
+		}
+		amount, err := c.matchingRepo.Reserve(ctx, campaign.ID, campaign.MatchFor(donation.Amount.Rupees()))
+		if err != nil {
+			log.Printf("Warning: failed to reserve match from campaign %v: %v", campaign.ID, err)
+			continue
+		}
+		if amount <= 0 {
+			continue
+		}
+
+		campaignID := campaign.ID
+		// One donation can be matched by several campaigns, so the
+		// reference names both.
+		paymentRef := "match_" + campaign.ID.String() + "_" + donation.ID.String()
+		match := &models.Donation{
+			ID:                 uuid.New(),
+			CauseID:            cause.ID,
+			UserID:             campaign.SponsorUserID,
+			Name:               campaign.SponsorName,
+			Amount:             models.ToPaise(amount),
+			Status:             models.DonationStatusCompleted,
+			PaymentID:          &paymentRef,
+			CreatedAt:          time.Now(),
+			MatchingCampaignID: &campaignID,
+			MatchedDonationID:  &donation.ID,
+		}
+
+		if err := c.recordLater(ctx, cause, match); err != nil {
+			log.Printf("Warning: failed to record match from campaign %v for donation %v: %v", campaign.ID, donation.ID, err)
+			if err := c.matchingRepo.Release(ctx, campaign.ID, amount); err != nil {
+				log.Printf("Warning: failed to release match reservation on campaign %v: %v", campaign.ID, err)
+			}
+		}
+	}
+}
+
+const maxTributeMessageLength = 500
+
+// setTribute validates a tribute dedication and copies it onto the donation.
+func setTribute(donation *models.Donation, tribute *models.DonationTribute) error {
+	if tribute == nil {
+		return nil
+	}
+	if !tribute.Type.IsValid() {
+		return fmt.Errorf("invalid tribute type: %s", tribute.Type)
+	}
+	name := strings.TrimSpace(tribute.Name)
+	if name == "" {
+		return errors.New("tribute name is required")
+	}
+	if tribute.Message != nil && len(*tribute.Message) > maxTributeMessageLength {
+		return fmt.Errorf("tribute message must be at most %d characters", maxTributeMessageLength)
+	}
+	if tribute.NotifyEmail != nil {
+		addr, err := mail.ParseAddress(*tribute.NotifyEmail)
+		if err != nil {
+			return errors.New("invalid tribute notify email")
+		}
+		donation.TributeNotifyEmail = &addr.Address
+	}
+
+	donation.TributeType = &tribute.Type
+	donation.TributeName = &name
+	donation.TributeMessage = tribute.Message
+	return nil
+}
+
+// sendTributeCard emails the tribute e-card. Failures are logged and not
+// retried; tribute_notified_at stays unset so the donor can see the card
+// was never sent.
+func (c *donationService) sendTributeCard(ctx context.Context, cause *models.Cause, donation *models.Donation) {
+	if c.mailer == nil {
+		return
+	}
+
+	donor := donation.Name
+	if donation.IsAnonymous {
+		donor = "A well-wisher"
+	}
+	dedication := "in honour of"
+	if *donation.TributeType == models.TributeTypeMemory {
+		dedication = "in memory of"
+	}
+
+	subject := fmt.Sprintf("A donation has been made %s %s", dedication, *donation.TributeName)
+	body := fmt.Sprintf("Hello,\n\n%s has made a donation to \"%s\" %s %s.\n", donor, cause.Title, dedication, *donation.TributeName)
+	if donation.TributeMessage != nil && *donation.TributeMessage != "" {
+		body += fmt.Sprintf("\nTheir message:\n\n%s\n", *donation.TributeMessage)
+	}
+	body += "\n- CharityLight\n"
+
+	if err := c.mailer.Send(ctx, *donation.TributeNotifyEmail, subject, body); err != nil {
+		log.Printf("Warning: failed to send tribute card for donation %v: %v", donation.ID, err)
+		return
+	}
+
+	now := time.Now()
+	if err := c.donationRepo.MarkTributeNotified(ctx, donation.ID, now); err != nil {
+		log.Printf("Warning: failed to mark tribute card sent for donation %v: %v", donation.ID, err)
+		return
+	}
+	donation.TributeNotifiedAt = &now
+}
+
+// checkFundraiser returns an error unless a donation to causeID may be
+// attributed to the fundraiser.
+func (c *donationService) checkFundraiser(ctx context.Context, fundraiserID uuid.UUID, causeID uuid.UUID) error {
+	fundraiser, err := c.fundraiserRepo.GetByID(ctx, fundraiserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("fundraiser not found")
+	}
+	if err != nil {
+		return err
+	}
+	if fundraiser.CauseID != causeID {
+		return errors.New("fundraiser is not for this cause")
+	}
+	if !fundraiser.AcceptsDonations(time.Now()) {
+		return errors.New("fundraiser is no longer accepting donations")
+	}
+	return nil
+}
+
+// buildDonationItems validates the requested line items against the cause's
+// products: each product must belong to the cause, be listed once, be priced
+// as the donor was shown and still need the quantity asked for. The items
+// must add up to the donation amount.
+func (c *donationService) buildDonationItems(ctx context.Context, req *models.CreateDonationRequest) ([]*models.DonationItem, error) {
+	if len(req.Items) == 0 {
+		return nil, nil
+	}
+
+	products, err := c.causeRepo.GetProductsByCauseID(ctx, req.CauseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cause products: %w", err)
+	}
+	byID := make(map[uuid.UUID]*models.CauseProduct, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	items := make([]*models.DonationItem, 0, len(req.Items))
+	seen := make(map[uuid.UUID]bool, len(req.Items))
+	var total models.Paise
+	for _, in := range req.Items {
+		if in == nil {
+			continue
+		}
+		product, ok := byID[in.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("product %s does not belong to this cause", in.ProductID)
+		}
+		if seen[in.ProductID] {
+			return nil, fmt.Errorf("product %s is listed more than once", in.ProductID)
+		}
+		seen[in.ProductID] = true
+
+		if in.Quantity <= 0 {
+			return nil, errors.New("item quantity must be greater than zero")
+		}
+		if in.UnitPrice != models.ToPaise(product.PricePerUnit) {
+			return nil, fmt.Errorf("price for %s has changed to %.2f", product.Name, product.PricePerUnit)
+		}
+		if remaining := product.QuantityNeeded - product.QuantityFunded; in.Quantity > remaining {
+			return nil, fmt.Errorf("%w: only %d of %s still needed", repository.ErrProductOversubscribed, max(remaining, 0), product.Name)
+		}
+
+		items = append(items, &models.DonationItem{
+			ID:        uuid.New(),
+			ProductID: product.ID,
+			Quantity:  in.Quantity,
+			UnitPrice: product.PricePerUnit,
+		})
+		total += models.ToPaise(product.PricePerUnit) * models.Paise(in.Quantity)
+	}
+
+	if total != req.Amount {
+		return nil, fmt.Errorf("amount must equal the item total of %s", total)
+	}
+
+	return items, nil
 }
 
 func (c *donationService) GetByID(ctx context.Context, id uuid.UUID) (*models.Donation, error) {
@@ -134,11 +608,15 @@ func (c *donationService) GetByID(ctx context.Context, id uuid.UUID) (*models.Do
 		return nil, err
 	}
 
+	if donation.Items, err = c.donationRepo.GetItems(ctx, donation.ID); err != nil {
+		return nil, err
+	}
+
 	return donation, nil
 }
 
-func (c *donationService) GetByCauseID(ctx context.Context, id uuid.UUID) ([]*models.Donation, error) {
-	donationsResult, err := c.donationRepo.GetByCauseID(ctx, id)
+func (c *donationService) GetByCauseID(ctx context.Context, id uuid.UUID, page models.CursorParams) (*models.Page[*models.Donation], error) {
+	donationsResult, err := c.donationRepo.GetByCauseID(ctx, id, page)
 
 	if err != nil {
 		return nil, err
@@ -154,11 +632,15 @@ func (c *donationService) GetByPaymentID(ctx context.Context, id uuid.UUID) (*mo
 		return nil, err
 	}
 
+	if donation.Items, err = c.donationRepo.GetItems(ctx, donation.ID); err != nil {
+		return nil, err
+	}
+
 	return donation, nil
 }
 
-func (c *donationService) GetByUserID(ctx context.Context, id uuid.UUID) ([]*models.Donation, error) {
-	donationsResult, err := c.donationRepo.GetByUserID(ctx, id)
+func (c *donationService) GetByUserID(ctx context.Context, id uuid.UUID, causeID *uuid.UUID, page models.CursorParams) (*models.Page[*models.Donation], error) {
+	donationsResult, err := c.donationRepo.GetByUserID(ctx, id, causeID, page)
 
 	if err != nil {
 		return nil, err
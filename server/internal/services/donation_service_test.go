@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func newTestDonationService(t *testing.T, causes ...*models.Cause) (*donationService, *fakeDonationRepo, *fakeLedger, *fakeCauseRepo, *[]string) {
+	t.Helper()
+	events := &[]string{}
+	donations := newFakeDonationRepo(events)
+	ledger := &fakeLedger{events: events}
+	causeRepo := newFakeCauseRepo(causes...)
+	donations.causes = causeRepo
+	service := NewDonationService(donations, ledger, nil, causeRepo, nil, nil, nil, nopWebhooks{}, nopNotifier{})
+	return service, donations, ledger, causeRepo, events
+}
+
+func liveCause() *models.Cause {
+	return &models.Cause{ID: uuid.New(), Title: "Clean water for Rampur", State: models.CauseStateLive}
+}
+
+func donationRequest(cause *models.Cause, amount models.Paise) *models.CreateDonationRequest {
+	name := "Asha"
+	paymentID := "pay_" + uuid.NewString()
+	return &models.CreateDonationRequest{
+		CauseID:   cause.ID,
+		UserID:    uuid.New(),
+		Name:      &name,
+		Phone:     "9876543210",
+		Amount:    amount,
+		PaymentID: &paymentID,
+	}
+}
+
+func TestDonationCreateSavesBeforeLedger(t *testing.T) {
+	cause := liveCause()
+	service, donations, ledger, _, events := newTestDonationService(t, cause)
+
+	donation, err := service.Create(context.Background(), donationRequest(cause, 125050))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if want := []string{"create", "ledger", "set_tx_hash"}; !reflect.DeepEqual(*events, want) {
+		t.Errorf("calls = %v, want %v", *events, want)
+	}
+	if donation.Amount != 125050 {
+		t.Errorf("Amount = %v, want 1250.50", donation.Amount)
+	}
+	if len(ledger.recorded) != 1 || ledger.recorded[0] != donation.ID {
+		t.Errorf("ledger recorded %v, want [%s]", ledger.recorded, donation.ID)
+	}
+	if len(ledger.amounts) != 1 || ledger.amounts[0] != 125050 {
+		t.Errorf("ledger amounts = %v, want [125050] paise", ledger.amounts)
+	}
+	saved, _ := donations.GetByID(context.Background(), donation.ID)
+	if saved.TxHash == nil || donation.TxHash == nil || *saved.TxHash != *donation.TxHash {
+		t.Errorf("saved tx hash %v, returned %v", saved.TxHash, donation.TxHash)
+	}
+}
+
+func TestDonationCreateFailedSaveSkipsLedger(t *testing.T) {
+	cause := liveCause()
+	service, donations, ledger, _, _ := newTestDonationService(t, cause)
+	donations.createErr = repository.ErrProductOversubscribed
+
+	_, err := service.Create(context.Background(), donationRequest(cause, 50000))
+	if !errors.Is(err, repository.ErrProductOversubscribed) {
+		t.Fatalf("Create() error = %v, want ErrProductOversubscribed", err)
+	}
+	if len(ledger.recorded) != 0 {
+		t.Errorf("ledger recorded %v for a donation that was never saved", ledger.recorded)
+	}
+}
+
+func TestDonationLedgerFailureIsRetried(t *testing.T) {
+	cause := liveCause()
+	service, donations, ledger, _, _ := newTestDonationService(t, cause)
+	ledger.err = errors.New("rpc unavailable")
+
+	donation, err := service.Create(context.Background(), donationRequest(cause, 50000))
+	if err != nil {
+		t.Fatalf("Create() error = %v, want the donation kept", err)
+	}
+	if donation.TxHash != nil {
+		t.Fatalf("TxHash = %v, want unset", *donation.TxHash)
+	}
+
+	// Not due until the request's lease runs out.
+	service.syncLedger(context.Background())
+	if len(ledger.recorded) != 0 {
+		t.Fatalf("ledger sync retried before the lease expired")
+	}
+
+	ledger.err = nil
+	saved := donations.all()[0]
+	past := time.Now().Add(-time.Second)
+	saved.LedgerRetryAt = &past
+
+	service.syncLedger(context.Background())
+	if len(ledger.recorded) != 1 || ledger.recorded[0] != donation.ID {
+		t.Fatalf("ledger recorded %v, want [%s]", ledger.recorded, donation.ID)
+	}
+	if saved.TxHash == nil {
+		t.Error("tx hash not saved after the retry")
+	}
+
+	service.syncLedger(context.Background())
+	if len(ledger.recorded) != 1 {
+		t.Errorf("ledger recorded a donation twice: %v", ledger.recorded)
+	}
+}
+
+func TestDonationTxHashSaveFailure(t *testing.T) {
+	goal := float32(100000)
+	cause := liveCause()
+	cause.GoalAmount = &goal
+
+	t.Run("retried", func(t *testing.T) {
+		service, donations, _, _, _ := newTestDonationService(t, cause)
+		donations.setTxHashErr = errors.New("connection reset")
+		donations.setTxHashFails = 1
+
+		donation, err := service.Create(context.Background(), donationRequest(cause, 50000))
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if saved, _ := donations.GetByID(context.Background(), donation.ID); saved.TxHash == nil || saved.LedgerRetryAt != nil {
+			t.Errorf("saved tx hash %v, ledger retry %v; want the hash saved on a second try", saved.TxHash, saved.LedgerRetryAt)
+		}
+	})
+
+	t.Run("never counted twice", func(t *testing.T) {
+		service, donations, ledger, _, _ := newTestDonationService(t, cause)
+		tracker := newFakeTracker()
+		service.tracker = tracker
+		donations.setTxHashErr = errors.New("database unavailable")
+		donations.setTxHashFails = txHashSaveAttempts
+
+		donation, err := service.Create(context.Background(), donationRequest(cause, 50000))
+		if err != nil {
+			t.Fatalf("Create() error = %v, want the donation kept", err)
+		}
+		counted := tracker.total(cause.ID)
+		if counted == 0 {
+			t.Fatal("donation on the ledger wasn't counted toward milestones")
+		}
+
+		// The hash wasn't saved, so the ledger sync picks the donation up
+		// again once the lease runs out.
+		saved := donations.all()[0]
+		past := time.Now().Add(-time.Second)
+		saved.LedgerRetryAt = &past
+		service.syncLedger(context.Background())
+
+		if len(ledger.recorded) != 1 || ledger.recorded[0] != donation.ID {
+			t.Errorf("ledger recorded %v, want [%s]", ledger.recorded, donation.ID)
+		}
+		if got := tracker.total(cause.ID); got != counted {
+			t.Errorf("tracker total = %d after the retry, want %d", got, counted)
+		}
+	})
+}
+
+func TestMilestoneTrackerCountsFirstDonationOnce(t *testing.T) {
+	goal := float32(100000)
+
+	t.Run("recorded with the request", func(t *testing.T) {
+		cause := liveCause()
+		cause.GoalAmount = &goal
+		service, _, _, _, _ := newTestDonationService(t, cause)
+		tracker := newFakeTracker()
+		service.tracker = tracker
+
+		for _, step := range []struct {
+			amount models.Paise
+			total  int64
+		}{{50000, 50000}, {75000, 125000}} {
+			if _, err := service.Create(context.Background(), donationRequest(cause, step.amount)); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if got := tracker.total(cause.ID); got != step.total {
+				t.Errorf("after donating %s tracker total = %d, want %d", step.amount, got, step.total)
+			}
+		}
+	})
+
+	t.Run("recorded by the ledger sync", func(t *testing.T) {
+		cause := liveCause()
+		cause.GoalAmount = &goal
+		service, donations, ledger, _, _ := newTestDonationService(t, cause)
+		tracker := newFakeTracker()
+		service.tracker = tracker
+		ledger.err = errors.New("rpc unavailable")
+
+		if _, err := service.Create(context.Background(), donationRequest(cause, 50000)); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		ledger.err = nil
+		past := time.Now().Add(-time.Second)
+		donations.all()[0].LedgerRetryAt = &past
+		service.syncLedger(context.Background())
+
+		if got := tracker.total(cause.ID); got != 50000 {
+			t.Errorf("tracker total = %d, want 50000", got)
+		}
+	})
+}
+
+func TestDonationItemsUseExactPaise(t *testing.T) {
+	cause := liveCause()
+	service, _, _, causeRepo, _ := newTestDonationService(t, cause)
+	product := &models.CauseProduct{ID: uuid.New(), CauseID: cause.ID, Name: "Blanket", PricePerUnit: 19.99, QuantityNeeded: 10}
+	causeRepo.products[cause.ID] = []*models.CauseProduct{product}
+
+	// 3 × 19.99 isn't exactly 59.97 in floating point.
+	req := donationRequest(cause, 5997)
+	req.Items = []*models.DonationItemInput{{ProductID: product.ID, Quantity: 3, UnitPrice: 1999}}
+	if _, err := service.Create(context.Background(), req); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	req = donationRequest(cause, 5996)
+	req.Items = []*models.DonationItemInput{{ProductID: product.ID, Quantity: 3, UnitPrice: 1999}}
+	if _, err := service.Create(context.Background(), req); err == nil || !strings.Contains(err.Error(), "59.97") {
+		t.Errorf("Create() error = %v, want the item total of 59.97", err)
+	}
+}
+
+func TestEncryptPlaintextPIIRunsUntilDone(t *testing.T) {
+	service, donations, _, _, _ := newTestDonationService(t)
+	for i := 0; i < piiBackfillBatchSize*2+1; i++ {
+		id := uuid.New()
+		donations.donations[id] = &models.Donation{ID: id, Phone: "9876543210"}
+	}
+
+	service.encryptPlaintextPII(context.Background())
+
+	for _, d := range donations.all() {
+		if !strings.HasPrefix(d.Phone, "enc:") {
+			t.Fatalf("donation %s phone left in plaintext", d.ID)
+		}
+	}
+	if donations.encryptCalls != 3 {
+		t.Errorf("EncryptPlaintextPII called %d times, want 3", donations.encryptCalls)
+	}
+}
+
+func TestUnpublishedCauseNeverReachesTracker(t *testing.T) {
+	for _, state := range []models.CauseState{models.CauseStateDraft, models.CauseStatePendingReview} {
+		t.Run(string(state), func(t *testing.T) {
+			goal := float32(100000)
+			cause := causeIn(state)
+			cause.GoalAmount = &goal
+			service, donations, ledger, _, _ := newTestDonationService(t, cause)
+			tracker := newFakeTracker()
+			service.tracker = tracker
+
+			if _, err := service.Create(context.Background(), donationRequest(cause, 50000)); err == nil {
+				t.Fatal("Create() accepted a donation to an unpublished cause")
+			}
+			if len(donations.all()) != 0 || len(ledger.recorded) != 0 || tracker.total(cause.ID) != 0 {
+				t.Errorf("unpublished cause got %d donations, %d ledger entries and %d on the tracker",
+					len(donations.all()), len(ledger.recorded), tracker.total(cause.ID))
+			}
+		})
+	}
+}
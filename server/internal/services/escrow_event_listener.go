@@ -26,6 +26,8 @@ type EscrowEventListener struct {
 	disbursementRepo repository.DisbursementRepository
 	organizationRepo repository.OrganizationRepository
 	causeRepo        repository.CauseRepository
+	webhooks         WebhookPublisher
+	notifier         Notifier
 }
 
 func NewEscrowEventListener(
@@ -34,6 +36,8 @@ func NewEscrowEventListener(
 	disbursementRepo repository.DisbursementRepository,
 	organizationRepo repository.OrganizationRepository,
 	causeRepo repository.CauseRepository,
+	webhooks WebhookPublisher,
+	notifier Notifier,
 ) (*EscrowEventListener, error) {
 	addr := common.HexToAddress(contractAddress)
 	instance, err := contracts.NewMilestoneTracker(addr, client.EthClient)
@@ -48,6 +52,8 @@ func NewEscrowEventListener(
 		disbursementRepo: disbursementRepo,
 		organizationRepo: organizationRepo,
 		causeRepo:        causeRepo,
+		webhooks:         webhooks,
+		notifier:         notifier,
 	}, nil
 }
 
@@ -172,6 +178,11 @@ func (l *EscrowEventListener) processMilestone(ctx context.Context, event *contr
 	}
 	log.Printf("[MILESTONE] Found cause: %s (org: %v)", cause.Title, cause.Organization.ID)
 
+	if !cause.State.AllowsDisbursement() {
+		log.Printf("[MILESTONE] Cause %v is %s, refusing disbursement for milestone %d", causeID, cause.State, event.Milestone)
+		return fmt.Errorf("cause %v is %s and cannot receive disbursements", causeID, cause.State)
+	}
+
 	// Check if this disbursement already exists
 	existing, err := l.disbursementRepo.GetByCauseAndMilestone(ctx, causeID, int(event.Milestone))
 	if err == nil && existing != nil {
@@ -180,9 +191,9 @@ func (l *EscrowEventListener) processMilestone(ctx context.Context, event *contr
 	}
 	log.Printf("[MILESTONE] No existing disbursement found, creating new one...")
 
-	// Convert amount to float
-	// event.AmountToDiburse contains the milestone disbursement amount (25% of goal)
-	amountFloat := weiToFloat(event.AmountToDiburse)
+	// Convert amount to rupees
+	// event.AmountToDiburse contains the milestone disbursement amount (25% of goal), in paise
+	amountFloat := paiseToRupees(event.AmountToDiburse)
 
 	// Create the disbursement record
 	// NOTE: This is a VIRTUAL disbursement - actual fund transfer happens off-chain
@@ -216,17 +227,24 @@ func (l *EscrowEventListener) processMilestone(ctx context.Context, event *contr
 	log.Printf("[SUCCESS] Created VIRTUAL disbursement %v: %.2f for organization %v (to be paid off-chain)",
 		disbursement.ID, amountFloat, cause.Organization.ID)
 
+	l.webhooks.Publish(ctx, cause.Organization.ID, models.WebhookMilestoneReached, &models.WebhookMilestoneData{
+		CauseID:         causeID,
+		MilestoneNumber: disbursement.MilestoneNumber,
+		DisbursementID:  disbursement.ID,
+		Amount:          amountFloat,
+		TransactionHash: disbursement.TransactionHash,
+	})
+	l.notifier.MilestoneReached(ctx, cause, disbursement)
+
 	return nil
 }
 
-// weiToFloat converts wei (big.Int) to float64
-func weiToFloat(wei *big.Int) float64 {
-	if wei == nil {
+// paiseToRupees converts an on-chain amount in paise to rupees
+func paiseToRupees(paise *big.Int) float64 {
+	if paise == nil {
 		return 0.0
 	}
-	f := new(big.Float).SetInt(wei)
-	result, _ := f.Float64()
-	return result
+	return models.Paise(paise.Int64()).Rupees()
 }
 
 func ptrString(s string) *string {
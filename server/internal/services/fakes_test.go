@@ -0,0 +1,962 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"server/internal/blockchain/contracts"
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// The fakes below embed the repository interface they stand in for, so a
+// test only has to implement the methods its code path calls; anything
+// else panics on the nil embedded value.
+
+type fakeCauseRepo struct {
+	repository.CauseRepository
+
+	mu        sync.Mutex
+	causes    map[uuid.UUID]*models.Cause
+	products  map[uuid.UUID][]*models.CauseProduct
+	revisions map[uuid.UUID][]*models.CauseRevision
+
+	transitions []*models.CauseStateTransition
+	reviews     []*models.CausePublicationReview
+	// beforeTransition runs just before TransitionState, to let a test
+	// change a cause underneath the service.
+	beforeTransition func()
+}
+
+func newFakeCauseRepo(causes ...*models.Cause) *fakeCauseRepo {
+	r := &fakeCauseRepo{
+		causes:    make(map[uuid.UUID]*models.Cause),
+		products:  make(map[uuid.UUID][]*models.CauseProduct),
+		revisions: make(map[uuid.UUID][]*models.CauseRevision),
+	}
+	for _, c := range causes {
+		r.causes[c.ID] = c
+	}
+	return r
+}
+
+func (r *fakeCauseRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Cause, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cause, ok := r.causes[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *cause
+	return &copied, nil
+}
+
+// Update saves the edit like the repository does: a cause without history
+// first gets its stored state as revision 1.
+func (r *fakeCauseRepo) Update(ctx context.Context, cause *models.Cause, revision *models.CauseRevision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.causes[cause.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if cause.GoalAmount != nil && *cause.GoalAmount < stored.CollectedAmount {
+		return errors.New("goal amount cannot be lower than the amount already collected")
+	}
+	if len(r.revisions[cause.ID]) == 0 {
+		r.revisions[cause.ID] = append(r.revisions[cause.ID], &models.CauseRevision{
+			CauseID:        stored.ID,
+			RevisionNumber: 1,
+			Title:          stored.Title,
+			GoalAmount:     stored.GoalAmount,
+			Deadline:       stored.Deadline,
+		})
+	}
+	revision.RevisionNumber = len(r.revisions[cause.ID]) + 1
+	revision.CreatedAt = time.Now()
+	r.revisions[cause.ID] = append(r.revisions[cause.ID], revision)
+	copied := *cause
+	r.causes[cause.ID] = &copied
+	return nil
+}
+
+// TransitionState moves the cause only if it is still in FromState, like
+// the repository's UPDATE ... WHERE id = $1 AND state = $2.
+func (r *fakeCauseRepo) TransitionState(ctx context.Context, transition *models.CauseStateTransition) error {
+	if r.beforeTransition != nil {
+		r.beforeTransition()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cause, ok := r.causes[transition.CauseID]
+	if !ok || cause.State != *transition.FromState {
+		return repository.ErrCauseStateChanged
+	}
+	cause.State = transition.ToState
+	cause.StateChangedAt = transition.CreatedAt
+	r.transitions = append(r.transitions, transition)
+	return nil
+}
+
+func (r *fakeCauseRepo) setState(id uuid.UUID, state models.CauseState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.causes[id].State = state
+}
+
+// GetScheduledTransitions applies the repository query's rules to the
+// stored causes.
+func (r *fakeCauseRepo) GetScheduledTransitions(ctx context.Context) ([]*models.ScheduledCauseTransition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	due := make([]*models.ScheduledCauseTransition, 0)
+	for _, c := range r.causes {
+		funded := c.GoalAmount != nil && *c.GoalAmount > 0 && c.CollectedAmount >= *c.GoalAmount
+		expired := c.Deadline != nil && c.Deadline.Before(now)
+		switch {
+		case c.State == models.CauseStateLive && funded:
+			due = append(due, &models.ScheduledCauseTransition{CauseID: c.ID, From: c.State, To: models.CauseStateFullyFunded, Reason: "goal amount reached"})
+		case c.State == models.CauseStateLive && expired:
+			due = append(due, &models.ScheduledCauseTransition{CauseID: c.ID, From: c.State, To: models.CauseStateClosed, Reason: "deadline passed before goal was reached"})
+		case c.State == models.CauseStateFullyFunded && c.GoalAmount != nil && *c.GoalAmount > c.CollectedAmount && !expired:
+			due = append(due, &models.ScheduledCauseTransition{CauseID: c.ID, From: c.State, To: models.CauseStateLive, Reason: "goal raised above collected amount"})
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeCauseRepo) Create(ctx context.Context, cause *models.Cause) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *cause
+	r.causes[cause.ID] = &copied
+	return nil
+}
+
+func (r *fakeCauseRepo) GetDomainByID(ctx context.Context, id uuid.UUID) (*models.CauseCategory, error) {
+	return &models.CauseCategory{ID: id, Name: "Water"}, nil
+}
+
+func (r *fakeCauseRepo) GetAidTypeByID(ctx context.Context, id uuid.UUID) (*models.CauseCategory, error) {
+	return &models.CauseCategory{ID: id, Name: "Funds"}, nil
+}
+
+func (r *fakeCauseRepo) GetByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]*models.Cause, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	causes := make([]*models.Cause, 0)
+	for _, c := range r.causes {
+		if c.Organization.ID == organizationID {
+			copied := *c
+			causes = append(causes, &copied)
+		}
+	}
+	return causes, nil
+}
+
+func (r *fakeCauseRepo) GetByState(ctx context.Context, state models.CauseState, page models.CursorParams) (*models.Page[*models.Cause], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := make([]*models.Cause, 0)
+	for _, c := range r.causes {
+		if c.State == state {
+			copied := *c
+			items = append(items, &copied)
+		}
+	}
+	return &models.Page[*models.Cause]{Items: items}, nil
+}
+
+func (r *fakeCauseRepo) CreatePublicationReview(ctx context.Context, review *models.CausePublicationReview) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reviews = append(r.reviews, review)
+	return nil
+}
+
+func (r *fakeCauseRepo) GetProductsByCauseID(ctx context.Context, causeID uuid.UUID) ([]*models.CauseProduct, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.products[causeID], nil
+}
+
+type fakeDonationRepo struct {
+	repository.DonationRepository
+
+	mu        sync.Mutex
+	donations map[uuid.UUID]*models.Donation
+	createErr error
+	// setTxHashErr makes that many SetTxHash calls fail.
+	setTxHashErr   error
+	setTxHashFails int
+	// causes, when set, has its collected amounts raised by Create like the
+	// real repository does.
+	causes *fakeCauseRepo
+	// milestoneRecorded holds the donations ClaimMilestoneRecording took.
+	milestoneRecorded map[uuid.UUID]bool
+	// encryptCalls counts EncryptPlaintextPII batches.
+	encryptCalls int
+	// events records Create and SetTxHash calls in order.
+	events *[]string
+}
+
+func newFakeDonationRepo(events *[]string) *fakeDonationRepo {
+	return &fakeDonationRepo{
+		donations:         make(map[uuid.UUID]*models.Donation),
+		milestoneRecorded: make(map[uuid.UUID]bool),
+		events:            events,
+	}
+}
+
+func (r *fakeDonationRepo) Create(ctx context.Context, donation *models.Donation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*r.events = append(*r.events, "create")
+	if r.createErr != nil {
+		return r.createErr
+	}
+	copied := *donation
+	r.donations[donation.ID] = &copied
+	if r.causes != nil {
+		r.causes.mu.Lock()
+		if cause, ok := r.causes.causes[donation.CauseID]; ok {
+			cause.CollectedAmount += float32(donation.Amount.Rupees())
+		}
+		r.causes.mu.Unlock()
+	}
+	return nil
+}
+
+func (r *fakeDonationRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Donation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	donation, ok := r.donations[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *donation
+	return &copied, nil
+}
+
+func (r *fakeDonationRepo) SetTxHash(ctx context.Context, id uuid.UUID, txHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*r.events = append(*r.events, "set_tx_hash")
+	if r.setTxHashFails > 0 {
+		r.setTxHashFails--
+		return r.setTxHashErr
+	}
+	r.donations[id].TxHash = &txHash
+	r.donations[id].LedgerRetryAt = nil
+	return nil
+}
+
+func (r *fakeDonationRepo) ClaimUnrecorded(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := []uuid.UUID{}
+	for id, d := range r.donations {
+		if d.TxHash == nil && d.LedgerRetryAt != nil && !d.LedgerRetryAt.After(now) && len(ids) < limit {
+			retryAt := now.Add(lease)
+			d.LedgerRetryAt = &retryAt
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeDonationRepo) ClaimMilestoneRecording(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.milestoneRecorded[id] {
+		return false, nil
+	}
+	r.milestoneRecorded[id] = true
+	return true, nil
+}
+
+// EncryptPlaintextPII stands in for encryption by prefixing the phone
+// number, and counts its calls in encryptCalls.
+func (r *fakeDonationRepo) EncryptPlaintextPII(ctx context.Context, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.encryptCalls++
+	n := 0
+	for _, d := range r.donations {
+		if n < limit && !strings.HasPrefix(d.Phone, "enc:") {
+			d.Phone = "enc:" + d.Phone
+			n++
+		}
+	}
+	return n, nil
+}
+
+// all returns the saved donations in no particular order.
+func (r *fakeDonationRepo) all() []*models.Donation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]*models.Donation, 0, len(r.donations))
+	for _, d := range r.donations {
+		out = append(out, d)
+	}
+	return out
+}
+
+type fakeLedger struct {
+	mu       sync.Mutex
+	err      error
+	recorded []uuid.UUID
+	refs     []string
+	amounts  []int64
+	events   *[]string
+}
+
+func (l *fakeLedger) RecordDonation(ctx context.Context, donationID, causeID, donorID uuid.UUID, amount *big.Int, paymentRef string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	*l.events = append(*l.events, "ledger")
+	if l.err != nil {
+		return "", l.err
+	}
+	// Like the contract, the ledger refuses a donation ID it already has.
+	for _, id := range l.recorded {
+		if id == donationID {
+			return "", errors.New("execution reverted: donation already recorded")
+		}
+	}
+	l.recorded = append(l.recorded, donationID)
+	l.refs = append(l.refs, paymentRef)
+	l.amounts = append(l.amounts, amount.Int64())
+	return "0x" + donationID.String(), nil
+}
+
+func (l *fakeLedger) GetDonation(ctx context.Context, donationID uuid.UUID) (*contracts.DonationLedgerDonation, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (l *fakeLedger) GetDonationsByCause(ctx context.Context, causeID uuid.UUID) ([][16]byte, error) {
+	return nil, nil
+}
+
+// fakeTracker keeps each cause's total like the MilestoneTracker contract.
+type fakeTracker struct {
+	mu        sync.Mutex
+	goals     map[uuid.UUID]int64
+	collected map[uuid.UUID]int64
+}
+
+func newFakeTracker() *fakeTracker {
+	return &fakeTracker{goals: make(map[uuid.UUID]int64), collected: make(map[uuid.UUID]int64)}
+}
+
+func (t *fakeTracker) EnsureCauseRegistered(ctx context.Context, causeID uuid.UUID, goal, initialCollected *big.Int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.goals[causeID]; !ok {
+		t.goals[causeID] = goal.Int64()
+		t.collected[causeID] = initialCollected.Int64()
+	}
+	return nil
+}
+
+func (t *fakeTracker) RecordDonation(ctx context.Context, causeID uuid.UUID, amount *big.Int) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.collected[causeID] += amount.Int64()
+	return "0xtracker", nil
+}
+
+func (t *fakeTracker) total(causeID uuid.UUID) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.collected[causeID]
+}
+
+type nopWebhooks struct{}
+
+func (nopWebhooks) Publish(ctx context.Context, organizationID uuid.UUID, eventType models.WebhookEventType, data any) {
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) DonationCompleted(ctx context.Context, cause *models.Cause, donation *models.Donation) {
+}
+func (nopNotifier) MilestoneReached(ctx context.Context, cause *models.Cause, disbursement *models.Disbursement) {
+}
+func (nopNotifier) CauseUpdatePosted(ctx context.Context, causeID uuid.UUID, update *models.CauseUpdate) {
+}
+func (nopNotifier) ReceiptVerified(ctx context.Context, job *models.ReceiptVerificationJob) {}
+func (nopNotifier) DisputeUpdated(ctx context.Context, dispute *models.Dispute)             {}
+
+type fakePledgeRepo struct {
+	repository.GoodsPledgeRepository
+
+	mu      sync.Mutex
+	pledges map[uuid.UUID]*models.GoodsPledge
+}
+
+func newFakePledgeRepo() *fakePledgeRepo {
+	return &fakePledgeRepo{pledges: make(map[uuid.UUID]*models.GoodsPledge)}
+}
+
+func (r *fakePledgeRepo) Create(ctx context.Context, pledge *models.GoodsPledge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *pledge
+	r.pledges[pledge.ID] = &copied
+	return nil
+}
+
+func (r *fakePledgeRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.GoodsPledge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pledge, ok := r.pledges[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *pledge
+	return &copied, nil
+}
+
+func (r *fakePledgeRepo) save(pledge *models.GoodsPledge, from models.GoodsPledgeStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pledges[pledge.ID].Status != from {
+		return repository.ErrGoodsPledgeChanged
+	}
+	copied := *pledge
+	r.pledges[pledge.ID] = &copied
+	return nil
+}
+
+func (r *fakePledgeRepo) Update(ctx context.Context, pledge *models.GoodsPledge, from models.GoodsPledgeStatus) error {
+	return r.save(pledge, from)
+}
+
+func (r *fakePledgeRepo) MarkReceived(ctx context.Context, pledge *models.GoodsPledge, from models.GoodsPledgeStatus) error {
+	return r.save(pledge, from)
+}
+
+// fakeSearchRepo records the filter each search reached it with.
+type fakeSearchRepo struct {
+	repository.CauseSearchRepository
+
+	searched []models.CauseSearchFilter
+}
+
+func (r *fakeSearchRepo) Search(ctx context.Context, filter *models.CauseSearchFilter) (*models.CauseSearchResult, error) {
+	r.searched = append(r.searched, *filter)
+	return &models.CauseSearchResult{}, nil
+}
+
+type fakeMatchingRepo struct {
+	repository.MatchingCampaignRepository
+
+	mu        sync.Mutex
+	campaigns []*models.MatchingCampaign
+}
+
+// GetRunningForCause leaves the window and budget checks to the caller.
+func (r *fakeMatchingRepo) GetRunningForCause(ctx context.Context, causeID uuid.UUID, domainID uuid.UUID, at time.Time) ([]*models.MatchingCampaign, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*models.MatchingCampaign
+	for _, m := range r.campaigns {
+		if (m.CauseID != nil && *m.CauseID == causeID) || (m.DomainID != nil && *m.DomainID == domainID) {
+			copied := *m
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeMatchingRepo) Reserve(ctx context.Context, id uuid.UUID, amount float64) (float64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range r.campaigns {
+		if m.ID == id {
+			reserved := min(amount, m.RemainingAmount())
+			m.MatchedAmount += reserved
+			return reserved, nil
+		}
+	}
+	return 0, nil
+}
+
+func (r *fakeMatchingRepo) Release(ctx context.Context, id uuid.UUID, amount float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range r.campaigns {
+		if m.ID == id {
+			m.MatchedAmount -= amount
+		}
+	}
+	return nil
+}
+
+type fakeFundraiserRepo struct {
+	repository.FundraiserRepository
+
+	mu          sync.Mutex
+	fundraisers map[uuid.UUID]*models.Fundraiser
+}
+
+func newFakeFundraiserRepo(fundraisers ...*models.Fundraiser) *fakeFundraiserRepo {
+	r := &fakeFundraiserRepo{fundraisers: make(map[uuid.UUID]*models.Fundraiser)}
+	for _, f := range fundraisers {
+		r.fundraisers[f.ID] = f
+	}
+	return r
+}
+
+func (r *fakeFundraiserRepo) Create(ctx context.Context, fundraiser *models.Fundraiser) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *fundraiser
+	r.fundraisers[fundraiser.ID] = &copied
+	return nil
+}
+
+func (r *fakeFundraiserRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Fundraiser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fundraiser, ok := r.fundraisers[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *fundraiser
+	return &copied, nil
+}
+
+func (r *fakeFundraiserRepo) Update(ctx context.Context, fundraiser *models.Fundraiser) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *fundraiser
+	r.fundraisers[fundraiser.ID] = &copied
+	return nil
+}
+
+func (r *fakeFundraiserRepo) GetByCauseID(ctx context.Context, causeID uuid.UUID, page models.CursorParams) (*models.Page[*models.Fundraiser], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var items []*models.Fundraiser
+	for _, f := range r.fundraisers {
+		if f.CauseID == causeID {
+			items = append(items, f)
+		}
+	}
+	return &models.Page[*models.Fundraiser]{Items: items}, nil
+}
+
+func (r *fakeFundraiserRepo) GetLeaderboard(ctx context.Context, causeID uuid.UUID, limit int) ([]*models.FundraiserLeaderboardEntry, error) {
+	return nil, nil
+}
+
+type fakeUserRepo struct {
+	repository.UserRepository
+
+	mu    sync.Mutex
+	users map[uuid.UUID]*models.User
+}
+
+func newFakeUserRepo(users ...*models.User) *fakeUserRepo {
+	r := &fakeUserRepo{users: make(map[uuid.UUID]*models.User)}
+	for _, u := range users {
+		r.users[u.ID] = u
+	}
+	return r
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+// fakeIdentityRepo links provider accounts in memory. CreateWithUser saves
+// the user to users only if the link succeeds, like the transaction.
+type fakeIdentityRepo struct {
+	repository.UserIdentityRepository
+
+	mu         sync.Mutex
+	users      *fakeUserRepo
+	identities []*models.UserIdentity
+	linkErr    error
+}
+
+func (r *fakeIdentityRepo) GetByProviderUserID(ctx context.Context, provider, providerUserID string) (*models.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.identities {
+		if i.Provider == provider && i.ProviderUserID == providerUserID {
+			return i, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeIdentityRepo) CreateWithUser(ctx context.Context, user *models.User, identity *models.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.linkErr != nil {
+		return r.linkErr
+	}
+	r.identities = append(r.identities, identity)
+	return r.users.Create(ctx, user)
+}
+
+func (r *fakeIdentityRepo) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return nil
+}
+
+// update applies fn to the stored user.
+func (r *fakeUserRepo) update(id uuid.UUID, fn func(*models.User)) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if ok {
+		fn(user)
+	}
+	return ok
+}
+
+// fakeAccountTokenRepo keeps tokens in memory and applies them to a
+// fakeUserRepo.
+type fakeAccountTokenRepo struct {
+	repository.AccountTokenRepository
+
+	mu     sync.Mutex
+	users  *fakeUserRepo
+	tokens map[uuid.UUID]*models.AccountToken
+}
+
+func newFakeAccountTokenRepo(users *fakeUserRepo) *fakeAccountTokenRepo {
+	return &fakeAccountTokenRepo{users: users, tokens: make(map[uuid.UUID]*models.AccountToken)}
+}
+
+func (r *fakeAccountTokenRepo) Create(ctx context.Context, token *models.AccountToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.UserID == token.UserID && t.Purpose == token.Purpose && t.UsedAt == nil {
+			usedAt := token.CreatedAt
+			t.UsedAt = &usedAt
+		}
+	}
+	copied := *token
+	r.tokens[token.ID] = &copied
+	return nil
+}
+
+func (r *fakeAccountTokenRepo) consume(id uuid.UUID, purpose models.AccountTokenPurpose, now time.Time, fn func(*models.User)) (uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[id]
+	if !ok || token.Purpose != purpose || token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+		return uuid.Nil, repository.ErrInvalidAccountToken
+	}
+	if !r.users.update(token.UserID, fn) {
+		return uuid.Nil, repository.ErrInvalidAccountToken
+	}
+	token.UsedAt = &now
+	return token.UserID, nil
+}
+
+func (r *fakeAccountTokenRepo) VerifyEmail(ctx context.Context, id uuid.UUID, now time.Time) (uuid.UUID, error) {
+	return r.consume(id, models.AccountTokenEmailVerification, now, func(u *models.User) {
+		u.IsVerified = true
+	})
+}
+
+func (r *fakeAccountTokenRepo) ResetPassword(ctx context.Context, id uuid.UUID, passwordHash string, now time.Time) (uuid.UUID, error) {
+	return r.consume(id, models.AccountTokenPasswordReset, now, func(u *models.User) {
+		u.PasswordHash = &passwordHash
+		u.IsVerified = true
+	})
+}
+
+func (r *fakeAccountTokenRepo) CountSince(ctx context.Context, userID uuid.UUID, purpose models.AccountTokenPurpose, since time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.Purpose == purpose && !t.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// expire moves every stored token's expiry into the past.
+func (r *fakeAccountTokenRepo) expire() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		t.ExpiresAt = time.Now().Add(-time.Minute)
+	}
+}
+
+type sentMail struct {
+	to, subject, body string
+}
+
+// fakeMailer records what it is asked to send.
+type fakeMailer struct {
+	mu   sync.Mutex
+	sent []sentMail
+}
+
+func (m *fakeMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, sentMail{to: to, subject: subject, body: body})
+	return nil
+}
+
+// lastToken returns the token from the link in the latest email.
+func (m *fakeMailer) lastToken(t *testing.T) string {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.sent) == 0 {
+		t.Fatal("no email was sent")
+	}
+	_, rest, ok := strings.Cut(m.sent[len(m.sent)-1].body, "?token=")
+	if !ok {
+		t.Fatal("email has no token link")
+	}
+	token, err := url.QueryUnescape(strings.Fields(rest)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// fakeAPIKeyRepo keeps keys in memory. GetActiveByPrefix skips revoked and
+// expired keys like the real query.
+type fakeAPIKeyRepo struct {
+	repository.APIKeyRepository
+
+	mu   sync.Mutex
+	keys map[uuid.UUID]*models.OrganizationAPIKey
+}
+
+func newFakeAPIKeyRepo() *fakeAPIKeyRepo {
+	return &fakeAPIKeyRepo{keys: make(map[uuid.UUID]*models.OrganizationAPIKey)}
+}
+
+func (r *fakeAPIKeyRepo) Create(ctx context.Context, key *models.OrganizationAPIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *key
+	r.keys[key.ID] = &copied
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) CountActive(ctx context.Context, organizationID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, k := range r.keys {
+		if k.OrganizationID == organizationID && k.RevokedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeAPIKeyRepo) GetActiveByPrefix(ctx context.Context, prefix string) (*models.OrganizationAPIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.Prefix == prefix && k.RevokedAt == nil && (k.ExpiresAt == nil || k.ExpiresAt.After(time.Now())) {
+			copied := *k
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeAPIKeyRepo) Revoke(ctx context.Context, organizationID, keyID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k, ok := r.keys[keyID]
+	if !ok || k.OrganizationID != organizationID || k.RevokedAt != nil {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	k.RevokedAt = &now
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) TouchLastUsed(ctx context.Context, keyID uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if k, ok := r.keys[keyID]; ok {
+		k.LastUsedAt = &at
+	}
+	return nil
+}
+
+// update applies fn to the stored key.
+func (r *fakeAPIKeyRepo) update(id uuid.UUID, fn func(*models.OrganizationAPIKey)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(r.keys[id])
+}
+
+type fakeOrganizationRepo struct {
+	repository.OrganizationRepository
+
+	organizations map[uuid.UUID]*models.Organization
+}
+
+func newFakeOrganizationRepo(organizations ...*models.Organization) *fakeOrganizationRepo {
+	r := &fakeOrganizationRepo{organizations: make(map[uuid.UUID]*models.Organization)}
+	for _, o := range organizations {
+		r.organizations[o.ID] = o
+	}
+	return r
+}
+
+func (r *fakeOrganizationRepo) GetByOrganizationID(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	organization, ok := r.organizations[id]
+	if !ok {
+		return nil, errors.New("organization not found")
+	}
+	copied := *organization
+	return &copied, nil
+}
+
+type fakeRefreshToken struct {
+	sessionID uuid.UUID
+	used      bool
+}
+
+// fakeSessionRepo keeps refresh tokens by hash and follows the rotation
+// rules of the real repository: a used token presented again revokes the
+// session.
+type fakeSessionRepo struct {
+	repository.SessionRepository
+
+	mu       sync.Mutex
+	sessions map[uuid.UUID]*models.Session
+	tokens   map[string]*fakeRefreshToken
+}
+
+func newFakeSessionRepo() *fakeSessionRepo {
+	return &fakeSessionRepo{
+		sessions: make(map[uuid.UUID]*models.Session),
+		tokens:   make(map[string]*fakeRefreshToken),
+	}
+}
+
+func (r *fakeSessionRepo) Create(ctx context.Context, session *models.Session, token *models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *session
+	r.sessions[session.ID] = &copied
+	r.tokens[token.TokenHash] = &fakeRefreshToken{sessionID: session.ID}
+	return nil
+}
+
+func (r *fakeSessionRepo) Rotate(ctx context.Context, oldHash string, next *models.RefreshToken, client models.ClientInfo) (*models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[oldHash]
+	if !ok {
+		return nil, repository.ErrInvalidRefreshToken
+	}
+	session := r.sessions[token.sessionID]
+	if session.RevokedAt != nil {
+		return nil, repository.ErrInvalidRefreshToken
+	}
+	if token.used {
+		r.revoke(session, models.SessionRevokedTokenReuse)
+		return nil, repository.ErrRefreshTokenReused
+	}
+	token.used = true
+	next.SessionID = session.ID
+	r.tokens[next.TokenHash] = &fakeRefreshToken{sessionID: session.ID}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) GetByRefreshToken(ctx context.Context, tokenHash string) (*models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *r.sessions[token.sessionID]
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok || session.UserID != userID || session.RevokedAt != nil {
+		return sql.ErrNoRows
+	}
+	r.revoke(session, reason)
+	return nil
+}
+
+func (r *fakeSessionRepo) revoke(session *models.Session, reason string) {
+	now := time.Now()
+	session.RevokedAt = &now
+	session.RevokedReason = &reason
+}
+
+// session returns the stored session, revoked or not.
+func (r *fakeSessionRepo) session(id uuid.UUID) *models.Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sessions[id]
+}
+
+// fakeJWTService issues the session ID as the access token.
+type fakeJWTService struct {
+	JWTService
+}
+
+func (fakeJWTService) GenerateToken(userID uuid.UUID, email string, role string, sessionID uuid.UUID) (string, error) {
+	return sessionID.String(), nil
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultLeaderboardSize = 10
+	maxLeaderboardSize     = 50
+)
+
+type FundraiserService interface {
+	Create(ctx context.Context, ownerID uuid.UUID, req *models.CreateFundraiserRequest) (*models.Fundraiser, error)
+	// Update and Close may only be called by the fundraiser's owner.
+	Update(ctx context.Context, ownerID uuid.UUID, id uuid.UUID, req *models.UpdateFundraiserRequest) (*models.Fundraiser, error)
+	Close(ctx context.Context, ownerID uuid.UUID, id uuid.UUID) (*models.Fundraiser, error)
+
+	// GetByID, GetByCauseID, GetDonations and GetLeaderboard are public and
+	// return sql.ErrNoRows when the cause is not publicly visible.
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Fundraiser, error)
+	GetByCauseID(ctx context.Context, causeID uuid.UUID, page models.CursorParams) (*models.Page[*models.Fundraiser], error)
+	GetMine(ctx context.Context, ownerID uuid.UUID, page models.CursorParams) (*models.Page[*models.Fundraiser], error)
+	GetDonations(ctx context.Context, id uuid.UUID, page models.CursorParams) (*models.Page[*models.Donation], error)
+	GetLeaderboard(ctx context.Context, causeID uuid.UUID, limit int) ([]*models.FundraiserLeaderboardEntry, error)
+}
+
+type fundraiserService struct {
+	fundraiserRepo repository.FundraiserRepository
+	causeRepo      repository.CauseRepository
+	donationRepo   repository.DonationRepository
+}
+
+func NewFundraiserService(
+	fundraiserRepo repository.FundraiserRepository,
+	causeRepo repository.CauseRepository,
+	donationRepo repository.DonationRepository,
+) *fundraiserService {
+	return &fundraiserService{
+		fundraiserRepo: fundraiserRepo,
+		causeRepo:      causeRepo,
+		donationRepo:   donationRepo,
+	}
+}
+
+func (s *fundraiserService) Create(ctx context.Context, ownerID uuid.UUID, req *models.CreateFundraiserRequest) (*models.Fundraiser, error) {
+	cause, err := s.causeRepo.GetByID(ctx, req.CauseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cause: %w", err)
+	}
+	if !cause.State.AcceptsDonations() {
+		return nil, fmt.Errorf("cause is not accepting donations (state: %s)", cause.State)
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, errors.New("title is required")
+	}
+	if req.GoalAmount <= 0 {
+		return nil, errors.New("goal_amount must be greater than zero")
+	}
+	now := time.Now()
+	if req.Deadline != nil && !req.Deadline.After(now) {
+		return nil, errors.New("deadline must be in the future")
+	}
+
+	fundraiser := &models.Fundraiser{
+		ID:            uuid.New(),
+		CauseID:       cause.ID,
+		CauseTitle:    cause.Title,
+		OwnerID:       ownerID,
+		Title:         title,
+		Story:         req.Story,
+		GoalAmount:    req.GoalAmount,
+		CoverImageURL: req.CoverImageURL,
+		Deadline:      req.Deadline,
+		IsActive:      true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.fundraiserRepo.Create(ctx, fundraiser); err != nil {
+		return nil, err
+	}
+
+	// Reload for the owner's name.
+	return s.fundraiserRepo.GetByID(ctx, fundraiser.ID)
+}
+
+func (s *fundraiserService) ownFundraiser(ctx context.Context, ownerID uuid.UUID, id uuid.UUID) (*models.Fundraiser, error) {
+	fundraiser, err := s.fundraiserRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if fundraiser.OwnerID != ownerID {
+		return nil, fmt.Errorf("not authorized to modify this fundraiser")
+	}
+	return fundraiser, nil
+}
+
+func (s *fundraiserService) Update(ctx context.Context, ownerID uuid.UUID, id uuid.UUID, req *models.UpdateFundraiserRequest) (*models.Fundraiser, error) {
+	fundraiser, err := s.ownFundraiser(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return nil, errors.New("title cannot be empty")
+		}
+		fundraiser.Title = title
+	}
+	if req.Story != nil {
+		fundraiser.Story = req.Story
+	}
+	if req.GoalAmount != nil {
+		if *req.GoalAmount <= 0 {
+			return nil, errors.New("goal_amount must be greater than zero")
+		}
+		fundraiser.GoalAmount = *req.GoalAmount
+	}
+	if req.CoverImageURL != nil {
+		fundraiser.CoverImageURL = req.CoverImageURL
+	}
+	if req.Deadline != nil {
+		if !req.Deadline.After(time.Now()) {
+			return nil, errors.New("deadline must be in the future")
+		}
+		fundraiser.Deadline = req.Deadline
+	}
+
+	fundraiser.UpdatedAt = time.Now()
+	if err := s.fundraiserRepo.Update(ctx, fundraiser); err != nil {
+		return nil, err
+	}
+	fundraiser.SetProgress()
+	return fundraiser, nil
+}
+
+func (s *fundraiserService) Close(ctx context.Context, ownerID uuid.UUID, id uuid.UUID) (*models.Fundraiser, error) {
+	fundraiser, err := s.ownFundraiser(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !fundraiser.IsActive {
+		return nil, errors.New("fundraiser is already closed")
+	}
+
+	fundraiser.IsActive = false
+	fundraiser.UpdatedAt = time.Now()
+	if err := s.fundraiserRepo.Update(ctx, fundraiser); err != nil {
+		return nil, err
+	}
+	return fundraiser, nil
+}
+
+// checkPublicCause hides fundraisers whose cause is not publicly visible,
+// the same way the cause itself is hidden.
+func (s *fundraiserService) checkPublicCause(ctx context.Context, causeID uuid.UUID) error {
+	cause, err := s.causeRepo.GetByID(ctx, causeID)
+	if err != nil {
+		return err
+	}
+	if !cause.State.IsPublic() {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *fundraiserService) GetByID(ctx context.Context, id uuid.UUID) (*models.Fundraiser, error) {
+	fundraiser, err := s.fundraiserRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPublicCause(ctx, fundraiser.CauseID); err != nil {
+		return nil, err
+	}
+	return fundraiser, nil
+}
+
+func (s *fundraiserService) GetByCauseID(ctx context.Context, causeID uuid.UUID, page models.CursorParams) (*models.Page[*models.Fundraiser], error) {
+	if err := s.checkPublicCause(ctx, causeID); err != nil {
+		return nil, err
+	}
+	return s.fundraiserRepo.GetByCauseID(ctx, causeID, page)
+}
+
+func (s *fundraiserService) GetMine(ctx context.Context, ownerID uuid.UUID, page models.CursorParams) (*models.Page[*models.Fundraiser], error) {
+	return s.fundraiserRepo.GetByOwnerID(ctx, ownerID, page)
+}
+
+func (s *fundraiserService) GetDonations(ctx context.Context, id uuid.UUID, page models.CursorParams) (*models.Page[*models.Donation], error) {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.donationRepo.GetByFundraiserID(ctx, id, page)
+}
+
+func (s *fundraiserService) GetLeaderboard(ctx context.Context, causeID uuid.UUID, limit int) ([]*models.FundraiserLeaderboardEntry, error) {
+	if err := s.checkPublicCause(ctx, causeID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultLeaderboardSize
+	}
+	if limit > maxLeaderboardSize {
+		limit = maxLeaderboardSize
+	}
+	return s.fundraiserRepo.GetLeaderboard(ctx, causeID, limit)
+}
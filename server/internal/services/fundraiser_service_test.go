@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func newTestFundraiserService(causes ...*models.Cause) (*fundraiserService, *fakeFundraiserRepo) {
+	fundraisers := newFakeFundraiserRepo()
+	return NewFundraiserService(fundraisers, newFakeCauseRepo(causes...), nil), fundraisers
+}
+
+func openFundraiser(cause *models.Cause, owner uuid.UUID) *models.Fundraiser {
+	return &models.Fundraiser{
+		ID:         uuid.New(),
+		CauseID:    cause.ID,
+		OwnerID:    owner,
+		Title:      "Running 10k for Rampur",
+		GoalAmount: 5000,
+		IsActive:   true,
+	}
+}
+
+func TestFundraiserHiddenWithCause(t *testing.T) {
+	for _, state := range []models.CauseState{models.CauseStateDraft, models.CauseStatePendingReview} {
+		t.Run(string(state), func(t *testing.T) {
+			cause := liveCause()
+			cause.State = state
+			service, fundraisers := newTestFundraiserService(cause)
+			fundraiser := openFundraiser(cause, uuid.New())
+			fundraisers.Create(context.Background(), fundraiser)
+			ctx := context.Background()
+
+			if _, err := service.GetByID(ctx, fundraiser.ID); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("GetByID() error = %v, want sql.ErrNoRows", err)
+			}
+			if _, err := service.GetDonations(ctx, fundraiser.ID, models.CursorParams{Limit: 10}); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("GetDonations() error = %v, want sql.ErrNoRows", err)
+			}
+			if _, err := service.GetByCauseID(ctx, cause.ID, models.CursorParams{Limit: 10}); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("GetByCauseID() error = %v, want sql.ErrNoRows", err)
+			}
+			if _, err := service.GetLeaderboard(ctx, cause.ID, 10); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("GetLeaderboard() error = %v, want sql.ErrNoRows", err)
+			}
+		})
+	}
+}
+
+func TestFundraiserVisibleWithLiveCause(t *testing.T) {
+	cause := liveCause()
+	service, fundraisers := newTestFundraiserService(cause)
+	fundraiser := openFundraiser(cause, uuid.New())
+	fundraisers.Create(context.Background(), fundraiser)
+
+	got, err := service.GetByID(context.Background(), fundraiser.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.ID != fundraiser.ID {
+		t.Errorf("GetByID() = %s, want %s", got.ID, fundraiser.ID)
+	}
+
+	page, err := service.GetByCauseID(context.Background(), cause.ID, models.CursorParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetByCauseID() error = %v", err)
+	}
+	if len(page.Items) != 1 {
+		t.Errorf("GetByCauseID() returned %d fundraisers, want 1", len(page.Items))
+	}
+}
+
+func TestFundraiserCreateRequiresLiveCause(t *testing.T) {
+	cause := liveCause()
+	cause.State = models.CauseStateDraft
+	service, _ := newTestFundraiserService(cause)
+
+	_, err := service.Create(context.Background(), uuid.New(), &models.CreateFundraiserRequest{
+		CauseID:    cause.ID,
+		Title:      "Running 10k for Rampur",
+		GoalAmount: 5000,
+	})
+	if err == nil {
+		t.Fatal("Create() on a draft cause succeeded, want error")
+	}
+}
+
+func TestFundraiserUpdateOwnerOnly(t *testing.T) {
+	cause := liveCause()
+	service, fundraisers := newTestFundraiserService(cause)
+	owner := uuid.New()
+	fundraiser := openFundraiser(cause, owner)
+	fundraisers.Create(context.Background(), fundraiser)
+	title := "Someone else's title"
+
+	if _, err := service.Update(context.Background(), uuid.New(), fundraiser.ID, &models.UpdateFundraiserRequest{Title: &title}); err == nil {
+		t.Error("Update() by another user succeeded, want error")
+	}
+	if _, err := service.Close(context.Background(), uuid.New(), fundraiser.ID); err == nil {
+		t.Error("Close() by another user succeeded, want error")
+	}
+
+	closed, err := service.Close(context.Background(), owner, fundraiser.ID)
+	if err != nil {
+		t.Fatalf("Close() by owner error = %v", err)
+	}
+	if closed.IsActive {
+		t.Error("Close() left the fundraiser active")
+	}
+}
+
+func TestDonationRejectsFundraiserBeforeRecording(t *testing.T) {
+	cause, other := liveCause(), liveCause()
+	elsewhere := openFundraiser(other, uuid.New())
+	expired := openFundraiser(cause, uuid.New())
+	past := time.Now().Add(-time.Hour)
+	expired.Deadline = &past
+
+	for name, fundraiser := range map[string]*models.Fundraiser{"other cause": elsewhere, "past deadline": expired} {
+		t.Run(name, func(t *testing.T) {
+			service, donations, ledger, _, events := newTestDonationService(t, cause, other)
+			service.fundraiserRepo = newFakeFundraiserRepo(fundraiser)
+			req := donationRequest(cause, 50000)
+			req.FundraiserID = &fundraiser.ID
+
+			if _, err := service.Create(context.Background(), req); err == nil {
+				t.Fatal("Create() succeeded, want error")
+			}
+			if len(*events) != 0 || len(ledger.recorded) != 0 || len(donations.all()) != 0 {
+				t.Errorf("rejected donation reached storage: calls = %v", *events)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// ErrPledgeNotAuthorized is returned when someone other than the donor or
+// the cause's organization acts on a pledge.
+var ErrPledgeNotAuthorized = errors.New("not authorized for this pledge")
+
+type GoodsPledgeService interface {
+	// Pledge records a donor's offer of goods to a live cause.
+	Pledge(ctx context.Context, donorID uuid.UUID, req *models.CreateGoodsPledgeRequest) (*models.GoodsPledge, error)
+	// Cancel withdraws a donor's own pledge before it is received.
+	Cancel(ctx context.Context, donorID uuid.UUID, pledgeID uuid.UUID) (*models.GoodsPledge, error)
+	GetMine(ctx context.Context, donorID uuid.UUID, page models.CursorParams) (*models.Page[*models.GoodsPledge], error)
+
+	// The remaining methods act for the organization owning the cause.
+	Accept(ctx context.Context, orgID uuid.UUID, pledgeID uuid.UUID) (*models.GoodsPledge, error)
+	Reject(ctx context.Context, orgID uuid.UUID, pledgeID uuid.UUID, reason string) (*models.GoodsPledge, error)
+	ScheduleDelivery(ctx context.Context, orgID uuid.UUID, pledgeID uuid.UUID, req *models.ScheduleGoodsDeliveryRequest) (*models.GoodsPledge, error)
+	MarkReceived(ctx context.Context, orgID uuid.UUID, pledgeID uuid.UUID, req *models.ReceiveGoodsPledgeRequest) (*models.GoodsPledge, error)
+	GetForCause(ctx context.Context, orgID uuid.UUID, causeID uuid.UUID, status *models.GoodsPledgeStatus, page models.CursorParams) (*models.Page[*models.GoodsPledge], error)
+
+	// GetInKindSummary lists received goods for a public cause.
+	GetInKindSummary(ctx context.Context, causeID uuid.UUID) (*models.InKindSummary, error)
+}
+
+type goodsPledgeService struct {
+	pledgeRepo repository.GoodsPledgeRepository
+	causeRepo  repository.CauseRepository
+}
+
+func NewGoodsPledgeService(pledgeRepo repository.GoodsPledgeRepository, causeRepo repository.CauseRepository) *goodsPledgeService {
+	return &goodsPledgeService{
+		pledgeRepo: pledgeRepo,
+		causeRepo:  causeRepo,
+	}
+}
+
+func (s *goodsPledgeService) Pledge(ctx context.Context, donorID uuid.UUID, req *models.CreateGoodsPledgeRequest) (*models.GoodsPledge, error) {
+	cause, err := s.causeRepo.GetByID(ctx, req.CauseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cause: %w", err)
+	}
+	if !cause.State.AcceptsDonations() {
+		return nil, fmt.Errorf("cause is not accepting donations (state: %s)", cause.State)
+	}
+	if req.Quantity <= 0 {
+		return nil, errors.New("quantity must be greater than zero")
+	}
+
+	now := time.Now()
+	pledge := &models.GoodsPledge{
+		ID:          uuid.New(),
+		CauseID:     cause.ID,
+		DonorID:     donorID,
+		Description: req.Description,
+		Quantity:    req.Quantity,
+		Status:      models.GoodsPledgeStatusPledged,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if req.ProductID != nil {
+		product, err := s.findProduct(ctx, cause.ID, *req.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if remaining := product.QuantityNeeded - product.QuantityFunded; req.Quantity > remaining {
+			return nil, fmt.Errorf("only %d of %s still needed", max(remaining, 0), product.Name)
+		}
+		price := product.PricePerUnit
+		pledge.ProductID = &product.ID
+		pledge.ItemName = product.Name
+		pledge.EstimatedUnitValue = &price
+	} else {
+		pledge.ItemName = strings.TrimSpace(req.ItemName)
+		if pledge.ItemName == "" {
+			return nil, errors.New("item_name is required when no product is given")
+		}
+		if req.EstimatedUnitValue != nil && *req.EstimatedUnitValue < 0 {
+			return nil, errors.New("estimated_unit_value cannot be negative")
+		}
+		pledge.Unit = req.Unit
+		pledge.EstimatedUnitValue = req.EstimatedUnitValue
+	}
+
+	if err := s.pledgeRepo.Create(ctx, pledge); err != nil {
+		return nil, err
+	}
+	return pledge, nil
+}
+
+func (s *goodsPledgeService) findProduct(ctx context.Context, causeID uuid.UUID, productID uuid.UUID) (*models.CauseProduct, error) {
+	products, err := s.causeRepo.GetProductsByCauseID(ctx, causeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cause products: %w", err)
+	}
+	for _, p := range products {
+		if p.ID == productID {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("product %s does not belong to this cause", productID)
+}
+
+func (s *goodsPledgeService) Cancel(ctx context.Context, donorID uuid.UUID, pledgeID uuid.UUID) (*models.GoodsPledge, error) {
+	pledge, err := s.pledgeRepo.GetByID(ctx, pledgeID)
+	if err != nil {
+		return nil, err
+	}
+	if pledge.DonorID != donorID {
+		return nil, ErrPledgeNotAuthorized
+	}
+
+	return s.transition(ctx, pledge, models.GoodsPledgeStatusCancelled, nil)
+}
+
+func (s *goodsPledgeService) GetMine(ctx context.Context, donorID uuid.UUID, page models.CursorParams) (*models.Page[*models.GoodsPledge], error) {
+	return s.pledgeRepo.GetByDonorID(ctx, donorID, page)
+}
+
+// ownPledge loads a pledge and checks it is for a cause owned by orgID.
+func (s *goodsPledgeService) ownPledge(ctx context.Context, orgID uuid.UUID, pledgeID uuid.UUID) (*models.GoodsPledge, error) {
+	pledge, err := s.pledgeRepo.GetByID(ctx, pledgeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCauseOwner(ctx, orgID, pledge.CauseID); err != nil {
+		return nil, err
+	}
+	return pledge, nil
+}
+
+func (s *goodsPledgeService) checkCauseOwner(ctx context.Context, orgID uuid.UUID, causeID uuid.UUID) error {
+	cause, err := s.causeRepo.GetByID(ctx, causeID)
+	if err != nil {
+		return err
+	}
+	if cause.Organization.ID != orgID {
+		return ErrPledgeNotAuthorized
+	}
+	return nil
+}
+
+// transition moves a pledge to a new status. update sets any fields that
+// change along with the status before the pledge is saved.
+func (s *goodsPledgeService) transition(ctx context.Context, pledge *models.GoodsPledge, to models.GoodsPledgeStatus, update func(p *models.GoodsPledge)) (*models.GoodsPledge, error) {
+	if err := models.CanTransitionGoodsPledge(pledge.Status, to); err != nil {
+		return nil, err
+	}
+
+	from := pledge.Status
+	pledge.Status = to
+	pledge.UpdatedAt = time.Now()
+	if update != nil {
+		update(pledge)
+	}
+
+	if to == models.GoodsPledgeStatusReceived {
+		if err := s.pledgeRepo.MarkReceived(ctx, pledge, from); err != nil {
+			return nil, err
+		}
+		return pledge, nil
+	}
+	if err := s.pledgeRepo.Update(ctx, pledge, from); err != nil {
+		return nil, err
+	}
+	return pledge, nil
+}
+
+func (s *goodsPledgeService) Accept(ctx context.Context, orgID uuid.UUID, pledgeID uuid.UUID) (*models.GoodsPledge, error) {
+	pledge, err := s.ownPledge(ctx, orgID, pledgeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, pledge, models.GoodsPledgeStatusAccepted, nil)
+}
+
+func (s *goodsPledgeService) Reject(ctx context.Context, orgID uuid.UUID, pledgeID uuid.UUID, reason string) (*models.GoodsPledge, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("a reason is required to reject a pledge")
+	}
+
+	pledge, err := s.ownPledge(ctx, orgID, pledgeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, pledge, models.GoodsPledgeStatusRejected, func(p *models.GoodsPledge) {
+		p.RejectionReason = &reason
+	})
+}
+
+func (s *goodsPledgeService) ScheduleDelivery(ctx context.Context, orgID uuid.UUID, pledgeID uuid.UUID, req *models.ScheduleGoodsDeliveryRequest) (*models.GoodsPledge, error) {
+	if !req.Method.IsValid() {
+		return nil, fmt.Errorf("method must be %s or %s", models.GoodsDeliveryDropOff, models.GoodsDeliveryPickup)
+	}
+	if req.ScheduledAt.IsZero() {
+		return nil, errors.New("scheduled_at is required")
+	}
+
+	pledge, err := s.ownPledge(ctx, orgID, pledgeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, pledge, models.GoodsPledgeStatusScheduled, func(p *models.GoodsPledge) {
+		p.DeliveryMethod = &req.Method
+		p.DeliveryScheduledAt = &req.ScheduledAt
+		p.DeliveryNotes = req.Notes
+	})
+}
+
+func (s *goodsPledgeService) MarkReceived(ctx context.Context, orgID uuid.UUID, pledgeID uuid.UUID, req *models.ReceiveGoodsPledgeRequest) (*models.GoodsPledge, error) {
+	if req.ProofImageID == uuid.Nil {
+		return nil, errors.New("proof_image_id is required")
+	}
+
+	pledge, err := s.ownPledge(ctx, orgID, pledgeID)
+	if err != nil {
+		return nil, err
+	}
+
+	received := pledge.Quantity
+	if req.ReceivedQuantity != nil {
+		received = *req.ReceivedQuantity
+	}
+	// Goods beyond the pledge weren't promised to the cause and would push
+	// the product's funded quantity past what was pledged.
+	if received <= 0 || received > pledge.Quantity {
+		return nil, fmt.Errorf("received_quantity must be between 1 and the pledged %d", pledge.Quantity)
+	}
+
+	now := time.Now()
+	return s.transition(ctx, pledge, models.GoodsPledgeStatusReceived, func(p *models.GoodsPledge) {
+		p.ReceivedQuantity = &received
+		p.ReceivedAt = &now
+		p.ProofImageID = &req.ProofImageID
+	})
+}
+
+func (s *goodsPledgeService) GetForCause(ctx context.Context, orgID uuid.UUID, causeID uuid.UUID, status *models.GoodsPledgeStatus, page models.CursorParams) (*models.Page[*models.GoodsPledge], error) {
+	if err := s.checkCauseOwner(ctx, orgID, causeID); err != nil {
+		return nil, err
+	}
+	return s.pledgeRepo.GetByCauseID(ctx, causeID, status, page)
+}
+
+func (s *goodsPledgeService) GetInKindSummary(ctx context.Context, causeID uuid.UUID) (*models.InKindSummary, error) {
+	cause, err := s.causeRepo.GetByID(ctx, causeID)
+	if err != nil {
+		return nil, err
+	}
+	if !cause.State.IsPublic() {
+		return nil, sql.ErrNoRows
+	}
+
+	items, err := s.pledgeRepo.GetReceivedByCauseID(ctx, causeID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.InKindSummary{Items: items}
+	for _, item := range items {
+		if item.EstimatedValue != nil {
+			summary.TotalEstimatedValue += *item.EstimatedValue
+		}
+	}
+	return summary, nil
+}
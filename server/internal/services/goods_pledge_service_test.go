@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func newTestPledgeService(t *testing.T) (*goodsPledgeService, *fakePledgeRepo, *models.Cause, *models.CauseProduct) {
+	t.Helper()
+	cause := liveCause()
+	cause.Organization.ID = uuid.New()
+	product := &models.CauseProduct{ID: uuid.New(), CauseID: cause.ID, Name: "Blanket", PricePerUnit: 450, QuantityNeeded: 20, QuantityFunded: 15}
+
+	causeRepo := newFakeCauseRepo(cause)
+	causeRepo.products[cause.ID] = []*models.CauseProduct{product}
+	pledges := newFakePledgeRepo()
+	return NewGoodsPledgeService(pledges, causeRepo), pledges, cause, product
+}
+
+// acceptedPledge pledges quantity of product and has the organization
+// accept it.
+func acceptedPledge(t *testing.T, s *goodsPledgeService, cause *models.Cause, product *models.CauseProduct, quantity int) *models.GoodsPledge {
+	t.Helper()
+	pledge, err := s.Pledge(context.Background(), uuid.New(), &models.CreateGoodsPledgeRequest{
+		CauseID: cause.ID, ProductID: &product.ID, Quantity: quantity,
+	})
+	if err != nil {
+		t.Fatalf("Pledge() error = %v", err)
+	}
+	if pledge, err = s.Accept(context.Background(), cause.Organization.ID, pledge.ID); err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	return pledge
+}
+
+func TestPledgeChecksCauseAndProduct(t *testing.T) {
+	s, _, cause, product := newTestPledgeService(t)
+	ctx := context.Background()
+
+	if _, err := s.Pledge(ctx, uuid.New(), &models.CreateGoodsPledgeRequest{CauseID: cause.ID, ProductID: &product.ID, Quantity: 6}); err == nil {
+		t.Error("Pledge() accepted more than the 5 still needed")
+	}
+
+	pledge, err := s.Pledge(ctx, uuid.New(), &models.CreateGoodsPledgeRequest{CauseID: cause.ID, ProductID: &product.ID, Quantity: 5})
+	if err != nil {
+		t.Fatalf("Pledge() error = %v", err)
+	}
+	if pledge.ItemName != product.Name || pledge.EstimatedUnitValue == nil || *pledge.EstimatedUnitValue != 450 {
+		t.Errorf("pledge not filled from the product: %+v", pledge)
+	}
+
+	cause.State = models.CauseStateClosed
+	if _, err := s.Pledge(ctx, uuid.New(), &models.CreateGoodsPledgeRequest{CauseID: cause.ID, ItemName: "Rice", Quantity: 1}); err == nil {
+		t.Error("Pledge() accepted a pledge to a closed cause")
+	}
+}
+
+func TestMarkReceivedQuantity(t *testing.T) {
+	s, _, cause, product := newTestPledgeService(t)
+	ctx := context.Background()
+	proof := &models.ReceiveGoodsPledgeRequest{ProofImageID: uuid.New()}
+
+	for _, received := range []int{0, 5} {
+		pledge := acceptedPledge(t, s, cause, product, 4)
+		req := *proof
+		req.ReceivedQuantity = &received
+		if _, err := s.MarkReceived(ctx, cause.Organization.ID, pledge.ID, &req); err == nil {
+			t.Errorf("MarkReceived() accepted %d of 4 pledged", received)
+		}
+	}
+
+	pledge := acceptedPledge(t, s, cause, product, 4)
+	partial := 3
+	req := *proof
+	req.ReceivedQuantity = &partial
+	got, err := s.MarkReceived(ctx, cause.Organization.ID, pledge.ID, &req)
+	if err != nil {
+		t.Fatalf("MarkReceived() error = %v", err)
+	}
+	if got.Status != models.GoodsPledgeStatusReceived || *got.ReceivedQuantity != 3 {
+		t.Errorf("got status %s, received %d", got.Status, *got.ReceivedQuantity)
+	}
+
+	pledge = acceptedPledge(t, s, cause, product, 2)
+	got, err = s.MarkReceived(ctx, cause.Organization.ID, pledge.ID, proof)
+	if err != nil {
+		t.Fatalf("MarkReceived() error = %v", err)
+	}
+	if *got.ReceivedQuantity != 2 {
+		t.Errorf("received quantity = %d, want the pledged 2", *got.ReceivedQuantity)
+	}
+
+	if _, err := s.MarkReceived(ctx, cause.Organization.ID, pledge.ID, proof); err == nil {
+		t.Error("MarkReceived() received a pledge twice")
+	}
+}
+
+func TestPledgeAuthorization(t *testing.T) {
+	s, _, cause, product := newTestPledgeService(t)
+	ctx := context.Background()
+
+	donorID := uuid.New()
+	pledge, err := s.Pledge(ctx, donorID, &models.CreateGoodsPledgeRequest{CauseID: cause.ID, ProductID: &product.ID, Quantity: 1})
+	if err != nil {
+		t.Fatalf("Pledge() error = %v", err)
+	}
+
+	otherOrg := uuid.New()
+	if _, err := s.Accept(ctx, otherOrg, pledge.ID); !errors.Is(err, ErrPledgeNotAuthorized) {
+		t.Errorf("Accept() by another organization error = %v", err)
+	}
+	if _, err := s.GetForCause(ctx, otherOrg, cause.ID, nil, models.CursorParams{Limit: 10}); !errors.Is(err, ErrPledgeNotAuthorized) {
+		t.Errorf("GetForCause() by another organization error = %v", err)
+	}
+	if _, err := s.Cancel(ctx, uuid.New(), pledge.ID); !errors.Is(err, ErrPledgeNotAuthorized) {
+		t.Errorf("Cancel() by another donor error = %v", err)
+	}
+
+	cancelled, err := s.Cancel(ctx, donorID, pledge.ID)
+	if err != nil || cancelled.Status != models.GoodsPledgeStatusCancelled {
+		t.Errorf("Cancel() by the donor = %v, %v", cancelled, err)
+	}
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// identityLinkTTL is how long after the provider's callback the user has to
+// confirm linking the account.
+const identityLinkTTL = 10 * time.Minute
+
+const identityLinkAudience = "identity_link"
+
+var (
+	// ErrInvalidLinkToken covers malformed and expired link tokens, and
+	// tokens presented without the nonce they were bound to.
+	ErrInvalidLinkToken = errors.New("linking has expired, try again")
+	// ErrLastSignInMethod is returned when unlinking would leave a user
+	// with no way to sign in.
+	ErrLastSignInMethod = errors.New("set a password or link another provider before unlinking this one")
+)
+
+// IdentityService links and unlinks the OAuth and OpenID Connect accounts a
+// user signs in with.
+//
+// Linking takes two steps so that it always happens in the signed-in
+// user's own session: the provider's callback turns the account into a
+// short-lived link token, and the app then links it for whoever is signed
+// in. The token is bound to a nonce kept in the browser that went through
+// the provider, so a token can't be handed to someone else to link.
+type IdentityService interface {
+	List(ctx context.Context, userID uuid.UUID) (*models.IdentitiesResponse, error)
+	// LinkToken wraps a provider account the user has just authenticated
+	// as, bound to nonce.
+	LinkToken(provider, providerUserID, email, nonce string) (string, error)
+	// Link links the account in token to the user if nonce is the one the
+	// token was bound to. It fails with ErrInvalidLinkToken or
+	// repository.ErrIdentityLinked.
+	Link(ctx context.Context, userID uuid.UUID, token, nonce string) (*models.UserIdentity, error)
+	// Unlink fails with sql.ErrNoRows or ErrLastSignInMethod.
+	Unlink(ctx context.Context, userID uuid.UUID, provider string) error
+}
+
+type identityService struct {
+	identityRepo repository.UserIdentityRepository
+	userRepo     repository.UserRepository
+	secret       []byte
+}
+
+func NewIdentityService(identityRepo repository.UserIdentityRepository, userRepo repository.UserRepository) *identityService {
+	return &identityService{
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		secret:       accountTokenSecret(),
+	}
+}
+
+type identityLinkClaims struct {
+	Provider       string `json:"provider"`
+	ProviderUserID string `json:"provider_user_id"`
+	Email          string `json:"email,omitempty"`
+	// NonceHash is the SHA-256 of the nonce, which itself only travels in
+	// the session cookie.
+	NonceHash string `json:"nonce_hash"`
+	jwt.RegisteredClaims
+}
+
+func (s *identityService) List(ctx context.Context, userID uuid.UUID) (*models.IdentitiesResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := s.identityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.IdentitiesResponse{
+		HasPassword: user.PasswordHash != nil,
+		Identities:  identities,
+	}, nil
+}
+
+func (s *identityService) LinkToken(provider, providerUserID, email, nonce string) (string, error) {
+	if nonce == "" {
+		return "", errors.New("link token needs a nonce")
+	}
+	now := time.Now()
+	claims := &identityLinkClaims{
+		Provider:       provider,
+		ProviderUserID: providerUserID,
+		Email:          email,
+		NonceHash:      hashToken(nonce),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{identityLinkAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(identityLinkTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+}
+
+func (s *identityService) parseLinkToken(token, nonce string) (*identityLinkClaims, error) {
+	claims := &identityLinkClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(identityLinkAudience))
+	if err != nil || claims.Provider == "" || claims.ProviderUserID == "" {
+		return nil, ErrInvalidLinkToken
+	}
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(claims.NonceHash), []byte(hashToken(nonce))) != 1 {
+		return nil, ErrInvalidLinkToken
+	}
+	return claims, nil
+}
+
+func (s *identityService) Link(ctx context.Context, userID uuid.UUID, token, nonce string) (*models.UserIdentity, error) {
+	claims, err := s.parseLinkToken(token, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &models.UserIdentity{
+		ID:             uuid.New(),
+		UserID:         userID,
+		Provider:       claims.Provider,
+		ProviderUserID: claims.ProviderUserID,
+		CreatedAt:      time.Now(),
+	}
+	if claims.Email != "" {
+		identity.Email = &claims.Email
+	}
+	if err := s.identityRepo.Create(ctx, identity); err != nil {
+		if errors.Is(err, repository.ErrIdentityLinked) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to link %s account: %w", claims.Provider, err)
+	}
+	return identity, nil
+}
+
+func (s *identityService) Unlink(ctx context.Context, userID uuid.UUID, provider string) error {
+	current, err := s.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+	linked := false
+	for _, identity := range current.Identities {
+		linked = linked || identity.Provider == provider
+	}
+	if !linked {
+		return sql.ErrNoRows
+	}
+	if !current.HasPassword && len(current.Identities) == 1 {
+		return ErrLastSignInMethod
+	}
+	return s.identityRepo.Delete(ctx, userID, provider)
+}
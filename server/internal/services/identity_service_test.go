@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestLinkToken(t *testing.T) {
+	s := &identityService{secret: []byte("test-secret")}
+
+	const nonce = "browser-nonce"
+	token, err := s.LinkToken("github", "12345", "asha@example.com", nonce)
+	if err != nil {
+		t.Fatalf("LinkToken() error = %v", err)
+	}
+	claims, err := s.parseLinkToken(token, nonce)
+	if err != nil {
+		t.Fatalf("parseLinkToken() error = %v", err)
+	}
+	if claims.Provider != "github" || claims.ProviderUserID != "12345" || claims.Email != "asha@example.com" {
+		t.Fatalf("parseLinkToken() = %+v", claims)
+	}
+
+	other := &identityService{secret: []byte("other-secret")}
+	if _, err := other.parseLinkToken(token, nonce); err != ErrInvalidLinkToken {
+		t.Errorf("token signed with another secret: err = %v, want ErrInvalidLinkToken", err)
+	}
+
+	// Someone else's browser has a different nonce, or none.
+	for _, n := range []string{"other-nonce", ""} {
+		if _, err := s.parseLinkToken(token, n); err != ErrInvalidLinkToken {
+			t.Errorf("token with nonce %q: err = %v, want ErrInvalidLinkToken", n, err)
+		}
+	}
+	if _, err := s.LinkToken("github", "12345", "asha@example.com", ""); err == nil {
+		t.Error("LinkToken() without a nonce succeeded")
+	}
+
+	// An access token signed with the same secret isn't a link token.
+	access, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &identityLinkClaims{
+		Provider:       "github",
+		ProviderUserID: "12345",
+		NonceHash:      hashToken(nonce),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}).SignedString(s.secret)
+	if _, err := s.parseLinkToken(access, nonce); err != ErrInvalidLinkToken {
+		t.Errorf("token without the link audience: err = %v, want ErrInvalidLinkToken", err)
+	}
+
+	expired, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, &identityLinkClaims{
+		Provider:       "github",
+		ProviderUserID: "12345",
+		NonceHash:      hashToken(nonce),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{identityLinkAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		},
+	}).SignedString(s.secret)
+	if _, err := s.parseLinkToken(expired, nonce); err != ErrInvalidLinkToken {
+		t.Errorf("expired token: err = %v, want ErrInvalidLinkToken", err)
+	}
+}
+
+// noIdentities is a UserIdentityRepository with nothing linked, as after
+// the user unlinks their only provider account.
+type noIdentities struct {
+	repository.UserIdentityRepository
+}
+
+func (noIdentities) GetByProviderUserID(ctx context.Context, provider, providerUserID string) (*models.UserIdentity, error) {
+	return nil, sql.ErrNoRows
+}
+
+func TestOAuthSignInAfterUnlink(t *testing.T) {
+	// The account signed up with GitHub, so users.provider_id still names
+	// the GitHub account it has since unlinked.
+	providerID := "12345"
+	user := donorUser()
+	user.Provider = "github"
+	user.ProviderID = &providerID
+	auth := NewAuthService(newFakeUserRepo(user), nil, noIdentities{}, nil)
+
+	_, err := auth.CreateOrUpdateOAuthUser(context.Background(), "github", providerID, "Asha", user.Email, "")
+	if !errors.Is(err, ErrAccountExists) {
+		t.Errorf("CreateOrUpdateOAuthUser() error = %v, want ErrAccountExists", err)
+	}
+}
+
+// signInTokens is a TwoFactorService for users without 2FA.
+type signInTokens struct {
+	TwoFactorService
+}
+
+func (signInTokens) SignIn(ctx context.Context, user *models.User) (*models.AuthResponse, error) {
+	return &models.AuthResponse{User: *user}, nil
+}
+
+func TestOAuthSignUpLeavesNoAccountWhenLinkFails(t *testing.T) {
+	users := newFakeUserRepo()
+	identities := &fakeIdentityRepo{users: users, linkErr: errors.New("connection reset")}
+	auth := NewAuthService(users, nil, identities, signInTokens{})
+	ctx := context.Background()
+
+	if _, err := auth.CreateOrUpdateOAuthUser(ctx, "github", "12345", "Asha", "asha@example.com", ""); err == nil {
+		t.Fatal("CreateOrUpdateOAuthUser() succeeded while linking failed")
+	}
+	if _, err := users.GetByEmail(ctx, "asha@example.com"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("failed sign-up left an account behind: %v", err)
+	}
+
+	// Signing in again creates the account rather than hitting ErrAccountExists.
+	identities.linkErr = nil
+	resp, err := auth.CreateOrUpdateOAuthUser(ctx, "github", "12345", "Asha", "asha@example.com", "")
+	if err != nil {
+		t.Fatalf("retried CreateOrUpdateOAuthUser() error = %v", err)
+	}
+	user, err := users.GetByEmail(ctx, "asha@example.com")
+	if err != nil || resp.User.ID != user.ID {
+		t.Fatalf("retry signed in %s, saved user %v", resp.User.ID, err)
+	}
+	if linked, err := identities.GetByProviderUserID(ctx, "github", "12345"); err != nil || linked.UserID != user.ID {
+		t.Errorf("GitHub account linked to %v (%v), want %s", linked, err, user.ID)
+	}
+
+	// And the next sign-in finds the linked account.
+	again, err := auth.CreateOrUpdateOAuthUser(ctx, "github", "12345", "Asha", "asha@example.com", "")
+	if err != nil || again.User.ID != user.ID {
+		t.Errorf("second sign-in error = %v, want user %s", err, user.ID)
+	}
+}
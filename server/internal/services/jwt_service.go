@@ -1,47 +1,63 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
+	"server/internal/repository"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
+// AccessTokenTTL is kept short because access tokens are only checked
+// against their session, not revoked individually.
+const AccessTokenTTL = 15 * time.Minute
+
+var errSessionRevoked = errors.New("session has been revoked or has expired")
+
 type JWTService interface {
-	GenerateToken(userID uuid.UUID, email string, role string) (string, error)
-	ValidateToken(tokenString string) (*Claims, error)
+	GenerateToken(userID uuid.UUID, email string, role string, sessionID uuid.UUID) (string, error)
+	// ValidateToken checks the signature and expiry, and that the session
+	// the token was issued for is still active.
+	ValidateToken(ctx context.Context, tokenString string) (*Claims, error)
 }
 
 type jwtService struct {
-	secretKey []byte
+	secretKey   []byte
+	sessionRepo repository.SessionRepository
 }
 
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
-	Role   string    `json:"role"`
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	SessionID uuid.UUID `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func NewJWTService() JWTService {
+func NewJWTService(sessionRepo repository.SessionRepository) JWTService {
 	secretKey := os.Getenv("JWT_SECRET")
 	if secretKey == "" {
 		secretKey = "your-secret-key" // Default for development
 	}
 	return &jwtService{
-		secretKey: []byte(secretKey),
+		secretKey:   []byte(secretKey),
+		sessionRepo: sessionRepo,
 	}
 }
 
-func (j *jwtService) GenerateToken(userID uuid.UUID, email string, role string) (string, error) {
+func (j *jwtService) GenerateToken(userID uuid.UUID, email string, role string, sessionID uuid.UUID) (string, error) {
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // 24 hours
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "ngo-contribution-system",
@@ -52,7 +68,7 @@ func (j *jwtService) GenerateToken(userID uuid.UUID, email string, role string)
 	return token.SignedString(j.secretKey)
 }
 
-func (j *jwtService) ValidateToken(tokenString string) (*Claims, error) {
+func (j *jwtService) ValidateToken(ctx context.Context, tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (any, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -64,9 +80,23 @@ func (j *jwtService) ValidateToken(tokenString string) (*Claims, error) {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	// Tokens issued before sessions existed carry no sid and are rejected;
+	// those users sign in again.
+	if claims.SessionID == uuid.Nil {
+		return nil, errSessionRevoked
+	}
+	active, err := j.sessionRepo.IsActive(ctx, claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, errSessionRevoked
 	}
 
-	return nil, fmt.Errorf("invalid token")
+	return claims, nil
 }
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Mailer sends plain-text email.
+type Mailer interface {
+	Send(ctx context.Context, to string, subject string, body string) error
+}
+
+// NewMailerFromEnv picks the mail transport from MAIL_TRANSPORT: "smtp",
+// "file" (writes .eml files to MAIL_DIR, for local development) or "log",
+// the default.
+func NewMailerFromEnv() (Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "CharityLight <no-reply@charitylight.local>"
+	}
+
+	switch transport := os.Getenv("MAIL_TRANSPORT"); transport {
+	case "", "log":
+		return NewLogMailer(), nil
+	case "file":
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		return NewFileMailer(dir, from)
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required when MAIL_TRANSPORT=smtp")
+		}
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return NewSMTPMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_TRANSPORT %q", transport)
+	}
+}
+
+// LogMailer writes emails to the server log instead of sending them.
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	log.Printf("mail to=%s subject=%q\n%s", to, subject, body)
+	return nil
+}
+
+// FileMailer writes each email as an .eml file, so links in verification and
+// reset emails can be followed locally.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir string, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	msg, err := buildMessage(m.from, to, subject, body)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405"), uuid.NewString()[:8])
+	return os.WriteFile(filepath.Join(m.dir, name), msg, 0o600)
+}
+
+// SMTPMailer sends through an SMTP relay, upgrading to TLS when the server
+// offers STARTTLS.
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{
+		addr: net.JoinHostPort(host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	msg, err := buildMessage(m.from, to, subject, body)
+	if err != nil {
+		return err
+	}
+	sender, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("invalid MAIL_FROM: %w", err)
+	}
+	recipient, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+	return smtp.SendMail(m.addr, m.auth, sender.Address, []string{recipient.Address}, msg)
+}
+
+// buildMessage formats a plain-text RFC 5322 message. Subjects are
+// Q-encoded, which also keeps user-supplied text from adding headers.
+func buildMessage(from, to, subject, body string) ([]byte, error) {
+	recipient, err := mail.ParseAddress(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", recipient.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type MatchingCampaignService interface {
+	Create(ctx context.Context, adminID uuid.UUID, req *models.CreateMatchingCampaignRequest) (*models.MatchingCampaign, error)
+	List(ctx context.Context, page models.CursorParams) (*models.Page[*models.MatchingCampaign], error)
+	// Deactivate stops a campaign from matching further donations. Matches
+	// already made are kept.
+	Deactivate(ctx context.Context, id uuid.UUID) (*models.MatchingCampaign, error)
+}
+
+type matchingCampaignService struct {
+	matchingRepo repository.MatchingCampaignRepository
+	causeRepo    repository.CauseRepository
+	userRepo     repository.UserRepository
+}
+
+func NewMatchingCampaignService(
+	matchingRepo repository.MatchingCampaignRepository,
+	causeRepo repository.CauseRepository,
+	userRepo repository.UserRepository,
+) *matchingCampaignService {
+	return &matchingCampaignService{
+		matchingRepo: matchingRepo,
+		causeRepo:    causeRepo,
+		userRepo:     userRepo,
+	}
+}
+
+func (s *matchingCampaignService) Create(ctx context.Context, adminID uuid.UUID, req *models.CreateMatchingCampaignRequest) (*models.MatchingCampaign, error) {
+	name := strings.TrimSpace(req.SponsorName)
+	if name == "" {
+		return nil, errors.New("sponsor_name is required")
+	}
+	if (req.CauseID == nil) == (req.DomainID == nil) {
+		return nil, errors.New("exactly one of cause_id or domain_id is required")
+	}
+	if req.Ratio <= 0 {
+		return nil, errors.New("ratio must be greater than zero")
+	}
+	if req.CapAmount <= 0 {
+		return nil, errors.New("cap_amount must be greater than zero")
+	}
+
+	now := time.Now()
+	startsAt := now
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	if !req.EndsAt.After(startsAt) {
+		return nil, errors.New("ends_at must be after starts_at")
+	}
+	if !req.EndsAt.After(now) {
+		return nil, errors.New("ends_at must be in the future")
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, req.SponsorUserID); err != nil {
+		return nil, fmt.Errorf("sponsor user not found")
+	}
+	if req.CauseID != nil {
+		if _, err := s.causeRepo.GetByID(ctx, *req.CauseID); err != nil {
+			return nil, fmt.Errorf("cause not found")
+		}
+	}
+
+	campaign := &models.MatchingCampaign{
+		ID:            uuid.New(),
+		SponsorUserID: req.SponsorUserID,
+		SponsorName:   name,
+		CauseID:       req.CauseID,
+		DomainID:      req.DomainID,
+		Ratio:         req.Ratio,
+		CapAmount:     req.CapAmount,
+		StartsAt:      startsAt,
+		EndsAt:        req.EndsAt,
+		IsActive:      true,
+		CreatedBy:     &adminID,
+		CreatedAt:     now,
+	}
+	if err := s.matchingRepo.Create(ctx, campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+func (s *matchingCampaignService) List(ctx context.Context, page models.CursorParams) (*models.Page[*models.MatchingCampaign], error) {
+	return s.matchingRepo.List(ctx, page)
+}
+
+func (s *matchingCampaignService) Deactivate(ctx context.Context, id uuid.UUID) (*models.MatchingCampaign, error) {
+	if err := s.matchingRepo.Deactivate(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.matchingRepo.GetByID(ctx, id)
+}
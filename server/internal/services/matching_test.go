@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func matchingCampaign(cause *models.Cause, ratio, capAmount float64) *models.MatchingCampaign {
+	return &models.MatchingCampaign{
+		ID:            uuid.New(),
+		SponsorUserID: uuid.New(),
+		SponsorName:   "Tata Trusts",
+		CauseID:       &cause.ID,
+		Ratio:         ratio,
+		CapAmount:     capAmount,
+		StartsAt:      time.Now().Add(-time.Hour),
+		EndsAt:        time.Now().Add(time.Hour),
+		IsActive:      true,
+	}
+}
+
+// sponsorDonations returns the saved donations matching donationID.
+func sponsorDonations(repo *fakeDonationRepo, donationID uuid.UUID) []*models.Donation {
+	var out []*models.Donation
+	for _, d := range repo.all() {
+		if d.MatchedDonationID != nil && *d.MatchedDonationID == donationID {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func TestMatchingAppliesEachCampaign(t *testing.T) {
+	cause := liveCause()
+	service, donations, ledger, _, _ := newTestDonationService(t, cause)
+	double := matchingCampaign(cause, 2, 100000)
+	single := matchingCampaign(cause, 1, 100000)
+	service.matchingRepo = &fakeMatchingRepo{campaigns: []*models.MatchingCampaign{double, single}}
+
+	donation, err := service.Create(context.Background(), donationRequest(cause, 50000))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	matches := sponsorDonations(donations, donation.ID)
+	if len(matches) != 2 {
+		t.Fatalf("got %d sponsor donations, want 2", len(matches))
+	}
+	refs := make(map[string]bool)
+	for _, m := range matches {
+		want := models.Paise(50000)
+		if *m.MatchingCampaignID == double.ID {
+			want = 100000
+		}
+		if m.Amount != want {
+			t.Errorf("campaign %s matched %v, want %v", *m.MatchingCampaignID, m.Amount, want)
+		}
+		refs[*m.PaymentID] = true
+	}
+	if len(refs) != 2 {
+		t.Errorf("sponsor donations share a payment reference: %v", refs)
+	}
+
+	// Only the donor's own donation is written during the request.
+	if len(ledger.recorded) != 1 || ledger.recorded[0] != donation.ID {
+		t.Errorf("ledger recorded %v during the request, want only the donation", ledger.recorded)
+	}
+	service.syncLedger(context.Background())
+	if len(ledger.recorded) != 3 {
+		t.Errorf("ledger recorded %d donations after the sync, want 3", len(ledger.recorded))
+	}
+}
+
+func TestMatchingStopsAtCap(t *testing.T) {
+	cause := liveCause()
+	service, donations, _, _, _ := newTestDonationService(t, cause)
+	campaign := matchingCampaign(cause, 1, 800)
+	service.matchingRepo = &fakeMatchingRepo{campaigns: []*models.MatchingCampaign{campaign}}
+
+	amounts := []models.Paise{}
+	for range 3 {
+		donation, err := service.Create(context.Background(), donationRequest(cause, 50000))
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		var matched models.Paise
+		for _, m := range sponsorDonations(donations, donation.ID) {
+			matched += m.Amount
+		}
+		amounts = append(amounts, matched)
+	}
+
+	if amounts[0] != 50000 || amounts[1] != 30000 || amounts[2] != 0 {
+		t.Errorf("matched %v, want [500.00 300.00 0.00] against a cap of 800", amounts)
+	}
+}
+
+func TestMatchingOnlyInsideWindow(t *testing.T) {
+	cause := liveCause()
+	service, donations, _, _, _ := newTestDonationService(t, cause)
+	ended := matchingCampaign(cause, 1, 100000)
+	ended.EndsAt = time.Now().Add(-time.Minute)
+	upcoming := matchingCampaign(cause, 1, 100000)
+	upcoming.StartsAt = time.Now().Add(time.Hour)
+	upcoming.EndsAt = time.Now().Add(2 * time.Hour)
+	paused := matchingCampaign(cause, 1, 100000)
+	paused.IsActive = false
+	service.matchingRepo = &fakeMatchingRepo{campaigns: []*models.MatchingCampaign{ended, upcoming, paused}}
+
+	donation, err := service.Create(context.Background(), donationRequest(cause, 50000))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if matches := sponsorDonations(donations, donation.ID); len(matches) != 0 {
+		t.Errorf("got %d sponsor donations from campaigns that aren't running", len(matches))
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"sync"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// notificationStreamBuffer is how many events an open stream can fall behind
+// before further events to it are dropped.
+const notificationStreamBuffer = 16
+
+// notificationHub fans notification events out to the streams each user has
+// open on this server. Streams on other servers don't hear about them; their
+// clients see the notifications the next time they load the inbox.
+type notificationHub struct {
+	mu      sync.Mutex
+	streams map[uuid.UUID]map[chan *models.NotificationEvent]struct{}
+}
+
+func newNotificationHub() *notificationHub {
+	return &notificationHub{streams: make(map[uuid.UUID]map[chan *models.NotificationEvent]struct{})}
+}
+
+// subscribe opens a stream of the user's events. Calling the returned
+// func closes it.
+func (h *notificationHub) subscribe(userID uuid.UUID) (<-chan *models.NotificationEvent, func()) {
+	ch := make(chan *models.NotificationEvent, notificationStreamBuffer)
+
+	h.mu.Lock()
+	if h.streams[userID] == nil {
+		h.streams[userID] = make(map[chan *models.NotificationEvent]struct{})
+	}
+	h.streams[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.streams[userID], ch)
+			if len(h.streams[userID]) == 0 {
+				delete(h.streams, userID)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// listening reports whether the user has a stream open.
+func (h *notificationHub) listening(userID uuid.UUID) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.streams[userID]) > 0
+}
+
+// publish sends the event to each of the user's streams without waiting on
+// any of them. A stream that has fallen behind misses it, but the unread
+// count in its next event is right.
+func (h *notificationHub) publish(userID uuid.UUID, event *models.NotificationEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.streams[userID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
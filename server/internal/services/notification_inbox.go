@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// inboxEntry is what an in-app notification says.
+type inboxEntry struct {
+	Title string
+	Body  string
+	// Link is a path in the web app, or empty.
+	Link string
+}
+
+// post puts a notification of kind, saying what entryFor returns, in each
+// user's inbox and pushes it to the streams they have open.
+func (s *notificationService) post(ctx context.Context, kind models.NotificationKind, userIDs []uuid.UUID, entryFor func(uuid.UUID) *inboxEntry) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	now := time.Now()
+	notifications := make([]*models.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		entry := entryFor(userID)
+		n := &models.Notification{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Kind:      kind,
+			Title:     entry.Title,
+			Body:      entry.Body,
+			CreatedAt: now,
+		}
+		if entry.Link != "" {
+			link := entry.Link
+			n.Link = &link
+		}
+		notifications = append(notifications, n)
+	}
+
+	if err := s.notificationRepo.CreateNotifications(ctx, notifications); err != nil {
+		log.Printf("Warning: failed to save %d %s notifications: %v", len(notifications), kind, err)
+		return
+	}
+
+	for _, n := range notifications {
+		if !s.hub.listening(n.UserID) {
+			continue
+		}
+		unread, err := s.notificationRepo.CountUnread(ctx, n.UserID)
+		if err != nil {
+			log.Printf("Warning: failed to count unread notifications of user %v: %v", n.UserID, err)
+			continue
+		}
+		s.hub.publish(n.UserID, &models.NotificationEvent{
+			Type:         models.NotificationEventCreated,
+			Notification: n,
+			Unread:       unread,
+		})
+	}
+}
+
+// publishUnread tells the user's other open streams the unread count
+// changed.
+func (s *notificationService) publishUnread(ctx context.Context, userID uuid.UUID) {
+	if !s.hub.listening(userID) {
+		return
+	}
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		log.Printf("Warning: failed to count unread notifications of user %v: %v", userID, err)
+		return
+	}
+	s.hub.publish(userID, &models.NotificationEvent{Type: models.NotificationEventUnread, Unread: unread})
+}
+
+func (s *notificationService) ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, page models.CursorParams) (*models.Page[*models.Notification], error) {
+	return s.notificationRepo.ListNotifications(ctx, userID, unreadOnly, page)
+}
+
+func (s *notificationService) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.notificationRepo.CountUnread(ctx, userID)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	if err := s.notificationRepo.MarkNotificationRead(ctx, userID, notificationID, time.Now()); err != nil {
+		return err
+	}
+	s.publishUnread(ctx, userID)
+	return nil
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	if err := s.notificationRepo.MarkAllNotificationsRead(ctx, userID, time.Now()); err != nil {
+		return err
+	}
+	s.publishUnread(ctx, userID)
+	return nil
+}
+
+func (s *notificationService) Subscribe(userID uuid.UUID) (<-chan *models.NotificationEvent, func()) {
+	return s.hub.subscribe(userID)
+}
@@ -0,0 +1,712 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"server/internal/models"
+	"server/internal/policy"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultEmailDispatchInterval = 15 * time.Second
+	// emailLease is how long a claimed email is held before another
+	// dispatcher may take it, comfortably longer than one SMTP send.
+	emailLease     = 2 * time.Minute
+	emailBatchSize = 20
+)
+
+//go:embed templates/email/*.tmpl
+var emailTemplateFS embed.FS
+
+var emailTemplates = parseEmailTemplates()
+
+// parseEmailTemplates parses one template set per notification kind, each
+// defining "subject" and "body", with layout.tmpl's shared pieces.
+func parseEmailTemplates() map[models.NotificationKind]*template.Template {
+	funcs := template.FuncMap{
+		"rupees": formatRupees,
+		"date":   func(t time.Time) string { return t.Format("2 January 2006") },
+	}
+
+	templates := make(map[models.NotificationKind]*template.Template, len(models.NotificationKinds))
+	for _, info := range models.NotificationKinds {
+		templates[info.Kind] = template.Must(template.New(string(info.Kind)).Funcs(funcs).ParseFS(
+			emailTemplateFS,
+			"templates/email/layout.tmpl",
+			"templates/email/"+string(info.Kind)+".tmpl",
+		))
+	}
+	return templates
+}
+
+func formatRupees(amount float64) string {
+	return fmt.Sprintf("₹%.2f", amount)
+}
+
+// emailData is what every email template is executed with. Data holds the
+// fields particular to the kind.
+type emailData struct {
+	RecipientName  string
+	PreferencesURL string
+	UnsubscribeURL string
+	Data           any
+}
+
+// renderEmail executes the kind's templates. The subject is folded onto one
+// line.
+func renderEmail(kind models.NotificationKind, data *emailData) (subject string, body string, err error) {
+	tmpl, ok := emailTemplates[kind]
+	if !ok {
+		return "", "", fmt.Errorf("no email template for %s", kind)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", err
+	}
+	subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	buf.Reset()
+	if err := tmpl.ExecuteTemplate(&buf, "body", data); err != nil {
+		return "", "", err
+	}
+	return subject, strings.TrimSpace(buf.String()) + "\n", nil
+}
+
+type donationReceiptEmail struct {
+	CauseTitle string
+	Amount     float64
+	CreatedAt  time.Time
+	TxHash     string
+	ReceiptURL string
+	CauseURL   string
+}
+
+type donationReceivedEmail struct {
+	CauseTitle string
+	Amount     float64
+	DonorName  string
+	Matched    bool
+	CauseURL   string
+}
+
+type milestoneReachedEmail struct {
+	CauseTitle      string
+	MilestoneNumber int
+	Amount          float64
+	ForOrganization bool
+	CauseURL        string
+}
+
+type causeUpdateEmail struct {
+	CauseTitle  string
+	UpdateTitle string
+	Verified    bool
+	CauseURL    string
+}
+
+type receiptVerificationEmail struct {
+	JobID         uuid.UUID
+	Status        string
+	Outcome       string
+	ClaimedAmount float64
+	Score         string
+}
+
+type disputeUpdateEmail struct {
+	Title           string
+	CauseTitle      string
+	Opened          bool
+	StatusLabel     string
+	ResolutionNotes string
+	ForOrganization bool
+}
+
+// Notifier tells people about things that happened to them, their
+// donations or their organization, in their in-app inbox and by email. Like WebhookPublisher, it never fails
+// the action it's told about; problems are logged.
+type Notifier interface {
+	// DonationCompleted thanks the donor and tells the cause's
+	// organization.
+	DonationCompleted(ctx context.Context, cause *models.Cause, donation *models.Donation)
+	// MilestoneReached tells the cause's donors and its organization.
+	MilestoneReached(ctx context.Context, cause *models.Cause, disbursement *models.Disbursement)
+	// CauseUpdatePosted tells the cause's donors.
+	CauseUpdatePosted(ctx context.Context, causeID uuid.UUID, update *models.CauseUpdate)
+	// ReceiptVerified tells the organization how a receipt it uploaded was
+	// verified, from the job's final Status and ReceiptScore.
+	ReceiptVerified(ctx context.Context, job *models.ReceiptVerificationJob)
+	// DisputeUpdated tells whoever opened the dispute and the organization
+	// it's about, when it's opened and when its status changes.
+	DisputeUpdated(ctx context.Context, dispute *models.Dispute)
+}
+
+// NotificationService keeps each user's in-app inbox, pushing new
+// notifications to the streams they have open, and queues notification
+// emails in an outbox and sends them, retrying failures on
+// models.EmailRetrySchedule. Users choose which kinds of email they get and
+// can unsubscribe from all of them with the link in every email; the inbox
+// gets every kind.
+type NotificationService interface {
+	Notifier
+
+	ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, page models.CursorParams) (*models.Page[*models.Notification], error)
+	UnreadCount(ctx context.Context, userID uuid.UUID) (int, error)
+	// MarkRead returns sql.ErrNoRows if the user has no such notification.
+	MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+	// Subscribe opens a stream of the user's notification events on this
+	// server. Calling the returned func closes it.
+	Subscribe(userID uuid.UUID) (<-chan *models.NotificationEvent, func())
+
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferencesResponse, error)
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, req *models.UpdateNotificationPreferencesRequest) (*models.NotificationPreferencesResponse, error)
+	// Unsubscribe turns every notification email off for the user the
+	// token in an email's unsubscribe link was made for.
+	Unsubscribe(ctx context.Context, token string) error
+
+	// Start sends due emails until ctx is done.
+	Start(ctx context.Context)
+}
+
+// ErrInvalidUnsubscribeToken is returned for a forged or expired
+// unsubscribe link.
+var ErrInvalidUnsubscribeToken = errors.New("invalid or expired unsubscribe link")
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	memberRepo       repository.OrganizationMemberRepository
+	causeRepo        repository.CauseRepository
+	mailer           Mailer
+	hub              *notificationHub
+	signer           *accountTokenSigner
+	frontendURL      string
+	interval         time.Duration
+	wake             chan struct{}
+}
+
+func NewNotificationService(
+	notificationRepo repository.NotificationRepository,
+	memberRepo repository.OrganizationMemberRepository,
+	causeRepo repository.CauseRepository,
+	mailer Mailer,
+) *notificationService {
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:5173"
+	}
+	interval := defaultEmailDispatchInterval
+	if raw := os.Getenv("EMAIL_DISPATCH_INTERVAL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			interval = d
+		}
+	}
+
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		memberRepo:       memberRepo,
+		causeRepo:        causeRepo,
+		mailer:           mailer,
+		hub:              newNotificationHub(),
+		signer:           newAccountTokenSigner(accountTokenSecret()),
+		frontendURL:      strings.TrimRight(frontendURL, "/"),
+		interval:         interval,
+		wake:             make(chan struct{}, 1),
+	}
+}
+
+func causePath(causeID uuid.UUID) string {
+	return "/campaign/" + causeID.String()
+}
+
+func (s *notificationService) DonationCompleted(ctx context.Context, cause *models.Cause, donation *models.Donation) {
+	causeURL := s.frontendURL + causePath(cause.ID)
+	receiptPath := "/donations/" + donation.ID.String() + "/receipt"
+	amount := donation.Amount.Rupees()
+
+	s.post(ctx, models.NotificationDonationReceipt, []uuid.UUID{donation.UserID}, func(uuid.UUID) *inboxEntry {
+		return &inboxEntry{
+			Title: fmt.Sprintf("Thank you for donating %s to %s", formatRupees(amount), cause.Title),
+			Body:  "Your receipt is ready.",
+			Link:  receiptPath,
+		}
+	})
+	if donor, ok := s.userRecipient(ctx, donation.UserID, models.NotificationDonationReceipt); ok {
+		txHash := ""
+		if donation.TxHash != nil {
+			txHash = *donation.TxHash
+		}
+		s.queue(ctx, models.NotificationDonationReceipt, []*models.EmailRecipient{donor}, func(*models.EmailRecipient) any {
+			return &donationReceiptEmail{
+				CauseTitle: cause.Title,
+				Amount:     amount,
+				CreatedAt:  donation.CreatedAt,
+				TxHash:     txHash,
+				ReceiptURL: s.frontendURL + receiptPath,
+				CauseURL:   causeURL,
+			}
+		})
+	}
+
+	donorName := donation.Name
+	if donation.IsAnonymous {
+		donorName = models.AnonymousDonorName
+	}
+	members := s.organizationMemberIDs(ctx, cause.Organization.ID, policy.DisbursementView)
+	s.post(ctx, models.NotificationDonationReceived, members, func(uuid.UUID) *inboxEntry {
+		return &inboxEntry{
+			Title: fmt.Sprintf("%s received %s", cause.Title, formatRupees(amount)),
+			Body:  "From " + donorName + ".",
+			Link:  causePath(cause.ID),
+		}
+	})
+	s.queue(ctx, models.NotificationDonationReceived, s.emailRecipients(ctx, members, models.NotificationDonationReceived), func(*models.EmailRecipient) any {
+		return &donationReceivedEmail{
+			CauseTitle: cause.Title,
+			Amount:     amount,
+			DonorName:  donorName,
+			Matched:    donation.MatchingCampaignID != nil,
+			CauseURL:   causeURL,
+		}
+	})
+}
+
+func (s *notificationService) MilestoneReached(ctx context.Context, cause *models.Cause, disbursement *models.Disbursement) {
+	members := s.organizationMemberIDs(ctx, cause.Organization.ID, policy.DisbursementView)
+	forOrganization := make(map[uuid.UUID]bool, len(members))
+	for _, id := range members {
+		forOrganization[id] = true
+	}
+
+	// Donors can be many, so they're looked up after the caller moves on.
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		userIDs := members
+		donorIDs, err := s.notificationRepo.ListCauseDonorIDs(ctx, cause.ID)
+		if err != nil {
+			log.Printf("Warning: failed to load donors of cause %v to notify: %v", cause.ID, err)
+		}
+		for _, id := range donorIDs {
+			if !forOrganization[id] {
+				userIDs = append(userIDs, id)
+			}
+		}
+
+		amount := formatRupees(disbursement.Amount)
+		s.post(ctx, models.NotificationMilestoneReached, userIDs, func(userID uuid.UUID) *inboxEntry {
+			body := amount + " of the funds you helped raise is being released."
+			if forOrganization[userID] {
+				body = amount + " has been approved for your organization."
+			}
+			return &inboxEntry{
+				Title: fmt.Sprintf("%s reached milestone %d", cause.Title, disbursement.MilestoneNumber),
+				Body:  body,
+				Link:  causePath(cause.ID),
+			}
+		})
+
+		recipients := s.emailRecipients(ctx, members, models.NotificationMilestoneReached)
+		donors, err := s.notificationRepo.ListCauseDonorRecipients(ctx, cause.ID, models.NotificationMilestoneReached)
+		if err != nil {
+			log.Printf("Warning: failed to load donors of cause %v to notify: %v", cause.ID, err)
+		}
+		for _, d := range donors {
+			if !forOrganization[d.UserID] {
+				recipients = append(recipients, d)
+			}
+		}
+
+		s.queue(ctx, models.NotificationMilestoneReached, recipients, func(r *models.EmailRecipient) any {
+			return &milestoneReachedEmail{
+				CauseTitle:      cause.Title,
+				MilestoneNumber: disbursement.MilestoneNumber,
+				Amount:          disbursement.Amount,
+				ForOrganization: forOrganization[r.UserID],
+				CauseURL:        s.frontendURL + causePath(cause.ID),
+			}
+		})
+	}()
+}
+
+// causeUpdateTitle is the in-app title for an update, which calls out
+// execution updates whose receipts and proof checked out.
+func causeUpdateTitle(causeTitle string, update *models.CauseUpdate) string {
+	if update.IsVerified && strings.EqualFold(update.UpdateType, "Execution") {
+		return causeTitle + " posted a verified execution update"
+	}
+	return causeTitle + " posted an update"
+}
+
+func (s *notificationService) CauseUpdatePosted(ctx context.Context, causeID uuid.UUID, update *models.CauseUpdate) {
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		cause, err := s.causeRepo.GetByID(ctx, causeID)
+		if err != nil {
+			log.Printf("Warning: failed to load cause %v to notify its donors: %v", causeID, err)
+			return
+		}
+
+		donorIDs, err := s.notificationRepo.ListCauseDonorIDs(ctx, causeID)
+		if err != nil {
+			log.Printf("Warning: failed to load donors of cause %v to notify: %v", causeID, err)
+		}
+		s.post(ctx, models.NotificationCauseUpdate, donorIDs, func(uuid.UUID) *inboxEntry {
+			return &inboxEntry{
+				Title: causeUpdateTitle(cause.Title, update),
+				Body:  update.Title,
+				Link:  causePath(causeID),
+			}
+		})
+
+		donors, err := s.notificationRepo.ListCauseDonorRecipients(ctx, causeID, models.NotificationCauseUpdate)
+		if err != nil {
+			log.Printf("Warning: failed to load donors of cause %v to notify: %v", causeID, err)
+			return
+		}
+		s.queue(ctx, models.NotificationCauseUpdate, donors, func(*models.EmailRecipient) any {
+			return &causeUpdateEmail{
+				CauseTitle:  cause.Title,
+				UpdateTitle: update.Title,
+				Verified:    update.IsVerified,
+				CauseURL:    s.frontendURL + causePath(causeID),
+			}
+		})
+	}()
+}
+
+func (s *notificationService) ReceiptVerified(ctx context.Context, job *models.ReceiptVerificationJob) {
+	outcome := "could not be verified"
+	switch job.Status {
+	case "verified":
+		outcome = "verified"
+	case "review":
+		outcome = "sent for manual review"
+	case "rejected":
+		outcome = "rejected"
+	}
+	score := ""
+	if job.ReceiptScore != nil {
+		score = fmt.Sprintf("%.2f", *job.ReceiptScore)
+	}
+
+	members := s.organizationMemberIDs(ctx, job.OrganizationID, policy.UpdatePost)
+	s.post(ctx, models.NotificationReceiptVerification, members, func(uuid.UUID) *inboxEntry {
+		body := "Claimed amount " + formatRupees(job.ClaimedAmount) + "."
+		if score != "" {
+			body += " Score " + score + "."
+		}
+		return &inboxEntry{Title: "A receipt you uploaded was " + outcome, Body: body}
+	})
+	s.queue(ctx, models.NotificationReceiptVerification, s.emailRecipients(ctx, members, models.NotificationReceiptVerification), func(*models.EmailRecipient) any {
+		return &receiptVerificationEmail{
+			JobID:         job.ID,
+			Status:        job.Status,
+			Outcome:       outcome,
+			ClaimedAmount: job.ClaimedAmount,
+			Score:         score,
+		}
+	})
+}
+
+func (s *notificationService) DisputeUpdated(ctx context.Context, dispute *models.Dispute) {
+	causeTitle := ""
+	if dispute.CauseID != nil {
+		if cause, err := s.causeRepo.GetByID(ctx, *dispute.CauseID); err == nil {
+			causeTitle = cause.Title
+		}
+	}
+	notes := ""
+	if dispute.ResolutionNotes != nil {
+		notes = *dispute.ResolutionNotes
+	}
+	// A dispute that's never been changed was just opened.
+	opened := dispute.UpdatedAt.Equal(dispute.CreatedAt)
+	statusLabel := strings.ReplaceAll(string(dispute.Status), "_", " ")
+
+	userIDs := s.organizationMemberIDs(ctx, dispute.OrganizationID, policy.CauseUpdate)
+	forOrganization := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		forOrganization[id] = true
+	}
+	if dispute.OpenedBy != nil && !forOrganization[*dispute.OpenedBy] {
+		userIDs = append(userIDs, *dispute.OpenedBy)
+	}
+
+	s.post(ctx, models.NotificationDisputeUpdate, userIDs, func(uuid.UUID) *inboxEntry {
+		entry := &inboxEntry{Title: fmt.Sprintf("Dispute %q is now %s", dispute.Title, statusLabel), Body: notes}
+		if opened {
+			entry.Title = "Dispute opened: " + dispute.Title
+		}
+		if entry.Body == "" && causeTitle != "" {
+			entry.Body = "About " + causeTitle + "."
+		}
+		return entry
+	})
+	s.queue(ctx, models.NotificationDisputeUpdate, s.emailRecipients(ctx, userIDs, models.NotificationDisputeUpdate), func(r *models.EmailRecipient) any {
+		return &disputeUpdateEmail{
+			Title:           dispute.Title,
+			CauseTitle:      causeTitle,
+			Opened:          opened,
+			StatusLabel:     statusLabel,
+			ResolutionNotes: notes,
+			ForOrganization: forOrganization[r.UserID],
+		}
+	})
+}
+
+// userRecipient returns the user if they want emails of kind.
+func (s *notificationService) userRecipient(ctx context.Context, userID uuid.UUID, kind models.NotificationKind) (*models.EmailRecipient, bool) {
+	recipient, err := s.notificationRepo.GetEmailRecipient(ctx, userID, kind)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Warning: failed to look up user %v to notify: %v", userID, err)
+		}
+		return nil, false
+	}
+	return recipient, true
+}
+
+// emailRecipients returns those of the users who want emails of kind.
+func (s *notificationService) emailRecipients(ctx context.Context, userIDs []uuid.UUID, kind models.NotificationKind) []*models.EmailRecipient {
+	var recipients []*models.EmailRecipient
+	for _, id := range userIDs {
+		if recipient, ok := s.userRecipient(ctx, id, kind); ok {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients
+}
+
+// organizationMemberIDs returns the organization's members whose role
+// grants p.
+func (s *notificationService) organizationMemberIDs(ctx context.Context, organizationID uuid.UUID, p policy.Permission) []uuid.UUID {
+	members, err := s.memberRepo.ListByOrganization(ctx, organizationID)
+	if err != nil {
+		log.Printf("Warning: failed to load members of organization %v to notify: %v", organizationID, err)
+		return nil
+	}
+
+	var ids []uuid.UUID
+	for _, m := range members {
+		if policy.RolePermits(m.Role, p) {
+			ids = append(ids, m.UserID)
+		}
+	}
+	return ids
+}
+
+// queue renders an email of kind for each recipient, with the data dataFor
+// returns, and puts them in the outbox.
+func (s *notificationService) queue(ctx context.Context, kind models.NotificationKind, recipients []*models.EmailRecipient, dataFor func(*models.EmailRecipient) any) {
+	if len(recipients) == 0 {
+		return
+	}
+
+	now := time.Now()
+	emails := make([]*models.OutboxEmail, 0, len(recipients))
+	for _, r := range recipients {
+		subject, body, err := renderEmail(kind, &emailData{
+			RecipientName:  r.Name,
+			PreferencesURL: s.frontendURL + "/profile#notifications",
+			UnsubscribeURL: s.unsubscribeURL(r.UserID, now),
+			Data:           dataFor(r),
+		})
+		if err != nil {
+			log.Printf("Warning: failed to render %s email: %v", kind, err)
+			return
+		}
+
+		userID := r.UserID
+		emails = append(emails, &models.OutboxEmail{
+			ID:            uuid.New(),
+			UserID:        &userID,
+			Kind:          kind,
+			To:            r.Email,
+			Subject:       subject,
+			Body:          body,
+			Status:        models.EmailStatusPending,
+			NextAttemptAt: &now,
+			CreatedAt:     now,
+		})
+	}
+
+	if err := s.notificationRepo.EnqueueEmails(ctx, emails); err != nil {
+		log.Printf("Warning: failed to queue %d %s emails: %v", len(emails), kind, err)
+		return
+	}
+	s.nudge()
+}
+
+func (s *notificationService) unsubscribeURL(userID uuid.UUID, now time.Time) string {
+	token := s.signer.Sign(&models.AccountToken{
+		ID:        userID,
+		Purpose:   models.AccountTokenEmailUnsubscribe,
+		ExpiresAt: now.Add(models.AccountTokenEmailUnsubscribe.TTL()),
+	})
+	return s.frontendURL + "/unsubscribe?token=" + url.QueryEscape(token)
+}
+
+func (s *notificationService) GetPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferencesResponse, error) {
+	prefs, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return preferencesResponse(prefs), nil
+}
+
+func (s *notificationService) UpdatePreferences(ctx context.Context, userID uuid.UUID, req *models.UpdateNotificationPreferencesRequest) (*models.NotificationPreferencesResponse, error) {
+	for kind := range req.Email {
+		if !kind.IsValid() {
+			return nil, fmt.Errorf("unknown notification kind %q", kind)
+		}
+	}
+
+	prefs, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	disabled := make(map[models.NotificationKind]bool, len(prefs.EmailDisabled))
+	for _, kind := range prefs.EmailDisabled {
+		disabled[kind] = true
+	}
+	for kind, enabled := range req.Email {
+		disabled[kind] = !enabled
+	}
+	// Keep the stored list in display order so it reads the same every time.
+	prefs.EmailDisabled = prefs.EmailDisabled[:0]
+	for _, info := range models.NotificationKinds {
+		if disabled[info.Kind] {
+			prefs.EmailDisabled = append(prefs.EmailDisabled, info.Kind)
+		}
+	}
+
+	now := time.Now()
+	if req.Unsubscribed != nil {
+		if !*req.Unsubscribed {
+			prefs.UnsubscribedAt = nil
+		} else if prefs.UnsubscribedAt == nil {
+			prefs.UnsubscribedAt = &now
+		}
+	}
+	prefs.UpdatedAt = now
+
+	if err := s.notificationRepo.SavePreferences(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return preferencesResponse(prefs), nil
+}
+
+func preferencesResponse(prefs *models.NotificationPreferences) *models.NotificationPreferencesResponse {
+	email := make(map[models.NotificationKind]bool, len(models.NotificationKinds))
+	for _, info := range models.NotificationKinds {
+		email[info.Kind] = true
+	}
+	for _, kind := range prefs.EmailDisabled {
+		email[kind] = false
+	}
+	return &models.NotificationPreferencesResponse{
+		Email:        email,
+		Unsubscribed: prefs.UnsubscribedAt != nil,
+		Kinds:        models.NotificationKinds,
+	}
+}
+
+func (s *notificationService) Unsubscribe(ctx context.Context, token string) error {
+	userID, err := s.signer.Verify(token, models.AccountTokenEmailUnsubscribe, time.Now())
+	if err != nil {
+		return ErrInvalidUnsubscribeToken
+	}
+	return s.notificationRepo.Unsubscribe(ctx, userID, time.Now())
+}
+
+// nudge wakes the dispatcher without waiting for its next tick.
+func (s *notificationService) nudge() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *notificationService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.sendDue(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-s.wake:
+		case <-ctx.Done():
+			log.Println("Stopping email dispatcher")
+			return
+		}
+	}
+}
+
+// sendDue sends due emails one at a time, since most relays throttle
+// parallel connections anyway.
+func (s *notificationService) sendDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		emails, err := s.notificationRepo.ClaimDueEmails(ctx, time.Now(), emailLease, emailBatchSize)
+		if err != nil {
+			log.Printf("Warning: failed to claim outbox emails: %v", err)
+			return
+		}
+
+		for _, e := range emails {
+			sendErr := s.mailer.Send(ctx, e.To, e.Subject, e.Body)
+			recordEmailAttempt(e, time.Now(), sendErr)
+			if e.Status == models.EmailStatusDead {
+				log.Printf("Warning: giving up on %s email %v after %d attempts: %v", e.Kind, e.ID, e.Attempts, sendErr)
+			}
+			if err := s.notificationRepo.RecordEmailAttempt(ctx, e); err != nil {
+				log.Printf("Warning: failed to record attempt for email %v: %v", e.ID, err)
+			}
+		}
+
+		if len(emails) < emailBatchSize {
+			return
+		}
+	}
+}
+
+// recordEmailAttempt updates e after a send, scheduling the next try if it
+// failed and isn't out of attempts.
+func recordEmailAttempt(e *models.OutboxEmail, now time.Time, sendErr error) {
+	e.Attempts++
+
+	if sendErr == nil {
+		e.Status = models.EmailStatusSent
+		e.SentAt = &now
+		e.NextAttemptAt = nil
+		e.LastError = nil
+		return
+	}
+
+	msg := sendErr.Error()
+	e.LastError = &msg
+	if e.Attempts >= models.MaxEmailAttempts {
+		e.Status = models.EmailStatusDead
+		e.NextAttemptAt = nil
+		return
+	}
+	next := now.Add(models.EmailRetrySchedule[e.Attempts-1])
+	e.Status = models.EmailStatusPending
+	e.NextAttemptAt = &next
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestRenderEmail(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	data := map[models.NotificationKind]any{
+		models.NotificationDonationReceipt: &donationReceiptEmail{
+			CauseTitle: "Clean water for Rampur", Amount: 500, CreatedAt: now,
+			TxHash: "0xabc", ReceiptURL: "https://app.test/donations/1/receipt", CauseURL: "https://app.test/campaign/1",
+		},
+		models.NotificationDonationReceived: &donationReceivedEmail{
+			CauseTitle: "Clean water for Rampur", Amount: 500, DonorName: models.AnonymousDonorName,
+		},
+		models.NotificationMilestoneReached: &milestoneReachedEmail{
+			CauseTitle: "Clean water for Rampur", MilestoneNumber: 2, Amount: 25000, ForOrganization: true,
+		},
+		models.NotificationCauseUpdate: &causeUpdateEmail{
+			CauseTitle: "Clean water for Rampur", UpdateTitle: "Pump installed", Verified: true,
+		},
+		models.NotificationReceiptVerification: &receiptVerificationEmail{
+			JobID: uuid.New(), Status: "rejected", Outcome: "rejected", ClaimedAmount: 1200,
+		},
+		models.NotificationDisputeUpdate: &disputeUpdateEmail{
+			Title: "Funds not used", CauseTitle: "Clean water for Rampur", StatusLabel: "resolved",
+			ResolutionNotes: "The organization provided receipts.",
+		},
+	}
+
+	for _, info := range models.NotificationKinds {
+		subject, body, err := renderEmail(info.Kind, &emailData{
+			RecipientName:  "Asha",
+			PreferencesURL: "https://app.test/profile#notifications",
+			UnsubscribeURL: "https://app.test/unsubscribe?token=t",
+			Data:           data[info.Kind],
+		})
+		if err != nil {
+			t.Errorf("%s: %v", info.Kind, err)
+			continue
+		}
+		if subject == "" || strings.ContainsAny(subject, "\r\n") {
+			t.Errorf("%s: subject %q isn't a single line", info.Kind, subject)
+		}
+		if !strings.HasPrefix(body, "Hello Asha,") || !strings.Contains(body, "https://app.test/unsubscribe?token=t") {
+			t.Errorf("%s: body is missing the greeting or unsubscribe link:\n%s", info.Kind, body)
+		}
+		if strings.Contains(body, "<no value>") {
+			t.Errorf("%s: body refers to a missing field:\n%s", info.Kind, body)
+		}
+	}
+
+	_, body, _ := renderEmail(models.NotificationDonationReceipt, &emailData{Data: data[models.NotificationDonationReceipt]})
+	if !strings.Contains(body, "https://app.test/donations/1/receipt") || !strings.Contains(body, "₹500.00") {
+		t.Errorf("donation receipt is missing the receipt link or amount:\n%s", body)
+	}
+}
+
+func TestUnsubscribeToken(t *testing.T) {
+	s := &notificationService{signer: newAccountTokenSigner([]byte("secret")), frontendURL: "https://app.test"}
+	userID := uuid.New()
+	now := time.Now()
+
+	link := s.unsubscribeURL(userID, now)
+	token, ok := strings.CutPrefix(link, "https://app.test/unsubscribe?token=")
+	if !ok {
+		t.Fatalf("unsubscribeURL() = %q", link)
+	}
+
+	got, err := s.signer.Verify(token, models.AccountTokenEmailUnsubscribe, now)
+	if err != nil || got != userID {
+		t.Fatalf("Verify() = %v, %v; want %v", got, err, userID)
+	}
+	// The same signature can't be used as a password reset link.
+	if _, err := s.signer.Verify(token, models.AccountTokenPasswordReset, now); err == nil {
+		t.Error("unsubscribe token verified as a password reset token")
+	}
+}
+
+func TestRecordEmailAttempt(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := &models.OutboxEmail{Status: models.EmailStatusPending}
+
+	recordEmailAttempt(e, now, errors.New("connection refused"))
+	if e.Status != models.EmailStatusPending || e.NextAttemptAt == nil || !e.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("after first failure: status %s, next attempt %v", e.Status, e.NextAttemptAt)
+	}
+
+	for e.Attempts < models.MaxEmailAttempts {
+		recordEmailAttempt(e, now, errors.New("connection refused"))
+	}
+	if e.Status != models.EmailStatusDead || e.NextAttemptAt != nil {
+		t.Fatalf("after %d failures: status %s, next attempt %v", e.Attempts, e.Status, e.NextAttemptAt)
+	}
+
+	e = &models.OutboxEmail{Status: models.EmailStatusPending}
+	recordEmailAttempt(e, now, nil)
+	if e.Status != models.EmailStatusSent || e.SentAt == nil || e.LastError != nil {
+		t.Errorf("after success: %+v", e)
+	}
+}
+
+func TestCauseUpdateTitle(t *testing.T) {
+	tests := []struct {
+		update *models.CauseUpdate
+		want   string
+	}{
+		{&models.CauseUpdate{UpdateType: "Execution", IsVerified: true}, "Rampur posted a verified execution update"},
+		{&models.CauseUpdate{UpdateType: "Execution"}, "Rampur posted an update"},
+		{&models.CauseUpdate{UpdateType: "General", IsVerified: true}, "Rampur posted an update"},
+	}
+	for _, tt := range tests {
+		if got := causeUpdateTitle("Rampur", tt.update); got != tt.want {
+			t.Errorf("causeUpdateTitle(%+v) = %q; want %q", tt.update, got, tt.want)
+		}
+	}
+}
+
+func TestNotificationHub(t *testing.T) {
+	hub := newNotificationHub()
+	userID := uuid.New()
+
+	first, closeFirst := hub.subscribe(userID)
+	second, closeSecond := hub.subscribe(userID)
+	other, closeOther := hub.subscribe(uuid.New())
+	defer closeOther()
+
+	hub.publish(userID, &models.NotificationEvent{Type: models.NotificationEventUnread, Unread: 1})
+	for _, ch := range []<-chan *models.NotificationEvent{first, second} {
+		if event := <-ch; event.Unread != 1 {
+			t.Errorf("got %+v", event)
+		}
+	}
+	select {
+	case event := <-other:
+		t.Errorf("another user's stream got %+v", event)
+	default:
+	}
+
+	// A stream that isn't being read drops events rather than blocking.
+	for i := 0; i < notificationStreamBuffer+1; i++ {
+		hub.publish(userID, &models.NotificationEvent{Type: models.NotificationEventUnread})
+	}
+
+	closeFirst()
+	closeFirst()
+	if !hub.listening(userID) {
+		t.Error("user stopped listening with a stream still open")
+	}
+	closeSecond()
+	if hub.listening(userID) {
+		t.Error("user still listening after closing every stream")
+	}
+}
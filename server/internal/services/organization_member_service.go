@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"server/internal/models"
+	"server/internal/policy"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// An organization may send at most this many invitations per hour.
+const maxInvitationsPerHour = 20
+
+var (
+	// ErrPermissionDenied is returned when a member's role does not allow
+	// the action.
+	ErrPermissionDenied = errors.New("your role in this organization does not allow this")
+	// ErrPrimaryMember is returned for attempts to remove or demote the
+	// organization's own account.
+	ErrPrimaryMember = errors.New("the organization's primary account can't be removed or demoted")
+	// ErrTooManyInvitations is returned when an organization has sent too
+	// many invitations recently.
+	ErrTooManyInvitations = errors.New("too many invitations sent recently, try again later")
+)
+
+// OrganizationMemberService manages an organization's team and its
+// invitations.
+type OrganizationMemberService interface {
+	ListTeam(ctx context.Context, actorID uuid.UUID) (*models.OrganizationTeamResponse, error)
+	// Invite emails an invitation to join the actor's organization.
+	Invite(ctx context.Context, actorID uuid.UUID, req *models.InviteMemberRequest) (*models.OrganizationInvitation, error)
+	RevokeInvitation(ctx context.Context, actorID, invitationID uuid.UUID) error
+	// AcceptInvitation adds the user to the inviting organization and signs
+	// them out everywhere. The user's email must be the one the invitation
+	// was sent to.
+	AcceptInvitation(ctx context.Context, userID uuid.UUID, token string) (*models.OrganizationMember, error)
+	UpdateRole(ctx context.Context, actorID, memberID uuid.UUID, role models.OrganizationMemberRole) error
+	// Remove takes a member off the team. Members may always remove
+	// themselves.
+	Remove(ctx context.Context, actorID, memberID uuid.UUID) error
+}
+
+type organizationMemberService struct {
+	memberRepo       repository.OrganizationMemberRepository
+	organizationRepo repository.OrganizationRepository
+	userRepo         repository.UserRepository
+	mailer           Mailer
+	frontendURL      string
+}
+
+func NewOrganizationMemberService(
+	memberRepo repository.OrganizationMemberRepository,
+	organizationRepo repository.OrganizationRepository,
+	userRepo repository.UserRepository,
+	mailer Mailer,
+) *organizationMemberService {
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:5173"
+	}
+
+	return &organizationMemberService{
+		memberRepo:       memberRepo,
+		organizationRepo: organizationRepo,
+		userRepo:         userRepo,
+		mailer:           mailer,
+		frontendURL:      strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// authorize returns the actor's membership, with its organization, if
+// their role grants perm.
+func (s *organizationMemberService) authorize(ctx context.Context, actorID uuid.UUID, perm policy.Permission) (*models.OrganizationMember, error) {
+	member, err := s.memberRepo.GetByUserID(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.RolePermits(member.Role, perm) {
+		return nil, ErrPermissionDenied
+	}
+
+	member.Organization, err = s.organizationRepo.GetByOrganizationID(ctx, member.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *organizationMemberService) ListTeam(ctx context.Context, actorID uuid.UUID) (*models.OrganizationTeamResponse, error) {
+	actor, err := s.memberRepo.GetByUserID(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.memberRepo.ListByOrganization(ctx, actor.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	team := &models.OrganizationTeamResponse{
+		Members:     members,
+		Invitations: []*models.OrganizationInvitation{},
+	}
+
+	// Only those who can invite see who has been invited.
+	if policy.RolePermits(actor.Role, policy.MembersManage) {
+		if team.Invitations, err = s.memberRepo.ListPendingInvitations(ctx, actor.OrganizationID); err != nil {
+			return nil, err
+		}
+	}
+	return team, nil
+}
+
+func (s *organizationMemberService) Invite(ctx context.Context, actorID uuid.UUID, req *models.InviteMemberRequest) (*models.OrganizationInvitation, error) {
+	actor, err := s.authorize(ctx, actorID, policy.MembersManage)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := mail.ParseAddress(strings.TrimSpace(req.Email))
+	if err != nil {
+		return nil, errors.New("enter a valid email address")
+	}
+	if !req.Role.IsValid() {
+		return nil, fmt.Errorf("unknown role %q", req.Role)
+	}
+	if req.Role == models.OrganizationRoleOwner && actor.Role != models.OrganizationRoleOwner {
+		return nil, ErrPermissionDenied
+	}
+
+	now := time.Now()
+	sent, err := s.memberRepo.CountInvitationsSince(ctx, actor.OrganizationID, now.Add(-time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if sent >= maxInvitationsPerHour {
+		return nil, ErrTooManyInvitations
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invitation: %w", err)
+	}
+	invitation := &models.OrganizationInvitation{
+		ID:             uuid.New(),
+		OrganizationID: actor.OrganizationID,
+		Email:          address.Address,
+		Role:           req.Role,
+		TokenHash:      hashToken(token),
+		InvitedBy:      &actorID,
+		ExpiresAt:      now.Add(models.OrganizationInvitationTTL),
+		CreatedAt:      now,
+	}
+	if err := s.memberRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	link := s.frontendURL + "/invitations/accept?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf(
+		"Hello,\n\n%s has invited you to join their team on CharityLight as %s.\n\nTo accept, sign in or create an account with this email address and open this link:\n\n%s\n\nThe invitation expires in 7 days. If you weren't expecting it, you can ignore this email.\n",
+		actor.Organization.OrganizationName, strings.ReplaceAll(string(req.Role), "_", " "), link,
+	)
+	subject := "Join " + actor.Organization.OrganizationName + " on CharityLight"
+	if err := s.mailer.Send(ctx, invitation.Email, subject, body); err != nil {
+		return nil, fmt.Errorf("failed to send invitation: %w", err)
+	}
+	return invitation, nil
+}
+
+func (s *organizationMemberService) RevokeInvitation(ctx context.Context, actorID, invitationID uuid.UUID) error {
+	actor, err := s.authorize(ctx, actorID, policy.MembersManage)
+	if err != nil {
+		return err
+	}
+	return s.memberRepo.RevokeInvitation(ctx, actor.OrganizationID, invitationID)
+}
+
+func (s *organizationMemberService) AcceptInvitation(ctx context.Context, userID uuid.UUID, token string) (*models.OrganizationMember, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Admins and organization accounts act in their own right.
+	if user.Role != string(models.RoleTypeUser) {
+		return nil, repository.ErrAlreadyMember
+	}
+	return s.memberRepo.AcceptInvitation(ctx, hashToken(token), userID, user.Email)
+}
+
+func (s *organizationMemberService) UpdateRole(ctx context.Context, actorID, memberID uuid.UUID, role models.OrganizationMemberRole) error {
+	if !role.IsValid() {
+		return fmt.Errorf("unknown role %q", role)
+	}
+	actor, target, err := s.manageable(ctx, actorID, memberID)
+	if err != nil {
+		return err
+	}
+	if role == models.OrganizationRoleOwner && actor.Role != models.OrganizationRoleOwner {
+		return ErrPermissionDenied
+	}
+	return s.memberRepo.UpdateRole(ctx, actor.OrganizationID, target.UserID, role)
+}
+
+func (s *organizationMemberService) Remove(ctx context.Context, actorID, memberID uuid.UUID) error {
+	if actorID == memberID {
+		self, err := s.memberRepo.GetByUserID(ctx, actorID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkNotPrimary(ctx, self); err != nil {
+			return err
+		}
+		return s.memberRepo.Remove(ctx, self.OrganizationID, actorID)
+	}
+
+	actor, target, err := s.manageable(ctx, actorID, memberID)
+	if err != nil {
+		return err
+	}
+	return s.memberRepo.Remove(ctx, actor.OrganizationID, target.UserID)
+}
+
+// manageable loads the actor and the member they want to change, checking
+// that both are on the same team, that the actor may manage members, that
+// only owners change owners, and that the target isn't the primary account.
+func (s *organizationMemberService) manageable(ctx context.Context, actorID, memberID uuid.UUID) (*models.OrganizationMember, *models.OrganizationMember, error) {
+	actor, err := s.authorize(ctx, actorID, policy.MembersManage)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := s.memberRepo.GetByUserID(ctx, memberID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if target.OrganizationID != actor.OrganizationID {
+		return nil, nil, repository.ErrNotOrganizationMember
+	}
+	if target.Role == models.OrganizationRoleOwner && actor.Role != models.OrganizationRoleOwner {
+		return nil, nil, ErrPermissionDenied
+	}
+	if target.UserID == actor.Organization.UserID {
+		return nil, nil, ErrPrimaryMember
+	}
+	return actor, target, nil
+}
+
+func (s *organizationMemberService) checkNotPrimary(ctx context.Context, member *models.OrganizationMember) error {
+	org, err := s.organizationRepo.GetByOrganizationID(ctx, member.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if org.UserID == member.UserID {
+		return ErrPrimaryMember
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const dataExportFormat = "charitylight-data-export/v1"
+
+// PrivacyService handles data principal requests under the DPDP Act.
+type PrivacyService interface {
+	// ExportData returns a zip archive with one JSON file per kind of record
+	// held about the user, plus manifest.json.
+	ExportData(ctx context.Context, userID uuid.UUID) ([]byte, error)
+	// EraseAccount erases a donor account. Donations are pseudonymized, not
+	// deleted, because they are anchored on the ledger.
+	EraseAccount(ctx context.Context, userID uuid.UUID) (*models.ErasureSummary, error)
+}
+
+type privacyService struct {
+	privacyRepo repository.PrivacyRepository
+	userRepo    repository.UserRepository
+}
+
+func NewPrivacyService(privacyRepo repository.PrivacyRepository, userRepo repository.UserRepository) *privacyService {
+	return &privacyService{
+		privacyRepo: privacyRepo,
+		userRepo:    userRepo,
+	}
+}
+
+func (s *privacyService) ExportData(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	sections, err := s.privacyRepo.ExportUserData(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := models.DataExportManifest{
+		UserID:      userID,
+		GeneratedAt: time.Now().UTC(),
+		Format:      dataExportFormat,
+		Files:       make(map[string]int, len(sections)),
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, section := range sections {
+		name := section.Name + ".json"
+		if err := writeZipJSON(archive, name, section.Rows); err != nil {
+			return nil, err
+		}
+		manifest.Files[name] = len(section.Rows)
+	}
+	if err := writeZipJSON(archive, "manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeZipJSON(archive *zip.Writer, name string, v interface{}) error {
+	f, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *privacyService) EraseAccount(ctx context.Context, userID uuid.UUID) (*models.ErasureSummary, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Organizations own causes and received funds; closing them is a manual
+	// process.
+	if user.Role != string(models.RoleTypeUser) {
+		return nil, errors.New("only donor accounts can be erased here, contact support to close this account")
+	}
+
+	return s.privacyRepo.EraseUser(ctx, userID, time.Now())
+}
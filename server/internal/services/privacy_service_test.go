@@ -0,0 +1,100 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type fakePrivacyRepo struct {
+	sections []*models.DataExportSection
+	erased   []uuid.UUID
+}
+
+func (r *fakePrivacyRepo) ExportUserData(ctx context.Context, userID uuid.UUID) ([]*models.DataExportSection, error) {
+	return r.sections, nil
+}
+
+func (r *fakePrivacyRepo) EraseUser(ctx context.Context, userID uuid.UUID, requestedAt time.Time) (*models.ErasureSummary, error) {
+	r.erased = append(r.erased, userID)
+	return &models.ErasureSummary{RequestID: uuid.New(), CompletedAt: time.Now()}, nil
+}
+
+var _ repository.PrivacyRepository = (*fakePrivacyRepo)(nil)
+
+func TestExportDataArchive(t *testing.T) {
+	userID := uuid.New()
+	privacy := &fakePrivacyRepo{sections: []*models.DataExportSection{
+		{Name: "profile", Rows: []json.RawMessage{json.RawMessage(`{"name":"Asha"}`)}},
+		{Name: "donations", Rows: []json.RawMessage{json.RawMessage(`{"amount":500}`), json.RawMessage(`{"amount":250}`)}},
+		{Name: "votes", Rows: []json.RawMessage{}},
+	}}
+	service := NewPrivacyService(privacy, newFakeUserRepo())
+
+	archive, err := service.ExportData(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("ExportData() error = %v", err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("export is not a zip archive: %v", err)
+	}
+
+	files := map[string][]byte{}
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+
+	var donations []map[string]interface{}
+	if err := json.Unmarshal(files["donations.json"], &donations); err != nil || len(donations) != 2 {
+		t.Errorf("donations.json = %s, want both rows", files["donations.json"])
+	}
+	if string(bytes.TrimSpace(files["votes.json"])) != "[]" {
+		t.Errorf("votes.json = %s, want an empty list", files["votes.json"])
+	}
+
+	var manifest models.DataExportManifest
+	if err := json.Unmarshal(files["manifest.json"], &manifest); err != nil {
+		t.Fatalf("manifest.json: %v", err)
+	}
+	want := map[string]int{"profile.json": 1, "donations.json": 2, "votes.json": 0}
+	if manifest.UserID != userID || manifest.Format != dataExportFormat || len(manifest.Files) != len(want) {
+		t.Errorf("manifest = %+v", manifest)
+	}
+	for name, count := range want {
+		if manifest.Files[name] != count {
+			t.Errorf("manifest lists %s with %d rows, want %d", name, manifest.Files[name], count)
+		}
+	}
+}
+
+func TestEraseAccountOnlyForDonors(t *testing.T) {
+	donor := &models.User{ID: uuid.New(), Role: string(models.RoleTypeUser)}
+	ngo := &models.User{ID: uuid.New(), Role: string(models.RoleTypeOrganization)}
+	privacy := &fakePrivacyRepo{}
+	service := NewPrivacyService(privacy, newFakeUserRepo(donor, ngo))
+
+	if _, err := service.EraseAccount(context.Background(), ngo.ID); err == nil {
+		t.Error("EraseAccount() for an organization succeeded, want error")
+	}
+	if _, err := service.EraseAccount(context.Background(), donor.ID); err != nil {
+		t.Fatalf("EraseAccount() for a donor error = %v", err)
+	}
+	if len(privacy.erased) != 1 || privacy.erased[0] != donor.ID {
+		t.Errorf("erased %v, want only the donor", privacy.erased)
+	}
+}
@@ -24,7 +24,7 @@ type ProofService interface {
 	CreateSession(ctx context.Context, causeID, organizationID uuid.UUID) (*models.ProofSession, error)
 	ProcessUpload(ctx context.Context, sessionID uuid.UUID, lat, lng float64, timestamp time.Time, imageBytes []byte) (*models.ProofImage, int, bool, bool, error)
 	GetSession(ctx context.Context, id uuid.UUID) (*models.ProofSession, error)
-	GetProofImagesBySessionID(ctx context.Context, sessionID uuid.UUID) ([]*models.ProofImage, error)
+	GetProofImagesBySessionID(ctx context.Context, sessionID uuid.UUID, page models.CursorParams) (*models.Page[*models.ProofImage], error)
 	UpdateProofAIResultsAndMedia(ctx context.Context, imageID uuid.UUID, mediaPath string, finalScore *float64, validationStatus *string) error
 }
 
@@ -47,6 +47,14 @@ func NewProofService(
 }
 
 func (s *proofService) CreateSession(ctx context.Context, causeID, organizationID uuid.UUID) (*models.ProofSession, error) {
+	cause, err := s.causeRepo.GetByID(ctx, causeID)
+	if err != nil {
+		return nil, fmt.Errorf("get cause: %w", err)
+	}
+	if !cause.State.AllowsProofUploads() {
+		return nil, fmt.Errorf("proof uploads are not allowed while the cause is %s", cause.State)
+	}
+
 	session := &models.ProofSession{
 		ID:             uuid.New(),
 		OrganizationID: organizationID,
@@ -146,8 +154,8 @@ func (s *proofService) GetSession(ctx context.Context, id uuid.UUID) (*models.Pr
 	return s.sessionRepo.GetByID(ctx, id)
 }
 
-func (s *proofService) GetProofImagesBySessionID(ctx context.Context, sessionID uuid.UUID) ([]*models.ProofImage, error) {
-	return s.imageRepo.GetBySessionID(ctx, sessionID)
+func (s *proofService) GetProofImagesBySessionID(ctx context.Context, sessionID uuid.UUID, page models.CursorParams) (*models.Page[*models.ProofImage], error) {
+	return s.imageRepo.GetBySessionID(ctx, sessionID, page)
 }
 
 func (s *proofService) UpdateProofAIResultsAndMedia(
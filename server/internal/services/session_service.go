@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenTTL is how long a session lasts without being refreshed.
+// Every refresh extends it.
+const RefreshTokenTTL = 30 * 24 * time.Hour
+
+type clientInfoKey struct{}
+
+// WithClientInfo records the caller's device on ctx so that sessions started
+// further down are labelled with it.
+func WithClientInfo(ctx context.Context, client models.ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, client)
+}
+
+func clientInfoFromContext(ctx context.Context) models.ClientInfo {
+	client, _ := ctx.Value(clientInfoKey{}).(models.ClientInfo)
+	return client
+}
+
+// SessionService issues access and refresh tokens and manages the sessions
+// behind them.
+type SessionService interface {
+	// Start opens a session for a user who has just signed in.
+	Start(ctx context.Context, user *models.User) (*models.AuthResponse, error)
+	// Refresh exchanges a refresh token for a new access and refresh token.
+	// The old refresh token stops working.
+	Refresh(ctx context.Context, refreshToken string) (*models.AuthResponse, error)
+
+	List(ctx context.Context, userID uuid.UUID, currentID uuid.UUID) ([]*models.Session, error)
+	Revoke(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, reason string) error
+	RevokeAll(ctx context.Context, userID uuid.UUID) (int64, error)
+	// RevokeByRefreshToken signs out the session a refresh token belongs to.
+	RevokeByRefreshToken(ctx context.Context, refreshToken string) error
+}
+
+type sessionService struct {
+	sessionRepo repository.SessionRepository
+	userRepo    repository.UserRepository
+	jwtService  JWTService
+}
+
+func NewSessionService(sessionRepo repository.SessionRepository, userRepo repository.UserRepository, jwtService JWTService) SessionService {
+	return &sessionService{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		jwtService:  jwtService,
+	}
+}
+
+func (s *sessionService) Start(ctx context.Context, user *models.User) (*models.AuthResponse, error) {
+	client := clientInfoFromContext(ctx)
+	now := time.Now()
+
+	session := &models.Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		UserAgent:  optionalString(client.UserAgent),
+		IPAddress:  optionalString(client.IPAddress),
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(RefreshTokenTTL),
+	}
+
+	refreshToken, record, err := newRefreshToken(session.ID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.Create(ctx, session, record); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return s.authResponse(user, session.ID, refreshToken)
+}
+
+func (s *sessionService) Refresh(ctx context.Context, refreshToken string) (*models.AuthResponse, error) {
+	if refreshToken == "" {
+		return nil, repository.ErrInvalidRefreshToken
+	}
+
+	now := time.Now()
+	next, record, err := newRefreshToken(uuid.Nil, now)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.Rotate(ctx, hashToken(refreshToken), record, clientInfoFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	// Deactivated and erased users can't be loaded, so their sessions end
+	// here even if they were never revoked.
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, repository.ErrInvalidRefreshToken
+	}
+
+	return s.authResponse(user, session.ID, next)
+}
+
+func (s *sessionService) List(ctx context.Context, userID uuid.UUID, currentID uuid.UUID) ([]*models.Session, error) {
+	sessions, err := s.sessionRepo.ListActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		session.Current = session.ID == currentID
+	}
+	return sessions, nil
+}
+
+func (s *sessionService) Revoke(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, reason string) error {
+	return s.sessionRepo.Revoke(ctx, userID, sessionID, reason)
+}
+
+func (s *sessionService) RevokeAll(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.sessionRepo.RevokeAll(ctx, userID, models.SessionRevokedEverywhere)
+}
+
+func (s *sessionService) RevokeByRefreshToken(ctx context.Context, refreshToken string) error {
+	session, err := s.sessionRepo.GetByRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	return s.sessionRepo.Revoke(ctx, session.UserID, session.ID, models.SessionRevokedLogout)
+}
+
+func (s *sessionService) authResponse(user *models.User, sessionID uuid.UUID, refreshToken string) (*models.AuthResponse, error) {
+	token, err := s.jwtService.GenerateToken(user.ID, user.Email, user.Role, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &models.AuthResponse{
+		User:         *user,
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// newRefreshToken returns a random token for the client and the record to
+// store, which holds only its hash.
+func newRefreshToken(sessionID uuid.UUID, now time.Time) (string, *models.RefreshToken, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	return token, &models.RefreshToken{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(RefreshTokenTTL),
+		CreatedAt: now,
+	}, nil
+}
+
+// randomToken returns 256 random bits, base64url encoded.
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken is how bearer secrets such as refresh tokens are stored.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
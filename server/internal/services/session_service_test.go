@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func newTestSessionService(users ...*models.User) (SessionService, *fakeSessionRepo) {
+	sessions := newFakeSessionRepo()
+	return NewSessionService(sessions, newFakeUserRepo(users...), fakeJWTService{}), sessions
+}
+
+func donorUser() *models.User {
+	return &models.User{ID: uuid.New(), Email: "asha@example.com", Role: string(models.RoleTypeUser)}
+}
+
+// sessionOf reads the session ID back out of a fakeJWTService token.
+func sessionOf(t *testing.T, resp *models.AuthResponse) uuid.UUID {
+	t.Helper()
+	id, err := uuid.Parse(resp.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	user := donorUser()
+	service, sessions := newTestSessionService(user)
+	ctx := context.Background()
+
+	start, err := service.Start(ctx, user)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if _, stored := sessions.tokens[start.RefreshToken]; stored {
+		t.Error("refresh token stored in plaintext, want only its hash")
+	}
+
+	next, err := service.Refresh(ctx, start.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if next.RefreshToken == start.RefreshToken || next.RefreshToken == "" {
+		t.Errorf("Refresh() returned refresh token %q, want a new one", next.RefreshToken)
+	}
+	if sessionOf(t, next) != sessionOf(t, start) {
+		t.Error("Refresh() moved to a different session")
+	}
+	if _, err := service.Refresh(ctx, next.RefreshToken); err != nil {
+		t.Errorf("Refresh() with the rotated token error = %v", err)
+	}
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	user := donorUser()
+	service, sessions := newTestSessionService(user)
+	ctx := context.Background()
+
+	start, _ := service.Start(ctx, user)
+	next, err := service.Refresh(ctx, start.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	if _, err := service.Refresh(ctx, start.RefreshToken); !errors.Is(err, repository.ErrRefreshTokenReused) {
+		t.Fatalf("Refresh() with a used token error = %v, want ErrRefreshTokenReused", err)
+	}
+	session := sessions.session(sessionOf(t, start))
+	if session.RevokedAt == nil || *session.RevokedReason != models.SessionRevokedTokenReuse {
+		t.Errorf("session revoked_at=%v reason=%v, want revoked for token reuse", session.RevokedAt, session.RevokedReason)
+	}
+
+	// The legitimate holder of the newer token is signed out too.
+	if _, err := service.Refresh(ctx, next.RefreshToken); !errors.Is(err, repository.ErrInvalidRefreshToken) {
+		t.Errorf("Refresh() after reuse error = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRefreshForRemovedUser(t *testing.T) {
+	user := donorUser()
+	service, _ := newTestSessionService()
+
+	start, _ := service.Start(context.Background(), user)
+	if _, err := service.Refresh(context.Background(), start.RefreshToken); !errors.Is(err, repository.ErrInvalidRefreshToken) {
+		t.Errorf("Refresh() for a user that can't be loaded error = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	user, other := donorUser(), donorUser()
+	service, sessions := newTestSessionService(user, other)
+	ctx := context.Background()
+
+	start, _ := service.Start(ctx, user)
+	id := sessionOf(t, start)
+
+	if err := service.Revoke(ctx, other.ID, id, models.SessionRevokedByUser); err == nil {
+		t.Error("Revoke() of another user's session succeeded, want error")
+	}
+	if sessions.session(id).RevokedAt != nil {
+		t.Fatal("another user revoked the session")
+	}
+
+	if err := service.Revoke(ctx, user.ID, id, models.SessionRevokedByUser); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, err := service.Refresh(ctx, start.RefreshToken); !errors.Is(err, repository.ErrInvalidRefreshToken) {
+		t.Errorf("Refresh() after revoke error = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRevokeByRefreshToken(t *testing.T) {
+	user := donorUser()
+	service, sessions := newTestSessionService(user)
+	ctx := context.Background()
+
+	start, _ := service.Start(ctx, user)
+	if err := service.RevokeByRefreshToken(ctx, start.RefreshToken); err != nil {
+		t.Fatalf("RevokeByRefreshToken() error = %v", err)
+	}
+	session := sessions.session(sessionOf(t, start))
+	if session.RevokedAt == nil || *session.RevokedReason != models.SessionRevokedLogout {
+		t.Errorf("session revoked_at=%v reason=%v, want revoked on logout", session.RevokedAt, session.RevokedReason)
+	}
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+	"server/internal/totp"
+
+	"github.com/google/uuid"
+)
+
+const totpIssuer = "CharityLight"
+
+var (
+	// ErrInvalidTwoFactorCode is returned for a wrong, reused or expired
+	// TOTP or recovery code.
+	ErrInvalidTwoFactorCode = errors.New("invalid authentication code")
+	// ErrTwoFactorRequired is returned when an account that must use 2FA
+	// tries to turn it off.
+	ErrTwoFactorRequired = errors.New("two-factor authentication is required for this account")
+	// ErrTwoFactorNotEnabled is returned when confirming, checking or
+	// turning off 2FA for a user who hasn't set it up.
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not set up")
+)
+
+// TwoFactorService manages TOTP enrollment and decides whether a sign-in
+// gets tokens straight away or a challenge for a second factor first.
+type TwoFactorService interface {
+	// SignIn is called once a user's password or OAuth login has been
+	// checked. Users with 2FA, and accounts that must use it but haven't set
+	// it up, get a challenge instead of tokens. Organizations, admins and
+	// organization owners, admins and finance staff must use 2FA.
+	SignIn(ctx context.Context, user *models.User) (*models.AuthResponse, error)
+	// BeginChallengeEnrollment starts enrollment for a challenged sign-in
+	// of an account that must have 2FA but has not set it up.
+	BeginChallengeEnrollment(ctx context.Context, challengeToken string) (*models.TwoFactorEnrollment, error)
+	// CompleteChallenge checks the code for a challenged sign-in and issues
+	// the tokens. For enrollment challenges it also enables 2FA and returns
+	// the recovery codes.
+	CompleteChallenge(ctx context.Context, challengeToken string, code string) (*models.AuthResponse, error)
+
+	Status(ctx context.Context, user *models.User) (*models.TwoFactorStatus, error)
+	BeginEnrollment(ctx context.Context, userID uuid.UUID) (*models.TwoFactorEnrollment, error)
+	// ConfirmEnrollment enables 2FA with the first code from the app and
+	// returns the recovery codes, which are not shown again.
+	ConfirmEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	Disable(ctx context.Context, user *models.User, code string) error
+}
+
+type twoFactorService struct {
+	twoFactorRepo  repository.TwoFactorRepository
+	userRepo       repository.UserRepository
+	memberRepo     repository.OrganizationMemberRepository
+	sessionService SessionService
+}
+
+func NewTwoFactorService(twoFactorRepo repository.TwoFactorRepository, userRepo repository.UserRepository, memberRepo repository.OrganizationMemberRepository, sessionService SessionService) *twoFactorService {
+	return &twoFactorService{
+		twoFactorRepo:  twoFactorRepo,
+		userRepo:       userRepo,
+		memberRepo:     memberRepo,
+		sessionService: sessionService,
+	}
+}
+
+func (s *twoFactorService) SignIn(ctx context.Context, user *models.User) (*models.AuthResponse, error) {
+	tf, err := s.getTwoFactor(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := s.required(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if !tf.Enabled() && !required {
+		return s.sessionService.Start(ctx, user)
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+	now := time.Now()
+	challenge := &models.LoginChallenge{
+		ID:                 uuid.New(),
+		UserID:             user.ID,
+		TokenHash:          hashToken(token),
+		EnrollmentRequired: !tf.Enabled(),
+		ExpiresAt:          now.Add(models.LoginChallengeTTL),
+		CreatedAt:          now,
+	}
+	if err := s.twoFactorRepo.CreateChallenge(ctx, challenge); err != nil {
+		return nil, fmt.Errorf("failed to create challenge: %w", err)
+	}
+
+	return &models.AuthResponse{
+		User: *user,
+		Challenge: &models.LoginChallengeResponse{
+			Token:              token,
+			EnrollmentRequired: challenge.EnrollmentRequired,
+			ExpiresAt:          challenge.ExpiresAt,
+		},
+	}, nil
+}
+
+func (s *twoFactorService) BeginChallengeEnrollment(ctx context.Context, challengeToken string) (*models.TwoFactorEnrollment, error) {
+	challenge, err := s.twoFactorRepo.GetOpenChallenge(ctx, hashToken(challengeToken))
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.EnrollmentRequired {
+		return nil, repository.ErrTwoFactorAlreadyEnabled
+	}
+	return s.BeginEnrollment(ctx, challenge.UserID)
+}
+
+func (s *twoFactorService) CompleteChallenge(ctx context.Context, challengeToken string, code string) (*models.AuthResponse, error) {
+	challenge, err := s.twoFactorRepo.GetOpenChallenge(ctx, hashToken(challengeToken))
+	if err != nil {
+		return nil, err
+	}
+	// Counted before checking, so guesses are limited even if they race.
+	if err := s.twoFactorRepo.RecordChallengeAttempt(ctx, challenge.ID); err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if challenge.EnrollmentRequired {
+		if recoveryCodes, err = s.ConfirmEnrollment(ctx, challenge.UserID, code); err != nil {
+			return nil, err
+		}
+	} else if err := s.verify(ctx, challenge.UserID, code); err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.CompleteChallenge(ctx, challenge.ID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, repository.ErrInvalidLoginChallenge
+	}
+	resp, err := s.sessionService.Start(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	resp.RecoveryCodes = recoveryCodes
+	return resp, nil
+}
+
+func (s *twoFactorService) Status(ctx context.Context, user *models.User) (*models.TwoFactorStatus, error) {
+	tf, err := s.getTwoFactor(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := s.required(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	status := &models.TwoFactorStatus{
+		Enabled:  tf.Enabled(),
+		Required: required,
+	}
+	if status.Enabled {
+		if status.RecoveryCodesRemaining, err = s.twoFactorRepo.CountRecoveryCodes(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+func (s *twoFactorService) BeginEnrollment(ctx context.Context, userID uuid.UUID) (*models.TwoFactorEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+	if err := s.twoFactorRepo.SavePending(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    totp.URI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+func (s *twoFactorService) ConfirmEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	tf, err := s.twoFactorRepo.Get(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tf.Enabled() {
+		return nil, repository.ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := totp.Validate(tf.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.Enable(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	if err := s.verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *twoFactorService) Disable(ctx context.Context, user *models.User, code string) error {
+	required, err := s.required(ctx, user)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrTwoFactorRequired
+	}
+	if err := s.verify(ctx, user.ID, code); err != nil {
+		return err
+	}
+	return s.twoFactorRepo.Disable(ctx, user.ID)
+}
+
+// verify accepts a current TOTP code, once, or an unused recovery code.
+func (s *twoFactorService) verify(ctx context.Context, userID uuid.UUID, code string) error {
+	tf, err := s.getTwoFactor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !tf.Enabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if step, ok := totp.Validate(tf.Secret, code, time.Now()); ok {
+		fresh, err := s.twoFactorRepo.UseStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := s.twoFactorRepo.UseRecoveryCode(ctx, userID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// required reports whether user must use 2FA, because of their account
+// role or their role in an organization.
+func (s *twoFactorService) required(ctx context.Context, user *models.User) (bool, error) {
+	if models.RoleType(user.Role).RequiresTwoFactor() {
+		return true, nil
+	}
+	member, err := s.memberRepo.GetByUserID(ctx, user.ID)
+	if errors.Is(err, repository.ErrNotOrganizationMember) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return member.Role.RequiresTwoFactor(), nil
+}
+
+// getTwoFactor returns nil, without an error, for users who never enrolled.
+func (s *twoFactorService) getTwoFactor(ctx context.Context, userID uuid.UUID) (*models.TwoFactor, error) {
+	tf, err := s.twoFactorRepo.Get(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return tf, err
+}
+
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// generateRecoveryCodes returns codes to show the user, formatted like
+// "abcde-fghij", and the hashes to store.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, models.RecoveryCodeCount)
+	hashes := make([]string, models.RecoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+		}
+		raw := recoveryCodeEncoding.EncodeToString(buf)[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashToken(raw)
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"server/internal/models"
+)
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("generateRecoveryCodes() error = %v", err)
+	}
+	if len(codes) != models.RecoveryCodeCount || len(hashes) != models.RecoveryCodeCount {
+		t.Fatalf("got %d codes and %d hashes, want %d", len(codes), len(hashes), models.RecoveryCodeCount)
+	}
+
+	seen := make(map[string]bool)
+	for i, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("code %q is not formatted xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("code %q issued twice", code)
+		}
+		seen[code] = true
+
+		// However the user types it back, it must match the stored hash.
+		for _, typed := range []string{code, strings.ToUpper(code), strings.ReplaceAll(code, "-", ""), " " + code + " "} {
+			if got := hashToken(normalizeRecoveryCode(typed)); got != hashes[i] {
+				t.Errorf("%q does not match the hash of %q", typed, code)
+			}
+		}
+	}
+}
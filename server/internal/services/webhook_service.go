@@ -0,0 +1,473 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultWebhookDispatchInterval = 15 * time.Second
+	webhookTimeout                 = 10 * time.Second
+	// webhookLease is how long a claimed delivery is held before another
+	// dispatcher may take it, comfortably longer than one attempt.
+	webhookLease        = time.Minute
+	webhookBatchSize    = 20
+	webhookSecretPrefix = "whsec_"
+)
+
+var (
+	// ErrTooManyWebhookEndpoints is returned when an organization already
+	// has models.MaxWebhookEndpointsPerOrganization endpoints.
+	ErrTooManyWebhookEndpoints = fmt.Errorf("an organization can have at most %d webhook endpoints", models.MaxWebhookEndpointsPerOrganization)
+	errWebhookAddressBlocked   = errors.New("webhook URL resolves to a private address")
+)
+
+// WebhookPublisher is what raises webhook events. Publishing never fails
+// the action the event is about; problems are logged.
+type WebhookPublisher interface {
+	Publish(ctx context.Context, organizationID uuid.UUID, eventType models.WebhookEventType, data any)
+}
+
+// WebhookService manages organizations' webhook endpoints and delivers the
+// events they subscribe to.
+//
+// Publish writes one delivery per subscribed endpoint to the database, and
+// Start sends them, retrying failures on models.WebhookRetrySchedule until
+// they succeed or are dead.
+type WebhookService interface {
+	WebhookPublisher
+
+	List(ctx context.Context, organizationID uuid.UUID) ([]*models.WebhookEndpoint, error)
+	// Create registers an endpoint. Its signing secret is in the response
+	// and can't be shown again.
+	Create(ctx context.Context, organizationID, createdBy uuid.UUID, req *models.CreateWebhookEndpointRequest) (*models.CreateWebhookEndpointResponse, error)
+	// Update, Delete, ListDeliveries and Replay return sql.ErrNoRows if the
+	// organization has no such endpoint or delivery.
+	Update(ctx context.Context, organizationID, endpointID uuid.UUID, req *models.UpdateWebhookEndpointRequest) (*models.WebhookEndpoint, error)
+	Delete(ctx context.Context, organizationID, endpointID uuid.UUID) error
+	ListDeliveries(ctx context.Context, organizationID, endpointID uuid.UUID, page models.CursorParams) (*models.Page[*models.WebhookDelivery], error)
+	// Replay sends a delivery again, whatever became of it before.
+	Replay(ctx context.Context, organizationID, endpointID, deliveryID uuid.UUID) (*models.WebhookDelivery, error)
+
+	// Start sends due deliveries until ctx is done.
+	Start(ctx context.Context)
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+	client      *http.Client
+	interval    time.Duration
+	// allowPrivate lets endpoints use http and private addresses, for
+	// local development.
+	allowPrivate bool
+	wake         chan struct{}
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository) *webhookService {
+	interval := defaultWebhookDispatchInterval
+	if raw := os.Getenv("WEBHOOK_DISPATCH_INTERVAL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			interval = d
+		}
+	}
+	allowPrivate, _ := strconv.ParseBool(os.Getenv("WEBHOOK_ALLOW_PRIVATE_URLS"))
+
+	return &webhookService{
+		webhookRepo:  webhookRepo,
+		client:       newWebhookClient(allowPrivate),
+		interval:     interval,
+		allowPrivate: allowPrivate,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// newWebhookClient returns a client that doesn't follow redirects and,
+// unless allowPrivate, refuses to connect to private, loopback and
+// link-local addresses, whatever the endpoint's hostname resolves to.
+func newWebhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return errWebhookAddressBlocked
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+func (s *webhookService) validateURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || len(raw) > 2048 {
+		return "", errors.New("url must be an absolute URL")
+	}
+	if u.Scheme != "https" && !(s.allowPrivate && u.Scheme == "http") {
+		return "", errors.New("url must use https")
+	}
+	if u.User != nil {
+		return "", errors.New("url must not contain credentials")
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !s.allowPrivate && !isPublicIP(ip) {
+		return "", errWebhookAddressBlocked
+	}
+	return u.String(), nil
+}
+
+func validateEventTypes(types []models.WebhookEventType) ([]models.WebhookEventType, error) {
+	if len(types) == 0 {
+		return nil, errors.New("subscribe to at least one event")
+	}
+	valid := []models.WebhookEventType{}
+	for _, t := range types {
+		if !t.IsValid() {
+			return nil, fmt.Errorf("unknown event type %q", t)
+		}
+		seen := false
+		for _, v := range valid {
+			seen = seen || v == t
+		}
+		if !seen {
+			valid = append(valid, t)
+		}
+	}
+	return valid, nil
+}
+
+func validateWebhookDescription(description string) (string, error) {
+	description = strings.TrimSpace(description)
+	if len(description) > 255 {
+		return "", errors.New("description must be at most 255 characters")
+	}
+	return description, nil
+}
+
+func (s *webhookService) List(ctx context.Context, organizationID uuid.UUID) ([]*models.WebhookEndpoint, error) {
+	return s.webhookRepo.ListEndpoints(ctx, organizationID)
+}
+
+func (s *webhookService) Create(ctx context.Context, organizationID, createdBy uuid.UUID, req *models.CreateWebhookEndpointRequest) (*models.CreateWebhookEndpointResponse, error) {
+	endpointURL, err := s.validateURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	eventTypes, err := validateEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	description, err := validateWebhookDescription(req.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.webhookRepo.CountEndpoints(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= models.MaxWebhookEndpointsPerOrganization {
+		return nil, ErrTooManyWebhookEndpoints
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	now := time.Now()
+	endpoint := &models.WebhookEndpoint{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		URL:            endpointURL,
+		Description:    description,
+		EventTypes:     eventTypes,
+		Secret:         webhookSecretPrefix + secret,
+		IsActive:       true,
+		CreatedBy:      &createdBy,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.webhookRepo.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+	return &models.CreateWebhookEndpointResponse{Endpoint: endpoint, Secret: endpoint.Secret}, nil
+}
+
+func (s *webhookService) Update(ctx context.Context, organizationID, endpointID uuid.UUID, req *models.UpdateWebhookEndpointRequest) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.webhookRepo.GetEndpoint(ctx, organizationID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if req.URL != nil {
+		if endpoint.URL, err = s.validateURL(*req.URL); err != nil {
+			return nil, err
+		}
+	}
+	if req.Description != nil {
+		if endpoint.Description, err = validateWebhookDescription(*req.Description); err != nil {
+			return nil, err
+		}
+	}
+	if req.EventTypes != nil {
+		if endpoint.EventTypes, err = validateEventTypes(req.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	if req.IsActive != nil {
+		endpoint.IsActive = *req.IsActive
+	}
+	endpoint.UpdatedAt = time.Now()
+
+	if err := s.webhookRepo.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	if endpoint.IsActive {
+		// Deliveries held while the endpoint was off are due now.
+		s.nudge()
+	}
+	return endpoint, nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, organizationID, endpointID uuid.UUID) error {
+	return s.webhookRepo.DeleteEndpoint(ctx, organizationID, endpointID)
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, organizationID, endpointID uuid.UUID, page models.CursorParams) (*models.Page[*models.WebhookDelivery], error) {
+	if _, err := s.webhookRepo.GetEndpoint(ctx, organizationID, endpointID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.ListDeliveries(ctx, endpointID, page)
+}
+
+func (s *webhookService) Replay(ctx context.Context, organizationID, endpointID, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	if _, err := s.webhookRepo.GetEndpoint(ctx, organizationID, endpointID); err != nil {
+		return nil, err
+	}
+	delivery, err := s.webhookRepo.ReplayDelivery(ctx, endpointID, deliveryID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.nudge()
+	return delivery, nil
+}
+
+func (s *webhookService) Publish(ctx context.Context, organizationID uuid.UUID, eventType models.WebhookEventType, data any) {
+	endpoints, err := s.webhookRepo.ListSubscribedEndpoints(ctx, organizationID, eventType)
+	if err != nil {
+		log.Printf("Warning: failed to load webhook endpoints for organization %v: %v", organizationID, err)
+		return
+	}
+	if len(endpoints) == 0 {
+		return
+	}
+
+	now := time.Now()
+	event := &models.WebhookEvent{
+		ID:             uuid.New(),
+		Type:           eventType,
+		OrganizationID: organizationID,
+		CreatedAt:      now,
+		Data:           data,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Warning: failed to encode %s webhook event: %v", eventType, err)
+		return
+	}
+
+	deliveries := make([]*models.WebhookDelivery, len(endpoints))
+	for i, endpoint := range endpoints {
+		deliveries[i] = &models.WebhookDelivery{
+			ID:            uuid.New(),
+			EndpointID:    endpoint.ID,
+			EventID:       event.ID,
+			EventType:     eventType,
+			Payload:       payload,
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+			CreatedAt:     now,
+		}
+	}
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		log.Printf("Warning: failed to queue %s webhook event %v: %v", eventType, event.ID, err)
+		return
+	}
+	s.nudge()
+}
+
+// nudge wakes the dispatcher without waiting for its next tick.
+func (s *webhookService) nudge() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *webhookService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.dispatchDue(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-s.wake:
+		case <-ctx.Done():
+			log.Println("Stopping webhook dispatcher")
+			return
+		}
+	}
+}
+
+// dispatchDue sends due deliveries a batch at a time until none are left.
+func (s *webhookService) dispatchDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, time.Now(), webhookLease, webhookBatchSize)
+		if err != nil {
+			log.Printf("Warning: failed to claim webhook deliveries: %v", err)
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, d := range deliveries {
+			wg.Add(1)
+			go func(d *models.WebhookDelivery) {
+				defer wg.Done()
+				s.deliver(ctx, d)
+			}(d)
+		}
+		wg.Wait()
+
+		if len(deliveries) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+func (s *webhookService) deliver(ctx context.Context, d *models.WebhookDelivery) {
+	endpoint, err := s.webhookRepo.GetEndpointByID(ctx, d.EndpointID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Deleted since; its deliveries went with it.
+		return
+	}
+	if err != nil {
+		// The lease runs out and the delivery is tried again.
+		log.Printf("Warning: failed to load webhook endpoint %v: %v", d.EndpointID, err)
+		return
+	}
+
+	now := time.Now()
+	statusCode, err := s.send(ctx, endpoint, d, now)
+	recordWebhookAttempt(d, now, statusCode, err)
+	if err := s.webhookRepo.RecordAttempt(ctx, d); err != nil {
+		log.Printf("Warning: failed to record webhook delivery %v: %v", d.ID, err)
+	}
+}
+
+// send POSTs the delivery's payload to the endpoint and returns the
+// response status, if there was one. Anything but a 2xx is an error.
+func (s *webhookService) send(ctx context.Context, endpoint *models.WebhookEndpoint, d *models.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "CharityLight-Webhooks/1.0")
+	req.Header.Set("X-Webhook-Id", d.EventID.String())
+	req.Header.Set("X-Webhook-Event", string(d.EventType))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", signWebhook(endpoint.Secret, timestamp, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// signWebhook returns the X-Webhook-Signature header for a payload:
+// "t=<timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<payload>">". Receivers
+// recompute it with the endpoint's secret and reject stale timestamps.
+func signWebhook(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// recordWebhookAttempt updates d with the outcome of an attempt made at
+// now, scheduling the next one or marking it dead after the last.
+func recordWebhookAttempt(d *models.WebhookDelivery, now time.Time, statusCode int, sendErr error) {
+	d.Attempts++
+	d.LastAttemptAt = &now
+	d.LastStatusCode = nil
+	if statusCode != 0 {
+		d.LastStatusCode = &statusCode
+	}
+
+	if sendErr == nil {
+		d.Status = models.WebhookDeliverySucceeded
+		d.DeliveredAt = &now
+		d.NextAttemptAt = nil
+		d.LastError = nil
+		return
+	}
+
+	msg := sendErr.Error()
+	d.LastError = &msg
+	if d.Attempts >= models.MaxWebhookAttempts {
+		d.Status = models.WebhookDeliveryDead
+		d.NextAttemptAt = nil
+		return
+	}
+	next := now.Add(models.WebhookRetrySchedule[d.Attempts-1])
+	d.Status = models.WebhookDeliveryPending
+	d.NextAttemptAt = &next
+}
@@ -0,0 +1,66 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The SHA1 vectors from RFC 6238 appendix B use 8 digits; the last six
+// digits are what a 6-digit authenticator shows.
+func TestCodeAtRFC6238Vectors(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, v := range vectors {
+		got, err := CodeAt(secret, Step(time.Unix(v.unix, 0)))
+		if err != nil {
+			t.Fatalf("CodeAt(%d) error = %v", v.unix, err)
+		}
+		if got != v.code {
+			t.Errorf("CodeAt(%d) = %s, want %s", v.unix, got, v.code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	code, _ := CodeAt(secret, Step(now))
+
+	if step, ok := Validate(secret, code, now); !ok || step != Step(now) {
+		t.Errorf("Validate() current code = %d, %v", step, ok)
+	}
+	if _, ok := Validate(secret, code, now.Add(Period)); !ok {
+		t.Error("Validate() rejected a code one step old")
+	}
+	if _, ok := Validate(secret, code, now.Add(3*Period)); ok {
+		t.Error("Validate() accepted a code three steps old")
+	}
+	if _, ok := Validate(secret, "12345", now); ok {
+		t.Error("Validate() accepted a short code")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("CharityLight", "ngo@example.com", "ABC")
+	if !strings.HasPrefix(uri, "otpauth://totp/CharityLight:ngo@example.com?") {
+		t.Errorf("URI() = %s", uri)
+	}
+	if !strings.Contains(uri, "secret=ABC") || !strings.Contains(uri, "issuer=CharityLight") {
+		t.Errorf("URI() = %s", uri)
+	}
+}